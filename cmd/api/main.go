@@ -1,7 +1,10 @@
 package main
 
-import "gitlab.com/a5805/ondeu/ondeu-back/internal/app"
+import (
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/app"
+	"os"
+)
 
 func main() {
-	app.Run()
+	app.Execute(os.Args[1:])
 }
@@ -2,7 +2,6 @@ package app
 
 import (
 	"context"
-	"fmt"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -15,12 +14,14 @@ import (
 	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
 	"gitlab.com/a5805/ondeu/ondeu-back/internal/server"
 	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/worker"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/gocloak/implementation"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
 	"os"
 	"os/signal"
 	"strconv"
 	"syscall"
+	"time"
 )
 
 func Run() {
@@ -29,40 +30,31 @@ func Run() {
 	cfg := initConfigs()
 	setLogLevel(cfg.LogLevel)
 
-	keycloak := implementation.Keycloak(cfg.Keycloak.Host, cfg.Keycloak.Realm)
-
-	db := repository.NewPostgresRepository(repository.Config{
-		Host:     cfg.Database.Host,
-		Username: cfg.Database.Username,
-		Password: cfg.Database.Password,
-		Dbname:   cfg.Database.DBName,
-		SSLMode:  cfg.Database.SSLMode,
-	})
-
-	objectStorageConfig := &aws.Config{
-		Credentials: credentials.NewStaticCredentials(
-			cfg.ObjectStorage.ClientKey,
-			cfg.ObjectStorage.ClientSecret,
-			""),
-		Endpoint:         aws.String(cfg.ObjectStorage.Endpoint),
-		Region:           aws.String("us-east-1"),
-		DisableSSL:       aws.Bool(true),
-		S3ForcePathStyle: aws.Bool(false), // // Configures to use subdomain/virtual calling format. Depending on your version, alternatively use o.UsePathStyle = false
+	// only the server scans uploads, the other commands never reach the daemon
+	if cfg.Scanner.Address == "" && !cfg.Scanner.Fake {
+		logrus.Fatal("CLAMD_ADDRESS is not set, set SCANNER=fake to develop without a clamd daemon")
 	}
-	newSession, err := session.NewSession(objectStorageConfig)
-	if err != nil {
-		fmt.Println(err.Error())
-	}
-	s3Client := s3.New(newSession)
 
-	repo := repository.NewRepository(db)
-	remote := remote2.NewRemote(s3Client, cfg.ObjectStorage)
+	keycloak := implementation.Keycloak(cfg.Keycloak.Host, cfg.Keycloak.Realm)
+
+	repo, remote := connect(cfg)
 	services := service.NewServices(cfg, keycloak, repo, remote)
 	handlers := handler.NewHandler(services, repo, keycloak)
 	srv := new(server.Server)
 
+	workers, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	go worker.NewPurger(services.TrashService, cfg.Trash.PurgeInterval).Run(workers)
+	go worker.NewDeleter(services.DeletionService, cfg.Deletions.Interval).Run(workers)
+	go worker.NewRelay(services.OutboxService, cfg.Outbox.Interval).Run(workers)
+	go worker.NewExpirer(services.UploadService, cfg.Sessions.Interval).Run(workers)
+	go worker.NewExtractor(services.TextService, cfg.Texts.Interval).Run(workers)
+	go worker.NewPreviewer(services.PreviewService, cfg.Previews.Interval).Run(workers)
+	go worker.NewReconciler(services.ReconcileService, cfg.Reconcile).Run(workers)
+
 	go func() {
-		if err = srv.Run(cfg.Port, handlers.Init()); err != nil {
+		if err := srv.Run(cfg.Port, handlers.Init()); err != nil {
 			logrus.Errorf("error occured while running http server %s/n", err.Error())
 		}
 	}()
@@ -80,6 +72,39 @@ func Run() {
 	}
 }
 
+// connect opens the database and the object storage the commands work on
+func connect(cfg *modules.AppConfigs) (*repository.Repository, *remote2.Remote) {
+	db := repository.NewPostgresRepository(databaseConfig(cfg))
+
+	objectStorageConfig := &aws.Config{
+		Credentials: credentials.NewStaticCredentials(
+			cfg.ObjectStorage.ClientKey,
+			cfg.ObjectStorage.ClientSecret,
+			""),
+		Endpoint:         aws.String(cfg.ObjectStorage.Endpoint),
+		Region:           aws.String("us-east-1"),
+		DisableSSL:       aws.Bool(true),
+		S3ForcePathStyle: aws.Bool(false), // // Configures to use subdomain/virtual calling format. Depending on your version, alternatively use o.UsePathStyle = false
+	}
+	newSession, err := session.NewSession(objectStorageConfig)
+	if err != nil {
+		logrus.Errorf("error occured on connecting to spaces: %s", err.Error())
+	}
+	s3Client := s3.New(newSession)
+
+	return repository.NewRepository(db), remote2.NewRemote(s3Client, cfg.ObjectStorage)
+}
+
+func databaseConfig(cfg *modules.AppConfigs) repository.Config {
+	return repository.Config{
+		Host:     cfg.Database.Host,
+		Username: cfg.Database.Username,
+		Password: cfg.Database.Password,
+		Dbname:   cfg.Database.DBName,
+		SSLMode:  cfg.Database.SSLMode,
+	}
+}
+
 func initConfigs() *modules.AppConfigs {
 	err := godotenv.Load(".env")
 	if err != nil {
@@ -97,7 +122,7 @@ func initConfigs() *modules.AppConfigs {
 	databasePort, err := strconv.Atoi(os.Getenv("DB_PORT"))
 	if err != nil {
 		databasePort = 5432
-		fmt.Printf("DB_PORT is not set, using default port: %d\n", databasePort)
+		logrus.Infof("DB_PORT is not set, using default port: %d", databasePort)
 	}
 	database := &modules.Postgre{
 		Host:     os.Getenv("DB_HOST"),
@@ -116,13 +141,104 @@ func initConfigs() *modules.AppConfigs {
 		ClientKey:    os.Getenv("SPACES_CLIENT_KEY"),
 	}
 
+	trash := &modules.Trash{
+		Retention:     durationEnv("TRASH_RETENTION", 30*24*time.Hour),
+		PurgeInterval: durationEnv("TRASH_PURGE_INTERVAL", time.Hour),
+	}
+
+	deletions := &modules.Deletions{
+		Interval: durationEnv("DELETION_INTERVAL", time.Minute),
+	}
+
+	outbox := &modules.Outbox{
+		Interval: durationEnv("OUTBOX_INTERVAL", time.Minute),
+		Timeout:  durationEnv("OUTBOX_TIMEOUT", time.Hour),
+	}
+
+	sessions := &modules.UploadSessions{
+		Lifetime: durationEnv("UPLOAD_SESSION_LIFETIME", 24*time.Hour),
+		Interval: durationEnv("UPLOAD_SESSION_INTERVAL", time.Hour),
+	}
+
+	texts := &modules.Texts{
+		Interval: durationEnv("TEXT_EXTRACTION_INTERVAL", time.Minute),
+	}
+
+	previews := &modules.Previews{
+		Interval: durationEnv("PREVIEW_INTERVAL", time.Minute),
+	}
+
+	scanner := &modules.Scanner{
+		Address: os.Getenv("CLAMD_ADDRESS"),
+		Timeout: durationEnv("CLAMD_TIMEOUT", time.Minute),
+		Fake:    os.Getenv("SCANNER") == "fake",
+	}
+
+	shares := &modules.Shares{
+		Lifetime:    durationEnv("SHARE_LIFETIME", time.Hour),
+		MaxLifetime: durationEnv("SHARE_MAX_LIFETIME", 30*24*time.Hour),
+	}
+
+	imports := &modules.Imports{
+		MaxEntries: int(intEnv("IMPORT_MAX_ENTRIES", 1000)),
+		MaxSize:    intEnv("IMPORT_MAX_SIZE", 2<<30),
+		MaxRatio:   intEnv("IMPORT_MAX_RATIO", 100),
+	}
+
+	reconcile := &modules.Reconcile{
+		Interval:   durationEnv("RECONCILE_INTERVAL", 24*time.Hour),
+		Grace:      durationEnv("RECONCILE_GRACE", 24*time.Hour),
+		Repair:     os.Getenv("RECONCILE_REPAIR") == "true",
+		MaxRepairs: int(intEnv("RECONCILE_MAX_REPAIRS", 100)),
+	}
+
+	permissions, err := modules.LoadPermissions(os.Getenv("PERMISSIONS_FILE"))
+	if err != nil {
+		logrus.Fatalf("error occured on loading permissions: %s", err.Error())
+	}
+
+	uploads, err := modules.LoadUploadPolicy(os.Getenv("UPLOAD_POLICY_FILE"))
+	if err != nil {
+		logrus.Fatalf("error occured on loading upload policy: %s", err.Error())
+	}
+
 	return &modules.AppConfigs{
 		Port:          os.Getenv("PORT"),
 		LogLevel:      os.Getenv("LOG_LEVEL"),
 		Keycloak:      keycloak,
 		Database:      database,
 		ObjectStorage: objectStorage,
+		Trash:         trash,
+		Deletions:     deletions,
+		Texts:         texts,
+		Previews:      previews,
+		Scanner:       scanner,
+		Shares:        shares,
+		Imports:       imports,
+		Reconcile:     reconcile,
+		Outbox:        outbox,
+		Sessions:      sessions,
+		Uploads:       uploads,
+		Permissions:   permissions,
+	}
+}
+
+func durationEnv(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		logrus.Infof("%s is not set, using default: %s", key, fallback)
+		return fallback
+	}
+	return value
+}
+
+func intEnv(key string, fallback int64) int64 {
+	value, err := strconv.ParseInt(os.Getenv(key), 10, 64)
+	if err != nil || value <= 0 {
+		logrus.Infof("%s is not set, using default: %d", key, fallback)
+		return fallback
 	}
+	return value
 }
 
 func setLogLevel(level string) {
@@ -0,0 +1,153 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/gocloak/implementation"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"os"
+)
+
+const usage = `usage: main [command]
+
+commands:
+  serve                        run the API server, the default
+  migrate up | down [steps] | status
+                               apply, revert or list the database migrations
+  verify                       re-hash stored blobs and report those not matching their checksum
+  reconcile [--repair]         classify mismatches between spaces and the database, a dry run unless repairing
+  recalculate-quotas           correct the usage of users that drifted from what they store
+  purge-trash                  permanently delete what expired in the trash
+  export-user <userID> <file>  write the folders, documents and data of a user to a ZIP`
+
+// Execute runs the command named by the first argument, the API server without one
+func Execute(args []string) {
+	if len(args) == 0 {
+		Run()
+		return
+	}
+
+	switch args[0] {
+	case "serve":
+		Run()
+	case "migrate":
+		Migrate(args[1:])
+	case "verify":
+		Verify()
+	case "reconcile":
+		Reconcile(args[1:])
+	case "recalculate-quotas":
+		RecalculateQuotas()
+	case "purge-trash":
+		PurgeTrash()
+	case "export-user":
+		ExportUser(args[1:])
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+// Reconcile prints the mismatches between spaces and the database, repairing them with
+// --repair. It exits with 1 when a mismatch old enough to repair is left unrepaired.
+func Reconcile(args []string) {
+	repair := len(args) == 1 && args[0] == "--repair"
+	if len(args) > 0 && !repair {
+		logrus.Fatal("usage: reconcile [--repair]")
+	}
+
+	services := commandServices()
+
+	report, err := services.ReconcileService.Reconcile(context.Background(), repair)
+	printReport(report)
+	if err != nil {
+		logrus.Fatalf("error occured on reconciling spaces: %s", err.Error())
+	}
+
+	for _, mismatch := range report.Mismatches {
+		if mismatch.Action == modules.MismatchPlanned || mismatch.Action == modules.MismatchFailed {
+			os.Exit(1)
+		}
+	}
+}
+
+// RecalculateQuotas corrects the stored usage of users and prints the corrections
+func RecalculateQuotas() {
+	services := commandServices()
+
+	corrections, err := services.QuotaService.Recalculate(context.Background())
+	if err != nil {
+		logrus.Fatalf("error occured on recalculating quotas: %s", err.Error())
+	}
+	printReport(corrections)
+}
+
+// PurgeTrash permanently deletes the items kept in the trash longer than the retention period
+func PurgeTrash() {
+	services := commandServices()
+
+	purged, err := services.TrashService.Purge(context.Background())
+	if err != nil {
+		logrus.Fatalf("error occured on purging the trash: %s", err.Error())
+	}
+	printReport(purged)
+}
+
+// ExportUser writes the export of a user to a ZIP file, the file is removed when it fails
+func ExportUser(args []string) {
+	if len(args) != 2 {
+		logrus.Fatal("usage: export-user <userID> <file>")
+	}
+	userID, path := args[0], args[1]
+
+	services := commandServices()
+	ctx := context.Background()
+
+	export, err := services.ExportService.Export(ctx, userID)
+	if err != nil {
+		logrus.Fatalf("error occured on exporting user %s: %s", userID, err.Error())
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		logrus.Fatalf("error occured on creating %s: %s", path, err.Error())
+	}
+
+	err = services.ExportService.WriteArchive(ctx, export, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		logrus.Fatalf("error occured on writing %s: %s", path, err.Error())
+	}
+
+	logrus.Printf("exported user %s to %s", userID, path)
+}
+
+// commandServices wires the services a command runs with the way the API server does
+func commandServices() *service.Services {
+	logrus.SetReportCaller(true)
+
+	cfg := initConfigs()
+	setLogLevel(cfg.LogLevel)
+
+	keycloak := implementation.Keycloak(cfg.Keycloak.Host, cfg.Keycloak.Realm)
+
+	repo, remote := connect(cfg)
+	return service.NewServices(cfg, keycloak, repo, remote)
+}
+
+// printReport writes the result of a command to the standard output as indented JSON
+func printReport(report interface{}) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		logrus.Fatalf("error occured on printing the report: %s", err.Error())
+	}
+}
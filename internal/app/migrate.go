@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+const migrateUsage = "usage: migrate up | down [steps] | status"
+
+// Migrate applies the pending migrations with up, reverts the last ones with down,
+// one unless steps are given, and lists them with status
+func Migrate(args []string) {
+	logrus.SetReportCaller(true)
+
+	if len(args) == 0 {
+		logrus.Fatal(migrateUsage)
+	}
+
+	cfg := initConfigs()
+	setLogLevel(cfg.LogLevel)
+
+	migrator, err := repository.NewMigrator(repository.OpenPostgres(databaseConfig(cfg)))
+	if err != nil {
+		logrus.Fatalf("error occured on loading migrations: %s", err.Error())
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		ran, err := migrator.Up(ctx)
+		for _, migration := range ran {
+			logrus.Printf("migrated up %04d_%s", migration.Version, migration.Name)
+		}
+		if err != nil {
+			logrus.Fatalf("error occured on migrating up: %s", err.Error())
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps <= 0 {
+				logrus.Fatal(migrateUsage)
+			}
+		}
+
+		ran, err := migrator.Down(ctx, steps)
+		for _, migration := range ran {
+			logrus.Printf("migrated down %04d_%s", migration.Version, migration.Name)
+		}
+		if err != nil {
+			logrus.Fatalf("error occured on migrating down: %s", err.Error())
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			logrus.Fatalf("error occured on reading migrations: %s", err.Error())
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, applied)
+		}
+		w.Flush()
+	default:
+		logrus.Fatal(migrateUsage)
+	}
+}
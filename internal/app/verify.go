@@ -0,0 +1,23 @@
+package app
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"os"
+)
+
+// Verify re-hashes every stored blob and prints a report of those whose
+// content doesn't match its checksum, it exits with 1 when any is found
+func Verify() {
+	services := commandServices()
+
+	report, err := services.BlobService.Verify(context.Background())
+	if err != nil {
+		logrus.Fatalf("error occured on verifying blobs: %s", err.Error())
+	}
+	printReport(report)
+
+	if len(report.Mismatches) > 0 {
+		os.Exit(1)
+	}
+}
@@ -56,4 +56,6 @@ func (h *Handler) InitRoutes(router *gin.Engine) {
 	{
 		handler.Init(api)
 	}
+
+	handler.InitPublic(router)
 }
@@ -0,0 +1,122 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"net/http"
+)
+
+func (h *Handler) initAccessRoutes(api *gin.RouterGroup) {
+	crud := api.Group("/:treeID/access")
+	{
+		crud.GET("/", h.permit(modules.ShareContent), h.listAccess)
+		crud.POST("/", h.permit(modules.ShareContent), h.grantAccess)
+		crud.DELETE("/:accessID", h.permit(modules.ShareContent), h.revokeAccess)
+	}
+}
+
+func (h *Handler) initSharedRoutes(api *gin.RouterGroup) {
+	crud := api.Group("/")
+	{
+		crud.GET("/", h.permit(modules.ReadContent), h.listShared)
+	}
+}
+
+type AccessInput struct {
+	TreeInput
+	AccessID uint `uri:"accessID" binding:"required"`
+}
+
+func (h *Handler) listAccess(ctx *gin.Context) {
+	var input TreeInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	entries, err := h.services.AccessService.List(ctx, input.TreeID)
+	if errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entries)
+	return
+}
+
+func (h *Handler) grantAccess(ctx *gin.Context) {
+	var input TreeInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	var access dto.TreeAccess
+	if err := ctx.ShouldBind(&access); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	access.ID = 0
+	access.TreeID = input.TreeID
+
+	granted, err := h.services.AccessService.Grant(ctx, access)
+	if errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, granted)
+	return
+}
+
+func (h *Handler) revokeAccess(ctx *gin.Context) {
+	var input AccessInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	revoked, err := h.services.AccessService.Revoke(ctx, dto.TreeAccess{ID: input.AccessID, TreeID: input.TreeID})
+	if errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, revoked)
+	return
+}
+
+func (h *Handler) listShared(ctx *gin.Context) {
+	entries, err := h.services.AccessService.Shared(ctx)
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entries)
+	return
+}
@@ -0,0 +1,251 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
+	servicemocks "gitlab.com/a5805/ondeu/ondeu-back/internal/service/mocks"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_grantAccess(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockAccessService)
+
+	createdData := time.Now()
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. Unknown level",
+			inputBody:            `{"subject":"user","subjectID":"student-1","level":"admin"}`,
+			mockBehavior:         func(r *servicemocks.MockAccessService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"Key: 'TreeAccess.Level' Error:Field validation for 'Level' failed on the 'oneof' tag"}`,
+		},
+		{
+			name:      "Failed. Not an owner.",
+			inputBody: `{"subject":"group","subjectID":"4","level":"viewer"}`,
+			mockBehavior: func(r *servicemocks.MockAccessService) {
+				r.EXPECT().
+					Grant(gomock.Any(), dto.TreeAccess{TreeID: 3, Subject: "group", SubjectID: "4", Level: "viewer"}).
+					Return(dto.TreeAccess{}, modules.ErrForbidden)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"reason":"you can not perform this action"}`,
+		},
+		{
+			name:      "Success.",
+			inputBody: `{"id":9,"subject":"group","subjectID":"4","level":"editor"}`,
+			mockBehavior: func(r *servicemocks.MockAccessService) {
+				r.EXPECT().
+					Grant(gomock.Any(), dto.TreeAccess{TreeID: 3, Subject: "group", SubjectID: "4", Level: "editor"}).
+					Return(dto.TreeAccess{
+						ID:        1,
+						TreeID:    3,
+						CreatedAt: createdData,
+						UpdatedAt: createdData,
+						Subject:   "group",
+						SubjectID: "4",
+						Level:     "editor",
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(
+				`{"id":1,"treeID":3,"createdAt":"%s","updatedAt":"%s","subject":"group","subjectID":"4","level":"editor"}`,
+				createdData.Format(time.RFC3339Nano),
+				createdData.Format(time.RFC3339Nano),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockAccessService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{AccessService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/api/v1/tree/:treeID/access/", handler.grantAccess)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/tree/%d/access/", 3),
+				strings.NewReader(tt.inputBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_revokeAccess(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockAccessService)
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Failed. Database. Record Not Found",
+			mockBehavior: func(r *servicemocks.MockAccessService) {
+				r.EXPECT().
+					Revoke(gomock.Any(), dto.TreeAccess{ID: 1, TreeID: 3}).
+					Return(dto.TreeAccess{}, gorm.ErrRecordNotFound)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"record not found"}`,
+		},
+		{
+			name: "Success.",
+			mockBehavior: func(r *servicemocks.MockAccessService) {
+				r.EXPECT().
+					Revoke(gomock.Any(), dto.TreeAccess{ID: 1, TreeID: 3}).
+					Return(dto.TreeAccess{ID: 1, TreeID: 3, Subject: "role", SubjectID: "bachelor", Level: "viewer"}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":1,"treeID":3,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z","subject":"role","subjectID":"bachelor","level":"viewer"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockAccessService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{AccessService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.DELETE("/api/v1/tree/:treeID/access/:accessID", handler.revokeAccess)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/tree/%d/access/%d", 3, 1), nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_listShared(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockAccessService)
+
+	createdData := time.Now()
+	false_ := false
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Failed. Unauthorized.",
+			mockBehavior: func(r *servicemocks.MockAccessService) {
+				r.EXPECT().
+					Shared(gomock.Any()).
+					Return(nil, fmt.Errorf("unauthorized action is prohibited"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"unauthorized action is prohibited"}`,
+		},
+		{
+			name: "Success.",
+			mockBehavior: func(r *servicemocks.MockAccessService) {
+				r.EXPECT().
+					Shared(gomock.Any()).
+					Return([]dto.TreeAccess{
+						{
+							ID:        2,
+							TreeID:    7,
+							CreatedAt: createdData,
+							UpdatedAt: createdData,
+							Subject:   "role",
+							SubjectID: "bachelor",
+							Level:     "viewer",
+							Tree: &dto.Tree{
+								ID:        7,
+								CreatedAt: createdData,
+								UpdatedAt: createdData,
+								Name:      "Course",
+								Template:  &false_,
+								Group:     &false_,
+							},
+						},
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(
+				`[{"id":2,"treeID":7,"tree":{"id":7,"parentID":0,"createdAt":"%s","updatedAt":"%s","name":"Course","role":"","template":false,"group":false,"documents":null},"createdAt":"%s","updatedAt":"%s","subject":"role","subjectID":"bachelor","level":"viewer"}]`,
+				createdData.Format(time.RFC3339Nano),
+				createdData.Format(time.RFC3339Nano),
+				createdData.Format(time.RFC3339Nano),
+				createdData.Format(time.RFC3339Nano),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockAccessService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{AccessService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/api/v1/shared/", handler.listShared)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/shared/", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// disposition quotes the name when it has spaces or separators and encodes it
+// when it isn't ASCII, RFC 6266
+func disposition(name string) string {
+	return mime.FormatMediaType("attachment", map[string]string{"filename": name})
+}
+
+// downloadRequest reads the range and conditional headers of a download.
+func downloadRequest(ctx *gin.Context) dto.Download {
+	download := dto.Download{
+		Range:       ctx.GetHeader("Range"),
+		IfNoneMatch: ctx.GetHeader("If-None-Match"),
+	}
+
+	// If-Modified-Since is ignored when If-None-Match is present, RFC 7232 3.3
+	if since, err := http.ParseTime(ctx.GetHeader("If-Modified-Since")); err == nil && download.IfNoneMatch == "" {
+		download.IfModifiedSince = since
+	}
+
+	return download
+}
+
+// sendContent streams stored content to the client, answering range and
+// conditional requests with 206 and 304.
+func sendContent(ctx *gin.Context, name, contentType string, content io.ReadCloser, download dto.Download) {
+	if content != nil {
+		defer content.Close()
+	}
+
+	ctx.Header("Accept-Ranges", "bytes")
+	ctx.Header("Cache-Control", "private, no-cache")
+	if download.ETag != "" {
+		ctx.Header("ETag", download.ETag)
+	}
+	if !download.LastModified.IsZero() {
+		ctx.Header("Last-Modified", download.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if download.NotModified {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	status := http.StatusOK
+	if download.ContentRange != "" {
+		status = http.StatusPartialContent
+		ctx.Header("Content-Range", download.ContentRange)
+	}
+
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	ctx.DataFromReader(status, download.ContentLength, contentType, content, map[string]string{
+		"Content-Disposition": disposition(name),
+	})
+}
+
+// sendArchive streams a ZIP archive written on the fly, its length is unknown upfront.
+// A failure midway can only be logged, the client gets a truncated archive.
+func sendArchive(ctx *gin.Context, name string, write func(w io.Writer) error) {
+	ctx.Header("Cache-Control", "private, no-cache")
+	ctx.Header("Content-Disposition", disposition(name))
+	ctx.Header("Content-Type", "application/zip")
+	ctx.Status(http.StatusOK)
+
+	if err := write(ctx.Writer); err != nil {
+		logrus.Errorf("[archive error] - %+v", err)
+	}
+}
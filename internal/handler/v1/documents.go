@@ -1,26 +1,27 @@
 package v1
 
 import (
-	"fmt"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/utils"
 	"net/http"
-	"strconv"
-	"time"
 )
 
 func (h *Handler) initDocumentsRoutes(api *gin.RouterGroup) {
 	crud := api.Group("/:treeID/document")
 	{
-		crud.POST("/", authorize(h.keycloak, []string{"admin", "manager", "student"}), h.createDocument)
-		crud.GET("/:docID", authorize(h.keycloak, []string{"admin", "manager", "student"}), h.readDocument)
-		crud.GET("/filter", authorize(h.keycloak, []string{"admin", "manager", "student"}), h.filterDocument)
-		crud.GET("/:docID/share", authorize(h.keycloak, []string{"admin", "manager", "student"}), h.shareDocument)
-		crud.PUT("/:docID", authorize(h.keycloak, []string{"admin", "manager", "student"}), h.updateDocument)
-		crud.DELETE("/:docID", authorize(h.keycloak, []string{"admin", "manager", "student"}), h.deleteDocument)
+		crud.POST("/", h.permit(modules.WriteContent), h.createDocument)
+		crud.GET("/:docID", h.permit(modules.ReadContent), h.readDocument)
+		crud.POST("/:docID/share", h.permit(modules.ShareContent), h.shareDocument)
+		crud.GET("/:docID/preview", h.permit(modules.ReadContent), h.previewDocument)
+		crud.PUT("/:docID", h.permit(modules.WriteContent), h.updateDocument)
+		crud.DELETE("/:docID", h.permit(modules.WriteContent), h.deleteDocument)
+		crud.POST("/:docID/move", h.permit(modules.WriteContent), h.moveDocument)
+		crud.POST("/:docID/copy", h.permit(modules.WriteContent), h.copyDocument)
 	}
 }
 
@@ -58,6 +59,23 @@ func (h *Handler) createDocument(ctx *gin.Context) {
 	document.TreeID = treeID
 
 	newDoc, err := h.services.DocumentService.Create(ctx, document, file)
+	var invalid *modules.ValidationError
+	if errors.As(err, &invalid) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error(), "violations": invalid.Violations})
+		return
+	}
+	if errors.Is(err, modules.ErrInfected) {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"reason": err.Error()})
+		return
+	}
+	if errors.Is(err, modules.ErrQuota) {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"reason": err.Error()})
+		return
+	}
+	if errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
 	if err != nil {
 		logrus.Errorf("[service error] - %+v", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
@@ -79,8 +97,19 @@ func (h *Handler) readDocument(ctx *gin.Context) {
 	_, download := ctx.GetQuery("download")
 
 	document := dto.Document{ID: input.DocumentID, TreeID: input.TreeID}
+	if download {
+		document.Download = downloadRequest(ctx)
+	}
 
 	stored, err := h.services.DocumentService.Get(ctx, document, download)
+	if errors.Is(err, modules.ErrNotClean) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
+	if errors.Is(err, modules.ErrInvalidRange) {
+		ctx.JSON(http.StatusRequestedRangeNotSatisfiable, gin.H{"reason": err.Error()})
+		return
+	}
 	if err != nil {
 		logrus.Errorf("[service error] - %+v", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
@@ -88,8 +117,7 @@ func (h *Handler) readDocument(ctx *gin.Context) {
 	}
 
 	if download {
-		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", stored.Name+stored.Extension))
-		ctx.Data(http.StatusOK, "application/octet-stream", stored.ResponseContent)
+		sendContent(ctx, stored.Name+stored.Extension, stored.Type, stored.ResponseContent, stored.Download)
 		return
 	}
 
@@ -116,6 +144,15 @@ func (h *Handler) updateDocument(ctx *gin.Context) {
 	doc.TreeID = input.TreeID
 
 	updated, err := h.services.DocumentService.Update(ctx, doc)
+	var invalid *modules.ValidationError
+	if errors.As(err, &invalid) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error(), "violations": invalid.Violations})
+		return
+	}
+	if errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
 	if err != nil {
 		logrus.Errorf("[service error] - %+v", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
@@ -137,6 +174,10 @@ func (h *Handler) deleteDocument(ctx *gin.Context) {
 	document := dto.Document{ID: input.DocumentID, TreeID: input.TreeID}
 
 	deleted, err := h.services.DocumentService.Delete(ctx, document)
+	if errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
 	if err != nil {
 		logrus.Errorf("[service error] - %+v", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
@@ -147,7 +188,7 @@ func (h *Handler) deleteDocument(ctx *gin.Context) {
 	return
 }
 
-func (h *Handler) shareDocument(ctx *gin.Context) {
+func (h *Handler) moveDocument(ctx *gin.Context) {
 	var input DocumentInput
 	if err := ctx.ShouldBindUri(&input); err != nil {
 		logrus.Errorf("[validaton error] - %+v", err)
@@ -155,36 +196,74 @@ func (h *Handler) shareDocument(ctx *gin.Context) {
 		return
 	}
 
-	expire := 3600 * time.Second
-	rawExpire, ok := ctx.GetQuery("expire")
-	if ok {
-		if try, err := strconv.Atoi(rawExpire); err == nil {
-			expire = time.Duration(try) * time.Second
-		}
+	var destination dto.Destination
+	if err := ctx.ShouldBind(&destination); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
 	}
 
 	document := dto.Document{ID: input.DocumentID, TreeID: input.TreeID}
 
-	stored, err := h.services.DocumentService.Share(ctx, document, expire)
+	moved, err := h.services.DocumentService.Move(ctx, document, destination.ParentID)
+	if errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
+	if errors.Is(err, modules.ErrNoParentTree) {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
 	if err != nil {
 		logrus.Errorf("[service error] - %+v", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, stored)
+	ctx.JSON(http.StatusOK, moved)
 	return
 }
 
-func (h *Handler) filterDocument(ctx *gin.Context) {
-	field := ctx.Query("field")
-	param := ctx.Query("param")
+func (h *Handler) copyDocument(ctx *gin.Context) {
+	var input DocumentInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	var destination dto.Destination
+	if err := ctx.ShouldBind(&destination); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	document := dto.Document{ID: input.DocumentID, TreeID: input.TreeID}
+
+	copied, err := h.services.DocumentService.Copy(ctx, document, destination.ParentID)
+	if errors.Is(err, modules.ErrQuota) {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"reason": err.Error()})
+		return
+	}
+	if errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
+	if errors.Is(err, modules.ErrNoParentTree) {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
 
-	tx, err := h.repos.DocumentRepository.FindByCondition(ctx, field, param)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err})
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, tx)
+	ctx.JSON(http.StatusOK, copied)
+	return
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
 	servicemocks "gitlab.com/a5805/ondeu/ondeu-back/internal/service/mocks"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
 	"gorm.io/gorm"
 	"io"
@@ -73,6 +74,44 @@ func TestHandler_createDocument(t *testing.T) {
 			expectedStatusCode:   500,
 			expectedResponseBody: `{"reason":"invalid value, should be pointer to struct or slice"}`,
 		},
+		{
+			name:          "Failed. Quota exceeded.",
+			fileExists:    true,
+			inputDocument: dto.Document{},
+			mockBehavior: func(r *servicemocks.MockDocumentService, document dto.Document) {
+				r.EXPECT().
+					Create(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(dto.Document{}, modules.ErrQuota)
+			},
+			expectedStatusCode:   413,
+			expectedResponseBody: `{"reason":"storage quota exceeded"}`,
+		},
+		{
+			name:          "Failed. Infected file.",
+			fileExists:    true,
+			inputDocument: dto.Document{},
+			mockBehavior: func(r *servicemocks.MockDocumentService, document dto.Document) {
+				r.EXPECT().
+					Create(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(dto.Document{ID: 123, ScanStatus: "infected"}, modules.ErrInfected)
+			},
+			expectedStatusCode:   422,
+			expectedResponseBody: `{"reason":"the file contains malware"}`,
+		},
+		{
+			name:          "Failed. Upload policy.",
+			fileExists:    true,
+			inputDocument: dto.Document{},
+			mockBehavior: func(r *servicemocks.MockDocumentService, document dto.Document) {
+				r.EXPECT().
+					Create(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(dto.Document{}, &modules.ValidationError{Violations: []modules.Violation{
+						{Field: "type", Rule: "content", Message: "the content is application/x-msdownload, not application/pdf"},
+					}})
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"upload rejected: the content is application/x-msdownload, not application/pdf","violations":[{"field":"type","rule":"content","message":"the content is application/x-msdownload, not application/pdf"}]}`,
+		},
 		{
 			name:       "Success.",
 			fileExists: true,
@@ -427,6 +466,20 @@ func TestHandler_deleteDocument(t *testing.T) {
 			expectedStatusCode:   500,
 			expectedResponseBody: `{"reason":"duplicated key not allowed"}`,
 		},
+		{
+			name: "Failed. Forbidden.",
+			inputDocument: dto.Document{
+				ID:     123,
+				TreeID: 1,
+			},
+			mockBehavior: func(r *servicemocks.MockDocumentService, document dto.Document) {
+				r.EXPECT().
+					Delete(gomock.Any(), gomock.Any()).
+					Return(dto.Document{}, modules.ErrForbidden)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"reason":"you can not perform this action"}`,
+		},
 		{
 			name: "Failed. Database. Invalid Value",
 			inputDocument: dto.Document{
@@ -509,112 +562,295 @@ func TestHandler_deleteDocument(t *testing.T) {
 	}
 }
 
-func TestHandler_shareDocument(t *testing.T) {
-	type mockBehavior func(r *servicemocks.MockDocumentService, document dto.Document)
+func TestHandler_readDocumentDownload(t *testing.T) {
+	type mockBehavior func(r *servicemocks.MockDocumentService)
 
-	updated := time.Now()
-	created := updated.Add(-time.Hour)
-	isTemplate := true
-	userID := uuid.New().String()
-	createdFileUUID := uuid.New()
+	modified := time.Date(2023, time.March, 1, 10, 0, 0, 0, time.UTC)
 
 	tests := []struct {
 		name                 string
-		inputDocument        dto.Document
+		headers              map[string]string
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
+		expectedHeaders      map[string]string
 		expectedResponseBody string
 	}{
 		{
-			name: "Failed. Database. Duplicate Key",
-			inputDocument: dto.Document{
-				ID:     123,
-				TreeID: 1,
-				Name:   "This is a template document",
+			name: "Success. Full content.",
+			mockBehavior: func(r *servicemocks.MockDocumentService) {
+				r.EXPECT().
+					Get(gomock.Any(), dto.Document{ID: 1, TreeID: 1}, true).
+					Return(dto.Document{
+						Name:            "lecture",
+						Extension:       ".txt",
+						Type:            "text/plain",
+						ResponseContent: io.NopCloser(strings.NewReader("hello world")),
+						Download:        dto.Download{ContentLength: 11, ETag: `"abc"`, LastModified: modified},
+					}, nil)
 			},
-			mockBehavior: func(r *servicemocks.MockDocumentService, document dto.Document) {
+			expectedStatusCode: 200,
+			expectedHeaders: map[string]string{
+				"Content-Type":        "text/plain",
+				"Content-Length":      "11",
+				"Content-Disposition": "attachment; filename=lecture.txt",
+				"Accept-Ranges":       "bytes",
+				"ETag":                `"abc"`,
+				"Last-Modified":       "Wed, 01 Mar 2023 10:00:00 GMT",
+			},
+			expectedResponseBody: "hello world",
+		},
+		{
+			name: "Success. Encoded name.",
+			mockBehavior: func(r *servicemocks.MockDocumentService) {
 				r.EXPECT().
-					Share(gomock.Any(), gomock.Any(), gomock.Any()).
+					Get(gomock.Any(), dto.Document{ID: 1, TreeID: 1}, true).
 					Return(dto.Document{
-						ID:     123,
-						TreeID: 1,
-						Name:   "This is a template document",
-					}, gorm.ErrDuplicatedKey)
+						Name:            "Лекция 1",
+						Extension:       ".txt",
+						Type:            "text/plain",
+						ResponseContent: io.NopCloser(strings.NewReader("hello world")),
+						Download:        dto.Download{ContentLength: 11},
+					}, nil)
 			},
-			expectedStatusCode:   500,
-			expectedResponseBody: `{"reason":"duplicated key not allowed"}`,
+			expectedStatusCode: 200,
+			expectedHeaders: map[string]string{
+				"Content-Disposition": "attachment; filename*=utf-8''%D0%9B%D0%B5%D0%BA%D1%86%D0%B8%D1%8F%201.txt",
+			},
+			expectedResponseBody: "hello world",
 		},
 		{
-			name: "Failed. Database. Invalid Value",
-			inputDocument: dto.Document{
-				ID:     123,
-				TreeID: 1,
-				Name:   "This is a template document",
+			name: "Failed. Not scanned clean.",
+			mockBehavior: func(r *servicemocks.MockDocumentService) {
+				r.EXPECT().
+					Get(gomock.Any(), dto.Document{ID: 1, TreeID: 1}, true).
+					Return(dto.Document{ID: 1, ScanStatus: "pending"}, modules.ErrNotClean)
 			},
-			mockBehavior: func(r *servicemocks.MockDocumentService, document dto.Document) {
+			expectedStatusCode:   403,
+			expectedHeaders:      map[string]string{},
+			expectedResponseBody: `{"reason":"the document is not scanned clean"}`,
+		},
+		{
+			name:    "Success. Partial content.",
+			headers: map[string]string{"Range": "bytes=6-"},
+			mockBehavior: func(r *servicemocks.MockDocumentService) {
 				r.EXPECT().
-					Share(gomock.Any(), gomock.Any(), gomock.Any()).
+					Get(gomock.Any(), dto.Document{ID: 1, TreeID: 1, Download: dto.Download{Range: "bytes=6-"}}, true).
 					Return(dto.Document{
-						ID:     123,
-						TreeID: 1,
-						Name:   "This is a template document",
-					}, gorm.ErrInvalidValue)
+						Name:            "lecture",
+						Extension:       ".txt",
+						ResponseContent: io.NopCloser(strings.NewReader("world")),
+						Download:        dto.Download{ContentLength: 5, ContentRange: "bytes 6-10/11", ETag: `"abc"`},
+					}, nil)
 			},
-			expectedStatusCode:   500,
-			expectedResponseBody: `{"reason":"invalid value, should be pointer to struct or slice"}`,
+			expectedStatusCode: 206,
+			expectedHeaders: map[string]string{
+				"Content-Type":   "application/octet-stream",
+				"Content-Length": "5",
+				"Content-Range":  "bytes 6-10/11",
+				"ETag":           `"abc"`,
+			},
+			expectedResponseBody: "world",
 		},
 		{
-			name: "Success. Update document",
-			inputDocument: dto.Document{
-				ID:        123,
-				UserID:    userID,
-				TreeID:    1,
-				UpdatedAt: updated,
-				Name:      "This is a template document",
-				Path:      createdFileUUID,
-				Template:  &isTemplate,
+			name: "Success. Not modified.",
+			headers: map[string]string{
+				"If-None-Match":     `"abc"`,
+				"If-Modified-Since": "Wed, 01 Mar 2023 10:00:00 GMT",
 			},
-			mockBehavior: func(r *servicemocks.MockDocumentService, document dto.Document) {
+			mockBehavior: func(r *servicemocks.MockDocumentService) {
 				r.EXPECT().
-					Share(gomock.Any(), gomock.Any(), gomock.Any()).
+					Get(gomock.Any(), dto.Document{ID: 1, TreeID: 1, Download: dto.Download{IfNoneMatch: `"abc"`}}, true).
 					Return(dto.Document{
-						ID:        123,
-						UserID:    userID,
-						TreeID:    1,
-						CreatedAt: created,
-						UpdatedAt: updated,
-						Name:      "This is a template document",
-						Path:      createdFileUUID,
-						Template:  &isTemplate,
+						Download: dto.Download{ETag: `"abc"`, NotModified: true},
 					}, nil)
 			},
-			expectedStatusCode: 200,
-			expectedResponseBody: fmt.Sprintf(`{"id":123,"createdAt":"%s","updatedAt":"%s","name":"This is a template document","path":"%s","template":true}`,
-				created.Format(time.RFC3339Nano),
-				updated.Format(time.RFC3339Nano),
-				createdFileUUID.String()),
+			expectedStatusCode: 304,
+			expectedHeaders: map[string]string{
+				"ETag": `"abc"`,
+			},
+			expectedResponseBody: "",
+		},
+		{
+			name:    "Failed. Invalid range.",
+			headers: map[string]string{"Range": "bytes=100-"},
+			mockBehavior: func(r *servicemocks.MockDocumentService) {
+				r.EXPECT().
+					Get(gomock.Any(), dto.Document{ID: 1, TreeID: 1, Download: dto.Download{Range: "bytes=100-"}}, true).
+					Return(dto.Document{}, modules.ErrInvalidRange)
+			},
+			expectedStatusCode:   416,
+			expectedResponseBody: `{"reason":"requested range not satisfiable"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockDocumentService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{DocumentService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/api/v1/tree/:treeID/document/:docID", handler.readDocument)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/tree/%d/document/%d?download", 1, 1), nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			for key, value := range tt.expectedHeaders {
+				assert.Equal(t, value, w.Header().Get(key), key)
+			}
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_moveDocument(t *testing.T) {
+	type mockBehavior func(r *servicemocks.MockDocumentService)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Failed. No parent tree.",
+			inputBody: `{"parentID":0}`,
+			mockBehavior: func(r *servicemocks.MockDocumentService) {
+				r.EXPECT().
+					Move(gomock.Any(), dto.Document{ID: 2, TreeID: 1}, uint(0)).
+					Return(dto.Document{}, modules.ErrNoParentTree)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"a document must be placed into a folder"}`,
+		},
+		{
+			name:      "Failed. Database. Record Not Found",
+			inputBody: `{"parentID":3}`,
+			mockBehavior: func(r *servicemocks.MockDocumentService) {
+				r.EXPECT().
+					Move(gomock.Any(), dto.Document{ID: 2, TreeID: 1}, uint(3)).
+					Return(dto.Document{}, gorm.ErrRecordNotFound)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"record not found"}`,
+		},
+		{
+			name:      "Success.",
+			inputBody: `{"parentID":3}`,
+			mockBehavior: func(r *servicemocks.MockDocumentService) {
+				r.EXPECT().
+					Move(gomock.Any(), dto.Document{ID: 2, TreeID: 1}, uint(3)).
+					Return(dto.Document{ID: 2, TreeID: 3, Name: "essay"}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":2,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z","name":"essay","path":"00000000-0000-0000-0000-000000000000","template":null}`,
 		},
 	}
 	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockDocumentService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{DocumentService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/api/v1/tree/:treeID/document/:docID/move", handler.moveDocument)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/tree/%d/document/%d/move", 1, 2),
+				strings.NewReader(tt.inputBody))
+			req.Header.Set("Content-Type", "application/json")
 
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_copyDocument(t *testing.T) {
+	type mockBehavior func(r *servicemocks.MockDocumentService)
+
+	path := uuid.New()
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Failed. No parent tree.",
+			inputBody: `{"parentID":0}`,
+			mockBehavior: func(r *servicemocks.MockDocumentService) {
+				r.EXPECT().
+					Copy(gomock.Any(), dto.Document{ID: 2, TreeID: 1}, uint(0)).
+					Return(dto.Document{}, modules.ErrNoParentTree)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"a document must be placed into a folder"}`,
+		},
+		{
+			name:      "Success.",
+			inputBody: `{"parentID":3}`,
+			mockBehavior: func(r *servicemocks.MockDocumentService) {
+				r.EXPECT().
+					Copy(gomock.Any(), dto.Document{ID: 2, TreeID: 1}, uint(3)).
+					Return(dto.Document{ID: 8, TreeID: 3, Name: "essay", Path: path}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(`{"id":8,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z","name":"essay","path":"%s","template":null}`,
+				path.String()),
+		},
+	}
+	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Init Dependencies
 			c := gomock.NewController(t)
 			defer c.Finish()
 
 			repo := servicemocks.NewMockDocumentService(c)
-			tt.mockBehavior(repo, tt.inputDocument)
+			tt.mockBehavior(repo)
 
 			services := &service.Services{DocumentService: repo}
 			handler := Handler{services, nil, nil}
 
 			// Init Endpoint
 			r := gin.New()
-			r.GET("/api/v1/tree/:treeID/document/:docID/share", handler.shareDocument)
+			r.POST("/api/v1/tree/:treeID/document/:docID/copy", handler.copyDocument)
 
 			// Create Request
 			w := httptest.NewRecorder()
-			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/tree/%d/document/%d/share", 1, 1), nil)
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/tree/%d/document/%d/copy", 1, 2),
+				strings.NewReader(tt.inputBody))
+			req.Header.Set("Content-Type", "application/json")
 
 			// Make Request
 			r.ServeHTTP(w, req)
@@ -0,0 +1,288 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"net/http"
+)
+
+func (h *Handler) initGroupRoutes(api *gin.RouterGroup) {
+	crud := api.Group("/")
+	{
+		crud.POST("/", h.permit(modules.ManageGroups), h.createGroup)
+		crud.GET("/", h.permit(modules.ReadGroups), h.listGroups)
+		crud.GET("/:groupID", h.permit(modules.ReadGroups), h.getGroup)
+		crud.PUT("/:groupID", h.permit(modules.ManageGroups), h.updateGroup)
+		crud.DELETE("/:groupID", h.permit(modules.ManageGroups), h.deleteGroup)
+
+		crud.POST("/:groupID/members", h.permit(modules.ManageGroups), h.addGroupMember)
+		crud.DELETE("/:groupID/members/:userID", h.permit(modules.ManageGroups), h.removeGroupMember)
+
+		crud.GET("/:groupID/documents", h.permit(modules.ReadGroups), h.listGroupDocuments)
+		crud.POST("/:groupID/documents/:docID", h.permit(modules.ManageGroups), h.attachGroupDocument)
+		crud.DELETE("/:groupID/documents/:docID", h.permit(modules.ManageGroups), h.detachGroupDocument)
+
+		crud.POST("/:groupID/trees/:treeID", h.permit(modules.ManageGroups), h.attachGroupTree)
+		crud.DELETE("/:groupID/trees/:treeID", h.permit(modules.ManageGroups), h.detachGroupTree)
+	}
+}
+
+type GroupInput struct {
+	GroupID uint `uri:"groupID" binding:"required"`
+}
+
+type GroupMemberInput struct {
+	GroupInput
+	UserID string `uri:"userID" binding:"required"`
+}
+
+type GroupDocumentInput struct {
+	GroupInput
+	DocumentID uint `uri:"docID" binding:"required"`
+}
+
+type GroupTreeInput struct {
+	GroupInput
+	TreeID uint `uri:"treeID" binding:"required"`
+}
+
+func (h *Handler) createGroup(ctx *gin.Context) {
+	var group dto.Group
+	if err := ctx.ShouldBind(&group); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	created, err := h.services.GroupService.Create(ctx, group)
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, created)
+	return
+}
+
+func (h *Handler) listGroups(ctx *gin.Context) {
+	groups, err := h.services.GroupService.List(ctx)
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, groups)
+	return
+}
+
+func (h *Handler) getGroup(ctx *gin.Context) {
+	var input GroupInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	group, err := h.services.GroupService.Get(ctx, dto.Group{ID: input.GroupID})
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, group)
+	return
+}
+
+func (h *Handler) updateGroup(ctx *gin.Context) {
+	var input GroupInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	var group dto.Group
+	if err := ctx.ShouldBind(&group); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	group.ID = input.GroupID
+
+	updated, err := h.services.GroupService.Update(ctx, group)
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+	return
+}
+
+func (h *Handler) deleteGroup(ctx *gin.Context) {
+	var input GroupInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	deleted, err := h.services.GroupService.Delete(ctx, dto.Group{ID: input.GroupID})
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deleted)
+	return
+}
+
+func (h *Handler) addGroupMember(ctx *gin.Context) {
+	var input GroupInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	var member dto.GroupMember
+	if err := ctx.ShouldBind(&member); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	member.GroupID = input.GroupID
+
+	added, err := h.services.GroupService.AddMember(ctx, member)
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, added)
+	return
+}
+
+func (h *Handler) removeGroupMember(ctx *gin.Context) {
+	var input GroupMemberInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	removed, err := h.services.GroupService.RemoveMember(ctx, dto.GroupMember{GroupID: input.GroupID, UserID: input.UserID})
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, removed)
+	return
+}
+
+func (h *Handler) listGroupDocuments(ctx *gin.Context) {
+	var input GroupInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	docs, err := h.services.GroupService.ListDocuments(ctx, dto.Group{ID: input.GroupID})
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, docs)
+	return
+}
+
+func (h *Handler) attachGroupDocument(ctx *gin.Context) {
+	var input GroupDocumentInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	group, err := h.services.GroupService.AttachDocument(ctx, dto.Group{ID: input.GroupID}, input.DocumentID)
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, group)
+	return
+}
+
+func (h *Handler) detachGroupDocument(ctx *gin.Context) {
+	var input GroupDocumentInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	group, err := h.services.GroupService.DetachDocument(ctx, dto.Group{ID: input.GroupID}, input.DocumentID)
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, group)
+	return
+}
+
+func (h *Handler) attachGroupTree(ctx *gin.Context) {
+	var input GroupTreeInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	group, err := h.services.GroupService.AttachTree(ctx, dto.Group{ID: input.GroupID}, input.TreeID)
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, group)
+	return
+}
+
+func (h *Handler) detachGroupTree(ctx *gin.Context) {
+	var input GroupTreeInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	group, err := h.services.GroupService.DetachTree(ctx, dto.Group{ID: input.GroupID}, input.TreeID)
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, group)
+	return
+}
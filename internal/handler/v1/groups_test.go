@@ -0,0 +1,420 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
+	servicemocks "gitlab.com/a5805/ondeu/ondeu-back/internal/service/mocks"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_createGroup(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockGroupService)
+
+	createdData := time.Now()
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. No name",
+			inputBody:            `{"desc":"first year"}`,
+			mockBehavior:         func(r *servicemocks.MockGroupService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"Key: 'Group.Name' Error:Field validation for 'Name' failed on the 'required' tag"}`,
+		},
+		{
+			name:      "Success.",
+			inputBody: `{"name":"CS-101","desc":"first year","role":"bachelor"}`,
+			mockBehavior: func(r *servicemocks.MockGroupService) {
+				r.EXPECT().
+					Create(gomock.Any(), dto.Group{Name: "CS-101", Desc: "first year", Role: "bachelor"}).
+					Return(dto.Group{
+						ID:        3,
+						CreatedAt: createdData,
+						UpdatedAt: createdData,
+						Name:      "CS-101",
+						Desc:      "first year",
+						Role:      "bachelor",
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(`{"id":3,"createdAt":"%s","updatedAt":"%s","name":"CS-101","desc":"first year","role":"bachelor"}`,
+				createdData.Format(time.RFC3339Nano),
+				createdData.Format(time.RFC3339Nano),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockGroupService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{GroupService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/api/v1/groups/", handler.createGroup)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/", strings.NewReader(tt.inputBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getGroup(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockGroupService)
+
+	createdData := time.Now()
+
+	tests := []struct {
+		name                 string
+		groupID              string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. Invalid id",
+			groupID:              "abc",
+			mockBehavior:         func(r *servicemocks.MockGroupService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"strconv.ParseUint: parsing \"abc\": invalid syntax"}`,
+		},
+		{
+			name:    "Failed. Not a member.",
+			groupID: "3",
+			mockBehavior: func(r *servicemocks.MockGroupService) {
+				r.EXPECT().
+					Get(gomock.Any(), dto.Group{ID: 3}).
+					Return(dto.Group{}, gorm.ErrRecordNotFound)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"record not found"}`,
+		},
+		{
+			name:    "Success.",
+			groupID: "3",
+			mockBehavior: func(r *servicemocks.MockGroupService) {
+				r.EXPECT().
+					Get(gomock.Any(), dto.Group{ID: 3}).
+					Return(dto.Group{
+						ID:        3,
+						CreatedAt: createdData,
+						UpdatedAt: createdData,
+						Name:      "CS-101",
+						Members:   []dto.GroupMember{{GroupID: 3, UserID: "student-sub", CreatedAt: createdData}},
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(`{"id":3,"createdAt":"%[1]s","updatedAt":"%[1]s","name":"CS-101","role":"","members":[{"userID":"student-sub","createdAt":"%[1]s"}]}`,
+				createdData.Format(time.RFC3339Nano),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockGroupService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{GroupService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/api/v1/groups/:groupID", handler.getGroup)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/groups/"+tt.groupID, nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_addGroupMember(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockGroupService)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. No user",
+			inputBody:            `{}`,
+			mockBehavior:         func(r *servicemocks.MockGroupService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"Key: 'GroupMember.UserID' Error:Field validation for 'UserID' failed on the 'required' tag"}`,
+		},
+		{
+			name:      "Failed. Not an owner.",
+			inputBody: `{"userID":"student-sub"}`,
+			mockBehavior: func(r *servicemocks.MockGroupService) {
+				r.EXPECT().
+					AddMember(gomock.Any(), dto.GroupMember{GroupID: 3, UserID: "student-sub"}).
+					Return(dto.GroupMember{}, gorm.ErrRecordNotFound)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"record not found"}`,
+		},
+		{
+			name:      "Success.",
+			inputBody: `{"userID":"student-sub"}`,
+			mockBehavior: func(r *servicemocks.MockGroupService) {
+				r.EXPECT().
+					AddMember(gomock.Any(), dto.GroupMember{GroupID: 3, UserID: "student-sub"}).
+					Return(dto.GroupMember{GroupID: 3, UserID: "student-sub"}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"userID":"student-sub","createdAt":"0001-01-01T00:00:00Z"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockGroupService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{GroupService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/api/v1/groups/:groupID/members", handler.addGroupMember)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/3/members", strings.NewReader(tt.inputBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_removeGroupMember(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockGroupService)
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Failed. Not a member.",
+			mockBehavior: func(r *servicemocks.MockGroupService) {
+				r.EXPECT().
+					RemoveMember(gomock.Any(), dto.GroupMember{GroupID: 3, UserID: "student-sub"}).
+					Return(dto.GroupMember{}, gorm.ErrRecordNotFound)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"record not found"}`,
+		},
+		{
+			name: "Success.",
+			mockBehavior: func(r *servicemocks.MockGroupService) {
+				r.EXPECT().
+					RemoveMember(gomock.Any(), dto.GroupMember{GroupID: 3, UserID: "student-sub"}).
+					Return(dto.GroupMember{GroupID: 3, UserID: "student-sub"}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"userID":"student-sub","createdAt":"0001-01-01T00:00:00Z"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockGroupService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{GroupService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.DELETE("/api/v1/groups/:groupID/members/:userID", handler.removeGroupMember)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/groups/3/members/student-sub", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_listGroupDocuments(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockGroupService)
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Failed. Not a member.",
+			mockBehavior: func(r *servicemocks.MockGroupService) {
+				r.EXPECT().
+					ListDocuments(gomock.Any(), dto.Group{ID: 3}).
+					Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"record not found"}`,
+		},
+		{
+			name: "Success.",
+			mockBehavior: func(r *servicemocks.MockGroupService) {
+				r.EXPECT().
+					ListDocuments(gomock.Any(), dto.Group{ID: 3}).
+					Return([]dto.Document{{ID: 7, Name: "syllabus"}}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `[{"id":7,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z","name":"syllabus","path":"00000000-0000-0000-0000-000000000000","template":null}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockGroupService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{GroupService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/api/v1/groups/:groupID/documents", handler.listGroupDocuments)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/groups/3/documents", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_attachGroupDocument(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockGroupService)
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Failed. Document of another user.",
+			mockBehavior: func(r *servicemocks.MockGroupService) {
+				r.EXPECT().
+					AttachDocument(gomock.Any(), dto.Group{ID: 3}, uint(7)).
+					Return(dto.Group{}, gorm.ErrRecordNotFound)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"record not found"}`,
+		},
+		{
+			name: "Success.",
+			mockBehavior: func(r *servicemocks.MockGroupService) {
+				r.EXPECT().
+					AttachDocument(gomock.Any(), dto.Group{ID: 3}, uint(7)).
+					Return(dto.Group{ID: 3, Name: "CS-101"}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":3,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z","name":"CS-101","role":""}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockGroupService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{GroupService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/api/v1/groups/:groupID/documents/:docID", handler.attachGroupDocument)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/3/documents/7", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
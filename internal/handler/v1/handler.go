@@ -28,7 +28,43 @@ func (h *Handler) Init(api *gin.RouterGroup) {
 		tree := v1.Group("/tree")
 		{
 			h.initDocumentsRoutes(tree)
+			h.initVersionRoutes(tree)
+			h.initUploadRoutes(tree)
 			h.initTreeRoutes(tree)
+			h.initAccessRoutes(tree)
+			h.initPolicyRoutes(tree)
+		}
+		search := v1.Group("/search")
+		{
+			h.initSearchRoutes(search)
+		}
+		shared := v1.Group("/shared")
+		{
+			h.initSharedRoutes(shared)
+		}
+		groups := v1.Group("/groups")
+		{
+			h.initGroupRoutes(groups)
+		}
+		templates := v1.Group("/templates")
+		{
+			h.initTemplateRoutes(templates)
+		}
+		trash := v1.Group("/trash")
+		{
+			h.initTrashRoutes(trash)
+		}
+		me := v1.Group("/me")
+		{
+			h.initMeRoutes(me)
+		}
+		shares := v1.Group("/shares")
+		{
+			h.initShareRoutes(shares)
+		}
+		quotas := v1.Group("/quotas")
+		{
+			h.initQuotaRoutes(quotas)
 		}
 		info := v1.Group("/info")
 		{
@@ -36,3 +72,11 @@ func (h *Handler) Init(api *gin.RouterGroup) {
 		}
 	}
 }
+
+// InitPublic registers the routes served without authentication
+func (h *Handler) InitPublic(router gin.IRouter) {
+	shares := router.Group("/s")
+	{
+		h.initPublicShareRoutes(shares)
+	}
+}
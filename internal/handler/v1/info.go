@@ -3,13 +3,14 @@ package v1
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
 	"net/http"
 )
 
 func (h *Handler) initInfoRoutes(api *gin.RouterGroup) {
 	info := api.Group("/")
 	{
-		info.GET("/roles", authorize(h.keycloak, []string{"admin", "manager", "student"}), h.getRoles)
+		info.GET("/roles", h.permit(modules.ReadInfo), h.getRoles)
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/sirupsen/logrus"
 	keycloak "gitlab.com/a5805/ondeu/ondeu-back/pkg/gocloak"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
@@ -12,6 +13,9 @@ import (
 	"net/http"
 )
 
+// resourceClient is the keycloak client whose roles are checked
+const resourceClient = "ondeu-front"
+
 var (
 	ErrAccessDenied = "access denied"
 	ErrInvalidToken = "token missing required parameters"
@@ -20,83 +24,168 @@ var (
 
 func authorize(auth keycloak.IKeycloak, roles []string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		valid, claims, err := auth.ValidateToken(ctx, ctx.Request.Header)
-		if !valid {
-			ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
-			ctx.Abort()
+		if !authenticate(ctx, auth, roles) {
 			return
 		}
 
-		logrus.Debugf("claims: %v", claims)
+		ctx.Next()
+	}
+}
 
+// permit lets the request through when the permission matrix grants the action to a role of the caller
+func (h *Handler) permit(action string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		roles := h.services.PermissionService.Roles(action)
 		if len(roles) == 0 {
-			roles = []string{"default-roles-ondeu"}
-		}
-
-		access, err := auth.CheckAccessToken(ctx, ctx.Request.Header, nil, map[string][]string{"ondeu-front": roles})
-		if err != nil {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"reason": err.Error()})
+			ctx.JSON(http.StatusForbidden, gin.H{"reason": ErrUnauthorized})
 			ctx.Abort()
 			return
 		}
 
-		if !access {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"reason": ErrAccessDenied})
-			ctx.Abort()
+		if !authenticate(ctx, h.keycloak, roles) {
 			return
 		}
 
-		userId, ok := claims["sub"].(string)
-		if !ok && userId == "" {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"reason": ErrInvalidToken})
+		if !h.services.PermissionService.Allowed(ctx, action) {
+			ctx.JSON(http.StatusForbidden, gin.H{"reason": ErrUnauthorized})
 			ctx.Abort()
 			return
 		}
 
-		clientID, ok := claims["azp"].(string)
-		if !ok && clientID == "" {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"reason": ErrInvalidToken})
-			ctx.Abort()
-			return
-		}
+		ctx.Next()
+	}
+}
+
+// authenticate validates the token of the caller and stores its identity in the context,
+// the request is aborted when false is returned
+func authenticate(ctx *gin.Context, auth keycloak.IKeycloak, roles []string) bool {
+	valid, claims, err := auth.ValidateToken(ctx, ctx.Request.Header)
+	if !valid {
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		ctx.Abort()
+		return false
+	}
 
-		ctx.Set(modules.ClientID, clientID)
-		ctx.Set(modules.UserID, userId)
+	logrus.Debugf("claims: %v", claims)
 
-		ctx.Next()
+	if len(roles) == 0 {
+		roles = []string{"default-roles-ondeu"}
+	}
+
+	access, err := auth.CheckAccessToken(ctx, ctx.Request.Header, nil, map[string][]string{resourceClient: roles})
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"reason": err.Error()})
+		ctx.Abort()
+		return false
+	}
+
+	if !access {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"reason": ErrAccessDenied})
+		ctx.Abort()
+		return false
+	}
+
+	userId, ok := claims["sub"].(string)
+	if !ok && userId == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"reason": ErrInvalidToken})
+		ctx.Abort()
+		return false
+	}
+
+	clientID, ok := claims["azp"].(string)
+	if !ok && clientID == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"reason": ErrInvalidToken})
+		ctx.Abort()
+		return false
 	}
+
+	ctx.Set(modules.ClientID, clientID)
+	ctx.Set(modules.UserID, userId)
+	ctx.Set(modules.Roles, claimRoles(claims))
+	ctx.Set(modules.Organization, claimOrganization(claims))
+
+	return true
 }
 
-func getRole(c *gin.Context) (string, error) {
-	role := c.GetString("role")
-	if role == "" {
-		return "", errors.New("empty role")
+// claimRoles returns the roles of the caller in the resource client
+func claimRoles(claims map[string]interface{}) []string {
+	roles := make([]string, 0)
+
+	access, ok := claims["resource_access"].(map[string]interface{})
+	if !ok {
+		return roles
+	}
+
+	client, ok := access[resourceClient].(map[string]interface{})
+	if !ok {
+		return roles
 	}
-	return role, nil
+
+	granted, ok := client["roles"].([]interface{})
+	if !ok {
+		return roles
+	}
+
+	for _, role := range granted {
+		if name, ok := role.(string); ok {
+			roles = append(roles, name)
+		}
+	}
+
+	return roles
 }
 
-func (h *Handler) adminIdentity(c *gin.Context) {
-	role, err := getRole(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"reason": err.Error()})
-		return
+// claimOrganization returns the customer id of the organization of the caller,
+// the claim has the shape of keycloak.UserClaim
+func claimOrganization(claims map[string]interface{}) int {
+	organization, ok := claims["organization"].(map[string]interface{})
+	if !ok {
+		return 0
 	}
-	if role != modules.Admin {
-		c.JSON(http.StatusUnauthorized, gin.H{"reason": ErrUnauthorized})
-		return
+
+	// numbers of decoded json claims are floats
+	id, ok := organization["customerId"].(float64)
+	if !ok {
+		return 0
+	}
+
+	return int(id)
+}
+
+// getRoles returns the roles stored in the context by authorize
+func getRoles(c *gin.Context) ([]string, error) {
+	roles, ok := c.Value(modules.Roles).([]string)
+	if !ok || len(roles) == 0 {
+		return nil, errors.New("empty role")
 	}
+	return roles, nil
+}
+
+func (h *Handler) adminIdentity(c *gin.Context) {
+	requireRole(c, modules.Admin)
 }
 
 func (h *Handler) managerIdentity(c *gin.Context) {
-	role, err := getRole(c)
+	requireRole(c, modules.Manager)
+}
+
+// requireRole aborts the request unless the caller has the role
+func requireRole(c *gin.Context, role string) {
+	roles, err := getRoles(c)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"reason": err.Error()})
+		c.Abort()
 		return
 	}
-	if role != modules.Manager {
-		c.JSON(http.StatusUnauthorized, gin.H{"reason": ErrUnauthorized})
-		return
+
+	for _, granted := range roles {
+		if granted == role {
+			return
+		}
 	}
+
+	c.JSON(http.StatusForbidden, gin.H{"reason": ErrUnauthorized})
+	c.Abort()
 }
 
 func CORSMiddleware() gin.HandlerFunc {
@@ -117,6 +206,13 @@ func CORSMiddleware() gin.HandlerFunc {
 
 func ReadRequestBody() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// file uploads are streamed to the handlers, buffering them here
+		// would keep whole documents in memory just to log their first bytes
+		if !loggableBody(c.ContentType()) {
+			c.Next()
+			return
+		}
+
 		var body []byte
 		if c.Request.Body != nil {
 			body, _ = ioutil.ReadAll(c.Request.Body)
@@ -129,3 +225,12 @@ func ReadRequestBody() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+func loggableBody(contentType string) bool {
+	switch contentType {
+	case binding.MIMEJSON, binding.MIMEPOSTForm, binding.MIMEPlain:
+		return true
+	default:
+		return false
+	}
+}
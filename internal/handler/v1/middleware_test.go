@@ -6,30 +6,34 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
 	servicemocks "gitlab.com/a5805/ondeu/ondeu-back/pkg/gocloak/mocks"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
-func Test_getRole(t *testing.T) {
+func Test_getRoles(t *testing.T) {
 	tests := []struct {
 		name  string
-		input string
-		want  string
+		input []string
+		want  []string
 		err   error
 	}{
 		{
 			name:  "Success.",
-			input: "test",
-			want:  "test",
+			input: []string{"student", "bachelor"},
+			want:  []string{"student", "bachelor"},
 			err:   nil,
 		},
 		{
 			name:  "Failed. No role in context.",
-			input: "",
-			want:  "",
+			input: []string{},
+			want:  nil,
 			err:   errors.New("empty role"),
 		},
 	}
@@ -37,11 +41,53 @@ func Test_getRole(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			w := httptest.NewRecorder()
 			testCtx, _ := gin.CreateTestContext(w)
-			testCtx.Set("role", tt.input)
+			testCtx.Set(modules.Roles, tt.input)
 
-			got, err := getRole(testCtx)
-			assert.Equalf(t, tt.want, got, "getRole()")
-			assert.Equalf(t, tt.err, err, "getRole()")
+			got, err := getRoles(testCtx)
+			assert.Equalf(t, tt.want, got, "getRoles()")
+			assert.Equalf(t, tt.err, err, "getRoles()")
+		})
+	}
+}
+
+func TestHandler_adminIdentity(t *testing.T) {
+	tests := []struct {
+		name     string
+		roles    []string
+		wantCode int
+	}{
+		{
+			name:     "Success.",
+			roles:    []string{"manager", "admin"},
+			wantCode: 200,
+		},
+		{
+			name:     "Failed. Manager.",
+			roles:    []string{"manager"},
+			wantCode: 403,
+		},
+		{
+			name:     "Failed. No roles.",
+			roles:    nil,
+			wantCode: 401,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Handler{}
+
+			w := httptest.NewRecorder()
+			_, engine := gin.CreateTestContext(w)
+
+			engine.GET("/test", func(ctx *gin.Context) {
+				ctx.Set(modules.Roles, tt.roles)
+			}, handler.adminIdentity, func(ctx *gin.Context) {
+				ctx.Status(200)
+			})
+
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+			assert.Equal(t, tt.wantCode, w.Code)
 		})
 	}
 }
@@ -110,3 +156,194 @@ func Test_authorize(t *testing.T) {
 		})
 	}
 }
+
+func TestReadRequestBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantBuffer  bool
+	}{
+		{
+			name:        "Success. JSON body is buffered.",
+			contentType: "application/json",
+			body:        `{"name":"test"}`,
+			wantBuffer:  true,
+		},
+		{
+			name:        "Success. Chunk is streamed.",
+			contentType: "application/octet-stream",
+			body:        "chunk",
+			wantBuffer:  false,
+		},
+		{
+			name:        "Success. Multipart form is streamed.",
+			contentType: "multipart/form-data; boundary=test",
+			body:        "--test--",
+			wantBuffer:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			_, engine := gin.CreateTestContext(w)
+
+			original := io.NopCloser(strings.NewReader(tt.body))
+
+			engine.POST("/test", ReadRequestBody(), func(ctx *gin.Context) {
+				assert.Equal(t, tt.wantBuffer, ctx.Request.Body != original)
+
+				body, err := io.ReadAll(ctx.Request.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.body, string(body))
+				ctx.Status(200)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/test", nil)
+			req.Body = original
+			req.Header.Set("Content-Type", tt.contentType)
+
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, 200, w.Code)
+		})
+	}
+}
+
+func Test_claimRoles(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		want   []string
+	}{
+		{
+			name: "Success.",
+			claims: map[string]interface{}{
+				"resource_access": map[string]interface{}{
+					"ondeu-front": map[string]interface{}{"roles": []interface{}{"student", "bachelor"}},
+					"account":     map[string]interface{}{"roles": []interface{}{"manage-account"}},
+				},
+			},
+			want: []string{"student", "bachelor"},
+		},
+		{
+			name: "Success. No roles of the client.",
+			claims: map[string]interface{}{
+				"resource_access": map[string]interface{}{
+					"account": map[string]interface{}{"roles": []interface{}{"manage-account"}},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name:   "Success. No resource access.",
+			claims: map[string]interface{}{"sub": "user"},
+			want:   []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, claimRoles(tt.claims))
+		})
+	}
+}
+
+func Test_claimOrganization(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		want   int
+	}{
+		{
+			name: "Success.",
+			claims: map[string]interface{}{
+				"organization": map[string]interface{}{"customerId": float64(42), "customerName": "KBTU"},
+			},
+			want: 42,
+		},
+		{
+			name:   "Success. No organization.",
+			claims: map[string]interface{}{"sub": "user"},
+			want:   0,
+		},
+		{
+			name: "Success. No customer id.",
+			claims: map[string]interface{}{
+				"organization": map[string]interface{}{"customerName": "KBTU"},
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, claimOrganization(tt.claims))
+		})
+	}
+}
+
+func TestHandler_permit(t *testing.T) {
+	permissions, err := modules.LoadPermissions("")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		roles    []interface{}
+		action   string
+		wantCode int
+	}{
+		{name: "Success. Admin deletes any content.", roles: []interface{}{"admin"}, action: modules.DeleteAnyContent, wantCode: 200},
+		{name: "Failed. Manager deletes any content.", roles: []interface{}{"manager"}, action: modules.DeleteAnyContent, wantCode: 403},
+		{name: "Failed. Student deletes any content.", roles: []interface{}{"student"}, action: modules.DeleteAnyContent, wantCode: 403},
+		{name: "Success. Admin creates a template.", roles: []interface{}{"admin"}, action: modules.CreateTemplate, wantCode: 200},
+		{name: "Success. Manager creates a template.", roles: []interface{}{"manager"}, action: modules.CreateTemplate, wantCode: 200},
+		{name: "Failed. Student creates a template.", roles: []interface{}{"student"}, action: modules.CreateTemplate, wantCode: 403},
+		{name: "Success. Manager manages groups.", roles: []interface{}{"manager"}, action: modules.ManageGroups, wantCode: 200},
+		{name: "Failed. Student manages groups.", roles: []interface{}{"student"}, action: modules.ManageGroups, wantCode: 403},
+		{name: "Success. Student reads groups.", roles: []interface{}{"student"}, action: modules.ReadGroups, wantCode: 200},
+		{name: "Success. Student writes content.", roles: []interface{}{"student"}, action: modules.WriteContent, wantCode: 200},
+		{name: "Success. Student uses a template.", roles: []interface{}{"student"}, action: modules.UseTemplate, wantCode: 200},
+		{name: "Success. Admin manages quotas.", roles: []interface{}{"admin"}, action: modules.ManageQuotas, wantCode: 200},
+		{name: "Failed. Manager manages quotas.", roles: []interface{}{"manager"}, action: modules.ManageQuotas, wantCode: 403},
+		{name: "Failed. Unknown role.", roles: []interface{}{"guest"}, action: modules.ReadContent, wantCode: 403},
+		{name: "Failed. Unknown action.", roles: []interface{}{"admin"}, action: "drop-database", wantCode: 403},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			keycloak := servicemocks.NewMockIKeycloak(c)
+			keycloak.EXPECT().
+				ValidateToken(gomock.Any(), gomock.Any()).
+				Return(true, map[string]interface{}{
+					"sub": "user",
+					"azp": "ondeu-front",
+					"resource_access": map[string]interface{}{
+						"ondeu-front": map[string]interface{}{"roles": tt.roles},
+					},
+				}, nil).
+				AnyTimes()
+			keycloak.EXPECT().
+				CheckAccessToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(true, nil).
+				AnyTimes()
+
+			handler := Handler{&service.Services{PermissionService: permissions}, keycloak, nil}
+
+			w := httptest.NewRecorder()
+			_, engine := gin.CreateTestContext(w)
+
+			engine.GET("/test", handler.permit(tt.action), func(ctx *gin.Context) {
+				ctx.Status(200)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Authorization", "Bearer test")
+
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
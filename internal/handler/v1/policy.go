@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"net/http"
+)
+
+func (h *Handler) initPolicyRoutes(api *gin.RouterGroup) {
+	crud := api.Group("/")
+	{
+		crud.GET("/:treeID/policy", h.permit(modules.ReadContent), h.readPolicy)
+		crud.PUT("/:treeID/policy", h.permit(modules.WriteContent), h.savePolicy)
+		crud.DELETE("/:treeID/policy", h.permit(modules.WriteContent), h.deletePolicy)
+	}
+}
+
+func (h *Handler) readPolicy(ctx *gin.Context) {
+	var input TreeInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	policy, err := h.services.PolicyService.Get(ctx, input.TreeID)
+	if errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, policy)
+	return
+}
+
+func (h *Handler) savePolicy(ctx *gin.Context) {
+	var input TreeInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	var policy dto.TreePolicy
+	if err := ctx.ShouldBind(&policy); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+	policy.TreeID = input.TreeID
+
+	saved, err := h.services.PolicyService.Save(ctx, policy)
+	if errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
+	var invalid *modules.ValidationError
+	if errors.As(err, &invalid) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error(), "violations": invalid.Violations})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, saved)
+	return
+}
+
+func (h *Handler) deletePolicy(ctx *gin.Context) {
+	var input TreeInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	deleted, err := h.services.PolicyService.Delete(ctx, input.TreeID)
+	if errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deleted)
+	return
+}
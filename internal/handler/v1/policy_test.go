@@ -0,0 +1,244 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
+	servicemocks "gitlab.com/a5805/ondeu/ondeu-back/internal/service/mocks"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_readPolicy(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockPolicyService)
+
+	tests := []struct {
+		name                 string
+		treeID               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. Invalid id.",
+			treeID:               "abc",
+			mockBehavior:         func(r *servicemocks.MockPolicyService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"strconv.ParseUint: parsing \"abc\": invalid syntax"}`,
+		},
+		{
+			name:   "Failed. Forbidden.",
+			treeID: "4",
+			mockBehavior: func(r *servicemocks.MockPolicyService) {
+				r.EXPECT().
+					Get(gomock.Any(), uint(4)).
+					Return(dto.TreePolicy{}, modules.ErrForbidden)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: fmt.Sprintf(`{"reason":"%s"}`, modules.ErrForbidden.Error()),
+		},
+		{
+			name:   "Success. No policy of its own.",
+			treeID: "4",
+			mockBehavior: func(r *servicemocks.MockPolicyService) {
+				r.EXPECT().
+					Get(gomock.Any(), uint(4)).
+					Return(dto.TreePolicy{TreeID: 4, Allow: []string{}, Deny: []string{}}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"treeID":4,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z","maxSize":0,"allow":[],"deny":[]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockPolicyService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{PolicyService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/api/v1/tree/:treeID/policy", handler.readPolicy)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tree/"+tt.treeID+"/policy", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_savePolicy(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockPolicyService)
+
+	createdData := time.Now()
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. Negative size.",
+			inputBody:            `{"maxSize":-1}`,
+			mockBehavior:         func(r *servicemocks.MockPolicyService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"Key: 'TreePolicy.MaxSize' Error:Field validation for 'MaxSize' failed on the 'min' tag"}`,
+		},
+		{
+			name:      "Failed. Invalid entry.",
+			inputBody: `{"maxSize":0,"allow":["*/pdf"]}`,
+			mockBehavior: func(r *servicemocks.MockPolicyService) {
+				r.EXPECT().
+					Save(gomock.Any(), dto.TreePolicy{TreeID: 4, Allow: []string{"*/pdf"}}).
+					Return(dto.TreePolicy{}, &modules.ValidationError{Violations: []modules.Violation{
+						{Field: "policy", Rule: "entry", Message: "invalid entry */pdf"},
+					}})
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"upload rejected: invalid entry */pdf","violations":[{"field":"policy","rule":"entry","message":"invalid entry */pdf"}]}`,
+		},
+		{
+			name:      "Failed. Not the owner.",
+			inputBody: `{"maxSize":1000}`,
+			mockBehavior: func(r *servicemocks.MockPolicyService) {
+				r.EXPECT().
+					Save(gomock.Any(), dto.TreePolicy{TreeID: 4, MaxSize: 1000}).
+					Return(dto.TreePolicy{}, modules.ErrForbidden)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: fmt.Sprintf(`{"reason":"%s"}`, modules.ErrForbidden.Error()),
+		},
+		{
+			name:      "Success.",
+			inputBody: `{"maxSize":1000,"allow":["pdf","image/*"],"deny":[]}`,
+			mockBehavior: func(r *servicemocks.MockPolicyService) {
+				r.EXPECT().
+					Save(gomock.Any(), dto.TreePolicy{TreeID: 4, MaxSize: 1000, Allow: []string{"pdf", "image/*"}, Deny: []string{}}).
+					Return(dto.TreePolicy{
+						TreeID:    4,
+						CreatedAt: createdData,
+						UpdatedAt: createdData,
+						MaxSize:   1000,
+						Allow:     []string{"pdf", "image/*"},
+						Deny:      []string{},
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(
+				`{"treeID":4,"createdAt":"%s","updatedAt":"%s","maxSize":1000,"allow":["pdf","image/*"],"deny":[]}`,
+				createdData.Format(time.RFC3339Nano),
+				createdData.Format(time.RFC3339Nano),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockPolicyService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{PolicyService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.PUT("/api/v1/tree/:treeID/policy", handler.savePolicy)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/tree/4/policy", strings.NewReader(tt.inputBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_deletePolicy(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockPolicyService)
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Failed. Not the owner.",
+			mockBehavior: func(r *servicemocks.MockPolicyService) {
+				r.EXPECT().
+					Delete(gomock.Any(), uint(4)).
+					Return(dto.TreePolicy{TreeID: 4}, modules.ErrForbidden)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: fmt.Sprintf(`{"reason":"%s"}`, modules.ErrForbidden.Error()),
+		},
+		{
+			name: "Success.",
+			mockBehavior: func(r *servicemocks.MockPolicyService) {
+				r.EXPECT().
+					Delete(gomock.Any(), uint(4)).
+					Return(dto.TreePolicy{TreeID: 4, MaxSize: 1000}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"treeID":4,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z","maxSize":1000,"allow":null,"deny":null}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockPolicyService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{PolicyService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.DELETE("/api/v1/tree/:treeID/policy", handler.deletePolicy)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/tree/4/policy", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
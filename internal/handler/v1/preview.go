@@ -0,0 +1,81 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/preview"
+	"net/http"
+	"time"
+)
+
+// previewMaxAge is how long a client may show a thumbnail before revalidating it
+const previewMaxAge = time.Hour
+
+type PreviewQuery struct {
+	Size string `form:"size" binding:"omitempty,oneof=small medium large"`
+}
+
+func (h *Handler) previewDocument(ctx *gin.Context) {
+	var input DocumentInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	var query PreviewQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	if query.Size == "" {
+		query.Size = preview.DefaultSize
+	}
+
+	// a thumbnail is always sent whole, only the conditional headers are honoured
+	download := downloadRequest(ctx)
+	download.Range = ""
+
+	document := dto.Document{ID: input.DocumentID, TreeID: input.TreeID, Download: download}
+
+	thumbnail, err := h.services.DocumentService.Preview(ctx, document, query.Size)
+	if errors.Is(err, modules.ErrNoPreview) {
+		ctx.JSON(http.StatusNotFound, gin.H{"reason": err.Error()})
+		return
+	}
+	if errors.Is(err, modules.ErrNotClean) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	if thumbnail.ResponseContent != nil {
+		defer thumbnail.ResponseContent.Close()
+	}
+
+	ctx.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", int(previewMaxAge.Seconds())))
+	if thumbnail.Download.ETag != "" {
+		ctx.Header("ETag", thumbnail.Download.ETag)
+	}
+	if !thumbnail.Download.LastModified.IsZero() {
+		ctx.Header("Last-Modified", thumbnail.Download.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if thumbnail.Download.NotModified {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	ctx.DataFromReader(http.StatusOK, thumbnail.Download.ContentLength, preview.ContentType, thumbnail.ResponseContent, nil)
+	return
+}
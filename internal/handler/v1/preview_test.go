@@ -0,0 +1,130 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
+	servicemocks "gitlab.com/a5805/ondeu/ondeu-back/internal/service/mocks"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler_previewDocument(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockDocumentService)
+
+	tests := []struct {
+		name                 string
+		query                string
+		headers              map[string]string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedHeaders      map[string]string
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. Unknown size",
+			query:                "?size=huge",
+			mockBehavior:         func(r *servicemocks.MockDocumentService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"Key: 'PreviewQuery.Size' Error:Field validation for 'Size' failed on the 'oneof' tag"}`,
+		},
+		{
+			name:  "Failed. Not previewed yet.",
+			query: "",
+			mockBehavior: func(r *servicemocks.MockDocumentService) {
+				r.EXPECT().
+					Preview(gomock.Any(), dto.Document{ID: 1, TreeID: 2}, "medium").
+					Return(dto.Document{}, modules.ErrNoPreview)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"reason":"no preview of the document"}`,
+		},
+		{
+			name:  "Failed. Not scanned clean.",
+			query: "",
+			mockBehavior: func(r *servicemocks.MockDocumentService) {
+				r.EXPECT().
+					Preview(gomock.Any(), dto.Document{ID: 1, TreeID: 2}, "medium").
+					Return(dto.Document{}, modules.ErrNotClean)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"reason":"the document is not scanned clean"}`,
+		},
+		{
+			name:    "Success. Range is ignored.",
+			query:   "?size=small",
+			headers: map[string]string{"Range": "bytes=0-1"},
+			mockBehavior: func(r *servicemocks.MockDocumentService) {
+				r.EXPECT().
+					Preview(gomock.Any(), dto.Document{ID: 1, TreeID: 2}, "small").
+					Return(dto.Document{
+						ResponseContent: io.NopCloser(strings.NewReader("webp")),
+						Download:        dto.Download{ContentLength: 4, ETag: `"abc"`},
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedHeaders: map[string]string{
+				"Content-Type":  "image/webp",
+				"Cache-Control": "private, max-age=3600",
+				"ETag":          `"abc"`,
+			},
+			expectedResponseBody: "webp",
+		},
+		{
+			name:    "Success. Not modified.",
+			query:   "?size=large",
+			headers: map[string]string{"If-None-Match": `"abc"`},
+			mockBehavior: func(r *servicemocks.MockDocumentService) {
+				r.EXPECT().
+					Preview(gomock.Any(), dto.Document{ID: 1, TreeID: 2, Download: dto.Download{IfNoneMatch: `"abc"`}}, "large").
+					Return(dto.Document{Download: dto.Download{NotModified: true, ETag: `"abc"`}}, nil)
+			},
+			expectedStatusCode: 304,
+			expectedHeaders: map[string]string{
+				"ETag": `"abc"`,
+			},
+			expectedResponseBody: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockDocumentService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{DocumentService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/api/v1/tree/:treeID/document/:docID/preview", handler.previewDocument)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/tree/%d/document/%d/preview%s", 2, 1, tt.query), nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			for key, value := range tt.expectedHeaders {
+				assert.Equal(t, value, w.Header().Get(key))
+			}
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
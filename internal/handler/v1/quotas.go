@@ -0,0 +1,115 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"net/http"
+)
+
+func (h *Handler) initMeRoutes(api *gin.RouterGroup) {
+	crud := api.Group("/")
+	{
+		crud.GET("/usage", h.permit(modules.ReadContent), h.readUsage)
+	}
+}
+
+func (h *Handler) initQuotaRoutes(api *gin.RouterGroup) {
+	crud := api.Group("/")
+	{
+		crud.GET("/", h.permit(modules.ManageQuotas), h.listQuotas)
+		crud.PUT("/", h.permit(modules.ManageQuotas), h.saveQuota)
+		crud.DELETE("/:quotaID", h.permit(modules.ManageQuotas), h.deleteQuota)
+		crud.GET("/usage/:userID", h.permit(modules.ManageQuotas), h.readUserUsage)
+	}
+}
+
+type QuotaInput struct {
+	QuotaID uint `uri:"quotaID" binding:"required"`
+}
+
+type UsageInput struct {
+	UserID string `uri:"userID" binding:"required"`
+}
+
+func (h *Handler) readUsage(ctx *gin.Context) {
+	usage, err := h.services.QuotaService.Usage(ctx)
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, usage)
+	return
+}
+
+func (h *Handler) readUserUsage(ctx *gin.Context) {
+	var input UsageInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	usage, err := h.services.QuotaService.UserUsage(ctx, input.UserID)
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, usage)
+	return
+}
+
+func (h *Handler) listQuotas(ctx *gin.Context) {
+	quotas, err := h.services.QuotaService.List(ctx)
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, quotas)
+	return
+}
+
+func (h *Handler) saveQuota(ctx *gin.Context) {
+	var quota dto.Quota
+	if err := ctx.ShouldBind(&quota); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	saved, err := h.services.QuotaService.Save(ctx, quota)
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, saved)
+	return
+}
+
+func (h *Handler) deleteQuota(ctx *gin.Context) {
+	var input QuotaInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	deleted, err := h.services.QuotaService.Delete(ctx, dto.Quota{ID: input.QuotaID})
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deleted)
+	return
+}
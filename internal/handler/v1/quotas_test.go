@@ -0,0 +1,235 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
+	servicemocks "gitlab.com/a5805/ondeu/ondeu-back/internal/service/mocks"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_readUsage(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockQuotaService)
+
+	maxBytes := int64(1000)
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Failed. Unauthorized.",
+			mockBehavior: func(r *servicemocks.MockQuotaService) {
+				r.EXPECT().
+					Usage(gomock.Any()).
+					Return(dto.Usage{}, fmt.Errorf("unauthorized action is prohibited"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"unauthorized action is prohibited"}`,
+		},
+		{
+			name: "Success. Unlimited.",
+			mockBehavior: func(r *servicemocks.MockQuotaService) {
+				r.EXPECT().
+					Usage(gomock.Any()).
+					Return(dto.Usage{UserID: "user", Bytes: 10}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"userID":"user","bytes":10,"maxBytes":null}`,
+		},
+		{
+			name: "Success. Limited by role and organization.",
+			mockBehavior: func(r *servicemocks.MockQuotaService) {
+				r.EXPECT().
+					Usage(gomock.Any()).
+					Return(dto.Usage{
+						UserID:            "user",
+						Bytes:             10,
+						MaxBytes:          &maxBytes,
+						Organization:      42,
+						OrganizationBytes: 500,
+						OrganizationMax:   &maxBytes,
+					}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"userID":"user","bytes":10,"maxBytes":1000,"organization":42,"organizationBytes":500,"organizationMaxBytes":1000}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockQuotaService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{QuotaService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/api/v1/me/usage", handler.readUsage)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/me/usage", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_saveQuota(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockQuotaService)
+
+	createdData := time.Now()
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. Unknown subject.",
+			inputBody:            `{"subject":"group","subjectID":"5","maxBytes":1000}`,
+			mockBehavior:         func(r *servicemocks.MockQuotaService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"Key: 'Quota.Subject' Error:Field validation for 'Subject' failed on the 'oneof' tag"}`,
+		},
+		{
+			name:                 "Failed. Validation. Negative limit.",
+			inputBody:            `{"subject":"role","subjectID":"student","maxBytes":-1}`,
+			mockBehavior:         func(r *servicemocks.MockQuotaService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"Key: 'Quota.MaxBytes' Error:Field validation for 'MaxBytes' failed on the 'min' tag"}`,
+		},
+		{
+			name:      "Success.",
+			inputBody: `{"subject":"role","subjectID":"student","maxBytes":1000}`,
+			mockBehavior: func(r *servicemocks.MockQuotaService) {
+				r.EXPECT().
+					Save(gomock.Any(), dto.Quota{Subject: "role", SubjectID: "student", MaxBytes: 1000}).
+					Return(dto.Quota{
+						ID:        3,
+						CreatedAt: createdData,
+						UpdatedAt: createdData,
+						Subject:   "role",
+						SubjectID: "student",
+						MaxBytes:  1000,
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(
+				`{"id":3,"createdAt":"%s","updatedAt":"%s","subject":"role","subjectID":"student","maxBytes":1000}`,
+				createdData.Format(time.RFC3339Nano),
+				createdData.Format(time.RFC3339Nano),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockQuotaService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{QuotaService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.PUT("/api/v1/quotas/", handler.saveQuota)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/quotas/", strings.NewReader(tt.inputBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_deleteQuota(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockQuotaService)
+
+	tests := []struct {
+		name                 string
+		quotaID              string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. Invalid id.",
+			quotaID:              "abc",
+			mockBehavior:         func(r *servicemocks.MockQuotaService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"strconv.ParseUint: parsing \"abc\": invalid syntax"}`,
+		},
+		{
+			name:    "Failed. Not found.",
+			quotaID: "7",
+			mockBehavior: func(r *servicemocks.MockQuotaService) {
+				r.EXPECT().
+					Delete(gomock.Any(), dto.Quota{ID: 7}).
+					Return(dto.Quota{ID: 7}, gorm.ErrRecordNotFound)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"record not found"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockQuotaService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{QuotaService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.DELETE("/api/v1/quotas/:quotaID", handler.deleteQuota)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/quotas/"+tt.quotaID, nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
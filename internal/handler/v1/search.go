@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"net/http"
+)
+
+func (h *Handler) initSearchRoutes(api *gin.RouterGroup) {
+	crud := api.Group("/")
+	{
+		crud.GET("/", h.permit(modules.ReadContent), h.searchDocuments)
+	}
+}
+
+func (h *Handler) searchDocuments(ctx *gin.Context) {
+	var search dto.DocumentSearch
+	if err := ctx.ShouldBindQuery(&search); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	page, err := h.services.DocumentService.Search(ctx, search)
+	if errors.Is(err, modules.ErrInvalidCursor) {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, page)
+	return
+}
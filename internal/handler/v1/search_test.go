@@ -0,0 +1,136 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
+	servicemocks "gitlab.com/a5805/ondeu/ondeu-back/internal/service/mocks"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler_searchDocuments(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockDocumentService)
+
+	createdData := time.Now()
+	from := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	minSize := int64(1024)
+	true_ := true
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. Unknown sort field",
+			query:                "?sort=user_id",
+			mockBehavior:         func(r *servicemocks.MockDocumentService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"Key: 'DocumentSearch.Sort' Error:Field validation for 'Sort' failed on the 'oneof' tag"}`,
+		},
+		{
+			name:                 "Failed. Validation. Limit too large",
+			query:                "?limit=1000",
+			mockBehavior:         func(r *servicemocks.MockDocumentService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"Key: 'DocumentSearch.Limit' Error:Field validation for 'Limit' failed on the 'max' tag"}`,
+		},
+		{
+			name:  "Failed. Invalid cursor.",
+			query: "?cursor=broken",
+			mockBehavior: func(r *servicemocks.MockDocumentService) {
+				r.EXPECT().
+					Search(gomock.Any(), dto.DocumentSearch{Cursor: "broken"}).
+					Return(dto.DocumentPage{}, modules.ErrInvalidCursor)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"invalid page cursor"}`,
+		},
+		{
+			name:  "Success.",
+			query: "?name=essay&extension=pdf&minSize=1024&createdFrom=2024-09-01T00:00:00Z&template=true&treeID=3&sort=-size&limit=1",
+			mockBehavior: func(r *servicemocks.MockDocumentService) {
+				r.EXPECT().
+					Search(gomock.Any(), dto.DocumentSearch{
+						Name:        "essay",
+						Extension:   "pdf",
+						MinSize:     &minSize,
+						CreatedFrom: &from,
+						Template:    &true_,
+						TreeID:      3,
+						Sort:        "-size",
+						Limit:       1,
+					}).
+					Return(dto.DocumentPage{
+						Documents: []dto.Document{
+							{ID: 4, CreatedAt: createdData, UpdatedAt: createdData, Name: "essay", Extension: ".pdf", Size: 2048, Template: &true_},
+						},
+						Next: "next-page",
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(
+				`{"documents":[{"id":4,"createdAt":"%s","updatedAt":"%s","name":"essay","extension":".pdf","size":2048,"path":"00000000-0000-0000-0000-000000000000","template":true}],"next":"next-page"}`,
+				createdData.Format(time.RFC3339Nano),
+				createdData.Format(time.RFC3339Nano),
+			),
+		},
+		{
+			name:  "Success. Full-text query.",
+			query: "?q=climate+essay&sort=-rank",
+			mockBehavior: func(r *servicemocks.MockDocumentService) {
+				r.EXPECT().
+					Search(gomock.Any(), dto.DocumentSearch{Query: "climate essay", Sort: "-rank"}).
+					Return(dto.DocumentPage{
+						Documents: []dto.Document{
+							{ID: 4, CreatedAt: createdData, UpdatedAt: createdData, Name: "essay", Template: &true_,
+								Snippet: "a <mark>climate</mark> <mark>essay</mark>", Rank: 0.5},
+						},
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(
+				`{"documents":[{"id":4,"createdAt":"%s","updatedAt":"%s","name":"essay","path":"00000000-0000-0000-0000-000000000000","template":true,"snippet":"a \u003cmark\u003eclimate\u003c/mark\u003e \u003cmark\u003eessay\u003c/mark\u003e","rank":0.5}]}`,
+				createdData.Format(time.RFC3339Nano),
+				createdData.Format(time.RFC3339Nano),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockDocumentService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{DocumentService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/api/v1/search/", handler.searchDocuments)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/search/"+tt.query, nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
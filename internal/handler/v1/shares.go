@@ -0,0 +1,268 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"io"
+	"net/http"
+)
+
+// sharePasswordHeader carries the password of a protected share link, a form field works as well
+const sharePasswordHeader = "X-Share-Password"
+
+func (h *Handler) initShareRoutes(api *gin.RouterGroup) {
+	crud := api.Group("/")
+	{
+		crud.GET("/", h.permit(modules.ShareContent), h.listShares)
+		crud.DELETE("/:shareID", h.permit(modules.ShareContent), h.revokeShare)
+		crud.GET("/:shareID/accesses", h.permit(modules.ShareContent), h.listShareAccesses)
+	}
+}
+
+func (h *Handler) initPublicShareRoutes(api *gin.RouterGroup) {
+	public := api.Group("/")
+	{
+		public.GET("/:token", h.openShare)
+		public.POST("/:token", h.openShare)
+		public.GET("/:token/document/:docID", h.openShare)
+		public.POST("/:token/document/:docID", h.openShare)
+		public.GET("/:token/zip", h.downloadShare)
+		public.POST("/:token/zip", h.downloadShare)
+	}
+}
+
+type ShareInput struct {
+	ShareID uint `uri:"shareID" binding:"required"`
+}
+
+type TokenInput struct {
+	Token      string `uri:"token" binding:"required"`
+	DocumentID uint   `uri:"docID"`
+}
+
+func (h *Handler) shareDocument(ctx *gin.Context) {
+	var input DocumentInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	var options dto.ShareOptions
+	if err := ctx.ShouldBind(&options); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	document := dto.Document{ID: input.DocumentID, TreeID: input.TreeID}
+
+	share, err := h.services.ShareService.Create(ctx, document, options)
+	if errors.Is(err, modules.ErrShareLifetime) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+	if errors.Is(err, modules.ErrNotClean) || errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, share)
+	return
+}
+
+func (h *Handler) shareTree(ctx *gin.Context) {
+	var input TreeInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	var options dto.ShareOptions
+	if err := ctx.ShouldBind(&options); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	share, err := h.services.ShareService.CreateTree(ctx, dto.Tree{ID: input.TreeID}, options)
+	if errors.Is(err, modules.ErrShareLifetime) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+	if errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, share)
+	return
+}
+
+func (h *Handler) listShares(ctx *gin.Context) {
+	shares, err := h.services.ShareService.List(ctx)
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, shares)
+	return
+}
+
+func (h *Handler) revokeShare(ctx *gin.Context) {
+	var input ShareInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	share, err := h.services.ShareService.Revoke(ctx, input.ShareID)
+	if errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, share)
+	return
+}
+
+func (h *Handler) listShareAccesses(ctx *gin.Context) {
+	var input ShareInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	accesses, err := h.services.ShareService.Accesses(ctx, input.ShareID)
+	if errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, accesses)
+	return
+}
+
+// openShare streams the document of a share link to anyone holding its token,
+// a folder share shows its structure or streams a document in it
+func (h *Handler) openShare(ctx *gin.Context) {
+	request, ok := shareRequest(ctx)
+	if !ok {
+		return
+	}
+
+	shared, err := h.services.ShareService.Open(ctx, request)
+	if !shareAllowed(ctx, err) {
+		return
+	}
+
+	// the structure is only returned for a folder share
+	if shared.Trees != nil {
+		ctx.JSON(http.StatusOK, shared.Trees)
+		return
+	}
+
+	document := shared.Document
+	sendContent(ctx, document.Name+document.Extension, document.Type, document.ResponseContent, document.Download)
+	return
+}
+
+// downloadShare streams the folder of a share link as a ZIP
+func (h *Handler) downloadShare(ctx *gin.Context) {
+	request, ok := shareRequest(ctx)
+	if !ok {
+		return
+	}
+
+	folder, err := h.services.ShareService.Archive(ctx, request)
+	if !shareAllowed(ctx, err) {
+		return
+	}
+
+	sendArchive(ctx, folder.Name, func(w io.Writer) error {
+		return h.services.ShareService.WriteArchive(ctx, folder, w)
+	})
+	return
+}
+
+// shareRequest reads an attempt to open a share link, the request is answered when false is returned
+func shareRequest(ctx *gin.Context) (dto.ShareRequest, bool) {
+	var input TokenInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return dto.ShareRequest{}, false
+	}
+
+	password := ctx.GetHeader(sharePasswordHeader)
+	if password == "" {
+		password = ctx.PostForm("password")
+	}
+
+	return dto.ShareRequest{
+		Token:      input.Token,
+		DocumentID: input.DocumentID,
+		Password:   password,
+		IP:         ctx.ClientIP(),
+		UserAgent:  ctx.Request.UserAgent(),
+		Download:   downloadRequest(ctx),
+	}, true
+}
+
+// shareAllowed answers the refusals of a share link, the request is answered when false is returned
+func shareAllowed(ctx *gin.Context, err error) bool {
+	if errors.Is(err, modules.ErrNoShare) {
+		ctx.JSON(http.StatusNotFound, gin.H{"reason": err.Error()})
+		return false
+	}
+	if errors.Is(err, modules.ErrShareGone) {
+		ctx.JSON(http.StatusGone, gin.H{"reason": err.Error()})
+		return false
+	}
+	if errors.Is(err, modules.ErrSharePassword) {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"reason": err.Error()})
+		return false
+	}
+	if errors.Is(err, modules.ErrNotClean) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return false
+	}
+	if errors.Is(err, modules.ErrInvalidRange) {
+		ctx.JSON(http.StatusRequestedRangeNotSatisfiable, gin.H{"reason": err.Error()})
+		return false
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return false
+	}
+	return true
+}
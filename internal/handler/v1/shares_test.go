@@ -0,0 +1,581 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
+	servicemocks "gitlab.com/a5805/ondeu/ondeu-back/internal/service/mocks"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_shareDocument(t *testing.T) {
+	type mockBehavior func(r *servicemocks.MockShareService)
+
+	created := time.Now()
+	expires := created.Add(time.Hour)
+	documentID := uint(123)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. Negative lifetime.",
+			inputBody:            `{"expiresIn":-1}`,
+			mockBehavior:         func(r *servicemocks.MockShareService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"Key: 'ShareOptions.ExpiresIn' Error:Field validation for 'ExpiresIn' failed on the 'min' tag"}`,
+		},
+		{
+			name:      "Failed. Lifetime over the limit.",
+			inputBody: `{"expiresIn":99999999}`,
+			mockBehavior: func(r *servicemocks.MockShareService) {
+				r.EXPECT().
+					Create(gomock.Any(), dto.Document{ID: 123, TreeID: 1}, dto.ShareOptions{ExpiresIn: 99999999}).
+					Return(dto.Share{}, modules.ErrShareLifetime)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"the share lifetime exceeds the limit"}`,
+		},
+		{
+			name:      "Failed. Infected.",
+			inputBody: `{}`,
+			mockBehavior: func(r *servicemocks.MockShareService) {
+				r.EXPECT().
+					Create(gomock.Any(), dto.Document{ID: 123, TreeID: 1}, dto.ShareOptions{}).
+					Return(dto.Share{}, modules.ErrNotClean)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"reason":"the document is not scanned clean"}`,
+		},
+		{
+			name:      "Failed. Database. Duplicate Key",
+			inputBody: `{}`,
+			mockBehavior: func(r *servicemocks.MockShareService) {
+				r.EXPECT().
+					Create(gomock.Any(), dto.Document{ID: 123, TreeID: 1}, dto.ShareOptions{}).
+					Return(dto.Share{}, gorm.ErrDuplicatedKey)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"duplicated key not allowed"}`,
+		},
+		{
+			name:      "Success. Protected and limited.",
+			inputBody: `{"expiresIn":3600,"password":"secret","maxDownloads":3}`,
+			mockBehavior: func(r *servicemocks.MockShareService) {
+				r.EXPECT().
+					Create(gomock.Any(), dto.Document{ID: 123, TreeID: 1}, dto.ShareOptions{ExpiresIn: 3600, Password: "secret", MaxDownloads: 3}).
+					Return(dto.Share{
+						ID:           5,
+						Token:        "token",
+						Kind:         "document",
+						DocumentID:   &documentID,
+						TreeID:       1,
+						UserID:       "owner",
+						CreatedBy:    "owner",
+						CreatedAt:    created,
+						ExpiresAt:    expires,
+						MaxDownloads: 3,
+						PasswordHash: "hash",
+						Protected:    true,
+						Link:         "/s/token",
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(
+				`{"id":5,"token":"token","kind":"document","documentID":123,"treeID":1,"userID":"owner","createdBy":"owner","createdAt":"%s","expiresAt":"%s","maxDownloads":3,"downloads":0,"protected":true,"link":"/s/token"}`,
+				created.Format(time.RFC3339Nano),
+				expires.Format(time.RFC3339Nano),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockShareService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{ShareService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/api/v1/tree/:treeID/document/:docID/share", handler.shareDocument)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/tree/1/document/123/share", strings.NewReader(tt.inputBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_shareTree(t *testing.T) {
+	type mockBehavior func(r *servicemocks.MockShareService)
+
+	created := time.Now()
+	expires := created.Add(24 * time.Hour)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Failed. Not an editor.",
+			inputBody: `{}`,
+			mockBehavior: func(r *servicemocks.MockShareService) {
+				r.EXPECT().
+					CreateTree(gomock.Any(), dto.Tree{ID: 2}, dto.ShareOptions{}).
+					Return(dto.Share{}, modules.ErrForbidden)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: fmt.Sprintf(`{"reason":"%s"}`, modules.ErrForbidden.Error()),
+		},
+		{
+			name:      "Success.",
+			inputBody: `{"expiresIn":86400}`,
+			mockBehavior: func(r *servicemocks.MockShareService) {
+				r.EXPECT().
+					CreateTree(gomock.Any(), dto.Tree{ID: 2}, dto.ShareOptions{ExpiresIn: 86400}).
+					Return(dto.Share{
+						ID:        6,
+						Token:     "token",
+						Kind:      "tree",
+						TreeID:    2,
+						UserID:    "manager",
+						CreatedBy: "manager",
+						CreatedAt: created,
+						ExpiresAt: expires,
+						Link:      "/s/token",
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(
+				`{"id":6,"token":"token","kind":"tree","treeID":2,"userID":"manager","createdBy":"manager","createdAt":"%s","expiresAt":"%s","maxDownloads":0,"downloads":0,"protected":false,"link":"/s/token"}`,
+				created.Format(time.RFC3339Nano),
+				expires.Format(time.RFC3339Nano),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockShareService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{ShareService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/api/v1/tree/:treeID/share", handler.shareTree)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/tree/2/share", strings.NewReader(tt.inputBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_revokeShare(t *testing.T) {
+	type mockBehavior func(r *servicemocks.MockShareService)
+
+	revoked := time.Now()
+
+	tests := []struct {
+		name                 string
+		shareID              string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. Invalid id.",
+			shareID:              "abc",
+			mockBehavior:         func(r *servicemocks.MockShareService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"strconv.ParseUint: parsing \"abc\": invalid syntax"}`,
+		},
+		{
+			name:    "Failed. Share of another user.",
+			shareID: "5",
+			mockBehavior: func(r *servicemocks.MockShareService) {
+				r.EXPECT().
+					Revoke(gomock.Any(), uint(5)).
+					Return(dto.Share{}, modules.ErrForbidden)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: fmt.Sprintf(`{"reason":"%s"}`, modules.ErrForbidden.Error()),
+		},
+		{
+			name:    "Success.",
+			shareID: "5",
+			mockBehavior: func(r *servicemocks.MockShareService) {
+				r.EXPECT().
+					Revoke(gomock.Any(), uint(5)).
+					Return(dto.Share{ID: 5, Token: "token", Kind: "tree", TreeID: 2, RevokedAt: &revoked, Link: "/s/token"}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(
+				`{"id":5,"token":"token","kind":"tree","treeID":2,"userID":"","createdBy":"","createdAt":"0001-01-01T00:00:00Z","expiresAt":"0001-01-01T00:00:00Z","maxDownloads":0,"downloads":0,"protected":false,"revokedAt":"%s","link":"/s/token"}`,
+				revoked.Format(time.RFC3339Nano),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockShareService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{ShareService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.DELETE("/api/v1/shares/:shareID", handler.revokeShare)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/shares/"+tt.shareID, nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_listShareAccesses(t *testing.T) {
+	type mockBehavior func(r *servicemocks.MockShareService)
+
+	accessed := time.Now()
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Failed. Share of another user.",
+			mockBehavior: func(r *servicemocks.MockShareService) {
+				r.EXPECT().
+					Accesses(gomock.Any(), uint(5)).
+					Return(nil, modules.ErrForbidden)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: fmt.Sprintf(`{"reason":"%s"}`, modules.ErrForbidden.Error()),
+		},
+		{
+			name: "Success.",
+			mockBehavior: func(r *servicemocks.MockShareService) {
+				r.EXPECT().
+					Accesses(gomock.Any(), uint(5)).
+					Return([]dto.ShareAccess{
+						{ID: 2, ShareID: 5, CreatedAt: accessed, IP: "10.0.0.2", UserAgent: "curl/8.0", Outcome: "password"},
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(
+				`[{"id":2,"shareID":5,"createdAt":"%s","ip":"10.0.0.2","userAgent":"curl/8.0","outcome":"password"}]`,
+				accessed.Format(time.RFC3339Nano),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockShareService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{ShareService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/api/v1/shares/:shareID/accesses", handler.listShareAccesses)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/shares/5/accesses", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_openShare(t *testing.T) {
+	type mockBehavior func(r *servicemocks.MockShareService)
+
+	tests := []struct {
+		name                 string
+		method               string
+		path                 string
+		headers              map[string]string
+		body                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Failed. Unknown token.",
+			method: http.MethodGet,
+			mockBehavior: func(r *servicemocks.MockShareService) {
+				r.EXPECT().
+					Open(gomock.Any(), gomock.Any()).
+					Return(dto.Shared{}, modules.ErrNoShare)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"reason":"the share link does not exist"}`,
+		},
+		{
+			name:   "Failed. Expired.",
+			method: http.MethodGet,
+			mockBehavior: func(r *servicemocks.MockShareService) {
+				r.EXPECT().
+					Open(gomock.Any(), gomock.Any()).
+					Return(dto.Shared{}, modules.ErrShareGone)
+			},
+			expectedStatusCode:   410,
+			expectedResponseBody: `{"reason":"the share link is expired, revoked or used up"}`,
+		},
+		{
+			name:    "Failed. Wrong password.",
+			method:  http.MethodGet,
+			headers: map[string]string{"X-Share-Password": "wrong"},
+			mockBehavior: func(r *servicemocks.MockShareService) {
+				r.EXPECT().
+					Open(gomock.Any(), dto.ShareRequest{Token: "token", Password: "wrong", IP: "192.0.2.1"}).
+					Return(dto.Shared{}, modules.ErrSharePassword)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"reason":"the share link requires a valid password"}`,
+		},
+		{
+			name:    "Success. Password in a form.",
+			method:  http.MethodPost,
+			headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			body:    "password=secret",
+			mockBehavior: func(r *servicemocks.MockShareService) {
+				r.EXPECT().
+					Open(gomock.Any(), dto.ShareRequest{Token: "token", Password: "secret", IP: "192.0.2.1"}).
+					Return(dto.Shared{Document: dto.Document{
+						Name:            "report",
+						Extension:       ".txt",
+						Type:            "text/plain",
+						ResponseContent: io.NopCloser(strings.NewReader("hello")),
+						Download:        dto.Download{ContentLength: 5},
+					}}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "hello",
+		},
+		{
+			name:   "Success. Folder structure.",
+			method: http.MethodGet,
+			mockBehavior: func(r *servicemocks.MockShareService) {
+				r.EXPECT().
+					Open(gomock.Any(), dto.ShareRequest{Token: "token", IP: "192.0.2.1"}).
+					Return(dto.Shared{Trees: []dto.Tree{
+						{ID: 2, Name: "Reading list", Role: "manager", Documents: []dto.Document{{ID: 10, Name: "syllabus", Extension: ".pdf"}}},
+						{ID: 3, ParentID: 2, Name: "Week 1", Role: "manager"},
+					}}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `[{"id":2,"parentID":0,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z","name":"Reading list","role":"manager","template":null,"group":null,"documents":[{"id":10,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z","name":"syllabus","extension":".pdf","path":"00000000-0000-0000-0000-000000000000","template":null}]},{"id":3,"parentID":2,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z","name":"Week 1","role":"manager","template":null,"group":null,"documents":null}]`,
+		},
+		{
+			name:   "Success. Document of a folder.",
+			method: http.MethodGet,
+			path:   "/s/token/document/10",
+			mockBehavior: func(r *servicemocks.MockShareService) {
+				r.EXPECT().
+					Open(gomock.Any(), dto.ShareRequest{Token: "token", DocumentID: 10, IP: "192.0.2.1"}).
+					Return(dto.Shared{Document: dto.Document{
+						Name:            "syllabus",
+						Extension:       ".pdf",
+						Type:            "application/pdf",
+						ResponseContent: io.NopCloser(strings.NewReader("%PDF")),
+						Download:        dto.Download{ContentLength: 4},
+					}}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "%PDF",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockShareService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{ShareService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/s/:token", handler.openShare)
+			r.POST("/s/:token", handler.openShare)
+			r.GET("/s/:token/document/:docID", handler.openShare)
+
+			// Create Request
+			path := tt.path
+			if path == "" {
+				path = "/s/token"
+			}
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, path, strings.NewReader(tt.body))
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_downloadShare(t *testing.T) {
+	type mockBehavior func(r *servicemocks.MockShareService)
+
+	folder := dto.Archive{Name: "Reading list.zip", Entries: []dto.ArchiveEntry{{Path: "Week 1/"}}}
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedHeaders      map[string]string
+		expectedResponseBody string
+	}{
+		{
+			name: "Failed. Not a folder.",
+			mockBehavior: func(r *servicemocks.MockShareService) {
+				r.EXPECT().
+					Archive(gomock.Any(), gomock.Any()).
+					Return(dto.Archive{}, modules.ErrNoShare)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"reason":"the share link does not exist"}`,
+		},
+		{
+			name: "Failed. Downloads used up.",
+			mockBehavior: func(r *servicemocks.MockShareService) {
+				r.EXPECT().
+					Archive(gomock.Any(), gomock.Any()).
+					Return(dto.Archive{}, modules.ErrShareGone)
+			},
+			expectedStatusCode:   410,
+			expectedResponseBody: `{"reason":"the share link is expired, revoked or used up"}`,
+		},
+		{
+			name: "Success.",
+			mockBehavior: func(r *servicemocks.MockShareService) {
+				r.EXPECT().
+					Archive(gomock.Any(), dto.ShareRequest{Token: "token", IP: "192.0.2.1"}).
+					Return(folder, nil)
+				r.EXPECT().
+					WriteArchive(gomock.Any(), folder, gomock.Any()).
+					DoAndReturn(func(_ interface{}, _ dto.Archive, w io.Writer) error {
+						_, err := w.Write([]byte("PK"))
+						return err
+					})
+			},
+			expectedStatusCode: 200,
+			expectedHeaders: map[string]string{
+				"Content-Type":        "application/zip",
+				"Content-Disposition": `attachment; filename="Reading list.zip"`,
+			},
+			expectedResponseBody: "PK",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockShareService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{ShareService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/s/:token/zip", handler.downloadShare)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/s/token/zip", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			for key, value := range tt.expectedHeaders {
+				assert.Equal(t, value, w.Header().Get(key))
+			}
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
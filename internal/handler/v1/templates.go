@@ -0,0 +1,60 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"net/http"
+)
+
+func (h *Handler) initTemplateRoutes(api *gin.RouterGroup) {
+	crud := api.Group("/")
+	{
+		crud.GET("/", h.permit(modules.UseTemplate), h.listTemplates)
+		crud.POST("/:treeID/instantiate", h.permit(modules.UseTemplate), h.instantiateTemplate)
+	}
+}
+
+func (h *Handler) listTemplates(ctx *gin.Context) {
+	templates, err := h.services.TreeService.ListTemplates(ctx, ctx.Query("role"))
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, templates)
+	return
+}
+
+func (h *Handler) instantiateTemplate(ctx *gin.Context) {
+	var input TreeInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	var destination dto.Destination
+	if err := ctx.ShouldBind(&destination); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	instance, err := h.services.TreeService.Instantiate(ctx, dto.Tree{ID: input.TreeID}, destination.ParentID)
+	if errors.Is(err, modules.ErrQuota) {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"reason": err.Error()})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, instance)
+	return
+}
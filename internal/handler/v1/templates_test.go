@@ -0,0 +1,197 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
+	servicemocks "gitlab.com/a5805/ondeu/ondeu-back/internal/service/mocks"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_listTemplates(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockTreeService)
+
+	createdData := time.Now()
+	true_ := true
+	false_ := false
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Failed. Unauthorized.",
+			query: "",
+			mockBehavior: func(r *servicemocks.MockTreeService) {
+				r.EXPECT().
+					ListTemplates(gomock.Any(), "").
+					Return(nil, fmt.Errorf("unauthorized action is prohibited"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"unauthorized action is prohibited"}`,
+		},
+		{
+			name:  "Success. Filtered by role.",
+			query: "?role=bachelor",
+			mockBehavior: func(r *servicemocks.MockTreeService) {
+				r.EXPECT().
+					ListTemplates(gomock.Any(), "bachelor").
+					Return([]dto.Tree{
+						{
+							ID:        5,
+							CreatedAt: createdData,
+							UpdatedAt: createdData,
+							Name:      "Syllabus",
+							Role:      "bachelor",
+							Template:  &true_,
+							Group:     &false_,
+						},
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(
+				`[{"id":5,"parentID":0,"createdAt":"%s","updatedAt":"%s","name":"Syllabus","role":"bachelor","template":true,"group":false,"documents":null}]`,
+				createdData.Format(time.RFC3339Nano),
+				createdData.Format(time.RFC3339Nano),
+			),
+		},
+		{
+			name:  "Success. Empty.",
+			query: "?role=master",
+			mockBehavior: func(r *servicemocks.MockTreeService) {
+				r.EXPECT().
+					ListTemplates(gomock.Any(), "master").
+					Return([]dto.Tree{}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `[]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockTreeService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{TreeService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/api/v1/templates/", handler.listTemplates)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/templates/"+tt.query, nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_instantiateTemplate(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockTreeService)
+
+	createdData := time.Now()
+	false_ := false
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. Invalid body",
+			inputBody:            `{"parentID":-1}`,
+			mockBehavior:         func(r *servicemocks.MockTreeService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"json: cannot unmarshal number -1 into Go struct field Destination.parentID of type uint"}`,
+		},
+		{
+			name:      "Failed. Not a template of the role.",
+			inputBody: `{"parentID":2}`,
+			mockBehavior: func(r *servicemocks.MockTreeService) {
+				r.EXPECT().
+					Instantiate(gomock.Any(), dto.Tree{ID: 5}, uint(2)).
+					Return(dto.Tree{}, gorm.ErrRecordNotFound)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"record not found"}`,
+		},
+		{
+			name:      "Success.",
+			inputBody: `{"parentID":2}`,
+			mockBehavior: func(r *servicemocks.MockTreeService) {
+				r.EXPECT().
+					Instantiate(gomock.Any(), dto.Tree{ID: 5}, uint(2)).
+					Return(dto.Tree{
+						ID:        11,
+						ParentID:  2,
+						CreatedAt: createdData,
+						UpdatedAt: createdData,
+						Name:      "Syllabus",
+						Role:      "bachelor",
+						Template:  &false_,
+						Group:     &false_,
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(
+				`{"id":11,"parentID":2,"createdAt":"%s","updatedAt":"%s","name":"Syllabus","role":"bachelor","template":false,"group":false,"documents":null}`,
+				createdData.Format(time.RFC3339Nano),
+				createdData.Format(time.RFC3339Nano),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockTreeService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{TreeService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/api/v1/templates/:treeID/instantiate", handler.instantiateTemplate)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/templates/%d/instantiate", 5),
+				strings.NewReader(tt.inputBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
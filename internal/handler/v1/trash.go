@@ -0,0 +1,55 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"net/http"
+)
+
+func (h *Handler) initTrashRoutes(api *gin.RouterGroup) {
+	crud := api.Group("/")
+	{
+		crud.GET("/", h.permit(modules.ReadContent), h.listTrash)
+		crud.POST("/:kind/:id/restore", h.permit(modules.WriteContent), h.restoreTrash)
+	}
+}
+
+func (h *Handler) listTrash(ctx *gin.Context) {
+	items, err := h.services.TrashService.List(ctx)
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, items)
+	return
+}
+
+func (h *Handler) restoreTrash(ctx *gin.Context) {
+	var item dto.TrashItem
+	if err := ctx.ShouldBindUri(&item); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	restored, err := h.services.TrashService.Restore(ctx, item)
+	if errors.Is(err, modules.ErrNoRootTree) || errors.Is(err, modules.ErrMissingContent) {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusConflict, gin.H{"reason": err.Error()})
+		return
+	}
+
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, restored)
+	return
+}
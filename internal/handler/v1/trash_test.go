@@ -0,0 +1,190 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
+	servicemocks "gitlab.com/a5805/ondeu/ondeu-back/internal/service/mocks"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler_listTrash(t *testing.T) {
+	type mockBehavior func(r *servicemocks.MockTrashService)
+
+	deleted := time.Date(2023, time.March, 1, 10, 0, 0, 0, time.UTC)
+	purge := deleted.Add(30 * 24 * time.Hour)
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Failed. Unauthorized.",
+			mockBehavior: func(r *servicemocks.MockTrashService) {
+				r.EXPECT().
+					List(gomock.Any()).
+					Return(nil, fmt.Errorf("unauthorized action is prohibited"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"unauthorized action is prohibited"}`,
+		},
+		{
+			name: "Success.",
+			mockBehavior: func(r *servicemocks.MockTrashService) {
+				r.EXPECT().
+					List(gomock.Any()).
+					Return([]dto.TrashItem{
+						{ID: 3, Kind: modules.TrashTree, Name: "Semester 1", DeletedAt: &deleted, PurgeAt: &purge},
+						{ID: 7, Kind: modules.TrashDocument, Name: "essay.docx", ParentID: 2, DeletedAt: &deleted, PurgeAt: &purge},
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `[{"id":3,"kind":"tree","name":"Semester 1","deletedAt":"2023-03-01T10:00:00Z","purgeAt":"2023-03-31T10:00:00Z"},` +
+				`{"id":7,"kind":"document","name":"essay.docx","parentID":2,"deletedAt":"2023-03-01T10:00:00Z","purgeAt":"2023-03-31T10:00:00Z"}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockTrashService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{TrashService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/api/v1/trash/", handler.listTrash)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/trash/", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_restoreTrash(t *testing.T) {
+	type mockBehavior func(r *servicemocks.MockTrashService)
+
+	tests := []struct {
+		name                 string
+		kind                 string
+		id                   string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. Unknown kind",
+			kind:                 "group",
+			id:                   "3",
+			mockBehavior:         func(r *servicemocks.MockTrashService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"Key: 'TrashItem.Kind' Error:Field validation for 'Kind' failed on the 'oneof' tag"}`,
+		},
+		{
+			name:                 "Failed. Validation. Invalid id",
+			kind:                 "tree",
+			id:                   "abc",
+			mockBehavior:         func(r *servicemocks.MockTrashService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"strconv.ParseUint: parsing \"abc\": invalid syntax"}`,
+		},
+		{
+			name: "Failed. Database. Record Not Found",
+			kind: "tree",
+			id:   "3",
+			mockBehavior: func(r *servicemocks.MockTrashService) {
+				r.EXPECT().
+					Restore(gomock.Any(), dto.TrashItem{ID: 3, Kind: modules.TrashTree}).
+					Return(dto.TrashItem{}, gorm.ErrRecordNotFound)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"record not found"}`,
+		},
+		{
+			name: "Failed. No root tree.",
+			kind: "document",
+			id:   "7",
+			mockBehavior: func(r *servicemocks.MockTrashService) {
+				r.EXPECT().
+					Restore(gomock.Any(), dto.TrashItem{ID: 7, Kind: modules.TrashDocument}).
+					Return(dto.TrashItem{}, modules.ErrNoRootTree)
+			},
+			expectedStatusCode:   409,
+			expectedResponseBody: `{"reason":"no root folder to restore into"}`,
+		},
+		{
+			name: "Failed. Missing content.",
+			kind: "document",
+			id:   "7",
+			mockBehavior: func(r *servicemocks.MockTrashService) {
+				r.EXPECT().
+					Restore(gomock.Any(), dto.TrashItem{ID: 7, Kind: modules.TrashDocument}).
+					Return(dto.TrashItem{}, modules.ErrMissingContent)
+			},
+			expectedStatusCode:   409,
+			expectedResponseBody: `{"reason":"the content of the document is missing"}`,
+		},
+		{
+			name: "Success.",
+			kind: "document",
+			id:   "7",
+			mockBehavior: func(r *servicemocks.MockTrashService) {
+				r.EXPECT().
+					Restore(gomock.Any(), dto.TrashItem{ID: 7, Kind: modules.TrashDocument}).
+					Return(dto.TrashItem{ID: 7, Kind: modules.TrashDocument, Name: "essay.docx", ParentID: 2}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":7,"kind":"document","name":"essay.docx","parentID":2}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockTrashService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{TrashService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/api/v1/trash/:kind/:id/restore", handler.restoreTrash)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/trash/%s/%s/restore", tt.kind, tt.id), nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -1,21 +1,29 @@
 package v1
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/utils"
+	"io"
 	"net/http"
 )
 
 func (h *Handler) initTreeRoutes(api *gin.RouterGroup) {
 	crud := api.Group("/")
 	{
-		crud.POST("/", authorize(h.keycloak, []string{"admin", "manager", "student"}), h.createTree)
-		crud.GET("/:treeID", authorize(h.keycloak, []string{"admin", "manager", "student"}), h.getTree)
-		crud.GET("/:treeID/list", authorize(h.keycloak, []string{"admin", "manager", "student"}), h.listTree)
-		crud.PUT("/:treeID", authorize(h.keycloak, []string{"admin", "manager", "student"}), h.updateTree)
-		crud.DELETE("/:treeID", authorize(h.keycloak, []string{"admin", "manager", "student"}), h.deleteTree)
+		crud.POST("/", h.permit(modules.WriteContent), h.createTree)
+		crud.GET("/:treeID", h.permit(modules.ReadContent), h.getTree)
+		crud.GET("/:treeID/list", h.permit(modules.ReadContent), h.listTree)
+		crud.GET("/:treeID/archive", h.permit(modules.ReadContent), h.archiveTree)
+		crud.POST("/:treeID/import", h.permit(modules.WriteContent), h.importTree)
+		crud.PUT("/:treeID", h.permit(modules.WriteContent), h.updateTree)
+		crud.DELETE("/:treeID", h.permit(modules.WriteContent), h.deleteTree)
+		crud.POST("/:treeID/move", h.permit(modules.WriteContent), h.moveTree)
+		crud.POST("/:treeID/copy", h.permit(modules.WriteContent), h.copyTree)
+		crud.POST("/:treeID/share", h.permit(modules.ShareContent), h.shareTree)
 	}
 }
 
@@ -32,6 +40,10 @@ func (h *Handler) createTree(ctx *gin.Context) {
 	}
 
 	tree, err := h.services.TreeService.Create(ctx, tree)
+	if errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
 	if err != nil {
 		logrus.Errorf("[validaton error] - %+v", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
@@ -125,6 +137,10 @@ func (h *Handler) updateTree(ctx *gin.Context) {
 	tree.ID = input.TreeID
 
 	updated, err := h.services.TreeService.Update(ctx, tree)
+	if errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
 	if err != nil {
 		logrus.Errorf("[service error] - %+v", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
@@ -145,6 +161,18 @@ func (h *Handler) deleteTree(ctx *gin.Context) {
 
 	tree := dto.Tree{ID: input.TreeID}
 
+	if _, permanent := ctx.GetQuery("permanent"); permanent {
+		purged, err := h.services.TreeService.Purge(ctx, tree)
+		if err != nil {
+			logrus.Errorf("[service error] - %+v", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, purged)
+		return
+	}
+
 	deleted, err := h.services.TreeService.Delete(ctx, tree)
 	if err != nil {
 		logrus.Errorf("[service error] - %+v", err)
@@ -155,3 +183,147 @@ func (h *Handler) deleteTree(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, deleted)
 	return
 }
+
+func (h *Handler) moveTree(ctx *gin.Context) {
+	var input TreeInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	var destination dto.Destination
+	if err := ctx.ShouldBind(&destination); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	moved, err := h.services.TreeService.Move(ctx, dto.Tree{ID: input.TreeID}, destination.ParentID)
+	if errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
+	if errors.Is(err, modules.ErrTreeCycle) {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, moved)
+	return
+}
+
+func (h *Handler) copyTree(ctx *gin.Context) {
+	var input TreeInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	var destination dto.Destination
+	if err := ctx.ShouldBind(&destination); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	copied, err := h.services.TreeService.Copy(ctx, dto.Tree{ID: input.TreeID}, destination.ParentID)
+	if errors.Is(err, modules.ErrQuota) {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"reason": err.Error()})
+		return
+	}
+	if errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, copied)
+	return
+}
+
+func (h *Handler) archiveTree(ctx *gin.Context) {
+	var input TreeInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	var selection dto.ArchiveSelection
+	if err := ctx.ShouldBindQuery(&selection); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	folder, err := h.services.TreeService.Archive(ctx, dto.Tree{ID: input.TreeID}, selection)
+	if errors.Is(err, modules.ErrNotInTree) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+	if errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	sendArchive(ctx, folder.Name, func(w io.Writer) error {
+		return h.services.TreeService.WriteArchive(ctx, folder, w)
+	})
+	return
+}
+
+func (h *Handler) importTree(ctx *gin.Context) {
+	var input TreeInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	report, err := h.services.TreeService.Import(ctx, dto.Tree{ID: input.TreeID}, file)
+	if errors.Is(err, modules.ErrBadArchive) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+	if errors.Is(err, modules.ErrArchiveLimits) {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"reason": err.Error()})
+		return
+	}
+	if errors.Is(err, modules.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+	return
+}
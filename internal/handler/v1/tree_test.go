@@ -1,15 +1,20 @@
 package v1
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
 	servicemocks "gitlab.com/a5805/ondeu/ondeu-back/internal/service/mocks"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
 	"gorm.io/gorm"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -57,6 +62,18 @@ func TestHandler_createTree(t *testing.T) {
 			expectedStatusCode:   500,
 			expectedResponseBody: `{"reason":"invalid value, should be pointer to struct or slice"}`,
 		},
+		{
+			name:  "Failed. Template by a student",
+			raw:   `{"parentId":0,"name":"1 grade","role":"bachelor","template":true,"group":true}`,
+			input: dto.Tree{},
+			mockBehavior: func(r *servicemocks.MockTreeService, tree dto.Tree) {
+				r.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					Return(dto.Tree{}, modules.ErrForbidden)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"reason":"you can not perform this action"}`,
+		},
 		{
 			name: "Success.",
 			raw:  `{"parentId":0,"name":"1 grade","role":"bachelor","template":true,"group":true}`,
@@ -333,3 +350,459 @@ func TestHandler_deleteTree(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_deleteTreePermanent(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockTreeService)
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Failed. Database. Record Not Found",
+			mockBehavior: func(r *servicemocks.MockTreeService) {
+				r.EXPECT().
+					Purge(gomock.Any(), dto.Tree{ID: 1}).
+					Return(dto.Purge{}, gorm.ErrRecordNotFound)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"record not found"}`,
+		},
+		{
+			name: "Success.",
+			mockBehavior: func(r *servicemocks.MockTreeService) {
+				r.EXPECT().
+					Purge(gomock.Any(), dto.Tree{ID: 1}).
+					Return(dto.Purge{Folders: 3, Documents: 5, Bytes: 2048}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"folders":3,"documents":5,"bytes":2048}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockTreeService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{TreeService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.DELETE("/api/v1/tree/:treeID", handler.deleteTree)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(
+				http.MethodDelete,
+				fmt.Sprintf("/api/v1/tree/%d?permanent", 1),
+				nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_moveTree(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockTreeService)
+
+	createdData := time.Now()
+	false_ := false
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. Invalid body",
+			inputBody:            `{"parentID":"abc"}`,
+			mockBehavior:         func(r *servicemocks.MockTreeService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"json: cannot unmarshal string into Go struct field Destination.parentID of type uint"}`,
+		},
+		{
+			name:      "Failed. Cycle.",
+			inputBody: `{"parentID":4}`,
+			mockBehavior: func(r *servicemocks.MockTreeService) {
+				r.EXPECT().
+					Move(gomock.Any(), dto.Tree{ID: 1}, uint(4)).
+					Return(dto.Tree{}, modules.ErrTreeCycle)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"a folder can't be moved into itself or its subfolder"}`,
+		},
+		{
+			name:      "Failed. Database. Record Not Found",
+			inputBody: `{"parentID":4}`,
+			mockBehavior: func(r *servicemocks.MockTreeService) {
+				r.EXPECT().
+					Move(gomock.Any(), dto.Tree{ID: 1}, uint(4)).
+					Return(dto.Tree{}, gorm.ErrRecordNotFound)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"record not found"}`,
+		},
+		{
+			name:      "Success.",
+			inputBody: `{"parentID":4}`,
+			mockBehavior: func(r *servicemocks.MockTreeService) {
+				r.EXPECT().
+					Move(gomock.Any(), dto.Tree{ID: 1}, uint(4)).
+					Return(dto.Tree{
+						ID:        1,
+						ParentID:  4,
+						CreatedAt: createdData,
+						UpdatedAt: createdData,
+						Name:      "1 grade",
+						Role:      "bachelor",
+						Template:  &false_,
+						Group:     &false_,
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(
+				`{"id":1,"parentID":4,"createdAt":"%s","updatedAt":"%s","name":"1 grade","role":"bachelor","template":false,"group":false,"documents":null}`,
+				createdData.Format(time.RFC3339Nano),
+				createdData.Format(time.RFC3339Nano),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockTreeService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{TreeService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/api/v1/tree/:treeID/move", handler.moveTree)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(
+				http.MethodPost,
+				fmt.Sprintf("/api/v1/tree/%d/move", 1),
+				strings.NewReader(tt.inputBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_copyTree(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockTreeService)
+
+	createdData := time.Now()
+	false_ := false
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Failed. Database. Record Not Found",
+			inputBody: `{"parentID":0}`,
+			mockBehavior: func(r *servicemocks.MockTreeService) {
+				r.EXPECT().
+					Copy(gomock.Any(), dto.Tree{ID: 1}, uint(0)).
+					Return(dto.Tree{}, gorm.ErrRecordNotFound)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"record not found"}`,
+		},
+		{
+			name:      "Success.",
+			inputBody: `{"parentID":0}`,
+			mockBehavior: func(r *servicemocks.MockTreeService) {
+				r.EXPECT().
+					Copy(gomock.Any(), dto.Tree{ID: 1}, uint(0)).
+					Return(dto.Tree{
+						ID:        9,
+						CreatedAt: createdData,
+						UpdatedAt: createdData,
+						Name:      "1 grade",
+						Role:      "bachelor",
+						Template:  &false_,
+						Group:     &false_,
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(
+				`{"id":9,"parentID":0,"createdAt":"%s","updatedAt":"%s","name":"1 grade","role":"bachelor","template":false,"group":false,"documents":null}`,
+				createdData.Format(time.RFC3339Nano),
+				createdData.Format(time.RFC3339Nano),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockTreeService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{TreeService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/api/v1/tree/:treeID/copy", handler.copyTree)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(
+				http.MethodPost,
+				fmt.Sprintf("/api/v1/tree/%d/copy", 1),
+				strings.NewReader(tt.inputBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_archiveTree(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockTreeService)
+
+	folder := dto.Archive{Name: "Course.zip", Entries: []dto.ArchiveEntry{{Path: "a.txt", Document: &dto.Document{ID: 7}}}}
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedHeaders      map[string]string
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. Invalid selection.",
+			query:                "?documentID=abc",
+			mockBehavior:         func(r *servicemocks.MockTreeService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"strconv.ParseUint: parsing \"abc\": invalid syntax"}`,
+		},
+		{
+			name:  "Failed. Document of another folder.",
+			query: "?documentID=7&documentID=99",
+			mockBehavior: func(r *servicemocks.MockTreeService) {
+				r.EXPECT().
+					Archive(gomock.Any(), dto.Tree{ID: 1}, dto.ArchiveSelection{DocumentIDs: []uint{7, 99}}).
+					Return(dto.Archive{}, modules.ErrNotInTree)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"a selected document is not in the folder"}`,
+		},
+		{
+			name: "Failed. Not found.",
+			mockBehavior: func(r *servicemocks.MockTreeService) {
+				r.EXPECT().
+					Archive(gomock.Any(), dto.Tree{ID: 1}, dto.ArchiveSelection{}).
+					Return(dto.Archive{}, gorm.ErrRecordNotFound)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"record not found"}`,
+		},
+		{
+			name:  "Success. Selection.",
+			query: "?documentID=7",
+			mockBehavior: func(r *servicemocks.MockTreeService) {
+				r.EXPECT().
+					Archive(gomock.Any(), dto.Tree{ID: 1}, dto.ArchiveSelection{DocumentIDs: []uint{7}}).
+					Return(folder, nil)
+				r.EXPECT().
+					WriteArchive(gomock.Any(), folder, gomock.Any()).
+					DoAndReturn(func(_ interface{}, _ dto.Archive, w io.Writer) error {
+						_, err := w.Write([]byte("PK"))
+						return err
+					})
+			},
+			expectedStatusCode: 200,
+			expectedHeaders: map[string]string{
+				"Content-Type":        "application/zip",
+				"Content-Disposition": "attachment; filename=Course.zip",
+			},
+			expectedResponseBody: "PK",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockTreeService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{TreeService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/api/v1/tree/:treeID/archive", handler.archiveTree)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tree/1/archive"+tt.query, nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			for key, value := range tt.expectedHeaders {
+				assert.Equal(t, value, w.Header().Get(key))
+			}
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_importTree(t *testing.T) {
+	type mockBehavior func(*servicemocks.MockTreeService)
+
+	tests := []struct {
+		name                 string
+		fileExists           bool
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. No file.",
+			mockBehavior:         func(r *servicemocks.MockTreeService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"request Content-Type isn't multipart/form-data"}`,
+		},
+		{
+			name:       "Failed. Not an archive.",
+			fileExists: true,
+			mockBehavior: func(r *servicemocks.MockTreeService) {
+				r.EXPECT().
+					Import(gomock.Any(), dto.Tree{ID: 1}, gomock.Any()).
+					Return(dto.ImportReport{}, fmt.Errorf("%w: zip: not a valid zip file", modules.ErrBadArchive))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"the file is not a readable ZIP archive: zip: not a valid zip file"}`,
+		},
+		{
+			name:       "Failed. Zip bomb.",
+			fileExists: true,
+			mockBehavior: func(r *servicemocks.MockTreeService) {
+				r.EXPECT().
+					Import(gomock.Any(), dto.Tree{ID: 1}, gomock.Any()).
+					Return(dto.ImportReport{}, fmt.Errorf("%w: more than 1000 entries", modules.ErrArchiveLimits))
+			},
+			expectedStatusCode:   413,
+			expectedResponseBody: `{"reason":"the archive exceeds the unpacking limits: more than 1000 entries"}`,
+		},
+		{
+			name:       "Failed. Forbidden.",
+			fileExists: true,
+			mockBehavior: func(r *servicemocks.MockTreeService) {
+				r.EXPECT().
+					Import(gomock.Any(), dto.Tree{ID: 1}, gomock.Any()).
+					Return(dto.ImportReport{}, modules.ErrForbidden)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"reason":"you can not perform this action"}`,
+		},
+		{
+			name:       "Success.",
+			fileExists: true,
+			mockBehavior: func(r *servicemocks.MockTreeService) {
+				r.EXPECT().
+					Import(gomock.Any(), dto.Tree{ID: 1}, gomock.Any()).
+					DoAndReturn(func(_ interface{}, _ dto.Tree, file *multipart.FileHeader) (dto.ImportReport, error) {
+						assert.Equal(t, "course.zip", file.Filename)
+						return dto.ImportReport{Created: 2, Rejected: 1, Entries: []dto.ImportEntry{
+							{Path: "../evil.sh", Kind: modules.ImportDocument, Status: modules.ImportRejected, Reason: "the path leaves the archive"},
+							{Path: "Week 1/", Kind: modules.ImportFolder, Status: modules.ImportCreated, ID: 5},
+							{Path: "Week 1/intro.pdf", Kind: modules.ImportDocument, Status: modules.ImportCreated, ID: 9},
+						}}, nil
+					})
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"created":2,"rejected":1,"failed":0,"entries":[` +
+				`{"path":"../evil.sh","kind":"document","status":"rejected","reason":"the path leaves the archive"},` +
+				`{"path":"Week 1/","kind":"folder","status":"created","id":5},` +
+				`{"path":"Week 1/intro.pdf","kind":"document","status":"created","id":9}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockTreeService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{TreeService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/api/v1/tree/:treeID/import", handler.importTree)
+
+			// Create Request
+			body := new(bytes.Buffer)
+			m := multipart.NewWriter(body)
+			if tt.fileExists {
+				writer, err := m.CreateFormFile("file", "course.zip")
+				require.NoError(t, err)
+				_, err = writer.Write([]byte("PK"))
+				require.NoError(t, err)
+				require.NoError(t, m.Close())
+			}
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/tree/1/import", body)
+			if tt.fileExists {
+				req.Header.Add("Content-Type", m.FormDataContentType())
+			}
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"io"
+	"net/http"
+	"os"
+)
+
+func (h *Handler) initUploadRoutes(api *gin.RouterGroup) {
+	crud := api.Group("/:treeID/uploads")
+	{
+		crud.POST("/", h.permit(modules.WriteContent), h.initiateUpload)
+		crud.GET("/", h.permit(modules.ReadContent), h.listUploads)
+		crud.GET("/:uploadID", h.permit(modules.ReadContent), h.getUpload)
+		crud.PUT("/:uploadID/parts/:number", h.permit(modules.WriteContent), h.putUploadPart)
+		crud.POST("/:uploadID/complete", h.permit(modules.WriteContent), h.completeUpload)
+		crud.DELETE("/:uploadID", h.permit(modules.WriteContent), h.abortUpload)
+	}
+}
+
+type UploadInput struct {
+	TreeID   uint   `uri:"treeID" binding:"required"`
+	UploadID string `uri:"uploadID" binding:"required,uuid"`
+}
+
+type UploadPartInput struct {
+	UploadInput
+	Number int64 `uri:"number" binding:"required"`
+}
+
+func (i UploadInput) session() dto.UploadSession {
+	return dto.UploadSession{ID: uuid.MustParse(i.UploadID), TreeID: i.TreeID}
+}
+
+func (h *Handler) initiateUpload(ctx *gin.Context) {
+	var input TreeInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	var session dto.UploadSession
+	if err := ctx.ShouldBindJSON(&session); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	session.TreeID = input.TreeID
+
+	created, err := h.services.UploadService.Initiate(ctx, session)
+	var invalid *modules.ValidationError
+	if errors.As(err, &invalid) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error(), "violations": invalid.Violations})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, created)
+	return
+}
+
+func (h *Handler) listUploads(ctx *gin.Context) {
+	var input TreeInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	sessions, err := h.services.UploadService.List(ctx, dto.UploadSession{TreeID: input.TreeID})
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sessions)
+	return
+}
+
+func (h *Handler) getUpload(ctx *gin.Context) {
+	var input UploadInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	session, err := h.services.UploadService.Get(ctx, input.session())
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, session)
+	return
+}
+
+func (h *Handler) putUploadPart(ctx *gin.Context) {
+	var input UploadPartInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	// the object storage client needs a seekable body, so the chunk is
+	// spooled to disk instead of being held in memory
+	chunk, err := os.CreateTemp("", "upload-part-*")
+	if err != nil {
+		logrus.Errorf("[internal error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+	defer os.Remove(chunk.Name())
+	defer chunk.Close()
+
+	size, err := io.Copy(chunk, io.LimitReader(ctx.Request.Body, modules.MaxUploadPartSize+1))
+	if err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	if size == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": "empty part"})
+		return
+	}
+
+	if size > modules.MaxUploadPartSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"reason": "part is too large"})
+		return
+	}
+
+	if _, err = chunk.Seek(0, io.SeekStart); err != nil {
+		logrus.Errorf("[internal error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	part := dto.UploadPart{Number: input.Number, Size: size, RequestContent: chunk}
+
+	stored, err := h.services.UploadService.PutPart(ctx, input.session(), part)
+	var invalid *modules.ValidationError
+	if errors.As(err, &invalid) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error(), "violations": invalid.Violations})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stored)
+	return
+}
+
+func (h *Handler) completeUpload(ctx *gin.Context) {
+	var input UploadInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	document, err := h.services.UploadService.Complete(ctx, input.session())
+	var invalid *modules.ValidationError
+	if errors.As(err, &invalid) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error(), "violations": invalid.Violations})
+		return
+	}
+	if errors.Is(err, modules.ErrInfected) {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"reason": err.Error()})
+		return
+	}
+	if errors.Is(err, modules.ErrQuota) {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"reason": err.Error()})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, document)
+	return
+}
+
+func (h *Handler) abortUpload(ctx *gin.Context) {
+	var input UploadInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	session, err := h.services.UploadService.Abort(ctx, input.session())
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, session)
+	return
+}
@@ -0,0 +1,344 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
+	servicemocks "gitlab.com/a5805/ondeu/ondeu-back/internal/service/mocks"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_initiateUpload(t *testing.T) {
+	type mockBehavior func(r *servicemocks.MockUploadService)
+
+	created := time.Now()
+	sessionID := uuid.New()
+
+	tests := []struct {
+		name                 string
+		raw                  string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. No name",
+			raw:                  `{"type":"video/mp4"}`,
+			mockBehavior:         func(r *servicemocks.MockUploadService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"Key: 'UploadSession.Name' Error:Field validation for 'Name' failed on the 'required' tag"}`,
+		},
+		{
+			name: "Failed. Service. Storage unavailable",
+			raw:  `{"name":"lecture.mp4","type":"video/mp4"}`,
+			mockBehavior: func(r *servicemocks.MockUploadService) {
+				r.EXPECT().
+					Initiate(gomock.Any(), dto.UploadSession{TreeID: 1, Name: "lecture.mp4", Type: "video/mp4"}).
+					Return(dto.UploadSession{}, io.ErrUnexpectedEOF)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"unexpected EOF"}`,
+		},
+		{
+			name: "Success.",
+			raw:  `{"name":"lecture.mp4","type":"video/mp4"}`,
+			mockBehavior: func(r *servicemocks.MockUploadService) {
+				r.EXPECT().
+					Initiate(gomock.Any(), dto.UploadSession{TreeID: 1, Name: "lecture.mp4", Type: "video/mp4"}).
+					Return(dto.UploadSession{
+						ID:        sessionID,
+						TreeID:    1,
+						CreatedAt: created,
+						UpdatedAt: created,
+						ExpiresAt: created.Add(24 * time.Hour),
+						Name:      "lecture.mp4",
+						Extension: ".mp4",
+						Type:      "video/mp4",
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(`{"id":"%s","treeID":1,"createdAt":"%s","updatedAt":"%s","expiresAt":"%s","name":"lecture.mp4","extension":".mp4","type":"video/mp4","parts":null}`,
+				sessionID.String(),
+				created.Format(time.RFC3339Nano),
+				created.Format(time.RFC3339Nano),
+				created.Add(24*time.Hour).Format(time.RFC3339Nano)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockUploadService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{UploadService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/api/v1/tree/:treeID/uploads", handler.initiateUpload)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/tree/%d/uploads", 1), strings.NewReader(tt.raw))
+			req.Header.Set("Content-Type", "application/json")
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_putUploadPart(t *testing.T) {
+	type mockBehavior func(r *servicemocks.MockUploadService)
+
+	updated := time.Now()
+	sessionID := uuid.New()
+
+	tests := []struct {
+		name                 string
+		uploadID             string
+		number               string
+		body                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. Invalid upload id",
+			uploadID:             "session",
+			number:               "1",
+			body:                 "chunk",
+			mockBehavior:         func(r *servicemocks.MockUploadService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"Key: 'UploadPartInput.UploadInput.UploadID' Error:Field validation for 'UploadID' failed on the 'uuid' tag"}`,
+		},
+		{
+			name:                 "Failed. Validation. Empty part",
+			uploadID:             sessionID.String(),
+			number:               "1",
+			body:                 "",
+			mockBehavior:         func(r *servicemocks.MockUploadService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"empty part"}`,
+		},
+		{
+			name:     "Failed. Database. Record Not Found",
+			uploadID: sessionID.String(),
+			number:   "2",
+			body:     "chunk",
+			mockBehavior: func(r *servicemocks.MockUploadService) {
+				r.EXPECT().
+					PutPart(gomock.Any(), dto.UploadSession{ID: sessionID, TreeID: 1}, gomock.Any()).
+					Return(dto.UploadPart{}, gorm.ErrRecordNotFound)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"record not found"}`,
+		},
+		{
+			name:     "Success.",
+			uploadID: sessionID.String(),
+			number:   "2",
+			body:     "chunk",
+			mockBehavior: func(r *servicemocks.MockUploadService) {
+				r.EXPECT().
+					PutPart(gomock.Any(), dto.UploadSession{ID: sessionID, TreeID: 1}, gomock.Any()).
+					DoAndReturn(func(_ interface{}, _ dto.UploadSession, part dto.UploadPart) (dto.UploadPart, error) {
+						content, err := io.ReadAll(part.RequestContent)
+						assert.NoError(t, err)
+						assert.Equal(t, "chunk", string(content))
+
+						return dto.UploadPart{SessionID: sessionID, Number: part.Number, ETag: `"etag"`, Size: part.Size, UpdatedAt: updated}, nil
+					})
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(`{"number":2,"etag":"\"etag\"","size":5,"updatedAt":"%s"}`,
+				updated.Format(time.RFC3339Nano)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockUploadService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{UploadService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.PUT("/api/v1/tree/:treeID/uploads/:uploadID/parts/:number", handler.putUploadPart)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/v1/tree/%d/uploads/%s/parts/%s", 1, tt.uploadID, tt.number), strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/octet-stream")
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_completeUpload(t *testing.T) {
+	type mockBehavior func(r *servicemocks.MockUploadService)
+
+	created := time.Now()
+	sessionID := uuid.New()
+	path := uuid.New()
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Failed. Service. Missing part",
+			mockBehavior: func(r *servicemocks.MockUploadService) {
+				r.EXPECT().
+					Complete(gomock.Any(), dto.UploadSession{ID: sessionID, TreeID: 1}).
+					Return(dto.Document{}, fmt.Errorf("part 2 is missing"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"part 2 is missing"}`,
+		},
+		{
+			name: "Success.",
+			mockBehavior: func(r *servicemocks.MockUploadService) {
+				r.EXPECT().
+					Complete(gomock.Any(), dto.UploadSession{ID: sessionID, TreeID: 1}).
+					Return(dto.Document{
+						ID:        5,
+						TreeID:    1,
+						CreatedAt: created,
+						UpdatedAt: created,
+						Name:      "lecture.mp4",
+						Extension: ".mp4",
+						Size:      15,
+						Type:      "video/mp4",
+						Path:      path,
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(`{"id":5,"createdAt":"%s","updatedAt":"%s","name":"lecture.mp4","extension":".mp4","size":15,"type":"video/mp4","path":"%s","template":null}`,
+				created.Format(time.RFC3339Nano),
+				created.Format(time.RFC3339Nano),
+				path.String()),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockUploadService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{UploadService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/api/v1/tree/:treeID/uploads/:uploadID/complete", handler.completeUpload)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/tree/%d/uploads/%s/complete", 1, sessionID), nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_abortUpload(t *testing.T) {
+	type mockBehavior func(r *servicemocks.MockUploadService)
+
+	sessionID := uuid.New()
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Failed. Database. Record Not Found",
+			mockBehavior: func(r *servicemocks.MockUploadService) {
+				r.EXPECT().
+					Abort(gomock.Any(), dto.UploadSession{ID: sessionID, TreeID: 1}).
+					Return(dto.UploadSession{}, gorm.ErrRecordNotFound)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"record not found"}`,
+		},
+		{
+			name: "Success.",
+			mockBehavior: func(r *servicemocks.MockUploadService) {
+				r.EXPECT().
+					Abort(gomock.Any(), dto.UploadSession{ID: sessionID, TreeID: 1}).
+					Return(dto.UploadSession{ID: sessionID, TreeID: 1, Name: "lecture.mp4"}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(`{"id":"%s","treeID":1,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z","expiresAt":"0001-01-01T00:00:00Z","name":"lecture.mp4","parts":null}`,
+				sessionID.String()),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockUploadService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{UploadService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.DELETE("/api/v1/tree/:treeID/uploads/:uploadID", handler.abortUpload)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/tree/%d/uploads/%s", 1, sessionID), nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
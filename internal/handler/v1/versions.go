@@ -0,0 +1,142 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"net/http"
+)
+
+func (h *Handler) initVersionRoutes(api *gin.RouterGroup) {
+	crud := api.Group("/:treeID/document/:docID/versions")
+	{
+		crud.POST("/", h.permit(modules.WriteContent), h.createVersion)
+		crud.GET("/", h.permit(modules.ReadContent), h.listVersions)
+		crud.GET("/:version", h.permit(modules.ReadContent), h.readVersion)
+		crud.POST("/:version/restore", h.permit(modules.WriteContent), h.restoreVersion)
+	}
+}
+
+type VersionInput struct {
+	DocumentID uint `uri:"docID" binding:"required"`
+	TreeID     uint `uri:"treeID" binding:"required"`
+	Version    uint `uri:"version" binding:"required"`
+}
+
+func (h *Handler) createVersion(ctx *gin.Context) {
+	var input DocumentInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	document := dto.Document{ID: input.DocumentID, TreeID: input.TreeID}
+
+	version, err := h.services.VersionService.Create(ctx, document, file)
+	var invalid *modules.ValidationError
+	if errors.As(err, &invalid) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error(), "violations": invalid.Violations})
+		return
+	}
+	if errors.Is(err, modules.ErrInfected) {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"reason": err.Error()})
+		return
+	}
+	if errors.Is(err, modules.ErrNotClean) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
+	if errors.Is(err, modules.ErrQuota) {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"reason": err.Error()})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, version)
+	return
+}
+
+func (h *Handler) listVersions(ctx *gin.Context) {
+	var input DocumentInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	document := dto.Document{ID: input.DocumentID, TreeID: input.TreeID}
+
+	versions, err := h.services.VersionService.List(ctx, document)
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, versions)
+	return
+}
+
+func (h *Handler) readVersion(ctx *gin.Context) {
+	var input VersionInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	document := dto.Document{ID: input.DocumentID, TreeID: input.TreeID, Download: downloadRequest(ctx)}
+
+	version, err := h.services.VersionService.Get(ctx, document, input.Version)
+	if errors.Is(err, modules.ErrNotClean) {
+		ctx.JSON(http.StatusForbidden, gin.H{"reason": err.Error()})
+		return
+	}
+	if errors.Is(err, modules.ErrInvalidRange) {
+		ctx.JSON(http.StatusRequestedRangeNotSatisfiable, gin.H{"reason": err.Error()})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	sendContent(ctx, version.Name, version.Type, version.ResponseContent, version.Download)
+	return
+}
+
+func (h *Handler) restoreVersion(ctx *gin.Context) {
+	var input VersionInput
+	if err := ctx.ShouldBindUri(&input); err != nil {
+		logrus.Errorf("[validaton error] - %+v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"reason": err.Error()})
+		return
+	}
+
+	document := dto.Document{ID: input.DocumentID, TreeID: input.TreeID}
+
+	restored, err := h.services.VersionService.Restore(ctx, document, input.Version)
+	if err != nil {
+		logrus.Errorf("[service error] - %+v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, restored)
+	return
+}
@@ -0,0 +1,338 @@
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
+	servicemocks "gitlab.com/a5805/ondeu/ondeu-back/internal/service/mocks"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_createVersion(t *testing.T) {
+	type mockBehavior func(r *servicemocks.MockVersionService)
+
+	created := time.Now()
+
+	tests := []struct {
+		name                 string
+		fileExists           bool
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. No file",
+			fileExists:           false,
+			mockBehavior:         func(r *servicemocks.MockVersionService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"request Content-Type isn't multipart/form-data"}`,
+		},
+		{
+			name:       "Failed. Database. Record Not Found",
+			fileExists: true,
+			mockBehavior: func(r *servicemocks.MockVersionService) {
+				r.EXPECT().
+					Create(gomock.Any(), dto.Document{ID: 2, TreeID: 1}, gomock.Any()).
+					Return(dto.DocumentVersion{}, gorm.ErrRecordNotFound)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"record not found"}`,
+		},
+		{
+			name:       "Success.",
+			fileExists: true,
+			mockBehavior: func(r *servicemocks.MockVersionService) {
+				r.EXPECT().
+					Create(gomock.Any(), dto.Document{ID: 2, TreeID: 1}, gomock.Any()).
+					Return(dto.DocumentVersion{
+						ID:         7,
+						DocumentID: 2,
+						Version:    3,
+						CreatedAt:  created,
+						Name:       "versions.go",
+						Extension:  ".go",
+						Size:       10,
+						Current:    true,
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(`{"id":7,"documentID":2,"version":3,"createdAt":"%s","name":"versions.go","extension":".go","size":10,"current":true}`,
+				created.Format(time.RFC3339Nano)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockVersionService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{VersionService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/api/v1/tree/:treeID/document/:docID/versions", handler.createVersion)
+
+			// Create Request
+			body := new(bytes.Buffer)
+			contentType := "application/json"
+			if tt.fileExists {
+				m := multipart.NewWriter(body)
+				writer, err := m.CreateFormFile("file", "versions.go")
+				require.NoError(t, err)
+				_, err = writer.Write([]byte("package v1"))
+				require.NoError(t, err)
+				require.NoError(t, m.Close())
+				contentType = m.FormDataContentType()
+			}
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/tree/%d/document/%d/versions", 1, 2), body)
+			req.Header.Add("Content-Type", contentType)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_listVersions(t *testing.T) {
+	type mockBehavior func(r *servicemocks.MockVersionService)
+
+	created := time.Now()
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Failed. Database. Invalid Value",
+			mockBehavior: func(r *servicemocks.MockVersionService) {
+				r.EXPECT().
+					List(gomock.Any(), dto.Document{ID: 2, TreeID: 1}).
+					Return(nil, gorm.ErrInvalidValue)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"invalid value, should be pointer to struct or slice"}`,
+		},
+		{
+			name: "Success.",
+			mockBehavior: func(r *servicemocks.MockVersionService) {
+				r.EXPECT().
+					List(gomock.Any(), dto.Document{ID: 2, TreeID: 1}).
+					Return([]dto.DocumentVersion{
+						{ID: 8, DocumentID: 2, Version: 2, CreatedAt: created, Name: "b.pdf", Current: true},
+						{ID: 5, DocumentID: 2, Version: 1, CreatedAt: created, Name: "a.pdf"},
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(`[{"id":8,"documentID":2,"version":2,"createdAt":"%s","name":"b.pdf","current":true},{"id":5,"documentID":2,"version":1,"createdAt":"%s","name":"a.pdf","current":false}]`,
+				created.Format(time.RFC3339Nano),
+				created.Format(time.RFC3339Nano)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockVersionService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{VersionService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/api/v1/tree/:treeID/document/:docID/versions", handler.listVersions)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/tree/%d/document/%d/versions", 1, 2), nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_readVersion(t *testing.T) {
+	type mockBehavior func(r *servicemocks.MockVersionService)
+
+	tests := []struct {
+		name                 string
+		version              string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedDisposition  string
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Failed. Validation. Invalid version",
+			version:              "latest",
+			mockBehavior:         func(r *servicemocks.MockVersionService) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"reason":"strconv.ParseUint: parsing \"latest\": invalid syntax"}`,
+		},
+		{
+			name:    "Failed. Database. Record Not Found",
+			version: "4",
+			mockBehavior: func(r *servicemocks.MockVersionService) {
+				r.EXPECT().
+					Get(gomock.Any(), dto.Document{ID: 2, TreeID: 1}, uint(4)).
+					Return(dto.DocumentVersion{}, gorm.ErrRecordNotFound)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"record not found"}`,
+		},
+		{
+			name:    "Success.",
+			version: "1",
+			mockBehavior: func(r *servicemocks.MockVersionService) {
+				r.EXPECT().
+					Get(gomock.Any(), dto.Document{ID: 2, TreeID: 1}, uint(1)).
+					Return(dto.DocumentVersion{
+						DocumentID:      2,
+						Version:         1,
+						Name:            "draft.txt",
+						ResponseContent: io.NopCloser(strings.NewReader("first draft")),
+						Download:        dto.Download{ContentLength: 11},
+					}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedDisposition:  "attachment; filename=draft.txt",
+			expectedResponseBody: "first draft",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockVersionService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{VersionService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/api/v1/tree/:treeID/document/:docID/versions/:version", handler.readVersion)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/tree/%d/document/%d/versions/%s", 1, 2, tt.version), nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedDisposition, w.Header().Get("Content-Disposition"))
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_restoreVersion(t *testing.T) {
+	type mockBehavior func(r *servicemocks.MockVersionService)
+
+	updated := time.Now()
+	created := updated.Add(-time.Hour)
+	createdFileUUID := uuid.New()
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Failed. Database. Record Not Found",
+			mockBehavior: func(r *servicemocks.MockVersionService) {
+				r.EXPECT().
+					Restore(gomock.Any(), dto.Document{ID: 2, TreeID: 1}, uint(1)).
+					Return(dto.Document{}, gorm.ErrRecordNotFound)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"reason":"record not found"}`,
+		},
+		{
+			name: "Success.",
+			mockBehavior: func(r *servicemocks.MockVersionService) {
+				r.EXPECT().
+					Restore(gomock.Any(), dto.Document{ID: 2, TreeID: 1}, uint(1)).
+					Return(dto.Document{
+						ID:        2,
+						TreeID:    1,
+						CreatedAt: created,
+						UpdatedAt: updated,
+						Name:      "essay",
+						Path:      createdFileUUID,
+						Version:   1,
+					}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: fmt.Sprintf(`{"id":2,"createdAt":"%s","updatedAt":"%s","name":"essay","path":"%s","template":null,"version":1}`,
+				created.Format(time.RFC3339Nano),
+				updated.Format(time.RFC3339Nano),
+				createdFileUUID.String()),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := servicemocks.NewMockVersionService(c)
+			tt.mockBehavior(repo)
+
+			services := &service.Services{VersionService: repo}
+			handler := Handler{services, nil, nil}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/api/v1/tree/:treeID/document/:docID/versions/:version/restore", handler.restoreVersion)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/tree/%d/document/%d/versions/%d/restore", 1, 2, 1), nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
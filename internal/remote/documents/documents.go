@@ -2,14 +2,16 @@ package documents
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/sirupsen/logrus"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
-	"io/ioutil"
-	"time"
+	"io"
+	"net/http"
 )
 
 type Remote struct {
@@ -24,10 +26,20 @@ func NewRemote(s3 *s3.S3, cfg *modules.ObjectStorage) *Remote {
 	}
 }
 
+// key is the object of the current content of a document, content with a checksum
+// is a blob shared by every document with the same content
 func key(doc dto.Document) string {
+	if doc.Checksum != "" {
+		return blobKey(doc.Checksum)
+	}
 	return fmt.Sprintf("%s/%s%s", doc.CreatedAt.Format("2006-01-02"), doc.Path.String(), doc.Extension)
 }
 
+// blobKey spreads blobs over prefixes by the leading characters of their checksum
+func blobKey(checksum string) string {
+	return fmt.Sprintf("blobs/%s/%s/%s", checksum[:2], checksum[2:4], checksum)
+}
+
 func (r *Remote) Upload(ctx context.Context, doc dto.Document) (dto.Document, error) {
 	object := s3.PutObjectInput{
 		Bucket:      aws.String(r.cfg.Bucket),
@@ -51,24 +63,62 @@ func (r *Remote) Upload(ctx context.Context, doc dto.Document) (dto.Document, er
 }
 
 func (r *Remote) Get(ctx context.Context, doc dto.Document) (dto.Document, error) {
+	var err error
+	doc.ResponseContent, doc.Download, err = r.get(ctx, key(doc), doc.Download)
+	if err != nil {
+		return doc, err
+	}
+
+	return doc, nil
+}
+
+// get opens a stream of an object, honouring the range and conditional
+// parts of the download. A not modified object is reported through the
+// download instead of an error, with no content.
+func (r *Remote) get(ctx context.Context, key string, download dto.Download) (io.ReadCloser, dto.Download, error) {
 	object := s3.GetObjectInput{
 		Bucket: aws.String(r.cfg.Bucket),
-		Key:    aws.String(key(doc)),
+		Key:    aws.String(key),
+	}
+
+	if download.Range != "" {
+		object.Range = aws.String(download.Range)
+	}
+	if download.IfNoneMatch != "" {
+		object.IfNoneMatch = aws.String(download.IfNoneMatch)
+	}
+	if !download.IfModifiedSince.IsZero() {
+		object.IfModifiedSince = aws.Time(download.IfModifiedSince)
 	}
 
 	logrus.Debugf("[object input]: %+v", object)
 	out, err := r.s3.GetObjectWithContext(ctx, &object)
 	if err != nil {
-		return doc, err
+		var failure awserr.RequestFailure
+		if errors.As(err, &failure) {
+			switch failure.StatusCode() {
+			case http.StatusNotModified:
+				download.NotModified = true
+				download.ETag = download.IfNoneMatch
+				return nil, download, nil
+			case http.StatusRequestedRangeNotSatisfiable:
+				return nil, download, modules.ErrInvalidRange
+			}
+		}
+		return nil, download, err
 	}
 	logrus.Debugf("[object output]: %+v", out)
 
-	doc.ResponseContent, err = ioutil.ReadAll(out.Body)
-	if err != nil {
-		return doc, err
-	}
+	download.ContentLength = aws.Int64Value(out.ContentLength)
+	download.ContentRange = aws.StringValue(out.ContentRange)
+	download.ETag = aws.StringValue(out.ETag)
+	download.LastModified = aws.TimeValue(out.LastModified)
 
-	return doc, nil
+	return out.Body, download, nil
+}
+
+func (r *Remote) Copy(ctx context.Context, from string, to dto.Document) (dto.Document, error) {
+	return to, r.copy(ctx, from, key(to))
 }
 
 func (r *Remote) Delete(ctx context.Context, doc dto.Document) (dto.Document, error) {
@@ -84,31 +134,16 @@ func (r *Remote) Delete(ctx context.Context, doc dto.Document) (dto.Document, er
 	}
 	logrus.Debugf("[object output]: %+v", out)
 
-	if err = r.s3.WaitUntilObjectNotExistsWithContext(ctx, &s3.HeadObjectInput{
-		Bucket: aws.String(r.cfg.Bucket),
-		Key:    aws.String(key(doc)),
-	}); err != nil {
+	if err = r.deletePreviews(ctx, doc); err != nil {
 		return doc, err
 	}
 
-	return doc, nil
-}
-
-func (r *Remote) Share(ctx context.Context, doc dto.Document, duration time.Duration) (dto.Document, error) {
-	object := s3.GetObjectInput{
+	if err = r.s3.WaitUntilObjectNotExistsWithContext(ctx, &s3.HeadObjectInput{
 		Bucket: aws.String(r.cfg.Bucket),
 		Key:    aws.String(key(doc)),
-	}
-
-	logrus.Debugf("[object input]: %+v", object)
-	req, out := r.s3.GetObjectRequest(&object)
-	link, err := req.Presign(duration)
-	if err != nil {
+	}); err != nil {
 		return doc, err
 	}
-	logrus.Debugf("[object output]: %+v", out)
-
-	doc.ShareLink = link
 
 	return doc, nil
 }
@@ -0,0 +1,107 @@
+package documents
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/preview"
+	"net/http"
+	"path"
+	"strings"
+)
+
+func (r *Remote) List(ctx context.Context, after string, limit int64) ([]dto.StoredObject, error) {
+	input := s3.ListObjectsV2Input{
+		Bucket:  aws.String(r.cfg.Bucket),
+		MaxKeys: aws.Int64(limit),
+	}
+	if after != "" {
+		input.StartAfter = aws.String(after)
+	}
+
+	logrus.Debugf("[object input]: %+v", input)
+	out, err := r.s3.ListObjectsV2WithContext(ctx, &input)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]dto.StoredObject, 0, len(out.Contents))
+	for _, object := range out.Contents {
+		key := aws.StringValue(object.Key)
+		objects = append(objects, dto.StoredObject{
+			Key:          key,
+			Size:         aws.Int64Value(object.Size),
+			LastModified: object.LastModified,
+			Checksum:     blobChecksum(key),
+		})
+	}
+	return objects, nil
+}
+
+func (r *Remote) Remove(ctx context.Context, key string) error {
+	object := s3.DeleteObjectInput{
+		Bucket: aws.String(r.cfg.Bucket),
+		Key:    aws.String(key),
+	}
+
+	logrus.Debugf("[object input]: %+v", object)
+	out, err := r.s3.DeleteObjectWithContext(ctx, &object)
+	if err != nil {
+		return err
+	}
+	logrus.Debugf("[object output]: %+v", out)
+
+	return nil
+}
+
+func (r *Remote) Key(doc dto.Document) string {
+	return key(doc)
+}
+
+func (r *Remote) Exists(ctx context.Context, key string) (bool, error) {
+	object := s3.HeadObjectInput{
+		Bucket: aws.String(r.cfg.Bucket),
+		Key:    aws.String(key),
+	}
+
+	logrus.Debugf("[object input]: %+v", object)
+	if _, err := r.s3.HeadObjectWithContext(ctx, &object); err != nil {
+		var failure awserr.RequestFailure
+		if errors.As(err, &failure) && failure.StatusCode() == http.StatusNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// blobChecksum returns the checksum a blob key is named after, empty for any other key
+func blobChecksum(key string) string {
+	checksum := path.Base(key)
+	if len(checksum) < 4 || strings.Contains(checksum, ".") || blobKey(checksum) != key {
+		return ""
+	}
+	return checksum
+}
+
+// Objects names the object of the current content of a document, those of its
+// versions and the thumbnails stored next to it. A blob shared by documents
+// with the same content is named for each of them.
+func (r *Remote) Objects(doc dto.Document, versions []dto.DocumentVersion) []dto.StoredObject {
+	objects := make([]dto.StoredObject, 0, 1+len(preview.Sizes)+len(versions))
+	objects = append(objects, dto.StoredObject{Key: key(doc), DocumentID: doc.ID})
+
+	for size := range preview.Sizes {
+		objects = append(objects, dto.StoredObject{Key: previewKey(doc, size), DocumentID: doc.ID, Preview: true})
+	}
+
+	for _, version := range versions {
+		objects = append(objects, dto.StoredObject{Key: versionKey(doc, version), DocumentID: doc.ID, Version: version.Version})
+	}
+	return objects
+}
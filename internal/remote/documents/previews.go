@@ -0,0 +1,93 @@
+package documents
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/preview"
+	"net/http"
+)
+
+// previewKey places the thumbnails of a size next to the object of its document
+func previewKey(doc dto.Document, size string) string {
+	return key(doc) + ".preview-" + size + ".webp"
+}
+
+// legacyPreviewKey is where thumbnails were kept while they were built as JPEG
+func legacyPreviewKey(doc dto.Document, size string) string {
+	return key(doc) + ".preview-" + size + ".jpg"
+}
+
+func (r *Remote) UploadPreview(ctx context.Context, doc dto.Document, size string, content []byte) (dto.Document, error) {
+	object := s3.PutObjectInput{
+		Bucket:      aws.String(r.cfg.Bucket),
+		Key:         aws.String(previewKey(doc, size)),
+		Body:        bytes.NewReader(content),
+		ContentType: aws.String(preview.ContentType),
+		ACL:         aws.String("private"),
+		Metadata: map[string]*string{
+			"x-amz-meta-my-key": aws.String(doc.Path.String()),
+		},
+	}
+
+	logrus.Debugf("[object input]: %+v", object)
+	out, err := r.s3.PutObjectWithContext(ctx, &object)
+	if err != nil {
+		return doc, err
+	}
+	logrus.Debugf("[object output]: %+v", out)
+
+	return doc, nil
+}
+
+func (r *Remote) GetPreview(ctx context.Context, doc dto.Document, size string) (dto.Document, error) {
+	var err error
+	doc.ResponseContent, doc.Download, err = r.get(ctx, previewKey(doc, size), doc.Download)
+	if err != nil {
+		var failure awserr.RequestFailure
+		if errors.As(err, &failure) && failure.StatusCode() == http.StatusNotFound {
+			return doc, modules.ErrNoPreview
+		}
+		return doc, err
+	}
+
+	doc.Type = preview.ContentType
+
+	return doc, nil
+}
+
+// deletePreviews removes the thumbnails of every size, including JPEG ones
+// left from before, missing ones are skipped
+func (r *Remote) deletePreviews(ctx context.Context, doc dto.Document) error {
+	objects := make([]*s3.ObjectIdentifier, 0, 2*len(preview.Sizes))
+	for size := range preview.Sizes {
+		objects = append(objects,
+			&s3.ObjectIdentifier{Key: aws.String(previewKey(doc, size))},
+			&s3.ObjectIdentifier{Key: aws.String(legacyPreviewKey(doc, size))},
+		)
+	}
+
+	object := s3.DeleteObjectsInput{
+		Bucket: aws.String(r.cfg.Bucket),
+		Delete: &s3.Delete{Objects: objects, Quiet: aws.Bool(true)},
+	}
+
+	logrus.Debugf("[object input]: %+v", object)
+	out, err := r.s3.DeleteObjectsWithContext(ctx, &object)
+	if err != nil {
+		return err
+	}
+	logrus.Debugf("[object output]: %+v", out)
+
+	if len(out.Errors) > 0 {
+		return errors.New(aws.StringValue(out.Errors[0].Message))
+	}
+
+	return nil
+}
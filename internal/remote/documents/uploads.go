@@ -0,0 +1,109 @@
+package documents
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+)
+
+// sessionKey is the key of the document the upload session will become.
+func sessionKey(session dto.UploadSession) string {
+	return key(dto.Document{CreatedAt: session.CreatedAt, Path: session.Path, Extension: session.Extension})
+}
+
+func (r *Remote) InitiateUpload(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error) {
+	object := s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(r.cfg.Bucket),
+		Key:         aws.String(sessionKey(session)),
+		ContentType: aws.String(session.Type),
+		ACL:         aws.String("private"),
+		Metadata: map[string]*string{
+			"x-amz-meta-my-key": aws.String(session.Path.String()),
+		},
+	}
+
+	logrus.Debugf("[object input]: %+v", object)
+	out, err := r.s3.CreateMultipartUploadWithContext(ctx, &object)
+	if err != nil {
+		return session, err
+	}
+	logrus.Debugf("[object output]: %+v", out)
+
+	session.UploadID = aws.StringValue(out.UploadId)
+
+	return session, nil
+}
+
+func (r *Remote) UploadPart(ctx context.Context, session dto.UploadSession, part dto.UploadPart) (dto.UploadPart, error) {
+	object := s3.UploadPartInput{
+		Bucket:        aws.String(r.cfg.Bucket),
+		Key:           aws.String(sessionKey(session)),
+		UploadId:      aws.String(session.UploadID),
+		PartNumber:    aws.Int64(part.Number),
+		ContentLength: aws.Int64(part.Size),
+		Body:          part.RequestContent,
+	}
+
+	logrus.Debugf("[object input]: %+v", object)
+	out, err := r.s3.UploadPartWithContext(ctx, &object)
+	if err != nil {
+		return part, err
+	}
+	logrus.Debugf("[object output]: %+v", out)
+
+	part.ETag = aws.StringValue(out.ETag)
+
+	return part, nil
+}
+
+func (r *Remote) CompleteUpload(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error) {
+	parts := make([]*s3.CompletedPart, 0, len(session.Parts))
+	for _, part := range session.Parts {
+		parts = append(parts, &s3.CompletedPart{
+			ETag:       aws.String(part.ETag),
+			PartNumber: aws.Int64(part.Number),
+		})
+	}
+
+	object := s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(r.cfg.Bucket),
+		Key:             aws.String(sessionKey(session)),
+		UploadId:        aws.String(session.UploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	}
+
+	logrus.Debugf("[object input]: %+v", object)
+	out, err := r.s3.CompleteMultipartUploadWithContext(ctx, &object)
+	if err != nil {
+		return session, err
+	}
+	logrus.Debugf("[object output]: %+v", out)
+
+	return session, nil
+}
+
+func (r *Remote) AbortUpload(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error) {
+	object := s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(r.cfg.Bucket),
+		Key:      aws.String(sessionKey(session)),
+		UploadId: aws.String(session.UploadID),
+	}
+
+	logrus.Debugf("[object input]: %+v", object)
+	out, err := r.s3.AbortMultipartUploadWithContext(ctx, &object)
+	if err != nil {
+		// an upload aborted or completed before has nothing left to discard
+		var failure awserr.Error
+		if errors.As(err, &failure) && failure.Code() == s3.ErrCodeNoSuchUpload {
+			return session, nil
+		}
+		return session, err
+	}
+	logrus.Debugf("[object output]: %+v", out)
+
+	return session, nil
+}
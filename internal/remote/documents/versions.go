@@ -0,0 +1,192 @@
+package documents
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+)
+
+const (
+	// copyLimit is the largest object a single CopyObject can copy
+	copyLimit = 5 << 30
+	// copyPart is the size of the parts larger objects are copied in, it keeps
+	// the largest object spaces store under the limit of 10000 parts
+	copyPart = 1 << 30
+)
+
+// versionKey places every version next to the current object of its document,
+// so one document prefix holds its whole history. A version with a checksum is a blob.
+func versionKey(doc dto.Document, version dto.DocumentVersion) string {
+	if version.Checksum != "" {
+		return blobKey(version.Checksum)
+	}
+	return fmt.Sprintf("%s/%s/v%d%s", doc.CreatedAt.Format("2006-01-02"), doc.Path.String(), version.Version, version.Extension)
+}
+
+func (r *Remote) UploadVersion(ctx context.Context, doc dto.Document, version dto.DocumentVersion) (dto.DocumentVersion, error) {
+	object := s3.PutObjectInput{
+		Bucket:      aws.String(r.cfg.Bucket),
+		Key:         aws.String(versionKey(doc, version)),
+		Body:        version.RequestContent,
+		ContentType: aws.String(version.Type),
+		ACL:         aws.String("private"),
+		Metadata: map[string]*string{
+			"x-amz-meta-my-key": aws.String(doc.Path.String()),
+		},
+	}
+
+	logrus.Debugf("[object input]: %+v", object)
+	out, err := r.s3.PutObjectWithContext(ctx, &object)
+	if err != nil {
+		return version, err
+	}
+	logrus.Debugf("[object output]: %+v", out)
+
+	return version, nil
+}
+
+func (r *Remote) GetVersion(ctx context.Context, doc dto.Document, version dto.DocumentVersion) (dto.DocumentVersion, error) {
+	var err error
+	version.ResponseContent, version.Download, err = r.get(ctx, versionKey(doc, version), version.Download)
+	if err != nil {
+		return version, err
+	}
+
+	return version, nil
+}
+
+func (r *Remote) SnapshotVersion(ctx context.Context, doc dto.Document, version dto.DocumentVersion) (dto.DocumentVersion, error) {
+	return version, r.copy(ctx, key(doc), versionKey(doc, version))
+}
+
+func (r *Remote) PromoteVersion(ctx context.Context, doc dto.Document, version dto.DocumentVersion) (dto.Document, error) {
+	return doc, r.copy(ctx, versionKey(doc, version), key(doc))
+}
+
+func (r *Remote) DeleteVersion(ctx context.Context, doc dto.Document, version dto.DocumentVersion) (dto.DocumentVersion, error) {
+	object := s3.DeleteObjectInput{
+		Bucket: aws.String(r.cfg.Bucket),
+		Key:    aws.String(versionKey(doc, version)),
+	}
+
+	logrus.Debugf("[object input]: %+v", object)
+	out, err := r.s3.DeleteObjectWithContext(ctx, &object)
+	if err != nil {
+		return version, err
+	}
+	logrus.Debugf("[object output]: %+v", out)
+
+	return version, nil
+}
+
+func (r *Remote) copy(ctx context.Context, from, to string) error {
+	// a document and its version may share a blob
+	if from == to {
+		return nil
+	}
+
+	head, err := r.s3.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(r.cfg.Bucket),
+		Key:    aws.String(from),
+	})
+	if err != nil {
+		return err
+	}
+	if aws.Int64Value(head.ContentLength) > copyLimit {
+		return r.copyParts(ctx, from, to, head)
+	}
+
+	object := s3.CopyObjectInput{
+		Bucket:     aws.String(r.cfg.Bucket),
+		CopySource: aws.String(fmt.Sprintf("%s/%s", r.cfg.Bucket, from)),
+		Key:        aws.String(to),
+		ACL:        aws.String("private"),
+	}
+
+	logrus.Debugf("[object input]: %+v", object)
+	out, err := r.s3.CopyObjectWithContext(ctx, &object)
+	if err != nil {
+		return err
+	}
+	logrus.Debugf("[object output]: %+v", out)
+
+	return nil
+}
+
+// copyParts copies an object too large for CopyObject as a multipart upload of ranges
+// of the source, the upload is aborted when a part fails so no parts are left behind.
+func (r *Remote) copyParts(ctx context.Context, from, to string, head *s3.HeadObjectOutput) error {
+	object := s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(r.cfg.Bucket),
+		Key:         aws.String(to),
+		ContentType: head.ContentType,
+		ACL:         aws.String("private"),
+		Metadata:    head.Metadata,
+	}
+
+	logrus.Debugf("[object input]: %+v", object)
+	upload, err := r.s3.CreateMultipartUploadWithContext(ctx, &object)
+	if err != nil {
+		return err
+	}
+	logrus.Debugf("[object output]: %+v", upload)
+
+	size := aws.Int64Value(head.ContentLength)
+	parts := make([]*s3.CompletedPart, 0, size/copyPart+1)
+	for start := int64(0); start < size; start += copyPart {
+		end := start + copyPart - 1
+		if end >= size {
+			end = size - 1
+		}
+
+		part := s3.UploadPartCopyInput{
+			Bucket:          aws.String(r.cfg.Bucket),
+			CopySource:      aws.String(fmt.Sprintf("%s/%s", r.cfg.Bucket, from)),
+			CopySourceRange: aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+			Key:             aws.String(to),
+			PartNumber:      aws.Int64(int64(len(parts) + 1)),
+			UploadId:        upload.UploadId,
+		}
+
+		logrus.Debugf("[object input]: %+v", part)
+		out, err := r.s3.UploadPartCopyWithContext(ctx, &part)
+		if err != nil {
+			r.abortCopy(ctx, to, upload.UploadId)
+			return err
+		}
+		logrus.Debugf("[object output]: %+v", out)
+
+		parts = append(parts, &s3.CompletedPart{ETag: out.CopyPartResult.ETag, PartNumber: part.PartNumber})
+	}
+
+	complete := s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(r.cfg.Bucket),
+		Key:             aws.String(to),
+		UploadId:        upload.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	}
+
+	logrus.Debugf("[object input]: %+v", complete)
+	out, err := r.s3.CompleteMultipartUploadWithContext(ctx, &complete)
+	if err != nil {
+		r.abortCopy(ctx, to, upload.UploadId)
+		return err
+	}
+	logrus.Debugf("[object output]: %+v", out)
+
+	return nil
+}
+
+func (r *Remote) abortCopy(ctx context.Context, key string, uploadID *string) {
+	_, err := r.s3.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(r.cfg.Bucket),
+		Key:      aws.String(key),
+		UploadId: uploadID,
+	})
+	if err != nil {
+		logrus.Errorf("[abort copy error]: %+v - %+v", key, err)
+	}
+}
@@ -0,0 +1,333 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: remote.go
+
+// Package mock_remote is a generated GoMock package.
+package mock_remote
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+)
+
+// MockDocumentsRemote is a mock of DocumentsRemote interface.
+type MockDocumentsRemote struct {
+	ctrl     *gomock.Controller
+	recorder *MockDocumentsRemoteMockRecorder
+}
+
+// MockDocumentsRemoteMockRecorder is the mock recorder for MockDocumentsRemote.
+type MockDocumentsRemoteMockRecorder struct {
+	mock *MockDocumentsRemote
+}
+
+// NewMockDocumentsRemote creates a new mock instance.
+func NewMockDocumentsRemote(ctrl *gomock.Controller) *MockDocumentsRemote {
+	mock := &MockDocumentsRemote{ctrl: ctrl}
+	mock.recorder = &MockDocumentsRemoteMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDocumentsRemote) EXPECT() *MockDocumentsRemoteMockRecorder {
+	return m.recorder
+}
+
+// AbortUpload mocks base method.
+func (m *MockDocumentsRemote) AbortUpload(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AbortUpload", ctx, session)
+	ret0, _ := ret[0].(dto.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AbortUpload indicates an expected call of AbortUpload.
+func (mr *MockDocumentsRemoteMockRecorder) AbortUpload(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AbortUpload", reflect.TypeOf((*MockDocumentsRemote)(nil).AbortUpload), ctx, session)
+}
+
+// CompleteUpload mocks base method.
+func (m *MockDocumentsRemote) CompleteUpload(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteUpload", ctx, session)
+	ret0, _ := ret[0].(dto.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteUpload indicates an expected call of CompleteUpload.
+func (mr *MockDocumentsRemoteMockRecorder) CompleteUpload(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteUpload", reflect.TypeOf((*MockDocumentsRemote)(nil).CompleteUpload), ctx, session)
+}
+
+// Copy mocks base method.
+func (m *MockDocumentsRemote) Copy(ctx context.Context, from string, to dto.Document) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Copy", ctx, from, to)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Copy indicates an expected call of Copy.
+func (mr *MockDocumentsRemoteMockRecorder) Copy(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Copy", reflect.TypeOf((*MockDocumentsRemote)(nil).Copy), ctx, from, to)
+}
+
+// Delete mocks base method.
+func (m *MockDocumentsRemote) Delete(ctx context.Context, doc dto.Document) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, doc)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDocumentsRemoteMockRecorder) Delete(ctx, doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDocumentsRemote)(nil).Delete), ctx, doc)
+}
+
+// DeleteVersion mocks base method.
+func (m *MockDocumentsRemote) DeleteVersion(ctx context.Context, doc dto.Document, version dto.DocumentVersion) (dto.DocumentVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVersion", ctx, doc, version)
+	ret0, _ := ret[0].(dto.DocumentVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteVersion indicates an expected call of DeleteVersion.
+func (mr *MockDocumentsRemoteMockRecorder) DeleteVersion(ctx, doc, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVersion", reflect.TypeOf((*MockDocumentsRemote)(nil).DeleteVersion), ctx, doc, version)
+}
+
+// Exists mocks base method.
+func (m *MockDocumentsRemote) Exists(ctx context.Context, key string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", ctx, key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockDocumentsRemoteMockRecorder) Exists(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockDocumentsRemote)(nil).Exists), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockDocumentsRemote) Get(ctx context.Context, doc dto.Document) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, doc)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockDocumentsRemoteMockRecorder) Get(ctx, doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDocumentsRemote)(nil).Get), ctx, doc)
+}
+
+// GetPreview mocks base method.
+func (m *MockDocumentsRemote) GetPreview(ctx context.Context, doc dto.Document, size string) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreview", ctx, doc, size)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreview indicates an expected call of GetPreview.
+func (mr *MockDocumentsRemoteMockRecorder) GetPreview(ctx, doc, size interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreview", reflect.TypeOf((*MockDocumentsRemote)(nil).GetPreview), ctx, doc, size)
+}
+
+// GetVersion mocks base method.
+func (m *MockDocumentsRemote) GetVersion(ctx context.Context, doc dto.Document, version dto.DocumentVersion) (dto.DocumentVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersion", ctx, doc, version)
+	ret0, _ := ret[0].(dto.DocumentVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersion indicates an expected call of GetVersion.
+func (mr *MockDocumentsRemoteMockRecorder) GetVersion(ctx, doc, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersion", reflect.TypeOf((*MockDocumentsRemote)(nil).GetVersion), ctx, doc, version)
+}
+
+// InitiateUpload mocks base method.
+func (m *MockDocumentsRemote) InitiateUpload(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InitiateUpload", ctx, session)
+	ret0, _ := ret[0].(dto.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InitiateUpload indicates an expected call of InitiateUpload.
+func (mr *MockDocumentsRemoteMockRecorder) InitiateUpload(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitiateUpload", reflect.TypeOf((*MockDocumentsRemote)(nil).InitiateUpload), ctx, session)
+}
+
+// Key mocks base method.
+func (m *MockDocumentsRemote) Key(doc dto.Document) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Key", doc)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Key indicates an expected call of Key.
+func (mr *MockDocumentsRemoteMockRecorder) Key(doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Key", reflect.TypeOf((*MockDocumentsRemote)(nil).Key), doc)
+}
+
+// List mocks base method.
+func (m *MockDocumentsRemote) List(ctx context.Context, after string, limit int64) ([]dto.StoredObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, after, limit)
+	ret0, _ := ret[0].([]dto.StoredObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockDocumentsRemoteMockRecorder) List(ctx, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDocumentsRemote)(nil).List), ctx, after, limit)
+}
+
+// Objects mocks base method.
+func (m *MockDocumentsRemote) Objects(doc dto.Document, versions []dto.DocumentVersion) []dto.StoredObject {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Objects", doc, versions)
+	ret0, _ := ret[0].([]dto.StoredObject)
+	return ret0
+}
+
+// Objects indicates an expected call of Objects.
+func (mr *MockDocumentsRemoteMockRecorder) Objects(doc, versions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Objects", reflect.TypeOf((*MockDocumentsRemote)(nil).Objects), doc, versions)
+}
+
+// PromoteVersion mocks base method.
+func (m *MockDocumentsRemote) PromoteVersion(ctx context.Context, doc dto.Document, version dto.DocumentVersion) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PromoteVersion", ctx, doc, version)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PromoteVersion indicates an expected call of PromoteVersion.
+func (mr *MockDocumentsRemoteMockRecorder) PromoteVersion(ctx, doc, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromoteVersion", reflect.TypeOf((*MockDocumentsRemote)(nil).PromoteVersion), ctx, doc, version)
+}
+
+// Remove mocks base method.
+func (m *MockDocumentsRemote) Remove(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockDocumentsRemoteMockRecorder) Remove(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockDocumentsRemote)(nil).Remove), ctx, key)
+}
+
+// SnapshotVersion mocks base method.
+func (m *MockDocumentsRemote) SnapshotVersion(ctx context.Context, doc dto.Document, version dto.DocumentVersion) (dto.DocumentVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SnapshotVersion", ctx, doc, version)
+	ret0, _ := ret[0].(dto.DocumentVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SnapshotVersion indicates an expected call of SnapshotVersion.
+func (mr *MockDocumentsRemoteMockRecorder) SnapshotVersion(ctx, doc, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapshotVersion", reflect.TypeOf((*MockDocumentsRemote)(nil).SnapshotVersion), ctx, doc, version)
+}
+
+// Upload mocks base method.
+func (m *MockDocumentsRemote) Upload(ctx context.Context, doc dto.Document) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upload", ctx, doc)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upload indicates an expected call of Upload.
+func (mr *MockDocumentsRemoteMockRecorder) Upload(ctx, doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockDocumentsRemote)(nil).Upload), ctx, doc)
+}
+
+// UploadPart mocks base method.
+func (m *MockDocumentsRemote) UploadPart(ctx context.Context, session dto.UploadSession, part dto.UploadPart) (dto.UploadPart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadPart", ctx, session, part)
+	ret0, _ := ret[0].(dto.UploadPart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadPart indicates an expected call of UploadPart.
+func (mr *MockDocumentsRemoteMockRecorder) UploadPart(ctx, session, part interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadPart", reflect.TypeOf((*MockDocumentsRemote)(nil).UploadPart), ctx, session, part)
+}
+
+// UploadPreview mocks base method.
+func (m *MockDocumentsRemote) UploadPreview(ctx context.Context, doc dto.Document, size string, content []byte) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadPreview", ctx, doc, size, content)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadPreview indicates an expected call of UploadPreview.
+func (mr *MockDocumentsRemoteMockRecorder) UploadPreview(ctx, doc, size, content interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadPreview", reflect.TypeOf((*MockDocumentsRemote)(nil).UploadPreview), ctx, doc, size, content)
+}
+
+// UploadVersion mocks base method.
+func (m *MockDocumentsRemote) UploadVersion(ctx context.Context, doc dto.Document, version dto.DocumentVersion) (dto.DocumentVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadVersion", ctx, doc, version)
+	ret0, _ := ret[0].(dto.DocumentVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadVersion indicates an expected call of UploadVersion.
+func (mr *MockDocumentsRemoteMockRecorder) UploadVersion(ctx, doc, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadVersion", reflect.TypeOf((*MockDocumentsRemote)(nil).UploadVersion), ctx, doc, version)
+}
@@ -6,18 +6,55 @@ import (
 	"gitlab.com/a5805/ondeu/ondeu-back/internal/remote/documents"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
-	"time"
 )
 
+//go:generate mockgen -source=remote.go -destination=mocks/mock.go
+
 type DocumentsRemote interface {
 	// Upload uploads a document to spaces
 	Upload(ctx context.Context, doc dto.Document) (dto.Document, error)
 	// Get returns a document from spaces
 	Get(ctx context.Context, doc dto.Document) (dto.Document, error)
+	// Copy copies an object in spaces to the content of a document
+	Copy(ctx context.Context, from string, to dto.Document) (dto.Document, error)
 	// Delete deletes a document from spaces
 	Delete(ctx context.Context, doc dto.Document) (dto.Document, error)
-	// Share create share link for a document
-	Share(ctx context.Context, doc dto.Document, duration time.Duration) (dto.Document, error)
+
+	// UploadPreview uploads a thumbnail of a size next to a document in spaces
+	UploadPreview(ctx context.Context, doc dto.Document, size string, content []byte) (dto.Document, error)
+	// GetPreview returns a thumbnail of a size of a document from spaces
+	GetPreview(ctx context.Context, doc dto.Document, size string) (dto.Document, error)
+
+	// UploadVersion uploads a new version of a document to spaces
+	UploadVersion(ctx context.Context, doc dto.Document, version dto.DocumentVersion) (dto.DocumentVersion, error)
+	// GetVersion returns a specific version of a document from spaces
+	GetVersion(ctx context.Context, doc dto.Document, version dto.DocumentVersion) (dto.DocumentVersion, error)
+	// SnapshotVersion copies the current content of a document into a version
+	SnapshotVersion(ctx context.Context, doc dto.Document, version dto.DocumentVersion) (dto.DocumentVersion, error)
+	// PromoteVersion makes a version the current content of a document
+	PromoteVersion(ctx context.Context, doc dto.Document, version dto.DocumentVersion) (dto.Document, error)
+	// DeleteVersion deletes a version of a document from spaces
+	DeleteVersion(ctx context.Context, doc dto.Document, version dto.DocumentVersion) (dto.DocumentVersion, error)
+
+	// InitiateUpload starts a multipart upload for an upload session
+	InitiateUpload(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error)
+	// UploadPart uploads a numbered chunk of an upload session
+	UploadPart(ctx context.Context, session dto.UploadSession, part dto.UploadPart) (dto.UploadPart, error)
+	// CompleteUpload assembles the uploaded parts into the document object
+	CompleteUpload(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error)
+	// AbortUpload discards the uploaded parts of an upload session
+	AbortUpload(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error)
+
+	// List returns objects in spaces ordered by key, starting after one
+	List(ctx context.Context, after string, limit int64) ([]dto.StoredObject, error)
+	// Key names the object in spaces holding the content of a document
+	Key(doc dto.Document) string
+	// Exists reports whether an object is in spaces by its key
+	Exists(ctx context.Context, key string) (bool, error)
+	// Remove deletes an object from spaces by its key
+	Remove(ctx context.Context, key string) error
+	// Objects names the objects in spaces a document and its versions point at
+	Objects(doc dto.Document, versions []dto.DocumentVersion) []dto.StoredObject
 }
 
 type Remote struct {
@@ -0,0 +1,144 @@
+package access
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (fm *Repository) Grant(ctx context.Context, access dto.TreeAccess) (dto.TreeAccess, error) {
+	logrus.Debugf("[input]: %+v", access)
+
+	// granting the same subject again changes its level
+	return access, fm.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tree_id"}, {Name: "subject"}, {Name: "subject_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"level", "updated_at"}),
+		}).
+		Omit("Tree").
+		Create(&access).
+		Error
+}
+
+func (fm *Repository) Revoke(ctx context.Context, access dto.TreeAccess) (dto.TreeAccess, error) {
+	logrus.Debugf("[input]: %+v", access)
+
+	tx := fm.db.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("id = ?", access.ID).
+		Where("tree_id = ?", access.TreeID).
+		Delete(&access)
+	if tx.Error != nil {
+		return access, tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return access, gorm.ErrRecordNotFound
+	}
+
+	return access, nil
+}
+
+func (fm *Repository) List(ctx context.Context, treeID uint) ([]dto.TreeAccess, error) {
+	var entries []dto.TreeAccess
+	if err := fm.db.WithContext(ctx).
+		Where("tree_id = ?", treeID).
+		Order("id").
+		Find(&entries).
+		Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (fm *Repository) Levels(ctx context.Context, treeID uint, userID string, roles []string) ([]string, error) {
+	logrus.Debugf("[input]: %+v, %+v, %+v", treeID, userID, roles)
+
+	// entries are inherited, so the ones on every ancestor of the tree count
+	sql := `WITH RECURSIVE ancestors AS (
+		SELECT t1.id, t1.parent_id
+		FROM   trees t1
+		WHERE  t1.id = ? and t1.deleted_at is null
+
+		UNION  ALL
+		SELECT t2.id, t2.parent_id
+		FROM trees t2 JOIN ancestors a ON t2.id = a.parent_id and t2.deleted_at is null
+	) SELECT ta.level FROM tree_accesses ta JOIN ancestors a ON ta.tree_id = a.id
+	WHERE ` + subjects("ta")
+
+	var levels []string
+	if err := fm.db.WithContext(ctx).
+		Raw(sql, treeID, userID, roles, userID, userID).
+		Scan(&levels).
+		Error; err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+func (fm *Repository) Shared(ctx context.Context, userID string, roles []string) ([]dto.TreeAccess, error) {
+	logrus.Debugf("[input]: %+v, %+v", userID, roles)
+
+	var entries []dto.TreeAccess
+	if err := fm.db.WithContext(ctx).
+		Model(dto.TreeAccess{}).
+		Preload("Tree").
+		Joins("JOIN trees ON trees.id = tree_accesses.tree_id").
+		Where("trees.deleted_at is null").
+		Where("trees.user_id <> ?", userID).
+		Where(subjects("tree_accesses"), userID, roles, userID, userID).
+		Order("tree_accesses.id").
+		Find(&entries).
+		Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (fm *Repository) SharedTrees(ctx context.Context, userID string, roles []string) ([]uint, error) {
+	logrus.Debugf("[input]: %+v, %+v", userID, roles)
+
+	sql := `WITH RECURSIVE shared AS (
+		SELECT t1.id
+		FROM   trees t1 JOIN tree_accesses ta ON ta.tree_id = t1.id
+		WHERE  t1.user_id <> ? and t1.deleted_at is null and ` + subjects("ta") + `
+
+		UNION
+		SELECT t2.id
+		FROM trees t2 JOIN shared s ON t2.parent_id = s.id and t2.deleted_at is null
+	) SELECT id FROM shared;`
+
+	var ids []uint
+	if err := fm.db.WithContext(ctx).
+		Raw(sql, userID, userID, roles, userID, userID).
+		Scan(&ids).
+		Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// subjects matches entries granted to a user directly, through one of the roles
+// or through a group the user owns or is a member of
+func subjects(table string) string {
+	return `((` + table + `.subject = 'user' and ` + table + `.subject_id = ?)
+		or (` + table + `.subject = 'role' and ` + table + `.subject_id in (?))
+		or (` + table + `.subject = 'group' and ` + table + `.subject_id in (
+			SELECT CAST(g.id AS text) FROM groups g WHERE g.user_id = ? and g.deleted_at is null
+			UNION
+			SELECT CAST(gm.group_id AS text) FROM group_members gm
+			JOIN groups g ON g.id = gm.group_id and g.deleted_at is null
+			WHERE gm.user_id = ?)))`
+}
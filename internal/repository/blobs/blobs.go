@@ -0,0 +1,162 @@
+package blobs
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (fm *Repository) Acquire(ctx context.Context, blob dto.Blob) (dto.Blob, error) {
+	logrus.Debugf("[input]: %+v", blob)
+
+	sql := `insert into blobs (checksum, size, refs, created_at, updated_at)
+			values (?, ?, 1, now(), now())
+			on conflict (checksum) do update set refs = blobs.refs + 1, updated_at = now()
+			returning *;`
+
+	err := fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw(sql, blob.Checksum, blob.Size).Scan(&blob).Error; err != nil {
+			return err
+		}
+
+		// content stored anew must outlive a removal queued when it was last released
+		if blob.Refs == 1 {
+			return tx.Where("checksum = ?", blob.Checksum).Delete(&dto.ObjectDeletion{}).Error
+		}
+		return nil
+	})
+
+	return blob, err
+}
+
+func (fm *Repository) Release(ctx context.Context, checksum string) error {
+	logrus.Debugf("[input]: %+v", checksum)
+
+	return fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return Release(tx, []string{checksum})
+	})
+}
+
+// Stored records that the object of a blob reached spaces, references acquired later reuse it
+func (fm *Repository) Stored(ctx context.Context, checksum string) error {
+	logrus.Debugf("[input]: %+v", checksum)
+
+	return fm.db.WithContext(ctx).
+		Model(&dto.Blob{}).
+		Where("checksum = ?", checksum).
+		Update("stored", true).
+		Error
+}
+
+func (fm *Repository) List(ctx context.Context, after string, limit int) ([]dto.Blob, error) {
+	var blobs []dto.Blob
+	if err := fm.db.WithContext(ctx).
+		Where("checksum > ?", after).
+		Order("checksum").
+		Limit(limit).
+		Find(&blobs).
+		Error; err != nil {
+		return nil, err
+	}
+	return blobs, nil
+}
+
+func (fm *Repository) Verified(ctx context.Context, blob dto.Blob) error {
+	logrus.Debugf("[input]: %+v", blob.Checksum)
+
+	return fm.db.WithContext(ctx).
+		Model(&dto.Blob{}).
+		Where("checksum = ?", blob.Checksum).
+		Update("verified_at", time.Now()).
+		Error
+}
+
+// Drop forgets a blob no document or version points at and queues its object for removal.
+// A blob referenced again, or acquired since before, is kept and false is returned.
+func (fm *Repository) Drop(ctx context.Context, checksum string, before time.Time) (bool, error) {
+	logrus.Debugf("[input]: %+v", checksum)
+
+	dropped := false
+	err := fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var blobs []dto.Blob
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("checksum = ?", checksum).
+			Find(&blobs).
+			Error; err != nil {
+			return err
+		}
+		if len(blobs) > 0 && !blobs[0].UpdatedAt.Before(before) {
+			return nil
+		}
+
+		var refs int64
+		if err := tx.Raw(`select (select count(*) from documents where checksum = ?) +
+			(select count(*) from document_versions where checksum = ?)`, checksum, checksum).
+			Scan(&refs).
+			Error; err != nil {
+			return err
+		}
+		if refs > 0 {
+			return nil
+		}
+
+		if err := tx.Where("checksum = ?", checksum).Delete(&dto.Blob{}).Error; err != nil {
+			return err
+		}
+
+		dropped = true
+		return tx.Create(&dto.ObjectDeletion{Checksum: checksum, RetryAt: time.Now()}).Error
+	})
+
+	return dropped, err
+}
+
+// Release drops one reference to every checksum inside tx, a checksum listed
+// twice loses two. Blobs left without references are queued for removal from spaces.
+func Release(tx *gorm.DB, checksums []string) error {
+	if len(checksums) == 0 {
+		return nil
+	}
+
+	for _, checksum := range checksums {
+		if err := tx.Model(&dto.Blob{}).
+			Where("checksum = ?", checksum).
+			Updates(map[string]interface{}{"refs": gorm.Expr("refs - 1"), "updated_at": time.Now()}).
+			Error; err != nil {
+			return err
+		}
+	}
+
+	var released []dto.Blob
+	if err := tx.Clauses(clause.Returning{}).
+		Where("checksum in ? and refs <= 0", checksums).
+		Delete(&released).
+		Error; err != nil {
+		return err
+	}
+
+	if len(released) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	queue := make([]dto.ObjectDeletion, 0, len(released))
+	for _, blob := range released {
+		queue = append(queue, dto.ObjectDeletion{Checksum: blob.Checksum, RetryAt: now})
+	}
+
+	return tx.Create(&queue).Error
+}
@@ -0,0 +1,206 @@
+package deletions
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/blobs"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/quotas"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"time"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (fm *Repository) ListDue(ctx context.Context, limit int) ([]dto.ObjectDeletion, error) {
+	var deletions []dto.ObjectDeletion
+	if err := fm.db.WithContext(ctx).
+		Where("retry_at <= ?", time.Now()).
+		Order("id").
+		Limit(limit).
+		Find(&deletions).
+		Error; err != nil {
+		return nil, err
+	}
+	return deletions, nil
+}
+
+func (fm *Repository) ListAfter(ctx context.Context, afterID uint, limit int) ([]dto.ObjectDeletion, error) {
+	var deletions []dto.ObjectDeletion
+	if err := fm.db.WithContext(ctx).
+		Where("id > ?", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&deletions).
+		Error; err != nil {
+		return nil, err
+	}
+	return deletions, nil
+}
+
+func (fm *Repository) Done(ctx context.Context, deletion dto.ObjectDeletion) error {
+	logrus.Debugf("[input]: %+v", deletion)
+
+	return fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&dto.ObjectDeletion{}, deletion.ID).Error; err != nil {
+			return err
+		}
+
+		if deletion.DocumentID == 0 {
+			return nil
+		}
+
+		// the document goes with the last of its objects
+		return tx.Unscoped().
+			Where("id = ?", deletion.DocumentID).
+			Where("state = ?", modules.DocumentDeleting).
+			Where("not exists (?)", tx.Model(dto.ObjectDeletion{}).Select("1").Where("document_id = ?", deletion.DocumentID)).
+			Delete(&dto.Document{}).Error
+	})
+}
+
+func (fm *Repository) Retry(ctx context.Context, deletion dto.ObjectDeletion) error {
+	logrus.Debugf("[input]: %+v", deletion)
+
+	return fm.db.WithContext(ctx).
+		Model(&deletion).
+		Select("attempts", "last_error", "retry_at").
+		Updates(&deletion).
+		Error
+}
+
+// Purge deletes the versions and tree links of documents inside tx, marks them deleting
+// and queues their objects for removal from spaces. A deleting document is removed once
+// its objects are gone. It is shared by every repository that permanently deletes documents.
+func Purge(tx *gorm.DB, ids []uint) (dto.Purge, error) {
+	var purge dto.Purge
+	if len(ids) == 0 {
+		return purge, nil
+	}
+
+	// a document already deleting gave back its usage and blobs
+	var documents []dto.Document
+	if err := tx.Unscoped().
+		Where("id in ?", ids).
+		Where("state <> ?", modules.DocumentDeleting).
+		Find(&documents).Error; err != nil {
+		return purge, err
+	}
+
+	ids = make([]uint, 0, len(documents))
+	for _, doc := range documents {
+		ids = append(ids, doc.ID)
+	}
+	if len(ids) == 0 {
+		return purge, nil
+	}
+
+	var versions []dto.DocumentVersion
+	if err := tx.Where("document_id in ?", ids).Find(&versions).Error; err != nil {
+		return purge, err
+	}
+
+	// the current content of a document with history is one of its versions
+	versioned := make(map[uint]bool, len(versions))
+	for _, version := range versions {
+		versioned[version.DocumentID] = true
+	}
+
+	now := time.Now()
+	byID := make(map[uint]dto.Document, len(documents))
+	queue := make([]dto.ObjectDeletion, 0, len(documents)+len(versions))
+	checksums := make([]string, 0)
+	usage := make(map[string]int64)
+	for _, doc := range documents {
+		byID[doc.ID] = doc
+		purge.Documents++
+		// a missing document gave back the usage of its content when it was quarantined
+		if !versioned[doc.ID] && doc.State != modules.DocumentMissing {
+			purge.Bytes += doc.Size
+			usage[doc.UserID] += doc.Size
+		}
+		if doc.Checksum != "" {
+			checksums = append(checksums, doc.Checksum)
+		}
+		// the own key of a document is queued even when it points at a blob,
+		// a document uploaded before checksums keeps its object after a new version
+		queue = append(queue, dto.ObjectDeletion{
+			DocumentID: doc.ID,
+			UploadedAt: doc.CreatedAt,
+			Path:       doc.Path,
+			Extension:  doc.Extension,
+			RetryAt:    now,
+		})
+	}
+
+	for _, version := range versions {
+		doc, ok := byID[version.DocumentID]
+		if !ok {
+			continue
+		}
+		purge.Bytes += version.Size
+		usage[doc.UserID] += version.Size
+		if version.Checksum != "" {
+			checksums = append(checksums, version.Checksum)
+			continue
+		}
+		queue = append(queue, dto.ObjectDeletion{
+			DocumentID: doc.ID,
+			UploadedAt: doc.CreatedAt,
+			Path:       doc.Path,
+			Extension:  version.Extension,
+			Version:    version.Version,
+			RetryAt:    now,
+		})
+	}
+
+	if len(queue) > 0 {
+		if err := tx.Create(&queue).Error; err != nil {
+			return purge, err
+		}
+	}
+
+	if err := blobs.Release(tx, checksums); err != nil {
+		return purge, err
+	}
+
+	for userID, bytes := range usage {
+		if err := quotas.Add(tx, userID, -bytes); err != nil {
+			return purge, err
+		}
+	}
+
+	if err := tx.Where("document_id in ?", ids).Delete(&dto.DocumentVersion{}).Error; err != nil {
+		return purge, err
+	}
+
+	if err := tx.Table("tree_documents").
+		Where("document_id in ?", ids).
+		Delete(&dto.TreeDocuments{}).Error; err != nil {
+		return purge, err
+	}
+
+	// nothing is stored for a deleting document anymore, whatever was queued for it
+	for _, queued := range []interface{}{&dto.OutboxEvent{}, &dto.DocumentPreview{}, &dto.DocumentText{}} {
+		if err := tx.Where("document_id in ?", ids).Delete(queued).Error; err != nil {
+			return purge, err
+		}
+	}
+
+	if err := tx.Unscoped().Model(dto.Document{}).
+		Where("id in ?", ids).
+		Update("state", modules.DocumentDeleting).Error; err != nil {
+		return purge, err
+	}
+
+	return purge, nil
+}
@@ -2,9 +2,17 @@ package documents
 
 import (
 	"context"
+	"errors"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/deletions"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/quotas"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/texts"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
 )
 
 type Repository struct {
@@ -38,6 +46,7 @@ func (fm *Repository) Get(ctx context.Context, doc dto.Document) (dto.Document,
 	if err := fm.db.WithContext(ctx).
 		Model(dto.Document{}).
 		Where("user_id = ?", doc.UserID).
+		Where("state = ?", modules.DocumentReady).
 		Find(&doc).Count(&documentCount).Error; err != nil {
 		logrus.Errorf("[error]: %+v", err)
 	}
@@ -51,29 +60,110 @@ func (fm *Repository) Get(ctx context.Context, doc dto.Document) (dto.Document,
 
 func (fm *Repository) Create(ctx context.Context, doc dto.Document) (dto.Document, error) {
 	logrus.Debugf("[input]: %+v", doc)
-	if err := fm.db.WithContext(ctx).
-		Model(dto.Document{}).
-		Create(&doc).
-		Error; err != nil {
+
+	return fm.create(ctx, doc, dto.OutboxEvent{Kind: modules.OutboxStore})
+}
+
+func (fm *Repository) Duplicate(ctx context.Context, doc dto.Document, source string) (dto.Document, error) {
+	logrus.Debugf("[input]: %+v - %+v", doc, source)
+
+	return fm.create(ctx, doc, dto.OutboxEvent{Kind: modules.OutboxCopy, Source: source})
+}
+
+// create writes a document linked to its tree, an uploading document
+// with the outbox event settling it when the request doesn't
+func (fm *Repository) create(ctx context.Context, doc dto.Document, event dto.OutboxEvent) (dto.Document, error) {
+	// the column defaults to clean for documents stored before scanning,
+	// new content is blocked until it is scanned
+	if doc.ScanStatus == "" {
+		doc.ScanStatus = modules.ScanPending
+	}
+	if doc.State == "" {
+		doc.State = modules.DocumentReady
+	}
+
+	err := fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(dto.Document{}).Create(&doc).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Table("tree_documents").
+			Create(&dto.TreeDocuments{TreeID: doc.TreeID, DocumentID: doc.ID}).
+			Error; err != nil {
+			return err
+		}
+
+		if err := quotas.Use(tx, doc.UserID, doc.Size); err != nil {
+			return err
+		}
+
+		if doc.State == modules.DocumentUploading {
+			event.DocumentID = doc.ID
+			event.RetryAt = time.Now()
+			return tx.Create(&event).Error
+		}
+
+		return texts.Queue(tx, doc.ID)
+	})
+
+	return doc, err
+}
+
+func (fm *Repository) Ready(ctx context.Context, doc dto.Document) (dto.Document, error) {
+	logrus.Debugf("[input]: %+v", doc)
+
+	err := fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Unscoped().Model(dto.Document{}).
+			Where("id = ?", doc.ID).
+			Where("state = ?", modules.DocumentUploading).
+			Update("state", modules.DocumentReady)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Where("document_id = ?", doc.ID).Delete(&dto.OutboxEvent{}).Error; err != nil {
+			return err
+		}
+
+		return texts.Queue(tx, doc.ID)
+	})
+	if err != nil {
 		return doc, err
 	}
 
-	return doc, fm.db.WithContext(ctx).
-		Table("tree_documents").
-		Create(&dto.TreeDocuments{TreeID: doc.TreeID, DocumentID: doc.ID}).
+	doc.State = modules.DocumentReady
+	return doc, nil
+}
+
+func (fm *Repository) Find(ctx context.Context, id uint) (dto.Document, error) {
+	logrus.Debugf("[input]: %+v", id)
+
+	var doc dto.Document
+	err := fm.db.WithContext(ctx).
+		Unscoped().
+		Where("id = ?", id).
+		First(&doc).
 		Error
+
+	return doc, err
 }
 
 func (fm *Repository) ListByTree(ctx context.Context, ids []uint) ([]dto.Document, error) {
-	sql := `select * from documents d 
+	sql := `select d.*, td.tree_id, coalesce(dp.ready, false) as preview from documents d 
     		join tree_documents td 
     		on d.id = td.document_id 
-         	where td.tree_id in ?;`
+    		left join document_previews dp 
+    		on d.id = dp.document_id 
+         	where td.tree_id in ? and d.deleted_at is null and d.state = ?;`
 
 	var document []dto.Document
 	if err := fm.db.WithContext(ctx).
 		Model(dto.Document{}).
-		Raw(sql, ids).
+		Raw(sql, ids, modules.DocumentReady).
 		Scan(&document).
 		Error; err != nil {
 		return nil, err
@@ -85,12 +175,12 @@ func (fm *Repository) ListByGroups(ctx context.Context, ids []uint) ([]dto.Docum
 	sql := `select * from documents d 
     		join group_documents gd 
     		on d.id = gd.document_id 
-         	where gd.group_id in ?;`
+         	where gd.group_id in ? and d.deleted_at is null and d.state = ?;`
 
 	var document []dto.Document
 	if err := fm.db.WithContext(ctx).
 		Model(dto.Document{}).
-		Raw(sql, ids).
+		Raw(sql, ids, modules.DocumentReady).
 		Scan(&document).
 		Error; err != nil {
 		return nil, err
@@ -101,16 +191,17 @@ func (fm *Repository) ListByGroups(ctx context.Context, ids []uint) ([]dto.Docum
 func (fm *Repository) Delete(ctx context.Context, doc dto.Document) (dto.Document, error) {
 	logrus.Debugf("[input]: %+v", doc)
 
-	res := fm.db.WithContext(ctx).
+	// the tree link is kept, so the document can be restored into the same tree
+	var count int64
+	if err := fm.db.WithContext(ctx).
 		Table("tree_documents").
 		Where("tree_id = ?", doc.TreeID).
 		Where("document_id = ?", doc.ID).
-		Delete(&dto.TreeDocuments{})
-	if res.Error != nil {
-		return doc, res.Error
+		Count(&count).Error; err != nil {
+		return doc, err
 	}
 
-	if res.RowsAffected == 0 {
+	if count == 0 {
 		return doc, gorm.ErrRecordNotFound
 	}
 
@@ -144,18 +235,355 @@ func (fm *Repository) Update(ctx context.Context, doc dto.Document) (dto.Documen
 	return doc, nil
 }
 
-func (fm *Repository) FindByCondition(ctx context.Context, field, param string) ([]dto.Document, error) {
-	logrus.Debugf("[input]: %+v, %+v", field, param)
+func (fm *Repository) Move(ctx context.Context, doc dto.Document, treeID uint) (dto.Document, error) {
+	logrus.Debugf("[input]: %+v, %+v", doc, treeID)
 
-	docs := make([]dto.Document, 0)
-	tx := fm.db.WithContext(ctx).Model(dto.Document{}).Find(&docs).Where("?=%?%", field, param).Save(&docs)
-	if tx != nil {
-		logrus.Errorf("[error]: %+v", tx.Error)
+	res := fm.db.WithContext(ctx).
+		Table("tree_documents").
+		Where("tree_id = ?", doc.TreeID).
+		Where("document_id = ?", doc.ID).
+		Update("tree_id", treeID)
+	if res.Error != nil {
+		return doc, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return doc, gorm.ErrRecordNotFound
+	}
+
+	doc.TreeID = treeID
+	return doc, nil
+}
+
+func (fm *Repository) UpdateContent(ctx context.Context, doc dto.Document) (dto.Document, error) {
+	logrus.Debugf("[input]: %+v", doc)
+
+	sql := `update documents 
+			set size = ?, type = ?, extension = ?, version = ?, checksum = ?, updated_at = now() 
+			where id = ? and user_id = ?;`
+
+	err := fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var previous dto.Document
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? and user_id = ?", doc.ID, doc.UserID).
+			First(&previous).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(sql, doc.Size, doc.Type, doc.Extension, doc.Version, doc.Checksum, doc.ID, doc.UserID).Error; err != nil {
+			return err
+		}
+
+		// the own key of a document carries its extension, a new one leaves
+		// the object under the old key behind
+		if previous.Extension != doc.Extension {
+			if err := tx.Where("document_id = ? and version = 0 and checksum = '' and extension = ?", doc.ID, doc.Extension).
+				Delete(&dto.ObjectDeletion{}).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Create(&dto.ObjectDeletion{
+				DocumentID: doc.ID,
+				UploadedAt: previous.CreatedAt,
+				Path:       previous.Path,
+				Extension:  previous.Extension,
+				RetryAt:    time.Now(),
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		// the usage follows the size of the current content, unless the content
+		// is one of the versions of the document, which count for it already
+		var versions int64
+		if err := tx.Model(dto.DocumentVersion{}).
+			Where("document_id = ?", doc.ID).
+			Count(&versions).Error; err != nil {
+			return err
+		}
+		if versions == 0 {
+			if err := quotas.Use(tx, doc.UserID, doc.Size-previous.Size); err != nil {
+				return err
+			}
+		}
+
+		// the content changed, so its text is extracted again
+		return texts.Queue(tx, doc.ID)
+	})
+
+	return doc, err
+}
+
+// Deduplicate points a document kept under its own key at the blob of its checksum
+// and queues the own object for removal. A document pointing at a blob already, or
+// gone meanwhile, is reported as not found.
+func (fm *Repository) Deduplicate(ctx context.Context, doc dto.Document) (dto.Document, error) {
+	logrus.Debugf("[input]: %+v", doc)
+
+	err := fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Unscoped().Model(dto.Document{}).
+			Where("id = ? and coalesce(checksum, '') = ''", doc.ID).
+			Update("checksum", doc.Checksum)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Create(&dto.ObjectDeletion{
+			DocumentID: doc.ID,
+			UploadedAt: doc.CreatedAt,
+			Path:       doc.Path,
+			Extension:  doc.Extension,
+			RetryAt:    time.Now(),
+		}).Error
+	})
+
+	return doc, err
+}
+
+func (fm *Repository) Scanned(ctx context.Context, doc dto.Document) (dto.Document, error) {
+	logrus.Debugf("[input]: %+v", doc)
+
+	// the status is written once after creation, updates of documents leave it alone
+	tx := fm.db.WithContext(ctx).
+		Exec("update documents set scan_status = ? where id = ?;", doc.ScanStatus, doc.ID)
+	if tx.Error != nil {
+		return doc, tx.Error
 	}
 
 	if tx.RowsAffected == 0 {
-		return docs, gorm.ErrRecordNotFound
+		return doc, gorm.ErrRecordNotFound
+	}
+
+	return doc, nil
+}
+
+func (fm *Repository) ListTrash(ctx context.Context, userID string) ([]dto.Document, error) {
+	// documents trashed together with their tree are listed as part of the tree
+	sql := `select d.*, td.tree_id from documents d
+			join tree_documents td on d.id = td.document_id
+			left join trees t on t.id = td.tree_id
+			where d.user_id = ? and d.deleted_at is not null and d.state in ?
+			and (t.deleted_at is null or t.deleted_at <> d.deleted_at)
+			order by d.deleted_at desc;`
+
+	var documents []dto.Document
+	if err := fm.db.WithContext(ctx).
+		Raw(sql, userID, []string{modules.DocumentReady, modules.DocumentMissing}).
+		Scan(&documents).
+		Error; err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+func (fm *Repository) Restore(ctx context.Context, doc dto.Document) (dto.Document, error) {
+	logrus.Debugf("[input]: %+v", doc)
+
+	err := fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Unscoped().Model(dto.Document{}).
+			Where("id = ?", doc.ID).
+			Where("user_id = ?", doc.UserID).
+			Where("deleted_at is not null").
+			Where("state = ?", modules.DocumentReady).
+			Update("deleted_at", nil)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		sql := `select td.tree_id from tree_documents td
+				join trees t on t.id = td.tree_id and t.deleted_at is null
+				where td.document_id = ?;`
+
+		var treeIDs []uint
+		if err := tx.Raw(sql, doc.ID).Scan(&treeIDs).Error; err != nil {
+			return err
+		}
+
+		if len(treeIDs) > 0 {
+			doc.TreeID = treeIDs[0]
+			return nil
+		}
+
+		// the original tree is gone, fall back to the oldest root tree of the user
+		var root dto.Tree
+		if err := tx.Where("user_id = ?", doc.UserID).
+			Where("parent_id = 0").
+			Order("created_at").
+			First(&root).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return modules.ErrNoRootTree
+			}
+			return err
+		}
+
+		if err := tx.Table("tree_documents").
+			Where("document_id = ?", doc.ID).
+			Delete(&dto.TreeDocuments{}).Error; err != nil {
+			return err
+		}
+
+		doc.TreeID = root.ID
+		return tx.Table("tree_documents").
+			Create(&dto.TreeDocuments{TreeID: root.ID, DocumentID: doc.ID}).
+			Error
+	})
+	if err != nil {
+		return doc, err
+	}
+
+	return doc, fm.db.WithContext(ctx).Find(&doc).Error
+}
+
+func (fm *Repository) ListExpired(ctx context.Context, before time.Time) ([]dto.Document, error) {
+	var documents []dto.Document
+	if err := fm.db.WithContext(ctx).
+		Unscoped().
+		Where("deleted_at < ?", before).
+		Where("state in ?", []string{modules.DocumentReady, modules.DocumentMissing}).
+		Find(&documents).
+		Error; err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+func (fm *Repository) FirstCreated(ctx context.Context, from time.Time) (dto.Document, error) {
+	var doc dto.Document
+	if err := fm.db.WithContext(ctx).
+		Unscoped().
+		Where("created_at >= ?", from).
+		Order("created_at").
+		First(&doc).
+		Error; err != nil {
+		return doc, err
+	}
+	return doc, nil
+}
+
+func (fm *Repository) ListCreated(ctx context.Context, from, to time.Time, afterPath uuid.UUID, limit int) ([]dto.Document, error) {
+	var documents []dto.Document
+	if err := fm.db.WithContext(ctx).
+		Unscoped().
+		Where("created_at >= ? and created_at < ?", from, to).
+		Where("path > ?", afterPath).
+		Order("path").
+		Limit(limit).
+		Find(&documents).
+		Error; err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+func (fm *Repository) ListChecksums(ctx context.Context, after string, limit int) ([]string, error) {
+	sql := `select checksum from documents where checksum > ?
+			union
+			select checksum from document_versions where checksum > ?
+			order by checksum limit ?;`
+
+	var checksums []string
+	if err := fm.db.WithContext(ctx).
+		Raw(sql, after, after, limit).
+		Scan(&checksums).
+		Error; err != nil {
+		return nil, err
 	}
+	return checksums, nil
+}
+
+func (fm *Repository) ListByChecksums(ctx context.Context, checksums []string) ([]dto.Document, error) {
+	db := fm.db.WithContext(ctx)
+	versioned := db.Model(dto.DocumentVersion{}).Select("document_id").Where("checksum in ?", checksums)
+
+	var documents []dto.Document
+	if err := db.
+		Unscoped().
+		Where("checksum in ? or id in (?)", checksums, versioned).
+		Order("id").
+		Find(&documents).
+		Error; err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+func (fm *Repository) Quarantine(ctx context.Context, ids []uint) error {
+	logrus.Debugf("[input]: %+v", ids)
+
+	return fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var documents []dto.Document
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id in ?", ids).
+			Where("state = ?", modules.DocumentReady).
+			Find(&documents).Error; err != nil {
+			return err
+		}
+		if len(documents) == 0 {
+			return nil
+		}
+
+		ids = make([]uint, 0, len(documents))
+		for _, doc := range documents {
+			ids = append(ids, doc.ID)
+		}
+
+		// the documents wait in the trash of their owners until they are purged,
+		// one trashed before keeps its place there
+		if err := tx.Unscoped().Model(dto.Document{}).
+			Where("id in ?", ids).
+			Updates(map[string]interface{}{
+				"state":      modules.DocumentMissing,
+				"deleted_at": gorm.Expr("coalesce(deleted_at, now())"),
+			}).Error; err != nil {
+			return err
+		}
+
+		// the lost content no longer counts, versions still stored count until the purge
+		var withHistory []uint
+		if err := tx.Model(dto.DocumentVersion{}).
+			Where("document_id in ?", ids).
+			Distinct().
+			Pluck("document_id", &withHistory).Error; err != nil {
+			return err
+		}
+
+		versioned := make(map[uint]bool, len(withHistory))
+		for _, id := range withHistory {
+			versioned[id] = true
+		}
+
+		usage := make(map[string]int64)
+		for _, doc := range documents {
+			if !versioned[doc.ID] {
+				usage[doc.UserID] += doc.Size
+			}
+		}
+		for userID, bytes := range usage {
+			if err := quotas.Add(tx, userID, -bytes); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (fm *Repository) Purge(ctx context.Context, ids []uint) (dto.Purge, error) {
+	logrus.Debugf("[input]: %+v", ids)
+
+	var purge dto.Purge
+	err := fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		purge, err = deletions.Purge(tx, ids)
+		return err
+	})
 
-	return docs, nil
+	return purge, err
 }
@@ -0,0 +1,227 @@
+package documents
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSort        = "-createdAt"
+	defaultQuerySort   = "-rank"
+	defaultSearchLimit = 20
+)
+
+// sortColumns whitelists the fields a search can be ordered by
+var sortColumns = map[string]string{
+	"name":      "d.name",
+	"size":      "d.size",
+	"createdAt": "d.created_at",
+	"updatedAt": "d.updated_at",
+	"rank":      "ts_rank(dt.search, q.query)",
+}
+
+// query matches the text of a document in every language it is indexed in
+const query = `CROSS JOIN LATERAL (SELECT websearch_to_tsquery('russian', @q) || ` +
+	`websearch_to_tsquery('kazakh', @q) || websearch_to_tsquery('english', @q) AS query) q`
+
+// headline marks the words matching the query in a couple of short fragments
+const headline = `ts_headline('russian', dt.content, q.query, ` +
+	`'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5') AS snippet`
+
+// likeEscaper escapes the wildcards of a like pattern, so a name is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// cursor is the position after the last document of a page,
+// the id breaks ties between documents with the same sort value
+type cursor struct {
+	Sort  string          `json:"s"`
+	Value json.RawMessage `json:"v"`
+	ID    uint            `json:"id"`
+}
+
+func (fm *Repository) Search(ctx context.Context, search dto.DocumentSearch) (dto.DocumentPage, error) {
+	logrus.Debugf("[input]: %+v", search)
+
+	page := dto.DocumentPage{Documents: make([]dto.Document, 0)}
+
+	if search.Sort == "" && search.Query != "" {
+		search.Sort = defaultQuerySort
+	}
+	// relevance is only known for a query
+	if search.Sort == "" || (search.Query == "" && strings.TrimPrefix(search.Sort, "-") == "rank") {
+		search.Sort = defaultSort
+	}
+	if search.Limit == 0 {
+		search.Limit = defaultSearchLimit
+	}
+
+	field := strings.TrimPrefix(search.Sort, "-")
+	column, ok := sortColumns[field]
+	if !ok {
+		return page, fmt.Errorf("unknown sort field: %s", field)
+	}
+
+	direction, operator := "asc", ">"
+	if strings.HasPrefix(search.Sort, "-") {
+		direction, operator = "desc", "<"
+	}
+
+	// a document linked to several trees is found once, through the first link the search may see
+	link := "SELECT l.tree_id FROM tree_documents l WHERE l.document_id = d.id"
+	var linkArgs []interface{}
+	if search.TreeID != 0 {
+		link += " AND l.tree_id = ?"
+		linkArgs = append(linkArgs, search.TreeID)
+	}
+	if len(search.SharedTrees) > 0 {
+		link += " AND (d.user_id = ? or l.tree_id in ?)"
+		linkArgs = append(linkArgs, search.UserID, search.SharedTrees)
+	}
+
+	tx := fm.db.WithContext(ctx).
+		Table("documents d").
+		Select("d.*, td.tree_id, coalesce(dp.ready, false) AS preview").
+		Joins("JOIN LATERAL ("+link+" ORDER BY l.tree_id LIMIT 1) td ON true", linkArgs...).
+		Joins("LEFT JOIN document_previews dp ON dp.document_id = d.id").
+		Where("d.deleted_at is null").
+		Where("d.state = ?", modules.DocumentReady)
+
+	if search.Query != "" {
+		tx = tx.
+			Select("d.*, td.tree_id, coalesce(dp.ready, false) AS preview, ts_rank(dt.search, q.query) AS rank, "+headline).
+			Joins("JOIN document_texts dt ON dt.document_id = d.id").
+			Joins(query, sql.Named("q", search.Query)).
+			Where("dt.search @@ q.query")
+	}
+
+	if len(search.SharedTrees) == 0 {
+		tx = tx.Where("d.user_id = ?", search.UserID)
+	}
+
+	if search.Name != "" {
+		tx = tx.Where("d.name ilike ?", "%"+likeEscaper.Replace(search.Name)+"%")
+	}
+	if search.Extension != "" {
+		tx = tx.Where("lower(d.extension) = lower(?)", "."+strings.TrimPrefix(search.Extension, "."))
+	}
+	if search.Type != "" {
+		tx = tx.Where("d.type = ?", search.Type)
+	}
+	if search.MinSize != nil {
+		tx = tx.Where("d.size >= ?", *search.MinSize)
+	}
+	if search.MaxSize != nil {
+		tx = tx.Where("d.size <= ?", *search.MaxSize)
+	}
+	if search.CreatedFrom != nil {
+		tx = tx.Where("d.created_at >= ?", *search.CreatedFrom)
+	}
+	if search.CreatedTo != nil {
+		tx = tx.Where("d.created_at <= ?", *search.CreatedTo)
+	}
+	if search.UpdatedFrom != nil {
+		tx = tx.Where("d.updated_at >= ?", *search.UpdatedFrom)
+	}
+	if search.UpdatedTo != nil {
+		tx = tx.Where("d.updated_at <= ?", *search.UpdatedTo)
+	}
+	if search.Template != nil {
+		tx = tx.Where("d.template = ?", *search.Template)
+	}
+
+	if search.Cursor != "" {
+		value, id, err := decodeCursor(search.Cursor, search.Sort)
+		if err != nil {
+			return page, err
+		}
+
+		tx = tx.Where(fmt.Sprintf("(%s %s ? or (%s = ? and d.id %s ?))", column, operator, column, operator),
+			value, value, id)
+	}
+
+	// one more document than asked tells whether there is a next page
+	if err := tx.
+		Order(column + " " + direction).
+		Order("d.id " + direction).
+		Limit(search.Limit + 1).
+		Scan(&page.Documents).
+		Error; err != nil {
+		return page, err
+	}
+
+	if len(page.Documents) > search.Limit {
+		page.Documents = page.Documents[:search.Limit]
+		page.Next = encodeCursor(page.Documents[search.Limit-1], search.Sort)
+	}
+
+	return page, nil
+}
+
+func encodeCursor(doc dto.Document, sort string) string {
+	var value interface{}
+	switch strings.TrimPrefix(sort, "-") {
+	case "name":
+		value = doc.Name
+	case "size":
+		value = doc.Size
+	case "createdAt":
+		value = doc.CreatedAt
+	case "updatedAt":
+		value = doc.UpdatedAt
+	case "rank":
+		value = doc.Rank
+	}
+
+	raw, _ := json.Marshal(value)
+	encoded, _ := json.Marshal(cursor{Sort: sort, Value: raw, ID: doc.ID})
+
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+// decodeCursor returns the sort value and the id a page starts after,
+// a cursor of a search with another order is rejected
+func decodeCursor(encoded, sort string) (interface{}, uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, 0, modules.ErrInvalidCursor
+	}
+
+	var c cursor
+	if err = json.Unmarshal(raw, &c); err != nil || c.Sort != sort {
+		return nil, 0, modules.ErrInvalidCursor
+	}
+
+	switch strings.TrimPrefix(sort, "-") {
+	case "name":
+		var name string
+		err = json.Unmarshal(c.Value, &name)
+		return name, c.ID, cursorError(err)
+	case "size":
+		var size int64
+		err = json.Unmarshal(c.Value, &size)
+		return size, c.ID, cursorError(err)
+	case "rank":
+		var rank float64
+		err = json.Unmarshal(c.Value, &rank)
+		return rank, c.ID, cursorError(err)
+	default:
+		var at time.Time
+		err = json.Unmarshal(c.Value, &at)
+		return at, c.ID, cursorError(err)
+	}
+}
+
+func cursorError(err error) error {
+	if err != nil {
+		return modules.ErrInvalidCursor
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package groups
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (fm *Repository) Create(ctx context.Context, group dto.Group) (dto.Group, error) {
+	logrus.Debugf("[input]: %+v", group)
+
+	return group, fm.db.WithContext(ctx).
+		Model(dto.Group{}).
+		Omit("Members", "Trees", "Documents").
+		Create(&group).
+		Error
+}
+
+func (fm *Repository) Get(ctx context.Context, group dto.Group) (dto.Group, error) {
+	logrus.Debugf("[input]: %+v", group)
+
+	tx := fm.db.WithContext(ctx).
+		Model(dto.Group{}).
+		Preload("Members").
+		Preload("Trees").
+		Where("id = ?", group.ID).
+		Find(&group)
+	if tx.Error != nil {
+		return group, tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return group, gorm.ErrRecordNotFound
+	}
+
+	return group, nil
+}
+
+func (fm *Repository) List(ctx context.Context, userID string) ([]dto.Group, error) {
+	var groups []dto.Group
+	if err := fm.db.WithContext(ctx).
+		Model(dto.Group{}).
+		Where("user_id = ? or id in (?)", userID,
+			fm.db.Table("group_members").Select("group_id").Where("user_id = ?", userID)).
+		Order("name").
+		Find(&groups).
+		Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (fm *Repository) Update(ctx context.Context, group dto.Group) (dto.Group, error) {
+	logrus.Debugf("[input]: %+v", group)
+
+	tx := fm.db.WithContext(ctx).Model(&group).
+		Where("user_id = ?", group.UserID).
+		Select("name", "desc", "role").
+		Updates(&group)
+	if tx.Error != nil {
+		return group, tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return group, gorm.ErrRecordNotFound
+	}
+
+	return fm.Get(ctx, group)
+}
+
+func (fm *Repository) Delete(ctx context.Context, group dto.Group) (dto.Group, error) {
+	logrus.Debugf("[input]: %+v", group)
+
+	tx := fm.db.WithContext(ctx).Model(dto.Group{}).
+		Where("user_id = ?", group.UserID).Delete(&group)
+	if tx.Error != nil {
+		return group, tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return group, gorm.ErrRecordNotFound
+	}
+
+	return group, nil
+}
+
+func (fm *Repository) IsMember(ctx context.Context, member dto.GroupMember) (bool, error) {
+	var count int64
+	if err := fm.db.WithContext(ctx).
+		Model(dto.GroupMember{}).
+		Where("group_id = ?", member.GroupID).
+		Where("user_id = ?", member.UserID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (fm *Repository) AddMember(ctx context.Context, member dto.GroupMember) (dto.GroupMember, error) {
+	logrus.Debugf("[input]: %+v", member)
+
+	return member, fm.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&member).
+		Error
+}
+
+func (fm *Repository) RemoveMember(ctx context.Context, member dto.GroupMember) (dto.GroupMember, error) {
+	logrus.Debugf("[input]: %+v", member)
+
+	tx := fm.db.WithContext(ctx).
+		Where("group_id = ?", member.GroupID).
+		Where("user_id = ?", member.UserID).
+		Delete(&dto.GroupMember{})
+	if tx.Error != nil {
+		return member, tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return member, gorm.ErrRecordNotFound
+	}
+
+	return member, nil
+}
+
+func (fm *Repository) AttachDocument(ctx context.Context, group dto.Group, documentID uint) error {
+	logrus.Debugf("[input]: %+v, %+v", group, documentID)
+
+	// only documents of the group owner can be attached
+	var count int64
+	if err := fm.db.WithContext(ctx).
+		Model(dto.Document{}).
+		Where("id = ?", documentID).
+		Where("user_id = ?", group.UserID).
+		Where("state = ?", modules.DocumentReady).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return fm.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&dto.GroupDocuments{GroupID: group.ID, DocumentID: documentID}).
+		Error
+}
+
+func (fm *Repository) DetachDocument(ctx context.Context, group dto.Group, documentID uint) error {
+	logrus.Debugf("[input]: %+v, %+v", group, documentID)
+
+	tx := fm.db.WithContext(ctx).
+		Where("group_id = ?", group.ID).
+		Where("document_id = ?", documentID).
+		Delete(&dto.GroupDocuments{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (fm *Repository) AttachTree(ctx context.Context, group dto.Group, treeID uint) error {
+	logrus.Debugf("[input]: %+v, %+v", group, treeID)
+
+	// only trees of the group owner can be attached
+	var count int64
+	if err := fm.db.WithContext(ctx).
+		Model(dto.Tree{}).
+		Where("id = ?", treeID).
+		Where("user_id = ?", group.UserID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return fm.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&dto.GroupTrees{GroupID: group.ID, TreeID: treeID}).
+		Error
+}
+
+func (fm *Repository) DetachTree(ctx context.Context, group dto.Group, treeID uint) error {
+	logrus.Debugf("[input]: %+v, %+v", group, treeID)
+
+	tx := fm.db.WithContext(ctx).
+		Where("group_id = ?", group.ID).
+		Where("tree_id = ?", treeID).
+		Delete(&dto.GroupTrees{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package locks
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+// TryLock runs fn holding the advisory lock of a key on a connection of its own, a key
+// another process holds skips fn instead of waiting for it
+func (fm *Repository) TryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) (bool, error) {
+	logrus.Debugf("[input]: %+v", key)
+
+	db, err := fm.db.DB()
+	if err != nil {
+		return false, err
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1);`, key).Scan(&locked); err != nil {
+		return false, err
+	}
+	if !locked {
+		return false, nil
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1);`, key)
+
+	return true, fn(ctx)
+}
@@ -0,0 +1,21 @@
+DROP TABLE IF EXISTS share_accesses;
+DROP TABLE IF EXISTS shares;
+DROP TABLE IF EXISTS tree_policies;
+DROP TABLE IF EXISTS storage_usages;
+DROP TABLE IF EXISTS quota;
+DROP TABLE IF EXISTS blobs;
+DROP TABLE IF EXISTS document_previews;
+DROP TABLE IF EXISTS document_texts;
+DROP TABLE IF EXISTS tree_accesses;
+DROP TABLE IF EXISTS group_documents;
+DROP TABLE IF EXISTS group_trees;
+DROP TABLE IF EXISTS group_members;
+DROP TABLE IF EXISTS groups;
+DROP TABLE IF EXISTS object_deletions;
+DROP TABLE IF EXISTS upload_parts;
+DROP TABLE IF EXISTS upload_sessions;
+DROP TABLE IF EXISTS document_versions;
+DROP TABLE IF EXISTS tree_documents;
+DROP TABLE IF EXISTS documents;
+DROP TABLE IF EXISTS trees;
+DROP TEXT SEARCH CONFIGURATION IF EXISTS kazakh;
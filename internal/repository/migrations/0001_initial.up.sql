@@ -0,0 +1,288 @@
+-- The schema as gorm AutoMigrate left it. Every statement is guarded and the columns
+-- added to a table after AutoMigrate first created it are added when missing, so a
+-- database AutoMigrate created with any earlier release adopts this migration.
+
+-- postgres has no kazakh stemmer, so words are only lowercased like in the simple configuration
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'kazakh') THEN
+		CREATE TEXT SEARCH CONFIGURATION kazakh (COPY = simple);
+	END IF;
+END
+$$;
+
+CREATE TABLE IF NOT EXISTS trees (
+	id bigserial,
+	user_id text,
+	doc_id bigint,
+	parent_id bigint,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	name text,
+	role text,
+	template boolean DEFAULT false,
+	"group" boolean DEFAULT false,
+	PRIMARY KEY (id)
+);
+ALTER TABLE trees ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_trees_deleted_at ON trees (deleted_at);
+
+CREATE TABLE IF NOT EXISTS documents (
+	id bigserial,
+	user_id text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	name text,
+	extension text,
+	size bigint,
+	type text,
+	path uuid DEFAULT gen_random_uuid(),
+	template boolean DEFAULT false,
+	version bigint DEFAULT 1,
+	checksum varchar(64),
+	scan_status text DEFAULT 'clean',
+	PRIMARY KEY (id)
+);
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS version bigint DEFAULT 1;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS checksum varchar(64);
+-- content stored before scanning existed is taken as clean
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS scan_status text DEFAULT 'clean';
+CREATE INDEX IF NOT EXISTS idx_documents_checksum ON documents (checksum);
+CREATE INDEX IF NOT EXISTS idx_documents_deleted_at ON documents (deleted_at);
+
+CREATE TABLE IF NOT EXISTS tree_documents (
+	tree_id bigint,
+	document_id bigint,
+	PRIMARY KEY (tree_id, document_id),
+	CONSTRAINT fk_tree_documents_document FOREIGN KEY (document_id) REFERENCES documents (id) ON DELETE SET NULL ON UPDATE CASCADE,
+	CONSTRAINT fk_tree_documents_tree FOREIGN KEY (tree_id) REFERENCES trees (id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS document_versions (
+	id bigserial,
+	document_id bigint,
+	user_id text,
+	version bigint,
+	created_at timestamptz,
+	name text,
+	extension text,
+	size bigint,
+	type text,
+	checksum varchar(64),
+	PRIMARY KEY (id)
+);
+ALTER TABLE document_versions ADD COLUMN IF NOT EXISTS checksum varchar(64);
+CREATE INDEX IF NOT EXISTS idx_document_versions_checksum ON document_versions (checksum);
+CREATE INDEX IF NOT EXISTS idx_document_versions_document_id ON document_versions (document_id);
+
+CREATE TABLE IF NOT EXISTS upload_sessions (
+	id uuid DEFAULT gen_random_uuid(),
+	user_id text,
+	tree_id bigint,
+	created_at timestamptz,
+	updated_at timestamptz,
+	name text,
+	extension text,
+	type text,
+	template boolean DEFAULT false,
+	path uuid,
+	upload_id text,
+	PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS upload_parts (
+	session_id uuid,
+	number bigint,
+	etag text,
+	size bigint,
+	updated_at timestamptz,
+	PRIMARY KEY (session_id, number),
+	CONSTRAINT fk_upload_sessions_parts FOREIGN KEY (session_id) REFERENCES upload_sessions (id)
+);
+
+CREATE TABLE IF NOT EXISTS object_deletions (
+	id bigserial,
+	created_at timestamptz,
+	updated_at timestamptz,
+	document_id bigint,
+	uploaded_at timestamptz,
+	path uuid,
+	extension text,
+	version bigint,
+	checksum varchar(64),
+	attempts bigint,
+	last_error text,
+	retry_at timestamptz,
+	PRIMARY KEY (id)
+);
+ALTER TABLE object_deletions ADD COLUMN IF NOT EXISTS checksum varchar(64);
+CREATE INDEX IF NOT EXISTS idx_object_deletions_retry_at ON object_deletions (retry_at);
+CREATE INDEX IF NOT EXISTS idx_object_deletions_checksum ON object_deletions (checksum);
+
+CREATE TABLE IF NOT EXISTS groups (
+	id bigserial,
+	user_id text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	name text,
+	"desc" text,
+	role text,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_groups_deleted_at ON groups (deleted_at);
+
+CREATE TABLE IF NOT EXISTS group_members (
+	group_id bigint,
+	user_id text,
+	created_at timestamptz,
+	PRIMARY KEY (group_id, user_id),
+	CONSTRAINT fk_groups_members FOREIGN KEY (group_id) REFERENCES groups (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS group_trees (
+	group_id bigint,
+	tree_id bigint,
+	PRIMARY KEY (group_id, tree_id),
+	CONSTRAINT fk_group_trees_group FOREIGN KEY (group_id) REFERENCES groups (id),
+	CONSTRAINT fk_group_trees_tree FOREIGN KEY (tree_id) REFERENCES trees (id)
+);
+
+CREATE TABLE IF NOT EXISTS group_documents (
+	group_id bigint,
+	document_id bigint,
+	PRIMARY KEY (group_id, document_id),
+	CONSTRAINT fk_group_documents_group FOREIGN KEY (group_id) REFERENCES groups (id),
+	CONSTRAINT fk_group_documents_document FOREIGN KEY (document_id) REFERENCES documents (id)
+);
+
+CREATE TABLE IF NOT EXISTS tree_accesses (
+	id bigserial,
+	tree_id bigint,
+	created_at timestamptz,
+	updated_at timestamptz,
+	subject text,
+	subject_id text,
+	level text,
+	PRIMARY KEY (id),
+	CONSTRAINT fk_tree_accesses_tree FOREIGN KEY (tree_id) REFERENCES trees (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tree_access_subject ON tree_accesses (tree_id, subject, subject_id);
+
+CREATE TABLE IF NOT EXISTS document_texts (
+	document_id bigint,
+	created_at timestamptz,
+	updated_at timestamptz,
+	content text,
+	search tsvector,
+	extracted boolean,
+	attempts bigint,
+	last_error text,
+	retry_at timestamptz,
+	PRIMARY KEY (document_id),
+	CONSTRAINT fk_document_texts_document FOREIGN KEY (document_id) REFERENCES documents (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_document_texts_retry_at ON document_texts (retry_at);
+CREATE INDEX IF NOT EXISTS idx_document_texts_search ON document_texts USING gin (search);
+
+CREATE TABLE IF NOT EXISTS document_previews (
+	document_id bigint,
+	created_at timestamptz,
+	updated_at timestamptz,
+	ready boolean,
+	attempts bigint,
+	last_error text,
+	retry_at timestamptz,
+	PRIMARY KEY (document_id),
+	CONSTRAINT fk_document_previews_document FOREIGN KEY (document_id) REFERENCES documents (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_document_previews_retry_at ON document_previews (retry_at);
+
+CREATE TABLE IF NOT EXISTS blobs (
+	checksum varchar(64),
+	created_at timestamptz,
+	updated_at timestamptz,
+	size bigint,
+	refs bigint,
+	verified_at timestamptz,
+	PRIMARY KEY (checksum)
+);
+
+CREATE TABLE IF NOT EXISTS quota (
+	id bigserial,
+	created_at timestamptz,
+	updated_at timestamptz,
+	subject text,
+	subject_id text,
+	max_bytes bigint,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_quota_subject ON quota (subject, subject_id);
+
+CREATE TABLE IF NOT EXISTS storage_usages (
+	user_id text,
+	updated_at timestamptz,
+	bytes bigint NOT NULL DEFAULT 0,
+	organization bigint,
+	roles text[],
+	PRIMARY KEY (user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_storage_usages_organization ON storage_usages (organization);
+
+CREATE TABLE IF NOT EXISTS tree_policies (
+	tree_id bigint,
+	created_at timestamptz,
+	updated_at timestamptz,
+	max_size bigint,
+	allow text[],
+	deny text[],
+	PRIMARY KEY (tree_id),
+	CONSTRAINT fk_tree_policies_tree FOREIGN KEY (tree_id) REFERENCES trees (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS shares (
+	id bigserial,
+	token text NOT NULL,
+	kind text DEFAULT 'document',
+	document_id bigint,
+	tree_id bigint,
+	user_id text,
+	created_by text,
+	created_at timestamptz,
+	expires_at timestamptz,
+	max_downloads bigint,
+	downloads bigint NOT NULL DEFAULT 0,
+	password_hash text,
+	revoked_at timestamptz,
+	PRIMARY KEY (id),
+	CONSTRAINT fk_shares_document FOREIGN KEY (document_id) REFERENCES documents (id) ON DELETE CASCADE,
+	CONSTRAINT fk_shares_tree FOREIGN KEY (tree_id) REFERENCES trees (id) ON DELETE CASCADE
+);
+ALTER TABLE shares ADD COLUMN IF NOT EXISTS kind text DEFAULT 'document';
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_shares_tree') THEN
+		ALTER TABLE shares ADD CONSTRAINT fk_shares_tree FOREIGN KEY (tree_id) REFERENCES trees (id) ON DELETE CASCADE;
+	END IF;
+END
+$$;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shares_token ON shares (token);
+CREATE INDEX IF NOT EXISTS idx_shares_document_id ON shares (document_id);
+CREATE INDEX IF NOT EXISTS idx_shares_tree_id ON shares (tree_id);
+CREATE INDEX IF NOT EXISTS idx_shares_user_id ON shares (user_id);
+CREATE INDEX IF NOT EXISTS idx_shares_created_by ON shares (created_by);
+
+CREATE TABLE IF NOT EXISTS share_accesses (
+	id bigserial,
+	share_id bigint,
+	created_at timestamptz,
+	ip text,
+	user_agent text,
+	outcome text,
+	PRIMARY KEY (id),
+	CONSTRAINT fk_share_accesses_share FOREIGN KEY (share_id) REFERENCES shares (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_share_accesses_share_id ON share_accesses (share_id);
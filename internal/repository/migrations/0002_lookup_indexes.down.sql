@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_upload_sessions_user_id;
+DROP INDEX IF EXISTS idx_group_members_user_id;
+DROP INDEX IF EXISTS idx_tree_accesses_subject;
+DROP INDEX IF EXISTS idx_documents_user_id;
+DROP INDEX IF EXISTS idx_tree_documents_document_id;
+DROP INDEX IF EXISTS idx_trees_user_id;
+DROP INDEX IF EXISTS idx_trees_parent_id;
@@ -0,0 +1,11 @@
+-- subtrees are walked by parent, within the trees of one owner
+CREATE INDEX IF NOT EXISTS idx_trees_parent_id ON trees (parent_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_trees_user_id ON trees (user_id);
+
+-- the primary key of the join leads with the tree, documents are looked up the other way too
+CREATE INDEX IF NOT EXISTS idx_tree_documents_document_id ON tree_documents (document_id);
+
+CREATE INDEX IF NOT EXISTS idx_documents_user_id ON documents (user_id);
+CREATE INDEX IF NOT EXISTS idx_tree_accesses_subject ON tree_accesses (subject, subject_id);
+CREATE INDEX IF NOT EXISTS idx_group_members_user_id ON group_members (user_id);
+CREATE INDEX IF NOT EXISTS idx_upload_sessions_user_id ON upload_sessions (user_id);
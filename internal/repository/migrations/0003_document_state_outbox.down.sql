@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_object_deletions_document_id;
+DROP TABLE IF EXISTS outbox_events;
+DROP INDEX IF EXISTS idx_documents_state;
+ALTER TABLE documents DROP COLUMN IF EXISTS state;
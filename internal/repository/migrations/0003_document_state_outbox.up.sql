@@ -0,0 +1,20 @@
+-- documents stored before states existed have their content in spaces
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS state varchar(20) DEFAULT 'ready';
+CREATE INDEX IF NOT EXISTS idx_documents_state ON documents (state) WHERE state <> 'ready';
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+	id bigserial,
+	created_at timestamptz,
+	updated_at timestamptz,
+	kind varchar(20),
+	document_id bigint,
+	attempts bigint,
+	last_error text,
+	retry_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_document_id ON outbox_events (document_id);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_retry_at ON outbox_events (retry_at);
+
+-- a deleting document is removed once none of its objects is queued
+CREATE INDEX IF NOT EXISTS idx_object_deletions_document_id ON object_deletions (document_id);
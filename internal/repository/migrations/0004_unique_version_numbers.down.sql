@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_document_versions_number;
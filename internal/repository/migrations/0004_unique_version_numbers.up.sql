@@ -0,0 +1,15 @@
+-- versions numbered twice by concurrent uploads move after the latest one
+WITH duplicates AS (
+	SELECT id, document_id,
+		row_number() OVER (PARTITION BY document_id, version ORDER BY id) AS position
+	FROM document_versions
+), renumbered AS (
+	SELECT d.id,
+		(SELECT max(version) FROM document_versions WHERE document_id = d.document_id)
+			+ row_number() OVER (PARTITION BY d.document_id ORDER BY d.id) AS version
+	FROM duplicates d
+	WHERE d.position > 1
+)
+UPDATE document_versions v SET version = r.version FROM renumbered r WHERE v.id = r.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_document_versions_number ON document_versions (document_id, version);
@@ -0,0 +1,2 @@
+-- the rebuilt WebP thumbnails stay, nothing to revert
+SELECT 1;
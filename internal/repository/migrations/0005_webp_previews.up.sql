@@ -0,0 +1,2 @@
+-- thumbnails are built as WebP now, queue every document to be previewed again
+UPDATE document_previews SET ready = false, attempts = 0, last_error = '', retry_at = now();
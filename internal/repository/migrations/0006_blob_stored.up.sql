@@ -0,0 +1,3 @@
+-- blobs acquired before the flag existed had their content uploaded by the first reference
+ALTER TABLE blobs ADD COLUMN IF NOT EXISTS stored boolean DEFAULT false;
+UPDATE blobs SET stored = true;
@@ -0,0 +1,8 @@
+-- the tokens can't be recovered from their hashes, links created before stop working
+ALTER TABLE shares ADD COLUMN IF NOT EXISTS token text;
+UPDATE shares SET token = token_hash WHERE token IS NULL;
+ALTER TABLE shares ALTER COLUMN token SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shares_token ON shares (token);
+
+DROP INDEX IF EXISTS idx_shares_token_hash;
+ALTER TABLE shares DROP COLUMN IF EXISTS token_hash;
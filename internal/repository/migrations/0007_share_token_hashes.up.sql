@@ -0,0 +1,8 @@
+-- share links are looked up by the SHA-256 of their token, the token is only handed out when a link is created
+ALTER TABLE shares ADD COLUMN IF NOT EXISTS token_hash varchar(64);
+UPDATE shares SET token_hash = encode(sha256(convert_to(token, 'UTF8')), 'hex') WHERE token_hash IS NULL;
+ALTER TABLE shares ALTER COLUMN token_hash SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shares_token_hash ON shares (token_hash);
+
+DROP INDEX IF EXISTS idx_shares_token;
+ALTER TABLE shares DROP COLUMN IF EXISTS token;
@@ -0,0 +1,2 @@
+DELETE FROM outbox_events WHERE kind = 'copy';
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS source;
@@ -0,0 +1,2 @@
+-- a copy event keeps the key of the object it copies, the source document may change meanwhile
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS source text;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_upload_sessions_expires_at;
+ALTER TABLE upload_sessions DROP COLUMN IF EXISTS expires_at;
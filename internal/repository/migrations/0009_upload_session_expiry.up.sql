@@ -0,0 +1,4 @@
+-- sessions started before they expired get a day from now, so uploads in progress can finish
+ALTER TABLE upload_sessions ADD COLUMN IF NOT EXISTS expires_at timestamptz;
+UPDATE upload_sessions SET expires_at = now() + interval '1 day' WHERE expires_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_upload_sessions_expires_at ON upload_sessions (expires_at);
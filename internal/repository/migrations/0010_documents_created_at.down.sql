@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_documents_created_at_path;
@@ -0,0 +1,2 @@
+-- the reconciler walks the documents in the order of their keys, a day of them at a time
+CREATE INDEX IF NOT EXISTS idx_documents_created_at_path ON documents (created_at, path);
@@ -0,0 +1,1943 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	dto "gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+)
+
+// MockDocumentRepository is a mock of DocumentRepository interface.
+type MockDocumentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDocumentRepositoryMockRecorder
+}
+
+// MockDocumentRepositoryMockRecorder is the mock recorder for MockDocumentRepository.
+type MockDocumentRepositoryMockRecorder struct {
+	mock *MockDocumentRepository
+}
+
+// NewMockDocumentRepository creates a new mock instance.
+func NewMockDocumentRepository(ctrl *gomock.Controller) *MockDocumentRepository {
+	mock := &MockDocumentRepository{ctrl: ctrl}
+	mock.recorder = &MockDocumentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDocumentRepository) EXPECT() *MockDocumentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDocumentRepository) Create(ctx context.Context, doc dto.Document) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, doc)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDocumentRepositoryMockRecorder) Create(ctx, doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDocumentRepository)(nil).Create), ctx, doc)
+}
+
+// Deduplicate mocks base method.
+func (m *MockDocumentRepository) Deduplicate(ctx context.Context, doc dto.Document) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deduplicate", ctx, doc)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Deduplicate indicates an expected call of Deduplicate.
+func (mr *MockDocumentRepositoryMockRecorder) Deduplicate(ctx, doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deduplicate", reflect.TypeOf((*MockDocumentRepository)(nil).Deduplicate), ctx, doc)
+}
+
+// Delete mocks base method.
+func (m *MockDocumentRepository) Delete(ctx context.Context, doc dto.Document) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, doc)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDocumentRepositoryMockRecorder) Delete(ctx, doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDocumentRepository)(nil).Delete), ctx, doc)
+}
+
+// Duplicate mocks base method.
+func (m *MockDocumentRepository) Duplicate(ctx context.Context, doc dto.Document, source string) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Duplicate", ctx, doc, source)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Duplicate indicates an expected call of Duplicate.
+func (mr *MockDocumentRepositoryMockRecorder) Duplicate(ctx, doc, source interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Duplicate", reflect.TypeOf((*MockDocumentRepository)(nil).Duplicate), ctx, doc, source)
+}
+
+// Find mocks base method.
+func (m *MockDocumentRepository) Find(ctx context.Context, id uint) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, id)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockDocumentRepositoryMockRecorder) Find(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockDocumentRepository)(nil).Find), ctx, id)
+}
+
+// FirstCreated mocks base method.
+func (m *MockDocumentRepository) FirstCreated(ctx context.Context, from time.Time) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FirstCreated", ctx, from)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FirstCreated indicates an expected call of FirstCreated.
+func (mr *MockDocumentRepositoryMockRecorder) FirstCreated(ctx, from interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FirstCreated", reflect.TypeOf((*MockDocumentRepository)(nil).FirstCreated), ctx, from)
+}
+
+// Get mocks base method.
+func (m *MockDocumentRepository) Get(ctx context.Context, doc dto.Document) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, doc)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockDocumentRepositoryMockRecorder) Get(ctx, doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDocumentRepository)(nil).Get), ctx, doc)
+}
+
+// ListByChecksums mocks base method.
+func (m *MockDocumentRepository) ListByChecksums(ctx context.Context, checksums []string) ([]dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByChecksums", ctx, checksums)
+	ret0, _ := ret[0].([]dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByChecksums indicates an expected call of ListByChecksums.
+func (mr *MockDocumentRepositoryMockRecorder) ListByChecksums(ctx, checksums interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByChecksums", reflect.TypeOf((*MockDocumentRepository)(nil).ListByChecksums), ctx, checksums)
+}
+
+// ListByGroups mocks base method.
+func (m *MockDocumentRepository) ListByGroups(ctx context.Context, ids []uint) ([]dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByGroups", ctx, ids)
+	ret0, _ := ret[0].([]dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByGroups indicates an expected call of ListByGroups.
+func (mr *MockDocumentRepositoryMockRecorder) ListByGroups(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByGroups", reflect.TypeOf((*MockDocumentRepository)(nil).ListByGroups), ctx, ids)
+}
+
+// ListByTree mocks base method.
+func (m *MockDocumentRepository) ListByTree(ctx context.Context, ids []uint) ([]dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByTree", ctx, ids)
+	ret0, _ := ret[0].([]dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByTree indicates an expected call of ListByTree.
+func (mr *MockDocumentRepositoryMockRecorder) ListByTree(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByTree", reflect.TypeOf((*MockDocumentRepository)(nil).ListByTree), ctx, ids)
+}
+
+// ListChecksums mocks base method.
+func (m *MockDocumentRepository) ListChecksums(ctx context.Context, after string, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChecksums", ctx, after, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChecksums indicates an expected call of ListChecksums.
+func (mr *MockDocumentRepositoryMockRecorder) ListChecksums(ctx, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChecksums", reflect.TypeOf((*MockDocumentRepository)(nil).ListChecksums), ctx, after, limit)
+}
+
+// ListCreated mocks base method.
+func (m *MockDocumentRepository) ListCreated(ctx context.Context, from, to time.Time, afterPath uuid.UUID, limit int) ([]dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCreated", ctx, from, to, afterPath, limit)
+	ret0, _ := ret[0].([]dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCreated indicates an expected call of ListCreated.
+func (mr *MockDocumentRepositoryMockRecorder) ListCreated(ctx, from, to, afterPath, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCreated", reflect.TypeOf((*MockDocumentRepository)(nil).ListCreated), ctx, from, to, afterPath, limit)
+}
+
+// ListExpired mocks base method.
+func (m *MockDocumentRepository) ListExpired(ctx context.Context, before time.Time) ([]dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpired", ctx, before)
+	ret0, _ := ret[0].([]dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpired indicates an expected call of ListExpired.
+func (mr *MockDocumentRepositoryMockRecorder) ListExpired(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpired", reflect.TypeOf((*MockDocumentRepository)(nil).ListExpired), ctx, before)
+}
+
+// ListTrash mocks base method.
+func (m *MockDocumentRepository) ListTrash(ctx context.Context, userID string) ([]dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", ctx, userID)
+	ret0, _ := ret[0].([]dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockDocumentRepositoryMockRecorder) ListTrash(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockDocumentRepository)(nil).ListTrash), ctx, userID)
+}
+
+// Move mocks base method.
+func (m *MockDocumentRepository) Move(ctx context.Context, doc dto.Document, treeID uint) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", ctx, doc, treeID)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockDocumentRepositoryMockRecorder) Move(ctx, doc, treeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockDocumentRepository)(nil).Move), ctx, doc, treeID)
+}
+
+// Purge mocks base method.
+func (m *MockDocumentRepository) Purge(ctx context.Context, ids []uint) (dto.Purge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, ids)
+	ret0, _ := ret[0].(dto.Purge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockDocumentRepositoryMockRecorder) Purge(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockDocumentRepository)(nil).Purge), ctx, ids)
+}
+
+// Quarantine mocks base method.
+func (m *MockDocumentRepository) Quarantine(ctx context.Context, ids []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Quarantine", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Quarantine indicates an expected call of Quarantine.
+func (mr *MockDocumentRepositoryMockRecorder) Quarantine(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Quarantine", reflect.TypeOf((*MockDocumentRepository)(nil).Quarantine), ctx, ids)
+}
+
+// Ready mocks base method.
+func (m *MockDocumentRepository) Ready(ctx context.Context, doc dto.Document) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ready", ctx, doc)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Ready indicates an expected call of Ready.
+func (mr *MockDocumentRepositoryMockRecorder) Ready(ctx, doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ready", reflect.TypeOf((*MockDocumentRepository)(nil).Ready), ctx, doc)
+}
+
+// Restore mocks base method.
+func (m *MockDocumentRepository) Restore(ctx context.Context, doc dto.Document) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, doc)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockDocumentRepositoryMockRecorder) Restore(ctx, doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockDocumentRepository)(nil).Restore), ctx, doc)
+}
+
+// Scanned mocks base method.
+func (m *MockDocumentRepository) Scanned(ctx context.Context, doc dto.Document) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Scanned", ctx, doc)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Scanned indicates an expected call of Scanned.
+func (mr *MockDocumentRepositoryMockRecorder) Scanned(ctx, doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scanned", reflect.TypeOf((*MockDocumentRepository)(nil).Scanned), ctx, doc)
+}
+
+// Search mocks base method.
+func (m *MockDocumentRepository) Search(ctx context.Context, search dto.DocumentSearch) (dto.DocumentPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, search)
+	ret0, _ := ret[0].(dto.DocumentPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockDocumentRepositoryMockRecorder) Search(ctx, search interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockDocumentRepository)(nil).Search), ctx, search)
+}
+
+// Update mocks base method.
+func (m *MockDocumentRepository) Update(ctx context.Context, doc dto.Document) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, doc)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDocumentRepositoryMockRecorder) Update(ctx, doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDocumentRepository)(nil).Update), ctx, doc)
+}
+
+// UpdateContent mocks base method.
+func (m *MockDocumentRepository) UpdateContent(ctx context.Context, doc dto.Document) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateContent", ctx, doc)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateContent indicates an expected call of UpdateContent.
+func (mr *MockDocumentRepositoryMockRecorder) UpdateContent(ctx, doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateContent", reflect.TypeOf((*MockDocumentRepository)(nil).UpdateContent), ctx, doc)
+}
+
+// MockVersionRepository is a mock of VersionRepository interface.
+type MockVersionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockVersionRepositoryMockRecorder
+}
+
+// MockVersionRepositoryMockRecorder is the mock recorder for MockVersionRepository.
+type MockVersionRepositoryMockRecorder struct {
+	mock *MockVersionRepository
+}
+
+// NewMockVersionRepository creates a new mock instance.
+func NewMockVersionRepository(ctrl *gomock.Controller) *MockVersionRepository {
+	mock := &MockVersionRepository{ctrl: ctrl}
+	mock.recorder = &MockVersionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVersionRepository) EXPECT() *MockVersionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockVersionRepository) Create(ctx context.Context, version dto.DocumentVersion) (dto.DocumentVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, version)
+	ret0, _ := ret[0].(dto.DocumentVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockVersionRepositoryMockRecorder) Create(ctx, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockVersionRepository)(nil).Create), ctx, version)
+}
+
+// Delete mocks base method.
+func (m *MockVersionRepository) Delete(ctx context.Context, documentID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, documentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockVersionRepositoryMockRecorder) Delete(ctx, documentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockVersionRepository)(nil).Delete), ctx, documentID)
+}
+
+// Get mocks base method.
+func (m *MockVersionRepository) Get(ctx context.Context, version dto.DocumentVersion) (dto.DocumentVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, version)
+	ret0, _ := ret[0].(dto.DocumentVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockVersionRepositoryMockRecorder) Get(ctx, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockVersionRepository)(nil).Get), ctx, version)
+}
+
+// List mocks base method.
+func (m *MockVersionRepository) List(ctx context.Context, documentID uint) ([]dto.DocumentVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, documentID)
+	ret0, _ := ret[0].([]dto.DocumentVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockVersionRepositoryMockRecorder) List(ctx, documentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockVersionRepository)(nil).List), ctx, documentID)
+}
+
+// ListByDocuments mocks base method.
+func (m *MockVersionRepository) ListByDocuments(ctx context.Context, ids []uint) ([]dto.DocumentVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByDocuments", ctx, ids)
+	ret0, _ := ret[0].([]dto.DocumentVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByDocuments indicates an expected call of ListByDocuments.
+func (mr *MockVersionRepositoryMockRecorder) ListByDocuments(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByDocuments", reflect.TypeOf((*MockVersionRepository)(nil).ListByDocuments), ctx, ids)
+}
+
+// Remove mocks base method.
+func (m *MockVersionRepository) Remove(ctx context.Context, version dto.DocumentVersion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockVersionRepositoryMockRecorder) Remove(ctx, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockVersionRepository)(nil).Remove), ctx, version)
+}
+
+// MockTreeRepository is a mock of TreeRepository interface.
+type MockTreeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTreeRepositoryMockRecorder
+}
+
+// MockTreeRepositoryMockRecorder is the mock recorder for MockTreeRepository.
+type MockTreeRepositoryMockRecorder struct {
+	mock *MockTreeRepository
+}
+
+// NewMockTreeRepository creates a new mock instance.
+func NewMockTreeRepository(ctrl *gomock.Controller) *MockTreeRepository {
+	mock := &MockTreeRepository{ctrl: ctrl}
+	mock.recorder = &MockTreeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTreeRepository) EXPECT() *MockTreeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Copy mocks base method.
+func (m *MockTreeRepository) Copy(ctx context.Context, root dto.Tree, sourceID uint, descendants []dto.Tree) (dto.Tree, map[uint]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Copy", ctx, root, sourceID, descendants)
+	ret0, _ := ret[0].(dto.Tree)
+	ret1, _ := ret[1].(map[uint]uint)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Copy indicates an expected call of Copy.
+func (mr *MockTreeRepositoryMockRecorder) Copy(ctx, root, sourceID, descendants interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Copy", reflect.TypeOf((*MockTreeRepository)(nil).Copy), ctx, root, sourceID, descendants)
+}
+
+// Create mocks base method.
+func (m *MockTreeRepository) Create(ctx context.Context, tree dto.Tree) (dto.Tree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, tree)
+	ret0, _ := ret[0].(dto.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTreeRepositoryMockRecorder) Create(ctx, tree interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTreeRepository)(nil).Create), ctx, tree)
+}
+
+// Delete mocks base method.
+func (m *MockTreeRepository) Delete(ctx context.Context, tree dto.Tree) (dto.Tree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, tree)
+	ret0, _ := ret[0].(dto.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTreeRepositoryMockRecorder) Delete(ctx, tree interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTreeRepository)(nil).Delete), ctx, tree)
+}
+
+// Get mocks base method.
+func (m *MockTreeRepository) Get(ctx context.Context, tree dto.Tree) (dto.Tree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, tree)
+	ret0, _ := ret[0].(dto.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTreeRepositoryMockRecorder) Get(ctx, tree interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTreeRepository)(nil).Get), ctx, tree)
+}
+
+// List mocks base method.
+func (m *MockTreeRepository) List(ctx context.Context, tree dto.Tree) ([]dto.Tree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, tree)
+	ret0, _ := ret[0].([]dto.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTreeRepositoryMockRecorder) List(ctx, tree interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTreeRepository)(nil).List), ctx, tree)
+}
+
+// ListTemplates mocks base method.
+func (m *MockTreeRepository) ListTemplates(ctx context.Context, roles []string) ([]dto.Tree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTemplates", ctx, roles)
+	ret0, _ := ret[0].([]dto.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTemplates indicates an expected call of ListTemplates.
+func (mr *MockTreeRepositoryMockRecorder) ListTemplates(ctx, roles interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTemplates", reflect.TypeOf((*MockTreeRepository)(nil).ListTemplates), ctx, roles)
+}
+
+// ListTrash mocks base method.
+func (m *MockTreeRepository) ListTrash(ctx context.Context, userID string) ([]dto.Tree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", ctx, userID)
+	ret0, _ := ret[0].([]dto.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockTreeRepositoryMockRecorder) ListTrash(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockTreeRepository)(nil).ListTrash), ctx, userID)
+}
+
+// Move mocks base method.
+func (m *MockTreeRepository) Move(ctx context.Context, tree dto.Tree) (dto.Tree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", ctx, tree)
+	ret0, _ := ret[0].(dto.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockTreeRepositoryMockRecorder) Move(ctx, tree interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockTreeRepository)(nil).Move), ctx, tree)
+}
+
+// Purge mocks base method.
+func (m *MockTreeRepository) Purge(ctx context.Context, tree dto.Tree) (dto.Purge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, tree)
+	ret0, _ := ret[0].(dto.Purge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockTreeRepositoryMockRecorder) Purge(ctx, tree interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockTreeRepository)(nil).Purge), ctx, tree)
+}
+
+// PurgeExpired mocks base method.
+func (m *MockTreeRepository) PurgeExpired(ctx context.Context, before time.Time) (dto.Purge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpired", ctx, before)
+	ret0, _ := ret[0].(dto.Purge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpired indicates an expected call of PurgeExpired.
+func (mr *MockTreeRepositoryMockRecorder) PurgeExpired(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpired", reflect.TypeOf((*MockTreeRepository)(nil).PurgeExpired), ctx, before)
+}
+
+// Restore mocks base method.
+func (m *MockTreeRepository) Restore(ctx context.Context, tree dto.Tree) (dto.Tree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, tree)
+	ret0, _ := ret[0].(dto.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockTreeRepositoryMockRecorder) Restore(ctx, tree interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockTreeRepository)(nil).Restore), ctx, tree)
+}
+
+// Update mocks base method.
+func (m *MockTreeRepository) Update(ctx context.Context, tree dto.Tree) (dto.Tree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, tree)
+	ret0, _ := ret[0].(dto.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTreeRepositoryMockRecorder) Update(ctx, tree interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTreeRepository)(nil).Update), ctx, tree)
+}
+
+// MockGroupRepository is a mock of GroupRepository interface.
+type MockGroupRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGroupRepositoryMockRecorder
+}
+
+// MockGroupRepositoryMockRecorder is the mock recorder for MockGroupRepository.
+type MockGroupRepositoryMockRecorder struct {
+	mock *MockGroupRepository
+}
+
+// NewMockGroupRepository creates a new mock instance.
+func NewMockGroupRepository(ctrl *gomock.Controller) *MockGroupRepository {
+	mock := &MockGroupRepository{ctrl: ctrl}
+	mock.recorder = &MockGroupRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGroupRepository) EXPECT() *MockGroupRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddMember mocks base method.
+func (m *MockGroupRepository) AddMember(ctx context.Context, member dto.GroupMember) (dto.GroupMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMember", ctx, member)
+	ret0, _ := ret[0].(dto.GroupMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockGroupRepositoryMockRecorder) AddMember(ctx, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockGroupRepository)(nil).AddMember), ctx, member)
+}
+
+// AttachDocument mocks base method.
+func (m *MockGroupRepository) AttachDocument(ctx context.Context, group dto.Group, documentID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachDocument", ctx, group, documentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachDocument indicates an expected call of AttachDocument.
+func (mr *MockGroupRepositoryMockRecorder) AttachDocument(ctx, group, documentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachDocument", reflect.TypeOf((*MockGroupRepository)(nil).AttachDocument), ctx, group, documentID)
+}
+
+// AttachTree mocks base method.
+func (m *MockGroupRepository) AttachTree(ctx context.Context, group dto.Group, treeID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachTree", ctx, group, treeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachTree indicates an expected call of AttachTree.
+func (mr *MockGroupRepositoryMockRecorder) AttachTree(ctx, group, treeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachTree", reflect.TypeOf((*MockGroupRepository)(nil).AttachTree), ctx, group, treeID)
+}
+
+// Create mocks base method.
+func (m *MockGroupRepository) Create(ctx context.Context, group dto.Group) (dto.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, group)
+	ret0, _ := ret[0].(dto.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockGroupRepositoryMockRecorder) Create(ctx, group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockGroupRepository)(nil).Create), ctx, group)
+}
+
+// Delete mocks base method.
+func (m *MockGroupRepository) Delete(ctx context.Context, group dto.Group) (dto.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, group)
+	ret0, _ := ret[0].(dto.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockGroupRepositoryMockRecorder) Delete(ctx, group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockGroupRepository)(nil).Delete), ctx, group)
+}
+
+// DetachDocument mocks base method.
+func (m *MockGroupRepository) DetachDocument(ctx context.Context, group dto.Group, documentID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachDocument", ctx, group, documentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachDocument indicates an expected call of DetachDocument.
+func (mr *MockGroupRepositoryMockRecorder) DetachDocument(ctx, group, documentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachDocument", reflect.TypeOf((*MockGroupRepository)(nil).DetachDocument), ctx, group, documentID)
+}
+
+// DetachTree mocks base method.
+func (m *MockGroupRepository) DetachTree(ctx context.Context, group dto.Group, treeID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachTree", ctx, group, treeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachTree indicates an expected call of DetachTree.
+func (mr *MockGroupRepositoryMockRecorder) DetachTree(ctx, group, treeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachTree", reflect.TypeOf((*MockGroupRepository)(nil).DetachTree), ctx, group, treeID)
+}
+
+// Get mocks base method.
+func (m *MockGroupRepository) Get(ctx context.Context, group dto.Group) (dto.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, group)
+	ret0, _ := ret[0].(dto.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockGroupRepositoryMockRecorder) Get(ctx, group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockGroupRepository)(nil).Get), ctx, group)
+}
+
+// IsMember mocks base method.
+func (m *MockGroupRepository) IsMember(ctx context.Context, member dto.GroupMember) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsMember", ctx, member)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsMember indicates an expected call of IsMember.
+func (mr *MockGroupRepositoryMockRecorder) IsMember(ctx, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsMember", reflect.TypeOf((*MockGroupRepository)(nil).IsMember), ctx, member)
+}
+
+// List mocks base method.
+func (m *MockGroupRepository) List(ctx context.Context, userID string) ([]dto.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].([]dto.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockGroupRepositoryMockRecorder) List(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockGroupRepository)(nil).List), ctx, userID)
+}
+
+// RemoveMember mocks base method.
+func (m *MockGroupRepository) RemoveMember(ctx context.Context, member dto.GroupMember) (dto.GroupMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, member)
+	ret0, _ := ret[0].(dto.GroupMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockGroupRepositoryMockRecorder) RemoveMember(ctx, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockGroupRepository)(nil).RemoveMember), ctx, member)
+}
+
+// Update mocks base method.
+func (m *MockGroupRepository) Update(ctx context.Context, group dto.Group) (dto.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, group)
+	ret0, _ := ret[0].(dto.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockGroupRepositoryMockRecorder) Update(ctx, group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockGroupRepository)(nil).Update), ctx, group)
+}
+
+// MockAccessRepository is a mock of AccessRepository interface.
+type MockAccessRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessRepositoryMockRecorder
+}
+
+// MockAccessRepositoryMockRecorder is the mock recorder for MockAccessRepository.
+type MockAccessRepositoryMockRecorder struct {
+	mock *MockAccessRepository
+}
+
+// NewMockAccessRepository creates a new mock instance.
+func NewMockAccessRepository(ctrl *gomock.Controller) *MockAccessRepository {
+	mock := &MockAccessRepository{ctrl: ctrl}
+	mock.recorder = &MockAccessRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessRepository) EXPECT() *MockAccessRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Grant mocks base method.
+func (m *MockAccessRepository) Grant(ctx context.Context, access dto.TreeAccess) (dto.TreeAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Grant", ctx, access)
+	ret0, _ := ret[0].(dto.TreeAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Grant indicates an expected call of Grant.
+func (mr *MockAccessRepositoryMockRecorder) Grant(ctx, access interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Grant", reflect.TypeOf((*MockAccessRepository)(nil).Grant), ctx, access)
+}
+
+// Levels mocks base method.
+func (m *MockAccessRepository) Levels(ctx context.Context, treeID uint, userID string, roles []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Levels", ctx, treeID, userID, roles)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Levels indicates an expected call of Levels.
+func (mr *MockAccessRepositoryMockRecorder) Levels(ctx, treeID, userID, roles interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Levels", reflect.TypeOf((*MockAccessRepository)(nil).Levels), ctx, treeID, userID, roles)
+}
+
+// List mocks base method.
+func (m *MockAccessRepository) List(ctx context.Context, treeID uint) ([]dto.TreeAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, treeID)
+	ret0, _ := ret[0].([]dto.TreeAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAccessRepositoryMockRecorder) List(ctx, treeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAccessRepository)(nil).List), ctx, treeID)
+}
+
+// Revoke mocks base method.
+func (m *MockAccessRepository) Revoke(ctx context.Context, access dto.TreeAccess) (dto.TreeAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, access)
+	ret0, _ := ret[0].(dto.TreeAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockAccessRepositoryMockRecorder) Revoke(ctx, access interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockAccessRepository)(nil).Revoke), ctx, access)
+}
+
+// Shared mocks base method.
+func (m *MockAccessRepository) Shared(ctx context.Context, userID string, roles []string) ([]dto.TreeAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Shared", ctx, userID, roles)
+	ret0, _ := ret[0].([]dto.TreeAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Shared indicates an expected call of Shared.
+func (mr *MockAccessRepositoryMockRecorder) Shared(ctx, userID, roles interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shared", reflect.TypeOf((*MockAccessRepository)(nil).Shared), ctx, userID, roles)
+}
+
+// SharedTrees mocks base method.
+func (m *MockAccessRepository) SharedTrees(ctx context.Context, userID string, roles []string) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SharedTrees", ctx, userID, roles)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SharedTrees indicates an expected call of SharedTrees.
+func (mr *MockAccessRepositoryMockRecorder) SharedTrees(ctx, userID, roles interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SharedTrees", reflect.TypeOf((*MockAccessRepository)(nil).SharedTrees), ctx, userID, roles)
+}
+
+// MockTextRepository is a mock of TextRepository interface.
+type MockTextRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTextRepositoryMockRecorder
+}
+
+// MockTextRepositoryMockRecorder is the mock recorder for MockTextRepository.
+type MockTextRepositoryMockRecorder struct {
+	mock *MockTextRepository
+}
+
+// NewMockTextRepository creates a new mock instance.
+func NewMockTextRepository(ctrl *gomock.Controller) *MockTextRepository {
+	mock := &MockTextRepository{ctrl: ctrl}
+	mock.recorder = &MockTextRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTextRepository) EXPECT() *MockTextRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListDue mocks base method.
+func (m *MockTextRepository) ListDue(ctx context.Context, limit int) ([]dto.DocumentText, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDue", ctx, limit)
+	ret0, _ := ret[0].([]dto.DocumentText)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDue indicates an expected call of ListDue.
+func (mr *MockTextRepositoryMockRecorder) ListDue(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDue", reflect.TypeOf((*MockTextRepository)(nil).ListDue), ctx, limit)
+}
+
+// Retry mocks base method.
+func (m *MockTextRepository) Retry(ctx context.Context, text dto.DocumentText) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retry", ctx, text)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Retry indicates an expected call of Retry.
+func (mr *MockTextRepositoryMockRecorder) Retry(ctx, text interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retry", reflect.TypeOf((*MockTextRepository)(nil).Retry), ctx, text)
+}
+
+// Save mocks base method.
+func (m *MockTextRepository) Save(ctx context.Context, text dto.DocumentText) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, text)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockTextRepositoryMockRecorder) Save(ctx, text interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockTextRepository)(nil).Save), ctx, text)
+}
+
+// MockBlobRepository is a mock of BlobRepository interface.
+type MockBlobRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlobRepositoryMockRecorder
+}
+
+// MockBlobRepositoryMockRecorder is the mock recorder for MockBlobRepository.
+type MockBlobRepositoryMockRecorder struct {
+	mock *MockBlobRepository
+}
+
+// NewMockBlobRepository creates a new mock instance.
+func NewMockBlobRepository(ctrl *gomock.Controller) *MockBlobRepository {
+	mock := &MockBlobRepository{ctrl: ctrl}
+	mock.recorder = &MockBlobRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlobRepository) EXPECT() *MockBlobRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Acquire mocks base method.
+func (m *MockBlobRepository) Acquire(ctx context.Context, blob dto.Blob) (dto.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Acquire", ctx, blob)
+	ret0, _ := ret[0].(dto.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Acquire indicates an expected call of Acquire.
+func (mr *MockBlobRepositoryMockRecorder) Acquire(ctx, blob interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Acquire", reflect.TypeOf((*MockBlobRepository)(nil).Acquire), ctx, blob)
+}
+
+// Drop mocks base method.
+func (m *MockBlobRepository) Drop(ctx context.Context, checksum string, before time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Drop", ctx, checksum, before)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Drop indicates an expected call of Drop.
+func (mr *MockBlobRepositoryMockRecorder) Drop(ctx, checksum, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drop", reflect.TypeOf((*MockBlobRepository)(nil).Drop), ctx, checksum, before)
+}
+
+// List mocks base method.
+func (m *MockBlobRepository) List(ctx context.Context, after string, limit int) ([]dto.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, after, limit)
+	ret0, _ := ret[0].([]dto.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockBlobRepositoryMockRecorder) List(ctx, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockBlobRepository)(nil).List), ctx, after, limit)
+}
+
+// Release mocks base method.
+func (m *MockBlobRepository) Release(ctx context.Context, checksum string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, checksum)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockBlobRepositoryMockRecorder) Release(ctx, checksum interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockBlobRepository)(nil).Release), ctx, checksum)
+}
+
+// Stored mocks base method.
+func (m *MockBlobRepository) Stored(ctx context.Context, checksum string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stored", ctx, checksum)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stored indicates an expected call of Stored.
+func (mr *MockBlobRepositoryMockRecorder) Stored(ctx, checksum interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stored", reflect.TypeOf((*MockBlobRepository)(nil).Stored), ctx, checksum)
+}
+
+// Verified mocks base method.
+func (m *MockBlobRepository) Verified(ctx context.Context, blob dto.Blob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verified", ctx, blob)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Verified indicates an expected call of Verified.
+func (mr *MockBlobRepositoryMockRecorder) Verified(ctx, blob interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verified", reflect.TypeOf((*MockBlobRepository)(nil).Verified), ctx, blob)
+}
+
+// MockPreviewRepository is a mock of PreviewRepository interface.
+type MockPreviewRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPreviewRepositoryMockRecorder
+}
+
+// MockPreviewRepositoryMockRecorder is the mock recorder for MockPreviewRepository.
+type MockPreviewRepositoryMockRecorder struct {
+	mock *MockPreviewRepository
+}
+
+// NewMockPreviewRepository creates a new mock instance.
+func NewMockPreviewRepository(ctrl *gomock.Controller) *MockPreviewRepository {
+	mock := &MockPreviewRepository{ctrl: ctrl}
+	mock.recorder = &MockPreviewRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPreviewRepository) EXPECT() *MockPreviewRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListDue mocks base method.
+func (m *MockPreviewRepository) ListDue(ctx context.Context, limit int) ([]dto.DocumentPreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDue", ctx, limit)
+	ret0, _ := ret[0].([]dto.DocumentPreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDue indicates an expected call of ListDue.
+func (mr *MockPreviewRepositoryMockRecorder) ListDue(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDue", reflect.TypeOf((*MockPreviewRepository)(nil).ListDue), ctx, limit)
+}
+
+// Queue mocks base method.
+func (m *MockPreviewRepository) Queue(ctx context.Context, documentID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Queue", ctx, documentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Queue indicates an expected call of Queue.
+func (mr *MockPreviewRepositoryMockRecorder) Queue(ctx, documentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Queue", reflect.TypeOf((*MockPreviewRepository)(nil).Queue), ctx, documentID)
+}
+
+// Ready mocks base method.
+func (m *MockPreviewRepository) Ready(ctx context.Context, preview dto.DocumentPreview) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ready", ctx, preview)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ready indicates an expected call of Ready.
+func (mr *MockPreviewRepositoryMockRecorder) Ready(ctx, preview interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ready", reflect.TypeOf((*MockPreviewRepository)(nil).Ready), ctx, preview)
+}
+
+// Retry mocks base method.
+func (m *MockPreviewRepository) Retry(ctx context.Context, preview dto.DocumentPreview) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retry", ctx, preview)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Retry indicates an expected call of Retry.
+func (mr *MockPreviewRepositoryMockRecorder) Retry(ctx, preview interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retry", reflect.TypeOf((*MockPreviewRepository)(nil).Retry), ctx, preview)
+}
+
+// Skip mocks base method.
+func (m *MockPreviewRepository) Skip(ctx context.Context, preview dto.DocumentPreview) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Skip", ctx, preview)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Skip indicates an expected call of Skip.
+func (mr *MockPreviewRepositoryMockRecorder) Skip(ctx, preview interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Skip", reflect.TypeOf((*MockPreviewRepository)(nil).Skip), ctx, preview)
+}
+
+// MockQuotaRepository is a mock of QuotaRepository interface.
+type MockQuotaRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuotaRepositoryMockRecorder
+}
+
+// MockQuotaRepositoryMockRecorder is the mock recorder for MockQuotaRepository.
+type MockQuotaRepositoryMockRecorder struct {
+	mock *MockQuotaRepository
+}
+
+// NewMockQuotaRepository creates a new mock instance.
+func NewMockQuotaRepository(ctrl *gomock.Controller) *MockQuotaRepository {
+	mock := &MockQuotaRepository{ctrl: ctrl}
+	mock.recorder = &MockQuotaRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuotaRepository) EXPECT() *MockQuotaRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Applicable mocks base method.
+func (m *MockQuotaRepository) Applicable(ctx context.Context, usage dto.StorageUsage) ([]dto.Quota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Applicable", ctx, usage)
+	ret0, _ := ret[0].([]dto.Quota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Applicable indicates an expected call of Applicable.
+func (mr *MockQuotaRepositoryMockRecorder) Applicable(ctx, usage interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Applicable", reflect.TypeOf((*MockQuotaRepository)(nil).Applicable), ctx, usage)
+}
+
+// Delete mocks base method.
+func (m *MockQuotaRepository) Delete(ctx context.Context, quota dto.Quota) (dto.Quota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, quota)
+	ret0, _ := ret[0].(dto.Quota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockQuotaRepositoryMockRecorder) Delete(ctx, quota interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockQuotaRepository)(nil).Delete), ctx, quota)
+}
+
+// List mocks base method.
+func (m *MockQuotaRepository) List(ctx context.Context) ([]dto.Quota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]dto.Quota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockQuotaRepositoryMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockQuotaRepository)(nil).List), ctx)
+}
+
+// OrganizationBytes mocks base method.
+func (m *MockQuotaRepository) OrganizationBytes(ctx context.Context, organization int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrganizationBytes", ctx, organization)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OrganizationBytes indicates an expected call of OrganizationBytes.
+func (mr *MockQuotaRepositoryMockRecorder) OrganizationBytes(ctx, organization interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrganizationBytes", reflect.TypeOf((*MockQuotaRepository)(nil).OrganizationBytes), ctx, organization)
+}
+
+// Recalculate mocks base method.
+func (m *MockQuotaRepository) Recalculate(ctx context.Context) ([]dto.UsageCorrection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recalculate", ctx)
+	ret0, _ := ret[0].([]dto.UsageCorrection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recalculate indicates an expected call of Recalculate.
+func (mr *MockQuotaRepositoryMockRecorder) Recalculate(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recalculate", reflect.TypeOf((*MockQuotaRepository)(nil).Recalculate), ctx)
+}
+
+// Save mocks base method.
+func (m *MockQuotaRepository) Save(ctx context.Context, quota dto.Quota) (dto.Quota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, quota)
+	ret0, _ := ret[0].(dto.Quota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockQuotaRepositoryMockRecorder) Save(ctx, quota interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockQuotaRepository)(nil).Save), ctx, quota)
+}
+
+// Seen mocks base method.
+func (m *MockQuotaRepository) Seen(ctx context.Context, usage dto.StorageUsage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Seen", ctx, usage)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Seen indicates an expected call of Seen.
+func (mr *MockQuotaRepositoryMockRecorder) Seen(ctx, usage interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Seen", reflect.TypeOf((*MockQuotaRepository)(nil).Seen), ctx, usage)
+}
+
+// Usage mocks base method.
+func (m *MockQuotaRepository) Usage(ctx context.Context, userID string) (dto.StorageUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Usage", ctx, userID)
+	ret0, _ := ret[0].(dto.StorageUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Usage indicates an expected call of Usage.
+func (mr *MockQuotaRepositoryMockRecorder) Usage(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Usage", reflect.TypeOf((*MockQuotaRepository)(nil).Usage), ctx, userID)
+}
+
+// MockPolicyRepository is a mock of PolicyRepository interface.
+type MockPolicyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPolicyRepositoryMockRecorder
+}
+
+// MockPolicyRepositoryMockRecorder is the mock recorder for MockPolicyRepository.
+type MockPolicyRepositoryMockRecorder struct {
+	mock *MockPolicyRepository
+}
+
+// NewMockPolicyRepository creates a new mock instance.
+func NewMockPolicyRepository(ctrl *gomock.Controller) *MockPolicyRepository {
+	mock := &MockPolicyRepository{ctrl: ctrl}
+	mock.recorder = &MockPolicyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPolicyRepository) EXPECT() *MockPolicyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockPolicyRepository) Delete(ctx context.Context, treeID uint) (dto.TreePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, treeID)
+	ret0, _ := ret[0].(dto.TreePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPolicyRepositoryMockRecorder) Delete(ctx, treeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPolicyRepository)(nil).Delete), ctx, treeID)
+}
+
+// Get mocks base method.
+func (m *MockPolicyRepository) Get(ctx context.Context, treeID uint) (dto.TreePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, treeID)
+	ret0, _ := ret[0].(dto.TreePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPolicyRepositoryMockRecorder) Get(ctx, treeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPolicyRepository)(nil).Get), ctx, treeID)
+}
+
+// Inherited mocks base method.
+func (m *MockPolicyRepository) Inherited(ctx context.Context, treeID uint) ([]dto.TreePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Inherited", ctx, treeID)
+	ret0, _ := ret[0].([]dto.TreePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Inherited indicates an expected call of Inherited.
+func (mr *MockPolicyRepositoryMockRecorder) Inherited(ctx, treeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Inherited", reflect.TypeOf((*MockPolicyRepository)(nil).Inherited), ctx, treeID)
+}
+
+// Save mocks base method.
+func (m *MockPolicyRepository) Save(ctx context.Context, policy dto.TreePolicy) (dto.TreePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, policy)
+	ret0, _ := ret[0].(dto.TreePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockPolicyRepositoryMockRecorder) Save(ctx, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockPolicyRepository)(nil).Save), ctx, policy)
+}
+
+// MockShareRepository is a mock of ShareRepository interface.
+type MockShareRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockShareRepositoryMockRecorder
+}
+
+// MockShareRepositoryMockRecorder is the mock recorder for MockShareRepository.
+type MockShareRepositoryMockRecorder struct {
+	mock *MockShareRepository
+}
+
+// NewMockShareRepository creates a new mock instance.
+func NewMockShareRepository(ctrl *gomock.Controller) *MockShareRepository {
+	mock := &MockShareRepository{ctrl: ctrl}
+	mock.recorder = &MockShareRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShareRepository) EXPECT() *MockShareRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Accesses mocks base method.
+func (m *MockShareRepository) Accesses(ctx context.Context, shareID uint) ([]dto.ShareAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Accesses", ctx, shareID)
+	ret0, _ := ret[0].([]dto.ShareAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Accesses indicates an expected call of Accesses.
+func (mr *MockShareRepositoryMockRecorder) Accesses(ctx, shareID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accesses", reflect.TypeOf((*MockShareRepository)(nil).Accesses), ctx, shareID)
+}
+
+// ByToken mocks base method.
+func (m *MockShareRepository) ByToken(ctx context.Context, hash string) (dto.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ByToken", ctx, hash)
+	ret0, _ := ret[0].(dto.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ByToken indicates an expected call of ByToken.
+func (mr *MockShareRepositoryMockRecorder) ByToken(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ByToken", reflect.TypeOf((*MockShareRepository)(nil).ByToken), ctx, hash)
+}
+
+// Create mocks base method.
+func (m *MockShareRepository) Create(ctx context.Context, share dto.Share) (dto.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, share)
+	ret0, _ := ret[0].(dto.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockShareRepositoryMockRecorder) Create(ctx, share interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockShareRepository)(nil).Create), ctx, share)
+}
+
+// Get mocks base method.
+func (m *MockShareRepository) Get(ctx context.Context, shareID uint) (dto.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, shareID)
+	ret0, _ := ret[0].(dto.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockShareRepositoryMockRecorder) Get(ctx, shareID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockShareRepository)(nil).Get), ctx, shareID)
+}
+
+// List mocks base method.
+func (m *MockShareRepository) List(ctx context.Context, userID string, now time.Time) ([]dto.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID, now)
+	ret0, _ := ret[0].([]dto.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockShareRepositoryMockRecorder) List(ctx, userID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockShareRepository)(nil).List), ctx, userID, now)
+}
+
+// Log mocks base method.
+func (m *MockShareRepository) Log(ctx context.Context, access dto.ShareAccess) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Log", ctx, access)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Log indicates an expected call of Log.
+func (mr *MockShareRepositoryMockRecorder) Log(ctx, access interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Log", reflect.TypeOf((*MockShareRepository)(nil).Log), ctx, access)
+}
+
+// Revoke mocks base method.
+func (m *MockShareRepository) Revoke(ctx context.Context, share dto.Share) (dto.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, share)
+	ret0, _ := ret[0].(dto.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockShareRepositoryMockRecorder) Revoke(ctx, share interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockShareRepository)(nil).Revoke), ctx, share)
+}
+
+// Use mocks base method.
+func (m *MockShareRepository) Use(ctx context.Context, share dto.Share) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Use", ctx, share)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Use indicates an expected call of Use.
+func (mr *MockShareRepositoryMockRecorder) Use(ctx, share interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Use", reflect.TypeOf((*MockShareRepository)(nil).Use), ctx, share)
+}
+
+// MockDeletionRepository is a mock of DeletionRepository interface.
+type MockDeletionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeletionRepositoryMockRecorder
+}
+
+// MockDeletionRepositoryMockRecorder is the mock recorder for MockDeletionRepository.
+type MockDeletionRepositoryMockRecorder struct {
+	mock *MockDeletionRepository
+}
+
+// NewMockDeletionRepository creates a new mock instance.
+func NewMockDeletionRepository(ctrl *gomock.Controller) *MockDeletionRepository {
+	mock := &MockDeletionRepository{ctrl: ctrl}
+	mock.recorder = &MockDeletionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeletionRepository) EXPECT() *MockDeletionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Done mocks base method.
+func (m *MockDeletionRepository) Done(ctx context.Context, deletion dto.ObjectDeletion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Done", ctx, deletion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Done indicates an expected call of Done.
+func (mr *MockDeletionRepositoryMockRecorder) Done(ctx, deletion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Done", reflect.TypeOf((*MockDeletionRepository)(nil).Done), ctx, deletion)
+}
+
+// ListAfter mocks base method.
+func (m *MockDeletionRepository) ListAfter(ctx context.Context, afterID uint, limit int) ([]dto.ObjectDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAfter", ctx, afterID, limit)
+	ret0, _ := ret[0].([]dto.ObjectDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAfter indicates an expected call of ListAfter.
+func (mr *MockDeletionRepositoryMockRecorder) ListAfter(ctx, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAfter", reflect.TypeOf((*MockDeletionRepository)(nil).ListAfter), ctx, afterID, limit)
+}
+
+// ListDue mocks base method.
+func (m *MockDeletionRepository) ListDue(ctx context.Context, limit int) ([]dto.ObjectDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDue", ctx, limit)
+	ret0, _ := ret[0].([]dto.ObjectDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDue indicates an expected call of ListDue.
+func (mr *MockDeletionRepositoryMockRecorder) ListDue(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDue", reflect.TypeOf((*MockDeletionRepository)(nil).ListDue), ctx, limit)
+}
+
+// Retry mocks base method.
+func (m *MockDeletionRepository) Retry(ctx context.Context, deletion dto.ObjectDeletion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retry", ctx, deletion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Retry indicates an expected call of Retry.
+func (mr *MockDeletionRepositoryMockRecorder) Retry(ctx, deletion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retry", reflect.TypeOf((*MockDeletionRepository)(nil).Retry), ctx, deletion)
+}
+
+// MockUploadRepository is a mock of UploadRepository interface.
+type MockUploadRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUploadRepositoryMockRecorder
+}
+
+// MockUploadRepositoryMockRecorder is the mock recorder for MockUploadRepository.
+type MockUploadRepositoryMockRecorder struct {
+	mock *MockUploadRepository
+}
+
+// NewMockUploadRepository creates a new mock instance.
+func NewMockUploadRepository(ctrl *gomock.Controller) *MockUploadRepository {
+	mock := &MockUploadRepository{ctrl: ctrl}
+	mock.recorder = &MockUploadRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUploadRepository) EXPECT() *MockUploadRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockUploadRepository) Create(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, session)
+	ret0, _ := ret[0].(dto.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUploadRepositoryMockRecorder) Create(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUploadRepository)(nil).Create), ctx, session)
+}
+
+// Delete mocks base method.
+func (m *MockUploadRepository) Delete(ctx context.Context, session dto.UploadSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUploadRepositoryMockRecorder) Delete(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUploadRepository)(nil).Delete), ctx, session)
+}
+
+// Get mocks base method.
+func (m *MockUploadRepository) Get(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, session)
+	ret0, _ := ret[0].(dto.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockUploadRepositoryMockRecorder) Get(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUploadRepository)(nil).Get), ctx, session)
+}
+
+// List mocks base method.
+func (m *MockUploadRepository) List(ctx context.Context, session dto.UploadSession) ([]dto.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, session)
+	ret0, _ := ret[0].([]dto.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockUploadRepositoryMockRecorder) List(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUploadRepository)(nil).List), ctx, session)
+}
+
+// ListExpired mocks base method.
+func (m *MockUploadRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]dto.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpired", ctx, before, limit)
+	ret0, _ := ret[0].([]dto.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpired indicates an expected call of ListExpired.
+func (mr *MockUploadRepositoryMockRecorder) ListExpired(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpired", reflect.TypeOf((*MockUploadRepository)(nil).ListExpired), ctx, before, limit)
+}
+
+// SavePart mocks base method.
+func (m *MockUploadRepository) SavePart(ctx context.Context, part dto.UploadPart) (dto.UploadPart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePart", ctx, part)
+	ret0, _ := ret[0].(dto.UploadPart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SavePart indicates an expected call of SavePart.
+func (mr *MockUploadRepositoryMockRecorder) SavePart(ctx, part interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePart", reflect.TypeOf((*MockUploadRepository)(nil).SavePart), ctx, part)
+}
+
+// MockOutboxRepository is a mock of OutboxRepository interface.
+type MockOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryMockRecorder
+}
+
+// MockOutboxRepositoryMockRecorder is the mock recorder for MockOutboxRepository.
+type MockOutboxRepositoryMockRecorder struct {
+	mock *MockOutboxRepository
+}
+
+// NewMockOutboxRepository creates a new mock instance.
+func NewMockOutboxRepository(ctrl *gomock.Controller) *MockOutboxRepository {
+	mock := &MockOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepository) EXPECT() *MockOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockOutboxRepository) Claim(ctx context.Context, before, until time.Time, limit int) ([]dto.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, before, until, limit)
+	ret0, _ := ret[0].([]dto.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockOutboxRepositoryMockRecorder) Claim(ctx, before, until, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockOutboxRepository)(nil).Claim), ctx, before, until, limit)
+}
+
+// Done mocks base method.
+func (m *MockOutboxRepository) Done(ctx context.Context, event dto.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Done", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Done indicates an expected call of Done.
+func (mr *MockOutboxRepositoryMockRecorder) Done(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Done", reflect.TypeOf((*MockOutboxRepository)(nil).Done), ctx, event)
+}
+
+// Retry mocks base method.
+func (m *MockOutboxRepository) Retry(ctx context.Context, event dto.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retry", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Retry indicates an expected call of Retry.
+func (mr *MockOutboxRepositoryMockRecorder) Retry(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retry", reflect.TypeOf((*MockOutboxRepository)(nil).Retry), ctx, event)
+}
+
+// MockLockRepository is a mock of LockRepository interface.
+type MockLockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLockRepositoryMockRecorder
+}
+
+// MockLockRepositoryMockRecorder is the mock recorder for MockLockRepository.
+type MockLockRepositoryMockRecorder struct {
+	mock *MockLockRepository
+}
+
+// NewMockLockRepository creates a new mock instance.
+func NewMockLockRepository(ctrl *gomock.Controller) *MockLockRepository {
+	mock := &MockLockRepository{ctrl: ctrl}
+	mock.recorder = &MockLockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLockRepository) EXPECT() *MockLockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// TryLock mocks base method.
+func (m *MockLockRepository) TryLock(ctx context.Context, key int64, fn func(context.Context) error) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TryLock", ctx, key, fn)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TryLock indicates an expected call of TryLock.
+func (mr *MockLockRepositoryMockRecorder) TryLock(ctx, key, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryLock", reflect.TypeOf((*MockLockRepository)(nil).TryLock), ctx, key, fn)
+}
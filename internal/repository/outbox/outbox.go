@@ -0,0 +1,71 @@
+package outbox
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (fm *Repository) Claim(ctx context.Context, before time.Time, until time.Time, limit int) ([]dto.OutboxEvent, error) {
+	var events []dto.OutboxEvent
+	err := fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// events another process is claiming are skipped instead of waited for
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("created_at <= ?", before).
+			Where("retry_at <= ?", time.Now()).
+			Order("id").
+			Limit(limit).
+			Find(&events).
+			Error; err != nil {
+			return err
+		}
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(events))
+		for _, event := range events {
+			ids = append(ids, event.ID)
+		}
+
+		// a claim left by a process that stopped midway runs out at until
+		return tx.Model(&dto.OutboxEvent{}).
+			Where("id in ?", ids).
+			Update("retry_at", until).
+			Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (fm *Repository) Done(ctx context.Context, event dto.OutboxEvent) error {
+	logrus.Debugf("[input]: %+v", event)
+
+	return fm.db.WithContext(ctx).Delete(&dto.OutboxEvent{}, event.ID).Error
+}
+
+func (fm *Repository) Retry(ctx context.Context, event dto.OutboxEvent) error {
+	logrus.Debugf("[input]: %+v", event)
+
+	return fm.db.WithContext(ctx).
+		Model(&event).
+		Select("attempts", "last_error", "retry_at").
+		Updates(&event).
+		Error
+}
@@ -0,0 +1,82 @@
+package policies
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (fm *Repository) Get(ctx context.Context, treeID uint) (dto.TreePolicy, error) {
+	var policy dto.TreePolicy
+	if err := fm.db.WithContext(ctx).
+		Where("tree_id = ?", treeID).
+		First(&policy).
+		Error; err != nil {
+		return policy, err
+	}
+	return policy, nil
+}
+
+func (fm *Repository) Inherited(ctx context.Context, treeID uint) ([]dto.TreePolicy, error) {
+	logrus.Debugf("[input]: %+v", treeID)
+
+	// a policy covers the whole subtree, so the ones on every ancestor of the tree count
+	sql := `WITH RECURSIVE ancestors AS (
+		SELECT t1.id, t1.parent_id
+		FROM   trees t1
+		WHERE  t1.id = ? and t1.deleted_at is null
+
+		UNION  ALL
+		SELECT t2.id, t2.parent_id
+		FROM trees t2 JOIN ancestors a ON t2.id = a.parent_id and t2.deleted_at is null
+	) SELECT tp.* FROM tree_policies tp JOIN ancestors a ON tp.tree_id = a.id`
+
+	var policies []dto.TreePolicy
+	if err := fm.db.WithContext(ctx).
+		Raw(sql, treeID).
+		Scan(&policies).
+		Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (fm *Repository) Save(ctx context.Context, policy dto.TreePolicy) (dto.TreePolicy, error) {
+	logrus.Debugf("[input]: %+v", policy)
+
+	return policy, fm.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tree_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"max_size", "allow", "deny", "updated_at"}),
+		}).
+		Create(&policy).
+		Error
+}
+
+func (fm *Repository) Delete(ctx context.Context, treeID uint) (dto.TreePolicy, error) {
+	logrus.Debugf("[input]: %+v", treeID)
+
+	policy := dto.TreePolicy{TreeID: treeID}
+	tx := fm.db.WithContext(ctx).Clauses(clause.Returning{}).Delete(&policy)
+	if tx.Error != nil {
+		return policy, tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return policy, gorm.ErrRecordNotFound
+	}
+
+	return policy, nil
+}
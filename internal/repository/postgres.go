@@ -1,8 +1,10 @@
 package repository
 
 import (
+	"context"
 	"fmt"
-	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/migrations"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/migrate"
 	"log"
 
 	_ "github.com/lib/pq"
@@ -19,23 +21,44 @@ type Config struct {
 	SSLMode  string
 }
 
+// NewPostgresRepository connects to the database and refuses a schema that isn't the one of the migrations
 func NewPostgresRepository(cfg Config) *gorm.DB {
+	db := OpenPostgres(cfg)
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		log.Fatalf("an error is occurred while loading migrations: %s", err.Error())
+	}
+
+	if err = migrator.Check(context.Background()); err != nil {
+		log.Fatalf("an error is occurred while checking the schema: %s, run the migrate command", err.Error())
+	}
+
+	return db
+}
+
+// OpenPostgres connects to the database whatever its schema is
+func OpenPostgres(cfg Config) *gorm.DB {
 	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s", cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.Dbname, cfg.SSLMode)
 	db, err := gorm.Open(postgres.Open(connStr), nil)
 	if err != nil {
 		log.Fatalf("an error is occured while connecting: %s", err.Error())
 	}
 
-	if err = AutoMigrate(db); err != nil {
-		log.Fatalf("an error is occurred while migrating: %s", err.Error())
-	}
-
 	return db
 }
 
-func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&dto.Tree{},
-		&dto.Document{},
-	)
+// NewMigrator runs the migrations embedded in the binary against the database
+func NewMigrator(db *gorm.DB) (*migrate.Migrator, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	loaded, err := migrate.Load(migrations.Files)
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.NewMigrator(sqlDB, loaded), nil
 }
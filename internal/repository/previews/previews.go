@@ -0,0 +1,84 @@
+package previews
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// MaxAttempts is the number of failed previews after which a document is left without thumbnails
+const MaxAttempts = 5
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+// Queue schedules thumbnails of a document, a document queued again
+// is previewed anew from its current content
+func (fm *Repository) Queue(ctx context.Context, documentID uint) error {
+	logrus.Debugf("[input]: %+v", documentID)
+
+	return fm.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "document_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"ready", "attempts", "last_error", "retry_at", "updated_at"}),
+		}).
+		Omit("Document").
+		Create(&dto.DocumentPreview{DocumentID: documentID, RetryAt: time.Now()}).
+		Error
+}
+
+func (fm *Repository) ListDue(ctx context.Context, limit int) ([]dto.DocumentPreview, error) {
+	var previews []dto.DocumentPreview
+	if err := fm.db.WithContext(ctx).
+		Preload("Document", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("ready = false and attempts < ? and retry_at <= ?", MaxAttempts, time.Now()).
+		Order("retry_at").
+		Limit(limit).
+		Find(&previews).
+		Error; err != nil {
+		return nil, err
+	}
+	return previews, nil
+}
+
+func (fm *Repository) Ready(ctx context.Context, preview dto.DocumentPreview) error {
+	logrus.Debugf("[input]: %+v", preview.DocumentID)
+
+	return fm.db.WithContext(ctx).
+		Model(&preview).
+		Select("ready", "last_error").
+		Updates(&dto.DocumentPreview{Ready: true}).
+		Error
+}
+
+func (fm *Repository) Skip(ctx context.Context, preview dto.DocumentPreview) error {
+	logrus.Debugf("[input]: %+v, %+v", preview.DocumentID, preview.LastError)
+
+	preview.Attempts = MaxAttempts
+
+	return fm.db.WithContext(ctx).
+		Model(&preview).
+		Select("attempts", "last_error").
+		Updates(&preview).
+		Error
+}
+
+func (fm *Repository) Retry(ctx context.Context, preview dto.DocumentPreview) error {
+	logrus.Debugf("[input]: %+v, %+v", preview.DocumentID, preview.LastError)
+
+	return fm.db.WithContext(ctx).
+		Model(&preview).
+		Select("attempts", "last_error", "retry_at").
+		Updates(&preview).
+		Error
+}
@@ -0,0 +1,212 @@
+package quotas
+
+import (
+	"context"
+	"errors"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strconv"
+	"time"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (fm *Repository) Usage(ctx context.Context, userID string) (dto.StorageUsage, error) {
+	usage := dto.StorageUsage{UserID: userID, Roles: pq.StringArray{}}
+
+	err := fm.db.WithContext(ctx).Where("user_id = ?", userID).First(&usage).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return usage, nil
+	}
+	return usage, err
+}
+
+func (fm *Repository) Seen(ctx context.Context, usage dto.StorageUsage) error {
+	logrus.Debugf("[input]: %+v", usage)
+
+	return fm.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"organization", "roles", "updated_at"}),
+		}).
+		Create(&dto.StorageUsage{UserID: usage.UserID, Organization: usage.Organization, Roles: usage.Roles}).
+		Error
+}
+
+func (fm *Repository) OrganizationBytes(ctx context.Context, organization int) (int64, error) {
+	var bytes int64
+	if err := fm.db.WithContext(ctx).
+		Model(&dto.StorageUsage{}).
+		Select("coalesce(sum(bytes), 0)").
+		Where("organization = ?", organization).
+		Scan(&bytes).
+		Error; err != nil {
+		return 0, err
+	}
+	return bytes, nil
+}
+
+func (fm *Repository) Applicable(ctx context.Context, usage dto.StorageUsage) ([]dto.Quota, error) {
+	tx := fm.db.WithContext(ctx).
+		Where("(subject = ? and subject_id = ?)", modules.QuotaUser, usage.UserID).
+		Or("(subject = ? and subject_id in ?)", modules.QuotaRole, []string(usage.Roles))
+	if usage.Organization != 0 {
+		tx = tx.Or("(subject = ? and subject_id = ?)", modules.QuotaOrganization, strconv.Itoa(usage.Organization))
+	}
+
+	var quotas []dto.Quota
+	if err := tx.Find(&quotas).Error; err != nil {
+		return nil, err
+	}
+	return quotas, nil
+}
+
+func (fm *Repository) List(ctx context.Context) ([]dto.Quota, error) {
+	var quotas []dto.Quota
+	if err := fm.db.WithContext(ctx).
+		Order("subject, subject_id").
+		Find(&quotas).
+		Error; err != nil {
+		return nil, err
+	}
+	return quotas, nil
+}
+
+func (fm *Repository) Save(ctx context.Context, quota dto.Quota) (dto.Quota, error) {
+	logrus.Debugf("[input]: %+v", quota)
+
+	return quota, fm.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "subject"}, {Name: "subject_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"max_bytes", "updated_at"}),
+		}).
+		Create(&quota).
+		Error
+}
+
+func (fm *Repository) Delete(ctx context.Context, quota dto.Quota) (dto.Quota, error) {
+	logrus.Debugf("[input]: %+v", quota)
+
+	tx := fm.db.WithContext(ctx).Clauses(clause.Returning{}).Delete(&quota)
+	if tx.Error != nil {
+		return quota, tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return quota, gorm.ErrRecordNotFound
+	}
+
+	return quota, nil
+}
+
+// stored is the usage of every user counted from its documents and their versions,
+// next to the usage recorded, for the users whose both differ. A deleting document
+// gave back its usage when it was purged and a missing one when it was quarantined,
+// the current content of a document with history is one of its versions.
+const stored = `with actual as (
+		select user_id, sum(bytes) as bytes from (
+			select user_id, size as bytes from documents d where state not in ('deleting', 'missing')
+				and not exists (select 1 from document_versions v where v.document_id = d.id)
+			union all
+			select d.user_id, v.size from document_versions v join documents d on d.id = v.document_id
+		) content group by user_id
+	)
+	select coalesce(a.user_id, u.user_id) as user_id,
+		coalesce(u.bytes, 0) as before,
+		coalesce(a.bytes, 0) as after
+	from actual a full join storage_usages u on u.user_id = a.user_id
+	where coalesce(u.bytes, 0) <> coalesce(a.bytes, 0);`
+
+func (fm *Repository) Recalculate(ctx context.Context) ([]dto.UsageCorrection, error) {
+	corrections := make([]dto.UsageCorrection, 0)
+
+	err := fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// usage writes wait for the correction, so none is lost between counting and correcting
+		if err := tx.Exec(`lock table storage_usages in exclusive mode;`).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Raw(stored).Scan(&corrections).Error; err != nil {
+			return err
+		}
+
+		for _, correction := range corrections {
+			if err := Add(tx, correction.UserID, correction.After-correction.Before); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return corrections, err
+}
+
+// use adds bytes to the usage of a user only while it stays within the quotas. The own
+// quota of the user overrides the most generous of its roles, the quota of its organization
+// is checked against all of its users together. Without a quota the bytes always fit.
+const use = `update storage_usages u set bytes = u.bytes + ?, updated_at = now()
+	where u.user_id = ?
+	and u.bytes + ? <= coalesce(
+		(select max_bytes from quota where subject = 'user' and subject_id = u.user_id),
+		(select max(max_bytes) from quota where subject = 'role' and subject_id = any(u.roles)),
+		u.bytes + ?)
+	and not exists (
+		select 1 from quota q
+		where q.subject = 'organization' and q.subject_id = u.organization::text
+		and q.max_bytes < (select sum(bytes) from storage_usages where organization = u.organization) + ?);`
+
+// Use counts bytes stored anew by a user inside tx. The quotas are checked by the same
+// statement that counts the bytes, so concurrent uploads can't exceed them together,
+// ErrQuota is returned when the bytes don't fit. Removed content always fits.
+func Use(tx *gorm.DB, userID string, bytes int64) error {
+	if bytes <= 0 {
+		return Add(tx, userID, bytes)
+	}
+
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&dto.StorageUsage{UserID: userID}).
+		Error; err != nil {
+		return err
+	}
+
+	res := tx.Exec(use, bytes, userID, bytes, bytes, bytes)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return modules.ErrQuota
+	}
+
+	return nil
+}
+
+// Add counts bytes stored by a user inside tx, removed content is counted with negative bytes
+func Add(tx *gorm.DB, userID string, bytes int64) error {
+	if bytes == 0 {
+		return nil
+	}
+
+	return tx.
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"bytes":      gorm.Expr("storage_usages.bytes + excluded.bytes"),
+				"updated_at": time.Now(),
+			}),
+		}).
+		Create(&dto.StorageUsage{UserID: userID, Bytes: bytes}).
+		Error
+}
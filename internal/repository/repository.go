@@ -2,50 +2,324 @@ package repository
 
 import (
 	"context"
+	"github.com/google/uuid"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/access"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/blobs"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/deletions"
 	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/documents"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/groups"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/locks"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/outbox"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/policies"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/previews"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/quotas"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/shares"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/texts"
 	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/tree"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/uploads"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/versions"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
 	"gorm.io/gorm"
+	"time"
 )
 
+//go:generate mockgen -source=repository.go -destination=mocks/mock.go
+
 type DocumentRepository interface {
 	// Create creates a new document
 	Create(ctx context.Context, doc dto.Document) (dto.Document, error)
+	// Duplicate creates a copy of a document, an uploading copy waits for its content to be copied from the source object
+	Duplicate(ctx context.Context, doc dto.Document, source string) (dto.Document, error)
 	// Get returns a document
 	Get(ctx context.Context, doc dto.Document) (dto.Document, error)
-	// Delete deletes a document
+	// Delete moves a document to the trash
 	Delete(ctx context.Context, doc dto.Document) (dto.Document, error)
 	// Update updates a document
 	Update(ctx context.Context, doc dto.Document) (dto.Document, error)
-	// FindByCondition Filter documents by condition
-	FindByCondition(ctx context.Context, field, param string) ([]dto.Document, error)
+	// Search returns a page of documents matching a search
+	Search(ctx context.Context, search dto.DocumentSearch) (dto.DocumentPage, error)
 	// ListByTree returns a slice of documents by tree id
 	ListByTree(ctx context.Context, ids []uint) ([]dto.Document, error)
 	// ListByGroups returns a slice of documents by group id
 	ListByGroups(ctx context.Context, ids []uint) ([]dto.Document, error)
+	// Move links a document to another tree
+	Move(ctx context.Context, doc dto.Document, treeID uint) (dto.Document, error)
+	// UpdateContent updates size, type and current version of a document
+	UpdateContent(ctx context.Context, doc dto.Document) (dto.Document, error)
+	// Scanned stores the result of the malware scan of a document
+	Scanned(ctx context.Context, doc dto.Document) (dto.Document, error)
+	// ListTrash returns documents a user moved to the trash
+	ListTrash(ctx context.Context, userID string) ([]dto.Document, error)
+	// Restore takes a document out of the trash
+	Restore(ctx context.Context, doc dto.Document) (dto.Document, error)
+	// ListExpired returns documents trashed before the given time
+	ListExpired(ctx context.Context, before time.Time) ([]dto.Document, error)
+	// Purge permanently deletes documents and queues removal of their objects
+	Purge(ctx context.Context, ids []uint) (dto.Purge, error)
+	// FirstCreated returns the document with those in the trash created first at or after a time
+	FirstCreated(ctx context.Context, from time.Time) (dto.Document, error)
+	// ListCreated returns documents with those in the trash created within a period, ordered by path starting after one
+	ListCreated(ctx context.Context, from, to time.Time, afterPath uuid.UUID, limit int) ([]dto.Document, error)
+	// ListChecksums returns the checksums documents and their versions point at in order, starting after one
+	ListChecksums(ctx context.Context, after string, limit int) ([]string, error)
+	// ListByChecksums returns documents with those in the trash pointing at a checksum themselves or by a version
+	ListByChecksums(ctx context.Context, checksums []string) ([]dto.Document, error)
+	// Ready marks a document whose content reached spaces as ready
+	Ready(ctx context.Context, doc dto.Document) (dto.Document, error)
+	// Find returns a document by id in any state, in the trash or not
+	Find(ctx context.Context, id uint) (dto.Document, error)
+	// Deduplicate points a document kept under its own key at the blob of its checksum
+	Deduplicate(ctx context.Context, doc dto.Document) (dto.Document, error)
+	// Quarantine marks ready documents whose content is missing and moves them into the trash
+	Quarantine(ctx context.Context, ids []uint) error
+}
+
+type VersionRepository interface {
+	// Create creates a new document version
+	Create(ctx context.Context, version dto.DocumentVersion) (dto.DocumentVersion, error)
+	// Get returns a document version by document id and version number
+	Get(ctx context.Context, version dto.DocumentVersion) (dto.DocumentVersion, error)
+	// List returns all versions of a document, newest first
+	List(ctx context.Context, documentID uint) ([]dto.DocumentVersion, error)
+	// ListByDocuments returns the versions of documents
+	ListByDocuments(ctx context.Context, ids []uint) ([]dto.DocumentVersion, error)
+	// Remove deletes a single version, releasing what it counted for
+	Remove(ctx context.Context, version dto.DocumentVersion) error
+	// Delete deletes all versions of a document
+	Delete(ctx context.Context, documentID uint) error
 }
 
 type TreeRepository interface {
 	// Create creates a new tree
 	Create(ctx context.Context, tree dto.Tree) (dto.Tree, error)
+	// Copy creates a root and copies of the descendants of a source tree under it in one
+	// transaction. The descendants keep the ids of their originals and come parents first,
+	// the ids of the copies are returned by the ids of the originals.
+	Copy(ctx context.Context, root dto.Tree, sourceID uint, descendants []dto.Tree) (dto.Tree, map[uint]uint, error)
 	// Get returns a tree
 	Get(ctx context.Context, tree dto.Tree) (dto.Tree, error)
 	// List returns a tree
 	List(ctx context.Context, tree dto.Tree) ([]dto.Tree, error)
+	// ListTemplates returns templates meant for the roles, nil roles return all templates
+	ListTemplates(ctx context.Context, roles []string) ([]dto.Tree, error)
 	// Update deletes a tree
 	Update(ctx context.Context, tree dto.Tree) (dto.Tree, error)
-	// Delete deletes a tree
+	// Move changes the parent of a tree, refusing to move it into its own subtree
+	Move(ctx context.Context, tree dto.Tree) (dto.Tree, error)
+	// Delete moves a tree with its subtree to the trash
 	Delete(ctx context.Context, tree dto.Tree) (dto.Tree, error)
+	// ListTrash returns trees a user moved to the trash
+	ListTrash(ctx context.Context, userID string) ([]dto.Tree, error)
+	// Restore takes a tree with its subtree out of the trash
+	Restore(ctx context.Context, tree dto.Tree) (dto.Tree, error)
+	// Purge permanently deletes a tree with its subtree and documents
+	Purge(ctx context.Context, tree dto.Tree) (dto.Purge, error)
+	// PurgeExpired permanently deletes trees trashed before the given time
+	PurgeExpired(ctx context.Context, before time.Time) (dto.Purge, error)
+}
+
+type GroupRepository interface {
+	// Create creates a new group
+	Create(ctx context.Context, group dto.Group) (dto.Group, error)
+	// Get returns a group with its members and trees
+	Get(ctx context.Context, group dto.Group) (dto.Group, error)
+	// List returns groups a user owns or is a member of
+	List(ctx context.Context, userID string) ([]dto.Group, error)
+	// Update updates a group of its owner
+	Update(ctx context.Context, group dto.Group) (dto.Group, error)
+	// Delete deletes a group of its owner
+	Delete(ctx context.Context, group dto.Group) (dto.Group, error)
+	// IsMember reports whether a user is a member of a group
+	IsMember(ctx context.Context, member dto.GroupMember) (bool, error)
+	// AddMember adds a user to a group
+	AddMember(ctx context.Context, member dto.GroupMember) (dto.GroupMember, error)
+	// RemoveMember removes a user from a group
+	RemoveMember(ctx context.Context, member dto.GroupMember) (dto.GroupMember, error)
+	// AttachDocument shares a document of the group owner with a group
+	AttachDocument(ctx context.Context, group dto.Group, documentID uint) error
+	// DetachDocument stops sharing a document with a group
+	DetachDocument(ctx context.Context, group dto.Group, documentID uint) error
+	// AttachTree shares a tree of the group owner with a group
+	AttachTree(ctx context.Context, group dto.Group, treeID uint) error
+	// DetachTree stops sharing a tree with a group
+	DetachTree(ctx context.Context, group dto.Group, treeID uint) error
+}
+
+type AccessRepository interface {
+	// Grant adds an entry to the access list of a tree or changes its level
+	Grant(ctx context.Context, access dto.TreeAccess) (dto.TreeAccess, error)
+	// Revoke removes an entry from the access list of a tree
+	Revoke(ctx context.Context, access dto.TreeAccess) (dto.TreeAccess, error)
+	// List returns the access list of a tree
+	List(ctx context.Context, treeID uint) ([]dto.TreeAccess, error)
+	// Levels returns the levels granted to a user on a tree and its ancestors
+	Levels(ctx context.Context, treeID uint, userID string, roles []string) ([]string, error)
+	// Shared returns entries granting a user access to trees of other users
+	Shared(ctx context.Context, userID string, roles []string) ([]dto.TreeAccess, error)
+	// SharedTrees returns ids of trees of other users a user can access, with their subtrees
+	SharedTrees(ctx context.Context, userID string, roles []string) ([]uint, error)
+}
+
+type TextRepository interface {
+	// ListDue returns queued text extractions ready to be attempted
+	ListDue(ctx context.Context, limit int) ([]dto.DocumentText, error)
+	// Save stores the extracted text of a document and indexes it
+	Save(ctx context.Context, text dto.DocumentText) error
+	// Retry stores a failed attempt of a text extraction
+	Retry(ctx context.Context, text dto.DocumentText) error
+}
+
+type BlobRepository interface {
+	// Acquire adds a reference to content stored under a checksum, a blob not stored yet has to be uploaded
+	Acquire(ctx context.Context, blob dto.Blob) (dto.Blob, error)
+	// Stored marks the object of a blob as uploaded
+	Stored(ctx context.Context, checksum string) error
+	// Release drops a reference to a blob and queues it for removal when none is left
+	Release(ctx context.Context, checksum string) error
+	// List returns blobs ordered by checksum, starting after one
+	List(ctx context.Context, after string, limit int) ([]dto.Blob, error)
+	// Verified records that the content of a blob matched its checksum
+	Verified(ctx context.Context, blob dto.Blob) error
+	// Drop forgets a blob nothing points at and queues its object for removal
+	Drop(ctx context.Context, checksum string, before time.Time) (bool, error)
+}
+
+type PreviewRepository interface {
+	// Queue schedules thumbnails of a document once its content is uploaded
+	Queue(ctx context.Context, documentID uint) error
+	// ListDue returns queued previews ready to be attempted
+	ListDue(ctx context.Context, limit int) ([]dto.DocumentPreview, error)
+	// Ready marks the thumbnails of a document as stored
+	Ready(ctx context.Context, preview dto.DocumentPreview) error
+	// Skip leaves a document without thumbnails, its format has none
+	Skip(ctx context.Context, preview dto.DocumentPreview) error
+	// Retry stores a failed attempt of a preview
+	Retry(ctx context.Context, preview dto.DocumentPreview) error
+}
+
+type QuotaRepository interface {
+	// Usage returns the bytes a user stores with the roles and organization last seen
+	Usage(ctx context.Context, userID string) (dto.StorageUsage, error)
+	// Seen records the roles and organization a user uploads with
+	Seen(ctx context.Context, usage dto.StorageUsage) error
+	// OrganizationBytes returns the bytes stored by all users of an organization
+	OrganizationBytes(ctx context.Context, organization int) (int64, error)
+	// Applicable returns the quotas of a user, its roles and its organization
+	Applicable(ctx context.Context, usage dto.StorageUsage) ([]dto.Quota, error)
+	// List returns all quotas
+	List(ctx context.Context) ([]dto.Quota, error)
+	// Save creates a quota or changes the limit of its subject
+	Save(ctx context.Context, quota dto.Quota) (dto.Quota, error)
+	// Delete deletes a quota
+	Delete(ctx context.Context, quota dto.Quota) (dto.Quota, error)
+	// Recalculate sets the usage of every user to the bytes its documents and their versions store
+	Recalculate(ctx context.Context) ([]dto.UsageCorrection, error)
+}
+
+type PolicyRepository interface {
+	// Get returns the upload policy set on a tree
+	Get(ctx context.Context, treeID uint) (dto.TreePolicy, error)
+	// Inherited returns the upload policies of a tree and its ancestors
+	Inherited(ctx context.Context, treeID uint) ([]dto.TreePolicy, error)
+	// Save sets the upload policy of a tree
+	Save(ctx context.Context, policy dto.TreePolicy) (dto.TreePolicy, error)
+	// Delete removes the upload policy of a tree
+	Delete(ctx context.Context, treeID uint) (dto.TreePolicy, error)
+}
+
+type ShareRepository interface {
+	// Create stores a new share link
+	Create(ctx context.Context, share dto.Share) (dto.Share, error)
+	// Get returns a share link by id
+	Get(ctx context.Context, shareID uint) (dto.Share, error)
+	// ByToken returns a share link by the hash of its token with its document or folder, unless it is in the trash
+	ByToken(ctx context.Context, hash string) (dto.Share, error)
+	// List returns usable share links of documents a user owns or shared
+	List(ctx context.Context, userID string, now time.Time) ([]dto.Share, error)
+	// Revoke stops a share link from working
+	Revoke(ctx context.Context, share dto.Share) (dto.Share, error)
+	// Use counts a download of a share link, false when its downloads are used up
+	Use(ctx context.Context, share dto.Share) (bool, error)
+	// Log records an attempt to open a share link
+	Log(ctx context.Context, access dto.ShareAccess) error
+	// Accesses returns the attempts to open a share link, newest first
+	Accesses(ctx context.Context, shareID uint) ([]dto.ShareAccess, error)
+}
+
+type DeletionRepository interface {
+	// ListDue returns queued object deletions ready to be attempted
+	ListDue(ctx context.Context, limit int) ([]dto.ObjectDeletion, error)
+	// ListAfter returns queued object deletions ordered by id, starting after one
+	ListAfter(ctx context.Context, afterID uint, limit int) ([]dto.ObjectDeletion, error)
+	// Done removes a completed object deletion from the queue
+	Done(ctx context.Context, deletion dto.ObjectDeletion) error
+	// Retry stores a failed attempt of an object deletion
+	Retry(ctx context.Context, deletion dto.ObjectDeletion) error
+}
+
+type UploadRepository interface {
+	// Create creates a new upload session
+	Create(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error)
+	// Get returns an upload session with its received parts
+	Get(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error)
+	// List returns unfinished upload sessions of a user in a tree
+	List(ctx context.Context, session dto.UploadSession) ([]dto.UploadSession, error)
+	// SavePart stores or replaces a received part of an upload session
+	SavePart(ctx context.Context, part dto.UploadPart) (dto.UploadPart, error)
+	// Delete deletes an upload session with its parts
+	Delete(ctx context.Context, session dto.UploadSession) error
+	// ListExpired returns upload sessions that expired before the given time
+	ListExpired(ctx context.Context, before time.Time, limit int) ([]dto.UploadSession, error)
+}
+
+type OutboxRepository interface {
+	// Claim returns outbox events created before a time and ready to be attempted, other processes skip them until a time
+	Claim(ctx context.Context, before time.Time, until time.Time, limit int) ([]dto.OutboxEvent, error)
+	// Done removes a settled outbox event
+	Done(ctx context.Context, event dto.OutboxEvent) error
+	// Retry stores a failed attempt of an outbox event
+	Retry(ctx context.Context, event dto.OutboxEvent) error
+}
+
+type LockRepository interface {
+	// TryLock runs fn holding an advisory lock and reports whether it ran, a lock held elsewhere skips it
+	TryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) (bool, error)
 }
 
 type Repository struct {
 	DocumentRepository
 	TreeRepository
+	VersionRepository
+	UploadRepository
+	DeletionRepository
+	OutboxRepository
+	GroupRepository
+	AccessRepository
+	TextRepository
+	PreviewRepository
+	BlobRepository
+	QuotaRepository
+	PolicyRepository
+	ShareRepository
+	LockRepository
 }
 
 func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{
 		DocumentRepository: documents.NewRepository(db),
 		TreeRepository:     tree.NewRepository(db),
+		VersionRepository:  versions.NewRepository(db),
+		UploadRepository:   uploads.NewRepository(db),
+		DeletionRepository: deletions.NewRepository(db),
+		OutboxRepository:   outbox.NewRepository(db),
+		GroupRepository:    groups.NewRepository(db),
+		AccessRepository:   access.NewRepository(db),
+		TextRepository:     texts.NewRepository(db),
+		PreviewRepository:  previews.NewRepository(db),
+		BlobRepository:     blobs.NewRepository(db),
+		QuotaRepository:    quotas.NewRepository(db),
+		PolicyRepository:   policies.NewRepository(db),
+		ShareRepository:    shares.NewRepository(db),
+		LockRepository:     locks.NewRepository(db),
 	}
 }
@@ -0,0 +1,128 @@
+package shares
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"time"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (fm *Repository) Create(ctx context.Context, share dto.Share) (dto.Share, error) {
+	logrus.Debugf("[input]: %+v", share)
+
+	if err := fm.db.WithContext(ctx).Omit("Document", "Tree").Create(&share).Error; err != nil {
+		return share, err
+	}
+	return share, nil
+}
+
+func (fm *Repository) Get(ctx context.Context, shareID uint) (dto.Share, error) {
+	var share dto.Share
+	if err := fm.db.WithContext(ctx).
+		Where("id = ?", shareID).
+		First(&share).
+		Error; err != nil {
+		return share, err
+	}
+	return share, nil
+}
+
+func (fm *Repository) ByToken(ctx context.Context, hash string) (dto.Share, error) {
+	var share dto.Share
+	if err := fm.db.WithContext(ctx).
+		Preload("Document", "state = ?", modules.DocumentReady).
+		Preload("Tree").
+		Where("token_hash = ?", hash).
+		First(&share).
+		Error; err != nil {
+		return share, err
+	}
+
+	// a document or a folder in the trash is not shared anymore
+	if share.Kind == modules.ShareTree && share.Tree == nil ||
+		share.Kind != modules.ShareTree && share.Document == nil {
+		return share, gorm.ErrRecordNotFound
+	}
+	return share, nil
+}
+
+func (fm *Repository) List(ctx context.Context, userID string, now time.Time) ([]dto.Share, error) {
+	logrus.Debugf("[input]: %+v", userID)
+
+	var shares []dto.Share
+	if err := fm.db.WithContext(ctx).
+		Where("(user_id = ? or created_by = ?)", userID, userID).
+		Where("revoked_at is null").
+		Where("expires_at > ?", now).
+		Where("(max_downloads = 0 or downloads < max_downloads)").
+		Where("(document_id in (select id from documents where deleted_at is null and state = ?) or "+
+			"kind = ? and tree_id in (select id from trees where deleted_at is null))", modules.DocumentReady, modules.ShareTree).
+		Order("created_at desc").
+		Find(&shares).
+		Error; err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+func (fm *Repository) Revoke(ctx context.Context, share dto.Share) (dto.Share, error) {
+	logrus.Debugf("[input]: %+v", share)
+
+	now := time.Now()
+	tx := fm.db.WithContext(ctx).
+		Model(&share).
+		Where("revoked_at is null").
+		Update("revoked_at", now)
+	if tx.Error != nil {
+		return share, tx.Error
+	}
+
+	// revoking twice keeps the first time
+	if tx.RowsAffected == 1 {
+		share.RevokedAt = &now
+	}
+	return share, nil
+}
+
+func (fm *Repository) Use(ctx context.Context, share dto.Share) (bool, error) {
+	logrus.Debugf("[input]: %+v", share.ID)
+
+	// the limit is checked in the same statement, so concurrent downloads can't exceed it
+	tx := fm.db.WithContext(ctx).
+		Exec(`update shares set downloads = downloads + 1
+			where id = ? and (max_downloads = 0 or downloads < max_downloads);`, share.ID)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	return tx.RowsAffected == 1, nil
+}
+
+func (fm *Repository) Log(ctx context.Context, access dto.ShareAccess) error {
+	logrus.Debugf("[input]: %+v", access)
+
+	return fm.db.WithContext(ctx).Omit("Share").Create(&access).Error
+}
+
+func (fm *Repository) Accesses(ctx context.Context, shareID uint) ([]dto.ShareAccess, error) {
+	var accesses []dto.ShareAccess
+	if err := fm.db.WithContext(ctx).
+		Where("share_id = ?", shareID).
+		Order("created_at desc").
+		Find(&accesses).
+		Error; err != nil {
+		return nil, err
+	}
+	return accesses, nil
+}
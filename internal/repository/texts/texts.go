@@ -0,0 +1,82 @@
+package texts
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// MaxAttempts is the number of failed extractions after which a document is left unindexed
+const MaxAttempts = 5
+
+// vector builds the search vector of the content in every language the documents are written in,
+// kazakh is a copy of the simple configuration created by the migration
+const vector = `to_tsvector('russian', content) || to_tsvector('kazakh', content) || to_tsvector('english', content)`
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (fm *Repository) ListDue(ctx context.Context, limit int) ([]dto.DocumentText, error) {
+	var texts []dto.DocumentText
+	if err := fm.db.WithContext(ctx).
+		Select("document_id", "attempts", "last_error", "retry_at").
+		Preload("Document", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("extracted = false and attempts < ? and retry_at <= ?", MaxAttempts, time.Now()).
+		Order("retry_at").
+		Limit(limit).
+		Find(&texts).
+		Error; err != nil {
+		return nil, err
+	}
+	return texts, nil
+}
+
+func (fm *Repository) Save(ctx context.Context, text dto.DocumentText) error {
+	logrus.Debugf("[input]: %+v, %d characters", text.DocumentID, len(text.Content))
+
+	sql := `update document_texts
+			set search = ` + vector + `, extracted = true, last_error = '', updated_at = now()
+			where document_id = ?;`
+
+	// set expressions read the old row, so the vector is built once the content is stored
+	return fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`update document_texts set content = ? where document_id = ?;`,
+			text.Content, text.DocumentID).Error; err != nil {
+			return err
+		}
+		return tx.Exec(sql, text.DocumentID).Error
+	})
+}
+
+func (fm *Repository) Retry(ctx context.Context, text dto.DocumentText) error {
+	logrus.Debugf("[input]: %+v, %+v", text.DocumentID, text.LastError)
+
+	return fm.db.WithContext(ctx).
+		Model(&text).
+		Select("attempts", "last_error", "retry_at").
+		Updates(&text).
+		Error
+}
+
+// Queue schedules the extraction of the text of a document inside tx,
+// a document queued again is extracted anew from its current content
+func Queue(tx *gorm.DB, documentID uint) error {
+	return tx.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "document_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"extracted", "attempts", "last_error", "retry_at", "updated_at"}),
+		}).
+		Omit("Document").
+		Create(&dto.DocumentText{DocumentID: documentID, RetryAt: time.Now()}).
+		Error
+}
@@ -2,9 +2,13 @@ package tree
 
 import (
 	"context"
+	"fmt"
 	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/deletions"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
 	"gorm.io/gorm"
+	"time"
 )
 
 type Repository struct {
@@ -21,27 +25,58 @@ func (fm *Repository) Create(ctx context.Context, tree dto.Tree) (dto.Tree, erro
 	return tree, fm.db.WithContext(ctx).Create(&tree).Error
 }
 
+func (fm *Repository) Copy(ctx context.Context, root dto.Tree, sourceID uint, descendants []dto.Tree) (dto.Tree, map[uint]uint, error) {
+	logrus.Debugf("[input]: %+v %+v %+v", root, sourceID, descendants)
+
+	copies := map[uint]uint{}
+	err := fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&root).Error; err != nil {
+			return err
+		}
+		copies[sourceID] = root.ID
+
+		for _, tree := range descendants {
+			parentID, ok := copies[tree.ParentID]
+			if !ok {
+				return fmt.Errorf("tree %d is copied before its parent %d", tree.ID, tree.ParentID)
+			}
+
+			original := tree.ID
+			tree.ID, tree.ParentID = 0, parentID
+			if err := tx.Create(&tree).Error; err != nil {
+				return err
+			}
+			copies[original] = tree.ID
+		}
+
+		return nil
+	})
+
+	return root, copies, err
+}
+
 func (fm *Repository) Get(ctx context.Context, tree dto.Tree) (dto.Tree, error) {
 	return tree, fm.db.WithContext(ctx).Find(&tree).Error
 }
 
 func (fm *Repository) List(ctx context.Context, tree dto.Tree) ([]dto.Tree, error) {
+	// a parent always comes before its children
 	sql := `WITH RECURSIVE cte AS (
 		SELECT t1.id, t1.parent_id, t1.name,
-			   t1.created_at, t1.updated_at, t1.role, t1.template, t1.group
+			   t1.created_at, t1.updated_at, t1.role, t1.template, t1.group, 1 as depth
 		FROM   trees t1
-		WHERE  t1.parent_id = ? and t1.user_id = ?
+		WHERE  t1.parent_id = ? and t1.user_id = ? and t1.deleted_at is null
 	
 		UNION  ALL
 		SELECT t2.id, t2.parent_id, t2.name,
-			   t2.created_at, t2.updated_at, t2.role, t2.template, t2.group
-		FROM trees t2 JOIN cte c ON t2.parent_id = c.id and t2.user_id = ?
-	) SELECT * from cte;`
+			   t2.created_at, t2.updated_at, t2.role, t2.template, t2.group, c.depth + 1
+		FROM trees t2 JOIN cte c ON t2.parent_id = c.id and t2.user_id = ? and t2.deleted_at is null
+	) SELECT id, parent_id, name, created_at, updated_at, role, template, "group" from cte ORDER BY depth, id;`
 
 	var trees []dto.Tree
 	if err := fm.db.WithContext(ctx).
 		Model(dto.Tree{}).
-		Preload("Documents").
+		Preload("Documents", "state = ?", modules.DocumentReady).
 		Raw(sql, tree.ID, tree.UserID, tree.UserID).
 		Scan(&trees).
 		Error; err != nil {
@@ -50,6 +85,26 @@ func (fm *Repository) List(ctx context.Context, tree dto.Tree) ([]dto.Tree, erro
 	return trees, nil
 }
 
+func (fm *Repository) ListTemplates(ctx context.Context, roles []string) ([]dto.Tree, error) {
+	logrus.Debugf("[input]: %+v", roles)
+
+	// only the topmost tree of a template is listed, nested folders are its content
+	query := fm.db.WithContext(ctx).
+		Model(dto.Tree{}).
+		Where("template = true").
+		Where("not exists (select 1 from trees p where p.id = trees.parent_id and p.template = true and p.deleted_at is null)")
+
+	if roles != nil {
+		query = query.Where("role in ?", roles)
+	}
+
+	var trees []dto.Tree
+	if err := query.Order("name").Find(&trees).Error; err != nil {
+		return nil, err
+	}
+	return trees, nil
+}
+
 func (fm *Repository) Update(ctx context.Context, tree dto.Tree) (dto.Tree, error) {
 	logrus.Debugf("[input]: %+v", tree)
 
@@ -67,27 +122,254 @@ func (fm *Repository) Update(ctx context.Context, tree dto.Tree) (dto.Tree, erro
 	return tree, nil
 }
 
-func (fm *Repository) Delete(ctx context.Context, tree dto.Tree) (dto.Tree, error) {
+func (fm *Repository) Move(ctx context.Context, tree dto.Tree) (dto.Tree, error) {
 	logrus.Debugf("[input]: %+v", tree)
 
-	tx := fm.db.WithContext(ctx).Model(dto.Tree{}).
-		Where("user_id = ?", tree.UserID).Delete(&tree)
-	if tx.Error != nil {
-		logrus.Errorf("[error]: %+v", tx.Error)
+	sql := `WITH RECURSIVE cte AS (
+		SELECT t1.id FROM trees t1
+		WHERE  t1.id = ?
+
+		UNION  ALL
+		SELECT t2.id FROM trees t2
+		JOIN cte c ON t2.parent_id = c.id
+	) SELECT count(*) from cte where id = ?;`
+
+	err := fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if tree.ParentID != 0 {
+			var count int64
+			if err := tx.Raw(sql, tree.ID, tree.ParentID).Scan(&count).Error; err != nil {
+				return err
+			}
+
+			if count > 0 {
+				return modules.ErrTreeCycle
+			}
+		}
+
+		// parent_id is create-only on the model, so it is moved with a raw update
+		res := tx.Exec(`update trees set parent_id = ?, updated_at = now() 
+			where id = ? and user_id = ? and deleted_at is null;`, tree.ParentID, tree.ID, tree.UserID)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return tree, err
 	}
 
-	if tx.RowsAffected == 0 {
-		return tree, gorm.ErrRecordNotFound
+	return tree, fm.db.WithContext(ctx).Find(&tree).Error
+}
+
+func (fm *Repository) Delete(ctx context.Context, tree dto.Tree) (dto.Tree, error) {
+	logrus.Debugf("[input]: %+v", tree)
+
+	// the whole subtree and its documents are trashed with one timestamp,
+	// so they can be told apart from items trashed on their own
+	sql := `WITH RECURSIVE cte AS (
+		SELECT t1.id FROM trees t1
+		WHERE  t1.id = ? and t1.user_id = ? and t1.deleted_at is null
+
+		UNION  ALL
+		SELECT t2.id FROM trees t2
+		JOIN cte c ON t2.parent_id = c.id and t2.deleted_at is null
+	) SELECT id from cte;`
+
+	err := fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		if err := tx.Raw(sql, tree.ID, tree.UserID).Scan(&ids).Error; err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		now := time.Now()
+		if err := tx.Model(dto.Tree{}).
+			Where("id in ?", ids).
+			Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(dto.Document{}).
+			Where("id in (?)", tx.Table("tree_documents").Select("document_id").Where("tree_id in ?", ids)).
+			Update("deleted_at", now).Error
+	})
+	if err != nil {
+		logrus.Errorf("[error]: %+v", err)
+		return tree, err
 	}
 
+	return tree, nil
+}
+
+func (fm *Repository) ListTrash(ctx context.Context, userID string) ([]dto.Tree, error) {
+	// only the topmost tree of a trashed subtree is listed
+	sql := `select t.* from trees t
+			left join trees p on p.id = t.parent_id
+			where t.user_id = ? and t.deleted_at is not null
+			and (p.deleted_at is null or p.deleted_at <> t.deleted_at)
+			order by t.deleted_at desc;`
+
+	var trees []dto.Tree
 	if err := fm.db.WithContext(ctx).
-		Table("tree_documents").
-		Where("tree_id = ?", tree.ID).
-		Delete(&dto.TreeDocuments{}).Error; err != nil {
+		Raw(sql, userID).
+		Scan(&trees).
+		Error; err != nil {
+		return nil, err
+	}
+	return trees, nil
+}
+
+func (fm *Repository) Restore(ctx context.Context, tree dto.Tree) (dto.Tree, error) {
+	logrus.Debugf("[input]: %+v", tree)
+
+	sql := `WITH RECURSIVE cte AS (
+		SELECT t1.id, t1.deleted_at FROM trees t1
+		WHERE  t1.id = ?
+
+		UNION  ALL
+		SELECT t2.id, t2.deleted_at FROM trees t2
+		JOIN cte c ON t2.parent_id = c.id and t2.deleted_at = c.deleted_at
+	) SELECT id from cte;`
+
+	err := fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var trashed dto.Tree
+		if err := tx.Unscoped().
+			Where("id = ?", tree.ID).
+			Where("user_id = ?", tree.UserID).
+			Where("deleted_at is not null").
+			First(&trashed).Error; err != nil {
+			return err
+		}
+
+		var ids []uint
+		if err := tx.Raw(sql, trashed.ID).Scan(&ids).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Model(dto.Tree{}).
+			Where("id in ?", ids).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Model(dto.Document{}).
+			Where("id in (?)", tx.Table("tree_documents").Select("document_id").Where("tree_id in ?", ids)).
+			Where("deleted_at = ?", trashed.DeletedAt).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		if trashed.ParentID == 0 {
+			return nil
+		}
+
+		// the original parent is gone, move the tree to the root
+		var count int64
+		if err := tx.Model(dto.Tree{}).Where("id = ?", trashed.ParentID).Count(&count).Error; err != nil {
+			return err
+		}
+
+		if count > 0 {
+			return nil
+		}
+
+		return tx.Exec(`update trees set parent_id = 0 where id = ?;`, trashed.ID).Error
+	})
+	if err != nil {
 		return tree, err
 	}
 
-	// TODO: add logic of delete files after delete tree
+	return tree, fm.db.WithContext(ctx).Find(&tree).Error
+}
 
-	return tree, nil
+func (fm *Repository) Purge(ctx context.Context, tree dto.Tree) (dto.Purge, error) {
+	logrus.Debugf("[input]: %+v", tree)
+
+	// only a trashed folder is purged, its descendants are removed as well
+	sql := `WITH RECURSIVE cte AS (
+		SELECT t1.id FROM trees t1
+		WHERE  t1.id = ? and t1.user_id = ? and t1.deleted_at is not null
+
+		UNION  ALL
+		SELECT t2.id FROM trees t2
+		JOIN cte c ON t2.parent_id = c.id
+	) SELECT id from cte;`
+
+	var purge dto.Purge
+	err := fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		if err := tx.Raw(sql, tree.ID, tree.UserID).Scan(&ids).Error; err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		var err error
+		purge, err = purgeTrees(tx, ids)
+		return err
+	})
+	if err != nil {
+		logrus.Errorf("[error]: %+v", err)
+	}
+
+	return purge, err
+}
+
+func (fm *Repository) PurgeExpired(ctx context.Context, before time.Time) (dto.Purge, error) {
+	var purge dto.Purge
+	err := fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		if err := tx.Unscoped().Model(dto.Tree{}).
+			Where("deleted_at < ?", before).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+
+		var err error
+		purge, err = purgeTrees(tx, ids)
+		return err
+	})
+
+	return purge, err
+}
+
+// purgeTrees deletes the trees inside tx with every document linked to them only,
+// a document also linked to a folder that stays loses just these links
+func purgeTrees(tx *gorm.DB, ids []uint) (dto.Purge, error) {
+	if len(ids) == 0 {
+		return dto.Purge{}, nil
+	}
+
+	var documentIDs []uint
+	if err := tx.Table("tree_documents").
+		Where("tree_id in ?", ids).
+		Where("not exists (select 1 from tree_documents other where other.document_id = tree_documents.document_id and other.tree_id not in ?)", ids).
+		Distinct().
+		Pluck("document_id", &documentIDs).Error; err != nil {
+		return dto.Purge{}, err
+	}
+
+	purge, err := deletions.Purge(tx, documentIDs)
+	if err != nil {
+		return purge, err
+	}
+
+	if err = tx.Table("tree_documents").
+		Where("tree_id in ?", ids).
+		Delete(&dto.TreeDocuments{}).Error; err != nil {
+		return purge, err
+	}
+
+	res := tx.Unscoped().Where("id in ?", ids).Delete(&dto.Tree{})
+	purge.Folders = res.RowsAffected
+	return purge, res.Error
 }
@@ -0,0 +1,122 @@
+package uploads
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (fm *Repository) Create(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error) {
+	logrus.Debugf("[input]: %+v", session)
+
+	return session, fm.db.WithContext(ctx).
+		Model(dto.UploadSession{}).
+		Omit("Parts").
+		Create(&session).
+		Error
+}
+
+func (fm *Repository) Get(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error) {
+	logrus.Debugf("[input]: %+v", session)
+
+	tx := fm.db.WithContext(ctx).
+		Model(dto.UploadSession{}).
+		Preload("Parts", func(db *gorm.DB) *gorm.DB {
+			return db.Order("number")
+		}).
+		Where("id = ?", session.ID).
+		Where("tree_id = ?", session.TreeID).
+		Where("user_id = ?", session.UserID).
+		Where("expires_at > ?", time.Now()).
+		Find(&session)
+	if tx.Error != nil {
+		return session, tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return session, gorm.ErrRecordNotFound
+	}
+
+	return session, nil
+}
+
+func (fm *Repository) List(ctx context.Context, session dto.UploadSession) ([]dto.UploadSession, error) {
+	var sessions []dto.UploadSession
+	if err := fm.db.WithContext(ctx).
+		Model(dto.UploadSession{}).
+		Preload("Parts", func(db *gorm.DB) *gorm.DB {
+			return db.Order("number")
+		}).
+		Where("tree_id = ?", session.TreeID).
+		Where("user_id = ?", session.UserID).
+		Where("expires_at > ?", time.Now()).
+		Order("created_at").
+		Find(&sessions).
+		Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (fm *Repository) SavePart(ctx context.Context, part dto.UploadPart) (dto.UploadPart, error) {
+	logrus.Debugf("[input]: %+v", part)
+
+	// a client resuming an upload may send the same part again
+	return part, fm.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "session_id"}, {Name: "number"}},
+			DoUpdates: clause.AssignmentColumns([]string{"etag", "size", "updated_at"}),
+		}).
+		Create(&part).
+		Error
+}
+
+func (fm *Repository) Delete(ctx context.Context, session dto.UploadSession) error {
+	logrus.Debugf("[input]: %+v", session)
+
+	return fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("session_id = ?", session.ID).
+			Delete(&dto.UploadPart{}).Error; err != nil {
+			return err
+		}
+
+		res := tx.Where("id = ?", session.ID).
+			Where("user_id = ?", session.UserID).
+			Delete(&dto.UploadSession{})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+}
+
+func (fm *Repository) ListExpired(ctx context.Context, before time.Time, limit int) ([]dto.UploadSession, error) {
+	var sessions []dto.UploadSession
+	if err := fm.db.WithContext(ctx).
+		Where("expires_at <= ?", before).
+		Order("expires_at").
+		Limit(limit).
+		Find(&sessions).
+		Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
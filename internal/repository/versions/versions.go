@@ -0,0 +1,150 @@
+package versions
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/blobs"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository/quotas"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (fm *Repository) Create(ctx context.Context, version dto.DocumentVersion) (dto.DocumentVersion, error) {
+	logrus.Debugf("[input]: %+v", version)
+
+	err := fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the document row is locked so concurrent uploads are numbered one after another
+		var doc dto.Document
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", version.DocumentID).
+			First(&doc).Error; err != nil {
+			return err
+		}
+
+		var latest uint
+		if err := tx.Model(dto.DocumentVersion{}).
+			Select("coalesce(max(version), 0)").
+			Where("document_id = ?", version.DocumentID).
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+
+		// a version without a number follows the latest one
+		if version.Version == 0 {
+			version.Version = latest + 1
+		}
+
+		if err := tx.Model(dto.DocumentVersion{}).Create(&version).Error; err != nil {
+			return err
+		}
+
+		// every kept version counts towards the usage of the document owner, the current
+		// content of a document with history is one of its versions and counts only there
+		bytes := version.Size
+		if latest == 0 {
+			bytes -= doc.Size
+		}
+		return quotas.Use(tx, version.UserID, bytes)
+	})
+
+	return version, err
+}
+
+func (fm *Repository) Get(ctx context.Context, version dto.DocumentVersion) (dto.DocumentVersion, error) {
+	logrus.Debugf("[input]: %+v", version)
+
+	tx := fm.db.WithContext(ctx).
+		Model(dto.DocumentVersion{}).
+		Where("document_id = ?", version.DocumentID).
+		Where("version = ?", version.Version).
+		Find(&version)
+	if tx.Error != nil {
+		return version, tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return version, gorm.ErrRecordNotFound
+	}
+
+	return version, nil
+}
+
+func (fm *Repository) List(ctx context.Context, documentID uint) ([]dto.DocumentVersion, error) {
+	var versions []dto.DocumentVersion
+	if err := fm.db.WithContext(ctx).
+		Model(dto.DocumentVersion{}).
+		Where("document_id = ?", documentID).
+		Order("version desc").
+		Find(&versions).
+		Error; err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (fm *Repository) ListByDocuments(ctx context.Context, ids []uint) ([]dto.DocumentVersion, error) {
+	var versions []dto.DocumentVersion
+	if err := fm.db.WithContext(ctx).
+		Model(dto.DocumentVersion{}).
+		Where("document_id in ?", ids).
+		Order("document_id, version").
+		Find(&versions).
+		Error; err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// Remove deletes a single version, releasing its blob and the usage it counted for
+func (fm *Repository) Remove(ctx context.Context, version dto.DocumentVersion) error {
+	logrus.Debugf("[input]: %+v", version)
+
+	return fm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tx = tx.Clauses(clause.Returning{}).
+			Where("document_id = ?", version.DocumentID).
+			Where("version = ?", version.Version).
+			Delete(&version)
+		if tx.Error != nil || tx.RowsAffected == 0 {
+			return tx.Error
+		}
+
+		if version.Checksum != "" {
+			if err := blobs.Release(tx, []string{version.Checksum}); err != nil {
+				return err
+			}
+		}
+
+		// without history the current content counts as the document again
+		bytes := -version.Size
+		var doc dto.Document
+		if err := tx.Unscoped().
+			Where("id = ?", version.DocumentID).
+			Where("not exists (select 1 from document_versions where document_id = documents.id)").
+			Find(&doc).Error; err != nil {
+			return err
+		}
+		bytes += doc.Size
+
+		return quotas.Add(tx, version.UserID, bytes)
+	})
+}
+
+func (fm *Repository) Delete(ctx context.Context, documentID uint) error {
+	logrus.Debugf("[input]: %+v", documentID)
+
+	return fm.db.WithContext(ctx).
+		Where("document_id = ?", documentID).
+		Delete(&dto.DocumentVersion{}).
+		Error
+}
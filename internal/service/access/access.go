@@ -0,0 +1,117 @@
+package access
+
+import (
+	"context"
+	"fmt"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+)
+
+// ranks orders the access levels, a level includes every lower one
+var ranks = map[string]int{
+	modules.AccessViewer: 1,
+	modules.AccessEditor: 2,
+	modules.AccessOwner:  3,
+}
+
+type Service struct {
+	repos repository.AccessRepository
+	trees repository.TreeRepository
+}
+
+func NewService(repos repository.AccessRepository, trees repository.TreeRepository) *Service {
+	return &Service{
+		repos: repos,
+		trees: trees,
+	}
+}
+
+// Owner returns the owner of a tree the caller has at least the level on.
+// Callers act on behalf of the owner, so everything created in a shared tree belongs to its owner.
+func (s *Service) Owner(ctx context.Context, treeID uint, level string) (string, error) {
+	userId, ok := ctx.Value(modules.UserID).(string)
+	if !ok {
+		return "", fmt.Errorf("unauthorized action is prohibited")
+	}
+
+	tree, err := s.trees.Get(ctx, dto.Tree{ID: treeID})
+	if err != nil {
+		return "", err
+	}
+
+	if tree.UserID == "" {
+		return "", gorm.ErrRecordNotFound
+	}
+
+	if tree.UserID == userId {
+		return userId, nil
+	}
+
+	roles, _ := ctx.Value(modules.Roles).([]string)
+
+	levels, err := s.repos.Levels(ctx, treeID, userId, roles)
+	if err != nil {
+		return "", err
+	}
+
+	// trees the caller can't see at all are reported as missing
+	if len(levels) == 0 {
+		return "", gorm.ErrRecordNotFound
+	}
+
+	for _, granted := range levels {
+		if ranks[granted] >= ranks[level] {
+			return tree.UserID, nil
+		}
+	}
+
+	return "", modules.ErrForbidden
+}
+
+func (s *Service) Grant(ctx context.Context, access dto.TreeAccess) (dto.TreeAccess, error) {
+	if _, err := s.Owner(ctx, access.TreeID, modules.AccessOwner); err != nil {
+		return access, err
+	}
+
+	return s.repos.Grant(ctx, access)
+}
+
+func (s *Service) Revoke(ctx context.Context, access dto.TreeAccess) (dto.TreeAccess, error) {
+	if _, err := s.Owner(ctx, access.TreeID, modules.AccessOwner); err != nil {
+		return access, err
+	}
+
+	return s.repos.Revoke(ctx, access)
+}
+
+func (s *Service) List(ctx context.Context, treeID uint) ([]dto.TreeAccess, error) {
+	if _, err := s.Owner(ctx, treeID, modules.AccessOwner); err != nil {
+		return nil, err
+	}
+
+	return s.repos.List(ctx, treeID)
+}
+
+func (s *Service) Shared(ctx context.Context) ([]dto.TreeAccess, error) {
+	userId, ok := ctx.Value(modules.UserID).(string)
+	if !ok {
+		return nil, fmt.Errorf("unauthorized action is prohibited")
+	}
+
+	roles, _ := ctx.Value(modules.Roles).([]string)
+
+	return s.repos.Shared(ctx, userId, roles)
+}
+
+func (s *Service) Trees(ctx context.Context) ([]uint, error) {
+	userId, ok := ctx.Value(modules.UserID).(string)
+	if !ok {
+		return nil, fmt.Errorf("unauthorized action is prohibited")
+	}
+
+	roles, _ := ctx.Value(modules.Roles).([]string)
+
+	return s.repos.SharedTrees(ctx, userId, roles)
+}
@@ -0,0 +1,72 @@
+package blobs
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/remote"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/utils"
+)
+
+const batchSize = 100
+
+type Service struct {
+	repos   repository.BlobRepository
+	remotes remote.DocumentsRemote
+}
+
+func NewService(repos repository.BlobRepository, remotes remote.DocumentsRemote) *Service {
+	return &Service{
+		repos:   repos,
+		remotes: remotes,
+	}
+}
+
+func (s *Service) Verify(ctx context.Context) (dto.Verification, error) {
+	report := dto.Verification{Mismatches: make([]dto.BlobMismatch, 0)}
+
+	for after := ""; ; {
+		blobs, err := s.repos.List(ctx, after, batchSize)
+		if err != nil {
+			return report, err
+		}
+		if len(blobs) == 0 {
+			return report, nil
+		}
+
+		for _, blob := range blobs {
+			report.Checked++
+
+			actual, err := s.hash(ctx, blob)
+			if err != nil {
+				logrus.Errorf("[verification error]: %+v - %+v", blob.Checksum, err)
+				report.Mismatches = append(report.Mismatches, dto.BlobMismatch{Checksum: blob.Checksum, Error: err.Error()})
+				continue
+			}
+
+			if actual != blob.Checksum {
+				logrus.Errorf("[checksum mismatch]: %+v - %+v", blob.Checksum, actual)
+				report.Mismatches = append(report.Mismatches, dto.BlobMismatch{Checksum: blob.Checksum, Actual: actual})
+				continue
+			}
+
+			if err = s.repos.Verified(ctx, blob); err != nil {
+				return report, err
+			}
+		}
+
+		after = blobs[len(blobs)-1].Checksum
+	}
+}
+
+// hash streams the stored object of a blob through SHA-256
+func (s *Service) hash(ctx context.Context, blob dto.Blob) (string, error) {
+	doc, err := s.remotes.Get(ctx, dto.Document{Checksum: blob.Checksum})
+	if err != nil {
+		return "", err
+	}
+	defer doc.ResponseContent.Close()
+
+	return utils.Checksum(doc.ResponseContent)
+}
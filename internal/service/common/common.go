@@ -0,0 +1,46 @@
+// Package common holds what the services share: the parts of other services
+// they depend on and the helpers settling the content of documents
+package common
+
+import (
+	"context"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"io"
+)
+
+//go:generate mockgen -source=common.go -destination=mocks/common.go
+//go:generate mockgen -source=scan.go -destination=mocks/scan.go
+
+// Access resolves on whose behalf the caller acts on a tree and which trees are shared with the caller
+type Access interface {
+	Owner(ctx context.Context, treeID uint, level string) (string, error)
+	Trees(ctx context.Context) ([]uint, error)
+}
+
+// Quotas checks that storing more bytes for the owner of a tree stays within the quotas
+type Quotas interface {
+	Check(ctx context.Context, owner string, bytes int64) error
+}
+
+// Usage reports the bytes a user stores against its quotas
+type Usage interface {
+	UserUsage(ctx context.Context, userID string) (dto.Usage, error)
+}
+
+// Policy cleans the name of a file placed into a tree and reports the upload rules it breaks
+type Policy interface {
+	Check(ctx context.Context, treeID uint, file dto.UploadFile) (dto.UploadFile, error)
+}
+
+// Documents is the part of the document service a tree needs to copy its content
+type Documents interface {
+	ListByTree(ctx context.Context, ids []uint) ([]dto.Document, error)
+	Duplicate(ctx context.Context, doc dto.Document, treeID uint) (dto.Document, error)
+	Store(ctx context.Context, document dto.Document, file dto.UploadFile, content io.ReadSeeker) (dto.Document, error)
+}
+
+// Folders is the part of the tree service that builds the structure of a folder
+type Folders interface {
+	GetTreeIDs(ctx context.Context, trees []dto.Tree) []uint
+	FormTree(ctx context.Context, trees []dto.Tree, docs []dto.Document) []dto.Tree
+}
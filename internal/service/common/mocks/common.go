@@ -0,0 +1,299 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: common.go
+
+// Package mock_common is a generated GoMock package.
+package mock_common
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+)
+
+// MockAccess is a mock of Access interface.
+type MockAccess struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessMockRecorder
+}
+
+// MockAccessMockRecorder is the mock recorder for MockAccess.
+type MockAccessMockRecorder struct {
+	mock *MockAccess
+}
+
+// NewMockAccess creates a new mock instance.
+func NewMockAccess(ctrl *gomock.Controller) *MockAccess {
+	mock := &MockAccess{ctrl: ctrl}
+	mock.recorder = &MockAccessMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccess) EXPECT() *MockAccessMockRecorder {
+	return m.recorder
+}
+
+// Owner mocks base method.
+func (m *MockAccess) Owner(ctx context.Context, treeID uint, level string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Owner", ctx, treeID, level)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Owner indicates an expected call of Owner.
+func (mr *MockAccessMockRecorder) Owner(ctx, treeID, level interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Owner", reflect.TypeOf((*MockAccess)(nil).Owner), ctx, treeID, level)
+}
+
+// Trees mocks base method.
+func (m *MockAccess) Trees(ctx context.Context) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trees", ctx)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Trees indicates an expected call of Trees.
+func (mr *MockAccessMockRecorder) Trees(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trees", reflect.TypeOf((*MockAccess)(nil).Trees), ctx)
+}
+
+// MockQuotas is a mock of Quotas interface.
+type MockQuotas struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuotasMockRecorder
+}
+
+// MockQuotasMockRecorder is the mock recorder for MockQuotas.
+type MockQuotasMockRecorder struct {
+	mock *MockQuotas
+}
+
+// NewMockQuotas creates a new mock instance.
+func NewMockQuotas(ctrl *gomock.Controller) *MockQuotas {
+	mock := &MockQuotas{ctrl: ctrl}
+	mock.recorder = &MockQuotasMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuotas) EXPECT() *MockQuotasMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockQuotas) Check(ctx context.Context, owner string, bytes int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, owner, bytes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockQuotasMockRecorder) Check(ctx, owner, bytes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockQuotas)(nil).Check), ctx, owner, bytes)
+}
+
+// MockUsage is a mock of Usage interface.
+type MockUsage struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsageMockRecorder
+}
+
+// MockUsageMockRecorder is the mock recorder for MockUsage.
+type MockUsageMockRecorder struct {
+	mock *MockUsage
+}
+
+// NewMockUsage creates a new mock instance.
+func NewMockUsage(ctrl *gomock.Controller) *MockUsage {
+	mock := &MockUsage{ctrl: ctrl}
+	mock.recorder = &MockUsageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsage) EXPECT() *MockUsageMockRecorder {
+	return m.recorder
+}
+
+// UserUsage mocks base method.
+func (m *MockUsage) UserUsage(ctx context.Context, userID string) (dto.Usage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserUsage", ctx, userID)
+	ret0, _ := ret[0].(dto.Usage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserUsage indicates an expected call of UserUsage.
+func (mr *MockUsageMockRecorder) UserUsage(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserUsage", reflect.TypeOf((*MockUsage)(nil).UserUsage), ctx, userID)
+}
+
+// MockPolicy is a mock of Policy interface.
+type MockPolicy struct {
+	ctrl     *gomock.Controller
+	recorder *MockPolicyMockRecorder
+}
+
+// MockPolicyMockRecorder is the mock recorder for MockPolicy.
+type MockPolicyMockRecorder struct {
+	mock *MockPolicy
+}
+
+// NewMockPolicy creates a new mock instance.
+func NewMockPolicy(ctrl *gomock.Controller) *MockPolicy {
+	mock := &MockPolicy{ctrl: ctrl}
+	mock.recorder = &MockPolicyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPolicy) EXPECT() *MockPolicyMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockPolicy) Check(ctx context.Context, treeID uint, file dto.UploadFile) (dto.UploadFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, treeID, file)
+	ret0, _ := ret[0].(dto.UploadFile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockPolicyMockRecorder) Check(ctx, treeID, file interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockPolicy)(nil).Check), ctx, treeID, file)
+}
+
+// MockDocuments is a mock of Documents interface.
+type MockDocuments struct {
+	ctrl     *gomock.Controller
+	recorder *MockDocumentsMockRecorder
+}
+
+// MockDocumentsMockRecorder is the mock recorder for MockDocuments.
+type MockDocumentsMockRecorder struct {
+	mock *MockDocuments
+}
+
+// NewMockDocuments creates a new mock instance.
+func NewMockDocuments(ctrl *gomock.Controller) *MockDocuments {
+	mock := &MockDocuments{ctrl: ctrl}
+	mock.recorder = &MockDocumentsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDocuments) EXPECT() *MockDocumentsMockRecorder {
+	return m.recorder
+}
+
+// Duplicate mocks base method.
+func (m *MockDocuments) Duplicate(ctx context.Context, doc dto.Document, treeID uint) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Duplicate", ctx, doc, treeID)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Duplicate indicates an expected call of Duplicate.
+func (mr *MockDocumentsMockRecorder) Duplicate(ctx, doc, treeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Duplicate", reflect.TypeOf((*MockDocuments)(nil).Duplicate), ctx, doc, treeID)
+}
+
+// ListByTree mocks base method.
+func (m *MockDocuments) ListByTree(ctx context.Context, ids []uint) ([]dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByTree", ctx, ids)
+	ret0, _ := ret[0].([]dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByTree indicates an expected call of ListByTree.
+func (mr *MockDocumentsMockRecorder) ListByTree(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByTree", reflect.TypeOf((*MockDocuments)(nil).ListByTree), ctx, ids)
+}
+
+// Store mocks base method.
+func (m *MockDocuments) Store(ctx context.Context, document dto.Document, file dto.UploadFile, content io.ReadSeeker) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Store", ctx, document, file, content)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Store indicates an expected call of Store.
+func (mr *MockDocumentsMockRecorder) Store(ctx, document, file, content interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Store", reflect.TypeOf((*MockDocuments)(nil).Store), ctx, document, file, content)
+}
+
+// MockFolders is a mock of Folders interface.
+type MockFolders struct {
+	ctrl     *gomock.Controller
+	recorder *MockFoldersMockRecorder
+}
+
+// MockFoldersMockRecorder is the mock recorder for MockFolders.
+type MockFoldersMockRecorder struct {
+	mock *MockFolders
+}
+
+// NewMockFolders creates a new mock instance.
+func NewMockFolders(ctrl *gomock.Controller) *MockFolders {
+	mock := &MockFolders{ctrl: ctrl}
+	mock.recorder = &MockFoldersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFolders) EXPECT() *MockFoldersMockRecorder {
+	return m.recorder
+}
+
+// FormTree mocks base method.
+func (m *MockFolders) FormTree(ctx context.Context, trees []dto.Tree, docs []dto.Document) []dto.Tree {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FormTree", ctx, trees, docs)
+	ret0, _ := ret[0].([]dto.Tree)
+	return ret0
+}
+
+// FormTree indicates an expected call of FormTree.
+func (mr *MockFoldersMockRecorder) FormTree(ctx, trees, docs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FormTree", reflect.TypeOf((*MockFolders)(nil).FormTree), ctx, trees, docs)
+}
+
+// GetTreeIDs mocks base method.
+func (m *MockFolders) GetTreeIDs(ctx context.Context, trees []dto.Tree) []uint {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTreeIDs", ctx, trees)
+	ret0, _ := ret[0].([]uint)
+	return ret0
+}
+
+// GetTreeIDs indicates an expected call of GetTreeIDs.
+func (mr *MockFoldersMockRecorder) GetTreeIDs(ctx, trees interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTreeIDs", reflect.TypeOf((*MockFolders)(nil).GetTreeIDs), ctx, trees)
+}
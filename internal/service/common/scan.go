@@ -0,0 +1,76 @@
+package common
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"io"
+)
+
+// Scanner looks for malware in content and returns the signature found, empty when it is clean
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) (string, error)
+}
+
+// Check refuses infected content that isn't stored yet with ErrInfected
+func Check(ctx context.Context, scanner Scanner, content io.Reader) error {
+	signature, err := scanner.Scan(ctx, content)
+	if err != nil {
+		return err
+	}
+
+	if signature != "" {
+		logrus.Warnf("[malware]: %s", signature)
+		return modules.ErrInfected
+	}
+
+	return nil
+}
+
+// Scan stores the result of the malware scan of the content of a document, infected content
+// is reported with ErrInfected. A document that can't be scanned is purged.
+func Scan(ctx context.Context, scanner Scanner, documents repository.DocumentRepository, doc dto.Document, content io.Reader) (dto.Document, error) {
+	signature, err := scanner.Scan(ctx, content)
+	if err != nil {
+		Discard(ctx, documents, doc)
+		return doc, err
+	}
+
+	doc.ScanStatus = modules.ScanClean
+	if signature != "" {
+		logrus.Warnf("[malware]: document %d - %s", doc.ID, signature)
+		doc.ScanStatus = modules.ScanInfected
+	}
+
+	if doc, err = documents.Scanned(ctx, doc); err != nil {
+		return doc, err
+	}
+
+	if signature != "" {
+		return doc, modules.ErrInfected
+	}
+
+	return doc, nil
+}
+
+// Discard purges a document that wasn't completely created,
+// the error of the creation is the one reported
+func Discard(ctx context.Context, documents repository.DocumentRepository, doc dto.Document) {
+	if _, err := documents.Purge(ctx, []uint{doc.ID}); err != nil {
+		logrus.Errorf("[purge error]: %+v - %+v", doc.ID, err)
+	}
+}
+
+// Settle marks a document ready whose content reached spaces or never will. The outbox
+// settles it when this fails, so the error is logged and the document returned as it was.
+func Settle(ctx context.Context, documents repository.DocumentRepository, doc dto.Document) dto.Document {
+	settled, err := documents.Ready(ctx, doc)
+	if err != nil {
+		logrus.Errorf("[settle error]: %+v - %+v", doc.ID, err)
+		return doc
+	}
+
+	return settled
+}
@@ -0,0 +1,72 @@
+package deletions
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/remote"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/utils"
+	"time"
+)
+
+const batchSize = 100
+
+type Service struct {
+	repos   repository.DeletionRepository
+	remotes remote.DocumentsRemote
+}
+
+func NewService(repos repository.DeletionRepository, remotes remote.DocumentsRemote) *Service {
+	return &Service{
+		repos:   repos,
+		remotes: remotes,
+	}
+}
+
+func (s *Service) Process(ctx context.Context) (int, error) {
+	due, err := s.repos.ListDue(ctx, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted int
+	for _, deletion := range due {
+		if err = s.delete(ctx, deletion); err != nil {
+			logrus.Errorf("[deletion error]: %+v - %+v", deletion, err)
+
+			deletion.Attempts++
+			deletion.LastError = err.Error()
+			deletion.RetryAt = time.Now().Add(utils.Backoff(deletion.Attempts))
+			if err = s.repos.Retry(ctx, deletion); err != nil {
+				return deleted, err
+			}
+			continue
+		}
+
+		if err = s.repos.Done(ctx, deletion); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// delete removes the object from spaces, deleting a missing object succeeds,
+// so a deletion may safely be attempted more than once
+func (s *Service) delete(ctx context.Context, deletion dto.ObjectDeletion) error {
+	doc := deletion.Document()
+
+	if deletion.Version == 0 {
+		_, err := s.remotes.Delete(ctx, doc)
+		return err
+	}
+
+	_, err := s.remotes.DeleteVersion(ctx, doc, dto.DocumentVersion{
+		DocumentID: doc.ID,
+		Version:    deletion.Version,
+		Extension:  deletion.Extension,
+	})
+	return err
+}
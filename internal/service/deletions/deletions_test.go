@@ -0,0 +1,109 @@
+package deletions
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	remotemocks "gitlab.com/a5805/ondeu/ondeu-back/internal/remote/mocks"
+	repomocks "gitlab.com/a5805/ondeu/ondeu-back/internal/repository/mocks"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"testing"
+	"time"
+)
+
+func TestService_Process(t *testing.T) {
+	type mockBehavior func(r *repomocks.MockDeletionRepository, remotes *remotemocks.MockDocumentsRemote, deletion dto.ObjectDeletion)
+
+	// a purged document is deleting until the last of its objects is removed,
+	// Done removes its row along with the deletion
+	content := dto.ObjectDeletion{ID: 1, DocumentID: 7, UploadedAt: time.Now(), Path: uuid.New(), Extension: ".pdf"}
+	version := dto.ObjectDeletion{ID: 2, DocumentID: 7, UploadedAt: time.Now(), Path: uuid.New(), Extension: ".pdf", Version: 3}
+
+	tests := []struct {
+		name          string
+		deletion      dto.ObjectDeletion
+		mockBehavior  mockBehavior
+		expectedCount int
+		expectedErr   error
+	}{
+		{
+			name:     "Success. Content of a purged document is removed and the deletion done",
+			deletion: content,
+			mockBehavior: func(r *repomocks.MockDeletionRepository, remotes *remotemocks.MockDocumentsRemote, deletion dto.ObjectDeletion) {
+				gomock.InOrder(
+					remotes.EXPECT().Delete(gomock.Any(), deletion.Document()).Return(deletion.Document(), nil),
+					r.EXPECT().Done(gomock.Any(), deletion).Return(nil),
+				)
+			},
+			expectedCount: 1,
+		},
+		{
+			name:     "Success. Version is removed and the deletion done",
+			deletion: version,
+			mockBehavior: func(r *repomocks.MockDeletionRepository, remotes *remotemocks.MockDocumentsRemote, deletion dto.ObjectDeletion) {
+				gomock.InOrder(
+					remotes.EXPECT().
+						DeleteVersion(gomock.Any(), deletion.Document(), dto.DocumentVersion{DocumentID: 7, Version: 3, Extension: ".pdf"}).
+						Return(dto.DocumentVersion{}, nil),
+					r.EXPECT().Done(gomock.Any(), deletion).Return(nil),
+				)
+			},
+			expectedCount: 1,
+		},
+		{
+			name:     "Failed. Spaces unreachable, the deletion is retried later",
+			deletion: content,
+			mockBehavior: func(r *repomocks.MockDeletionRepository, remotes *remotemocks.MockDocumentsRemote, deletion dto.ObjectDeletion) {
+				gomock.InOrder(
+					remotes.EXPECT().Delete(gomock.Any(), deletion.Document()).Return(dto.Document{}, errors.New("connection refused")),
+					r.EXPECT().Retry(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, retried dto.ObjectDeletion) error {
+						assert.Equal(t, 1, retried.Attempts)
+						assert.Equal(t, "connection refused", retried.LastError)
+						assert.WithinDuration(t, time.Now().Add(time.Minute), retried.RetryAt, time.Second)
+						return nil
+					}),
+				)
+			},
+			expectedCount: 0,
+		},
+		{
+			name:     "Failed. Database, the run stops",
+			deletion: content,
+			mockBehavior: func(r *repomocks.MockDeletionRepository, remotes *remotemocks.MockDocumentsRemote, deletion dto.ObjectDeletion) {
+				gomock.InOrder(
+					remotes.EXPECT().Delete(gomock.Any(), deletion.Document()).Return(deletion.Document(), nil),
+					r.EXPECT().Done(gomock.Any(), deletion).Return(errors.New("connection reset")),
+				)
+			},
+			expectedCount: 0,
+			expectedErr:   errors.New("connection reset"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repos := repomocks.NewMockDeletionRepository(c)
+			remotes := remotemocks.NewMockDocumentsRemote(c)
+			repos.EXPECT().ListDue(gomock.Any(), batchSize).Return([]dto.ObjectDeletion{tt.deletion}, nil)
+			tt.mockBehavior(repos, remotes, tt.deletion)
+
+			s := NewService(repos, remotes)
+
+			// Test
+			deleted, err := s.Process(context.Background())
+
+			// Assert
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedCount, deleted)
+		})
+	}
+}
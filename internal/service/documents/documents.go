@@ -2,71 +2,166 @@ package documents
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 	"gitlab.com/a5805/ondeu/ondeu-back/internal/remote"
 	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/common"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
-	"mime"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/upload"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/utils"
+	"gorm.io/gorm"
+	"io"
 	"mime/multipart"
-	"path/filepath"
-	"time"
 )
 
+// previewPath is the route the thumbnails of a document are served from
+const previewPath = "/api/v1/tree/%d/document/%d/preview"
+
 type Service struct {
-	repos   repository.DocumentRepository
-	remotes remote.DocumentsRemote
+	repos       repository.DocumentRepository
+	trees       repository.TreeRepository
+	previews    repository.PreviewRepository
+	blobs       repository.BlobRepository
+	remotes     remote.DocumentsRemote
+	access      common.Access
+	quotas      common.Quotas
+	scanner     common.Scanner
+	policy      common.Policy
+	permissions modules.Permissions
 }
 
-func NewService(repos repository.DocumentRepository, remotes remote.DocumentsRemote) *Service {
+func NewService(repos repository.DocumentRepository, trees repository.TreeRepository, previews repository.PreviewRepository, blobs repository.BlobRepository, remotes remote.DocumentsRemote, access common.Access, quotas common.Quotas, scanner common.Scanner, policy common.Policy, permissions modules.Permissions) *Service {
 	return &Service{
-		repos:   repos,
-		remotes: remotes,
+		repos:       repos,
+		trees:       trees,
+		previews:    previews,
+		blobs:       blobs,
+		remotes:     remotes,
+		access:      access,
+		quotas:      quotas,
+		scanner:     scanner,
+		policy:      policy,
+		permissions: permissions,
 	}
 }
 
 func (s *Service) Create(ctx context.Context, document dto.Document, file *multipart.FileHeader) (dto.Document, error) {
-	userID, ok := ctx.Value(modules.UserID).(string)
-	if !ok {
-		return document, fmt.Errorf("unauthorized action is prohibited")
-	}
-
 	content, err := file.Open()
 	if err != nil {
 		return document, err
 	}
 	defer content.Close()
 
+	return s.Store(ctx, document, dto.UploadFile{
+		Name: file.Filename,
+		Type: file.Header.Get("Content-Type"),
+		Size: file.Size,
+	}, content)
+}
+
+// Store checks a file against the upload rules of its tree and the quotas of the
+// owner, then stores its content and scans it. The name of the document wins over
+// the name of the file.
+func (s *Service) Store(ctx context.Context, document dto.Document, file dto.UploadFile, content io.ReadSeeker) (dto.Document, error) {
+	owner, err := s.access.Owner(ctx, document.TreeID, modules.AccessEditor)
+	if err != nil {
+		return document, err
+	}
+
+	if isTemplate(document) && !s.permissions.Allowed(ctx, modules.CreateTemplate) {
+		return document, modules.ErrForbidden
+	}
+
+	// the type is sniffed from the content instead of trusting the client
+	if file.Head, err = upload.Head(content); err != nil {
+		return document, err
+	}
+
+	checked, err := s.policy.Check(ctx, document.TreeID, file)
+	if err != nil {
+		return document, err
+	}
+
+	if err = s.quotas.Check(ctx, owner, file.Size); err != nil {
+		return document, err
+	}
+
+	document.Name = upload.SanitizeName(document.Name)
 	if document.Name == "" {
-		document.Name = file.Filename
+		document.Name = checked.Name
 	}
 
-	document.UserID = userID
+	document.UserID = owner
 	document.RequestContent = content
 	document.Size = file.Size
-	document.Type, _, _ = mime.ParseMediaType(file.Header.Get("Content-Type"))
-	document.Extension = filepath.Ext(file.Filename)
+	document.Type = checked.Type
+	document.Extension = checked.Extension
 
-	stored, err := s.repos.Create(ctx, document)
+	// the file is buffered by the request already, so it is hashed before
+	// deciding whether its content has to be stored at all
+	document.Checksum, err = utils.ChecksumSeeker(content)
+	if err != nil {
+		return document, err
+	}
+
+	blob, err := s.blobs.Acquire(ctx, dto.Blob{Checksum: document.Checksum, Size: document.Size})
 	if err != nil {
 		return document, err
 	}
 
-	uploaded, err := s.remotes.Upload(ctx, stored)
+	// identical content is stored once, a document sharing a stored blob is ready at once
+	if !blob.Stored {
+		document.State = modules.DocumentUploading
+	}
+
+	stored, err := s.repos.Create(ctx, document)
 	if err != nil {
-		return uploaded, err
+		s.release(ctx, blob.Checksum)
+		return document, err
+	}
+
+	// infected content never reaches spaces, the document is kept as a record of the upload
+	if stored, err = common.Scan(ctx, s.scanner, s.repos, stored, content); err != nil {
+		if errors.Is(err, modules.ErrInfected) && stored.State == modules.DocumentUploading {
+			stored = common.Settle(ctx, s.repos, stored)
+		}
+		return stored, err
+	}
+
+	if _, err = content.Seek(0, io.SeekStart); err != nil {
+		common.Discard(ctx, s.repos, stored)
+		return stored, err
+	}
+
+	if stored.State == modules.DocumentUploading {
+		if stored, err = s.remotes.Upload(ctx, stored); err != nil {
+			common.Discard(ctx, s.repos, stored)
+			return stored, err
+		}
+
+		// the content is stored, the outbox settles the blob and the document when this request can't
+		if err = s.blobs.Stored(ctx, stored.Checksum); err != nil {
+			logrus.Errorf("[blob error]: %+v - %+v", stored.Checksum, err)
+			return stored, s.previews.Queue(ctx, stored.ID)
+		}
+
+		stored = common.Settle(ctx, s.repos, stored)
 	}
 
-	return uploaded, nil
+	return stored, s.previews.Queue(ctx, stored.ID)
 }
 
 func (s *Service) Get(ctx context.Context, doc dto.Document, download bool) (dto.Document, error) {
-	userId, ok := ctx.Value(modules.UserID).(string)
-	if !ok {
-		return doc, fmt.Errorf("unauthorized action is prohibited")
+	owner, err := s.access.Owner(ctx, doc.TreeID, modules.AccessViewer)
+	if err != nil {
+		return doc, err
 	}
 
-	doc.UserID = userId
+	doc.UserID = owner
 
 	stored, err := s.repos.Get(ctx, doc)
 	if err != nil {
@@ -77,61 +172,270 @@ func (s *Service) Get(ctx context.Context, doc dto.Document, download bool) (dto
 		return stored, nil
 	}
 
+	if stored.ScanStatus != modules.ScanClean {
+		return stored, modules.ErrNotClean
+	}
+
+	stored.Download = doc.Download
+
 	return s.remotes.Get(ctx, stored)
 }
 
 func (s *Service) Delete(ctx context.Context, doc dto.Document) (dto.Document, error) {
-	userId, ok := ctx.Value(modules.UserID).(string)
-	if !ok {
-		return doc, fmt.Errorf("unauthorized action is prohibited")
+	owner, err := s.access.Owner(ctx, doc.TreeID, modules.AccessEditor)
+	if err != nil && s.permissions.Allowed(ctx, modules.DeleteAnyContent) {
+		owner, err = s.ownerOf(ctx, doc.TreeID)
 	}
+	if err != nil {
+		return doc, err
+	}
+
+	doc.UserID = owner
 
-	doc.UserID = userId
+	// the content stays in spaces until the document is purged from the trash
+	return s.repos.Delete(ctx, doc)
+}
 
-	document, err := s.repos.Get(ctx, doc)
+func (s *Service) Update(ctx context.Context, doc dto.Document) (dto.Document, error) {
+	owner, err := s.access.Owner(ctx, doc.TreeID, modules.AccessEditor)
 	if err != nil {
-		return document, err
+		return doc, err
 	}
 
-	document, err = s.remotes.Delete(ctx, document)
-	if err != nil {
-		return document, err
+	doc.UserID = owner
+
+	if isTemplate(doc) && !s.permissions.Allowed(ctx, modules.CreateTemplate) {
+		return doc, modules.ErrForbidden
 	}
 
-	return s.repos.Delete(ctx, doc)
+	// a rename follows the rules of uploaded names, an empty name isn't changed
+	if doc.Name != "" {
+		if doc.Name = upload.SanitizeName(doc.Name); doc.Name == "" {
+			return doc, &modules.ValidationError{Violations: []modules.Violation{
+				{Field: "name", Rule: "name", Message: "the file name is empty or invalid"},
+			}}
+		}
+	}
+
+	return s.repos.Update(ctx, doc)
 }
 
-func (s *Service) Update(ctx context.Context, doc dto.Document) (dto.Document, error) {
+func (s *Service) Search(ctx context.Context, search dto.DocumentSearch) (dto.DocumentPage, error) {
 	userId, ok := ctx.Value(modules.UserID).(string)
 	if !ok {
-		return doc, fmt.Errorf("unauthorized action is prohibited")
+		return dto.DocumentPage{}, fmt.Errorf("unauthorized action is prohibited")
 	}
 
-	doc.UserID = userId
+	shared, err := s.access.Trees(ctx)
+	if err != nil {
+		return dto.DocumentPage{}, err
+	}
 
-	return s.repos.Update(ctx, doc)
+	search.UserID = userId
+	search.SharedTrees = shared
+
+	page, err := s.repos.Search(ctx, search)
+	if err != nil {
+		return page, err
+	}
+
+	withPreviews(page.Documents)
+
+	return page, nil
 }
 
 func (s *Service) ListByTree(ctx context.Context, ids []uint) ([]dto.Document, error) {
-	return s.repos.ListByTree(ctx, ids)
+	docs, err := s.repos.ListByTree(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	withPreviews(docs)
+
+	return docs, nil
+}
+
+func (s *Service) Preview(ctx context.Context, doc dto.Document, size string) (dto.Document, error) {
+	owner, err := s.access.Owner(ctx, doc.TreeID, modules.AccessViewer)
+	if err != nil {
+		return doc, err
+	}
+
+	doc.UserID = owner
+
+	stored, err := s.repos.Get(ctx, doc)
+	if err != nil {
+		return stored, err
+	}
+
+	// a thumbnail shows the content, so it is withheld like the content itself
+	if stored.ScanStatus != modules.ScanClean {
+		return stored, modules.ErrNotClean
+	}
+
+	stored.Download = doc.Download
+
+	return s.remotes.GetPreview(ctx, stored, size)
 }
 
 func (s *Service) ListByGroups(ctx context.Context, groupIds []uint) ([]dto.Document, error) {
 	return s.repos.ListByGroups(ctx, groupIds)
 }
 
-func (s *Service) Share(ctx context.Context, doc dto.Document, duration time.Duration) (dto.Document, error) {
-	userId, ok := ctx.Value(modules.UserID).(string)
-	if !ok {
-		return doc, fmt.Errorf("unauthorized action is prohibited")
+func (s *Service) Move(ctx context.Context, doc dto.Document, treeID uint) (dto.Document, error) {
+	owner, err := s.access.Owner(ctx, doc.TreeID, modules.AccessEditor)
+	if err != nil {
+		return doc, err
 	}
 
-	doc.UserID = userId
+	doc.UserID = owner
 
-	document, err := s.repos.Get(ctx, doc)
+	stored, err := s.repos.Get(ctx, doc)
 	if err != nil {
-		return document, err
+		return stored, err
+	}
+
+	// a document stays with its owner, so it can't be moved into a tree of another user
+	destination, err := s.destination(ctx, treeID)
+	if err != nil {
+		return stored, err
+	}
+	if destination != owner {
+		return stored, modules.ErrForbidden
+	}
+
+	return s.repos.Move(ctx, stored, treeID)
+}
+
+func (s *Service) Copy(ctx context.Context, doc dto.Document, treeID uint) (dto.Document, error) {
+	owner, err := s.access.Owner(ctx, doc.TreeID, modules.AccessViewer)
+	if err != nil {
+		return doc, err
+	}
+
+	doc.UserID = owner
+
+	stored, err := s.repos.Get(ctx, doc)
+	if err != nil {
+		return stored, err
+	}
+
+	if _, err = s.destination(ctx, treeID); err != nil {
+		return stored, err
+	}
+
+	return s.Duplicate(ctx, stored, treeID)
+}
+
+// Duplicate copies a stored document into a tree, the copy belongs to the owner of the tree.
+// Access to the document and the tree must be checked before.
+func (s *Service) Duplicate(ctx context.Context, doc dto.Document, treeID uint) (dto.Document, error) {
+	owner, err := s.ownerOf(ctx, treeID)
+	if err != nil {
+		return doc, err
+	}
+
+	// a copy counts towards the quota even when its content is shared
+	if err = s.quotas.Check(ctx, owner, doc.Size); err != nil {
+		return doc, err
+	}
+
+	// a copy of a blob is one more reference to it
+	if doc.Checksum != "" {
+		if _, err = s.blobs.Acquire(ctx, dto.Blob{Checksum: doc.Checksum, Size: doc.Size}); err != nil {
+			return doc, err
+		}
+	}
+
+	// a document stored before checksums is copied in spaces, the copy waits for it
+	state := modules.DocumentReady
+	if doc.Checksum == "" {
+		state = modules.DocumentUploading
+	}
+
+	// the copy gets its own path, so both documents change independently,
+	// the outbox copies the content again when this request can't
+	source := s.remotes.Key(doc)
+	copied, err := s.repos.Duplicate(ctx, dto.Document{
+		UserID:     owner,
+		TreeID:     treeID,
+		Name:       doc.Name,
+		Extension:  doc.Extension,
+		Size:       doc.Size,
+		Type:       doc.Type,
+		Path:       uuid.New(),
+		Template:   doc.Template,
+		Checksum:   doc.Checksum,
+		ScanStatus: doc.ScanStatus,
+		State:      state,
+	}, source)
+	if err != nil {
+		s.release(ctx, doc.Checksum)
+		return copied, err
+	}
+
+	if copied.State == modules.DocumentUploading {
+		if copied, err = s.remotes.Copy(ctx, source, copied); err != nil {
+			common.Discard(ctx, s.repos, copied)
+			return copied, err
+		}
+
+		copied = common.Settle(ctx, s.repos, copied)
+	}
+
+	// a copy of blocked content has nothing to preview
+	if copied.ScanStatus != modules.ScanClean {
+		return copied, nil
+	}
+
+	return copied, s.previews.Queue(ctx, copied.ID)
+}
+
+// release drops a reference taken for a document that wasn't created,
+// the error of the creation is the one reported
+func (s *Service) release(ctx context.Context, checksum string) {
+	if checksum == "" {
+		return
+	}
+
+	if err := s.blobs.Release(ctx, checksum); err != nil {
+		logrus.Errorf("[blob error]: %+v - %+v", checksum, err)
+	}
+}
+
+// withPreviews links the thumbnails of documents that have them
+func withPreviews(docs []dto.Document) {
+	for i, doc := range docs {
+		if doc.Preview {
+			docs[i].PreviewURL = fmt.Sprintf(previewPath, doc.TreeID, doc.ID)
+		}
+	}
+}
+
+// isTemplate reports whether a document is marked as a template
+func isTemplate(doc dto.Document) bool {
+	return doc.Template != nil && *doc.Template
+}
+
+// destination returns the owner of the tree a document is placed into
+func (s *Service) destination(ctx context.Context, treeID uint) (string, error) {
+	if treeID == 0 {
+		return "", modules.ErrNoParentTree
+	}
+
+	return s.access.Owner(ctx, treeID, modules.AccessEditor)
+}
+
+// ownerOf returns the owner of a tree without checking access
+func (s *Service) ownerOf(ctx context.Context, treeID uint) (string, error) {
+	tree, err := s.trees.Get(ctx, dto.Tree{ID: treeID})
+	if err != nil {
+		return "", err
+	}
+
+	if tree.UserID == "" {
+		return "", gorm.ErrRecordNotFound
 	}
 
-	return s.remotes.Share(ctx, document, duration)
+	return tree.UserID, nil
 }
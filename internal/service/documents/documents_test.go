@@ -0,0 +1,371 @@
+package documents
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	remotemocks "gitlab.com/a5805/ondeu/ondeu-back/internal/remote/mocks"
+	repomocks "gitlab.com/a5805/ondeu/ondeu-back/internal/repository/mocks"
+	commonmocks "gitlab.com/a5805/ondeu/ondeu-back/internal/service/common/mocks"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"strings"
+	"testing"
+)
+
+type mocks struct {
+	repos    *repomocks.MockDocumentRepository
+	trees    *repomocks.MockTreeRepository
+	previews *repomocks.MockPreviewRepository
+	blobs    *repomocks.MockBlobRepository
+	remotes  *remotemocks.MockDocumentsRemote
+	access   *commonmocks.MockAccess
+	quotas   *commonmocks.MockQuotas
+	scanner  *commonmocks.MockScanner
+	policy   *commonmocks.MockPolicy
+}
+
+func newMocks(c *gomock.Controller) mocks {
+	return mocks{
+		repos:    repomocks.NewMockDocumentRepository(c),
+		trees:    repomocks.NewMockTreeRepository(c),
+		previews: repomocks.NewMockPreviewRepository(c),
+		blobs:    repomocks.NewMockBlobRepository(c),
+		remotes:  remotemocks.NewMockDocumentsRemote(c),
+		access:   commonmocks.NewMockAccess(c),
+		quotas:   commonmocks.NewMockQuotas(c),
+		scanner:  commonmocks.NewMockScanner(c),
+		policy:   commonmocks.NewMockPolicy(c),
+	}
+}
+
+func (m mocks) service() *Service {
+	return NewService(m.repos, m.trees, m.previews, m.blobs, m.remotes, m.access, m.quotas, m.scanner, m.policy, modules.Permissions{})
+}
+
+func TestService_Store(t *testing.T) {
+	type mockBehavior func(m mocks)
+
+	// the SHA-256 of the content
+	content := "hello"
+	checksum := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	owner := uuid.New().String()
+
+	// created returns the document the repository writes, with the state the service chose
+	created := func(_ context.Context, doc dto.Document) (dto.Document, error) {
+		doc.ID = 7
+		doc.ScanStatus = modules.ScanPending
+		return doc, nil
+	}
+	scanned := func(_ context.Context, doc dto.Document) (dto.Document, error) {
+		return doc, nil
+	}
+	ready := func(_ context.Context, doc dto.Document) (dto.Document, error) {
+		doc.State = modules.DocumentReady
+		return doc, nil
+	}
+
+	// checked expects the caller to be allowed to store the file
+	checked := func(m mocks) {
+		m.access.EXPECT().Owner(gomock.Any(), uint(3), modules.AccessEditor).Return(owner, nil)
+		m.policy.EXPECT().Check(gomock.Any(), uint(3), gomock.Any()).Return(dto.UploadFile{Name: "notes.txt", Extension: ".txt", Type: "text/plain"}, nil)
+		m.quotas.EXPECT().Check(gomock.Any(), owner, int64(len(content))).Return(nil)
+	}
+
+	tests := []struct {
+		name          string
+		mockBehavior  mockBehavior
+		expectedState string
+		expectedErr   error
+	}{
+		{
+			name: "Success. New content is uploaded and marked ready",
+			mockBehavior: func(m mocks) {
+				checked(m)
+				gomock.InOrder(
+					m.blobs.EXPECT().Acquire(gomock.Any(), dto.Blob{Checksum: checksum, Size: int64(len(content))}).Return(dto.Blob{Checksum: checksum, Refs: 1}, nil),
+					m.repos.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, doc dto.Document) (dto.Document, error) {
+						assert.Equal(t, modules.DocumentUploading, doc.State)
+						return created(ctx, doc)
+					}),
+					m.scanner.EXPECT().Scan(gomock.Any(), gomock.Any()).Return("", nil),
+					m.repos.EXPECT().Scanned(gomock.Any(), gomock.Any()).DoAndReturn(scanned),
+					m.remotes.EXPECT().Upload(gomock.Any(), gomock.Any()).DoAndReturn(scanned),
+					m.blobs.EXPECT().Stored(gomock.Any(), checksum).Return(nil),
+					m.repos.EXPECT().Ready(gomock.Any(), gomock.Any()).DoAndReturn(ready),
+					m.previews.EXPECT().Queue(gomock.Any(), uint(7)).Return(nil),
+				)
+			},
+			expectedState: modules.DocumentReady,
+		},
+		{
+			name: "Success. Content stored before is shared at once",
+			mockBehavior: func(m mocks) {
+				checked(m)
+				gomock.InOrder(
+					m.blobs.EXPECT().Acquire(gomock.Any(), gomock.Any()).Return(dto.Blob{Checksum: checksum, Refs: 2, Stored: true}, nil),
+					m.repos.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, doc dto.Document) (dto.Document, error) {
+						assert.Empty(t, doc.State)
+						doc.State = modules.DocumentReady
+						return created(ctx, doc)
+					}),
+					m.scanner.EXPECT().Scan(gomock.Any(), gomock.Any()).Return("", nil),
+					m.repos.EXPECT().Scanned(gomock.Any(), gomock.Any()).DoAndReturn(scanned),
+					m.previews.EXPECT().Queue(gomock.Any(), uint(7)).Return(nil),
+				)
+			},
+			expectedState: modules.DocumentReady,
+		},
+		{
+			name: "Success. Content shared with an upload still running waits for its blob",
+			mockBehavior: func(m mocks) {
+				checked(m)
+				gomock.InOrder(
+					m.blobs.EXPECT().Acquire(gomock.Any(), gomock.Any()).Return(dto.Blob{Checksum: checksum, Refs: 2}, nil),
+					m.repos.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, doc dto.Document) (dto.Document, error) {
+						assert.Equal(t, modules.DocumentUploading, doc.State)
+						return created(ctx, doc)
+					}),
+					m.scanner.EXPECT().Scan(gomock.Any(), gomock.Any()).Return("", nil),
+					m.repos.EXPECT().Scanned(gomock.Any(), gomock.Any()).DoAndReturn(scanned),
+					m.remotes.EXPECT().Upload(gomock.Any(), gomock.Any()).DoAndReturn(scanned),
+					m.blobs.EXPECT().Stored(gomock.Any(), checksum).Return(nil),
+					m.repos.EXPECT().Ready(gomock.Any(), gomock.Any()).DoAndReturn(ready),
+					m.previews.EXPECT().Queue(gomock.Any(), uint(7)).Return(nil),
+				)
+			},
+			expectedState: modules.DocumentReady,
+		},
+		{
+			name: "Success. Document whose Ready fails is left to the outbox",
+			mockBehavior: func(m mocks) {
+				checked(m)
+				gomock.InOrder(
+					m.blobs.EXPECT().Acquire(gomock.Any(), gomock.Any()).Return(dto.Blob{Checksum: checksum, Refs: 1}, nil),
+					m.repos.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(created),
+					m.scanner.EXPECT().Scan(gomock.Any(), gomock.Any()).Return("", nil),
+					m.repos.EXPECT().Scanned(gomock.Any(), gomock.Any()).DoAndReturn(scanned),
+					m.remotes.EXPECT().Upload(gomock.Any(), gomock.Any()).DoAndReturn(scanned),
+					m.blobs.EXPECT().Stored(gomock.Any(), checksum).Return(nil),
+					m.repos.EXPECT().Ready(gomock.Any(), gomock.Any()).Return(dto.Document{}, errors.New("connection reset")),
+					m.previews.EXPECT().Queue(gomock.Any(), uint(7)).Return(nil),
+				)
+			},
+			expectedState: modules.DocumentUploading,
+		},
+		{
+			name: "Failed. Upload, the document is discarded",
+			mockBehavior: func(m mocks) {
+				checked(m)
+				gomock.InOrder(
+					m.blobs.EXPECT().Acquire(gomock.Any(), gomock.Any()).Return(dto.Blob{Checksum: checksum, Refs: 1}, nil),
+					m.repos.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(created),
+					m.scanner.EXPECT().Scan(gomock.Any(), gomock.Any()).Return("", nil),
+					m.repos.EXPECT().Scanned(gomock.Any(), gomock.Any()).DoAndReturn(scanned),
+					m.remotes.EXPECT().Upload(gomock.Any(), gomock.Any()).Return(dto.Document{ID: 7}, errors.New("connection reset")),
+					m.repos.EXPECT().Purge(gomock.Any(), []uint{7}).Return(dto.Purge{Documents: 1}, nil),
+				)
+			},
+			expectedErr: errors.New("connection reset"),
+		},
+		{
+			name: "Failed. Infected content is kept as a record without being stored",
+			mockBehavior: func(m mocks) {
+				checked(m)
+				gomock.InOrder(
+					m.blobs.EXPECT().Acquire(gomock.Any(), gomock.Any()).Return(dto.Blob{Checksum: checksum, Refs: 1}, nil),
+					m.repos.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(created),
+					m.scanner.EXPECT().Scan(gomock.Any(), gomock.Any()).Return("Eicar-Signature", nil),
+					m.repos.EXPECT().Scanned(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, doc dto.Document) (dto.Document, error) {
+						assert.Equal(t, modules.ScanInfected, doc.ScanStatus)
+						return scanned(ctx, doc)
+					}),
+					m.repos.EXPECT().Ready(gomock.Any(), gomock.Any()).DoAndReturn(ready),
+				)
+			},
+			expectedState: modules.DocumentReady,
+			expectedErr:   modules.ErrInfected,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			m := newMocks(c)
+			tt.mockBehavior(m)
+
+			// Test
+			doc, err := m.service().Store(context.Background(), dto.Document{TreeID: 3}, dto.UploadFile{
+				Name: "notes.txt",
+				Type: "text/plain",
+				Size: int64(len(content)),
+			}, strings.NewReader(content))
+
+			// Assert
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.expectedState != "" {
+				assert.Equal(t, tt.expectedState, doc.State)
+			}
+		})
+	}
+}
+
+func TestService_Duplicate(t *testing.T) {
+	type mockBehavior func(m mocks)
+
+	owner := uuid.New().String()
+	source := dto.Document{ID: 5, Name: "notes", Extension: ".txt", Size: 5, Path: uuid.New(), ScanStatus: modules.ScanClean}
+	sourceKey := "2026-10-18/" + source.Path.String() + ".txt"
+
+	tests := []struct {
+		name          string
+		source        dto.Document
+		mockBehavior  mockBehavior
+		expectedState string
+		expectedErr   error
+	}{
+		{
+			name:   "Success. Content kept under its own key is copied and marked ready",
+			source: source,
+			mockBehavior: func(m mocks) {
+				m.trees.EXPECT().Get(gomock.Any(), dto.Tree{ID: 9}).Return(dto.Tree{ID: 9, UserID: owner}, nil)
+				m.quotas.EXPECT().Check(gomock.Any(), owner, source.Size).Return(nil)
+				gomock.InOrder(
+					m.remotes.EXPECT().Key(source).Return(sourceKey),
+					m.repos.EXPECT().Duplicate(gomock.Any(), gomock.Any(), sourceKey).DoAndReturn(func(_ context.Context, doc dto.Document, _ string) (dto.Document, error) {
+						assert.Equal(t, modules.DocumentUploading, doc.State)
+						doc.ID = 8
+						return doc, nil
+					}),
+					m.remotes.EXPECT().Copy(gomock.Any(), sourceKey, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, doc dto.Document) (dto.Document, error) {
+						return doc, nil
+					}),
+					m.repos.EXPECT().Ready(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, doc dto.Document) (dto.Document, error) {
+						doc.State = modules.DocumentReady
+						return doc, nil
+					}),
+					m.previews.EXPECT().Queue(gomock.Any(), uint(8)).Return(nil),
+				)
+			},
+			expectedState: modules.DocumentReady,
+		},
+		{
+			name: "Success. Copy of a blob is one more reference, ready at once",
+			source: func() dto.Document {
+				shared := source
+				shared.Checksum = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+				return shared
+			}(),
+			mockBehavior: func(m mocks) {
+				m.trees.EXPECT().Get(gomock.Any(), dto.Tree{ID: 9}).Return(dto.Tree{ID: 9, UserID: owner}, nil)
+				m.quotas.EXPECT().Check(gomock.Any(), owner, source.Size).Return(nil)
+				gomock.InOrder(
+					m.blobs.EXPECT().Acquire(gomock.Any(), gomock.Any()).Return(dto.Blob{Refs: 2, Stored: true}, nil),
+					m.remotes.EXPECT().Key(gomock.Any()).Return("blobs/2c/f2/2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"),
+					m.repos.EXPECT().Duplicate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, doc dto.Document, _ string) (dto.Document, error) {
+						assert.Equal(t, modules.DocumentReady, doc.State)
+						doc.ID = 8
+						return doc, nil
+					}),
+					m.previews.EXPECT().Queue(gomock.Any(), uint(8)).Return(nil),
+				)
+			},
+			expectedState: modules.DocumentReady,
+		},
+		{
+			name:   "Failed. Copy, the copy is discarded",
+			source: source,
+			mockBehavior: func(m mocks) {
+				m.trees.EXPECT().Get(gomock.Any(), dto.Tree{ID: 9}).Return(dto.Tree{ID: 9, UserID: owner}, nil)
+				m.quotas.EXPECT().Check(gomock.Any(), owner, source.Size).Return(nil)
+				gomock.InOrder(
+					m.remotes.EXPECT().Key(source).Return(sourceKey),
+					m.repos.EXPECT().Duplicate(gomock.Any(), gomock.Any(), sourceKey).DoAndReturn(func(_ context.Context, doc dto.Document, _ string) (dto.Document, error) {
+						doc.ID = 8
+						return doc, nil
+					}),
+					m.remotes.EXPECT().Copy(gomock.Any(), sourceKey, gomock.Any()).Return(dto.Document{ID: 8}, errors.New("connection reset")),
+					m.repos.EXPECT().Purge(gomock.Any(), []uint{8}).Return(dto.Purge{Documents: 1}, nil),
+				)
+			},
+			expectedErr: errors.New("connection reset"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			m := newMocks(c)
+			tt.mockBehavior(m)
+
+			// Test
+			doc, err := m.service().Duplicate(context.Background(), tt.source, 9)
+
+			// Assert
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedState, doc.State)
+		})
+	}
+}
+
+func TestService_Preview(t *testing.T) {
+	type mockBehavior func(m mocks)
+
+	owner := uuid.New().String()
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		expectedErr  error
+	}{
+		{
+			name: "Success. Clean document",
+			mockBehavior: func(m mocks) {
+				m.access.EXPECT().Owner(gomock.Any(), uint(2), modules.AccessViewer).Return(owner, nil)
+				m.repos.EXPECT().Get(gomock.Any(), dto.Document{ID: 1, TreeID: 2, UserID: owner}).
+					Return(dto.Document{ID: 1, ScanStatus: modules.ScanClean}, nil)
+				m.remotes.EXPECT().GetPreview(gomock.Any(), dto.Document{ID: 1, ScanStatus: modules.ScanClean}, "small").
+					Return(dto.Document{ID: 1}, nil)
+			},
+		},
+		{
+			name: "Failed. Infected document, its thumbnail isn't read",
+			mockBehavior: func(m mocks) {
+				m.access.EXPECT().Owner(gomock.Any(), uint(2), modules.AccessViewer).Return(owner, nil)
+				m.repos.EXPECT().Get(gomock.Any(), dto.Document{ID: 1, TreeID: 2, UserID: owner}).
+					Return(dto.Document{ID: 1, ScanStatus: modules.ScanInfected}, nil)
+			},
+			expectedErr: modules.ErrNotClean,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			m := newMocks(c)
+			tt.mockBehavior(m)
+
+			// Test
+			_, err := m.service().Preview(context.Background(), dto.Document{ID: 1, TreeID: 2}, "small")
+
+			// Assert
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
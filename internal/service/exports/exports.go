@@ -0,0 +1,109 @@
+package exports
+
+import (
+	"context"
+	"encoding/json"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/remote"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/common"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/archive"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"io"
+	"time"
+)
+
+// manifest is the path of the manifest in an export
+const manifest = "manifest.json"
+
+// documents is the folder the documents of an export are laid out in
+const documents = "documents/"
+
+type Service struct {
+	trees     repository.TreeRepository
+	documents repository.DocumentRepository
+	groups    repository.GroupRepository
+	shares    repository.ShareRepository
+	remotes   remote.DocumentsRemote
+	folders   common.Folders
+	usage     common.Usage
+}
+
+func NewService(trees repository.TreeRepository, documents repository.DocumentRepository, groups repository.GroupRepository, shares repository.ShareRepository, remotes remote.DocumentsRemote, folders common.Folders, usage common.Usage) *Service {
+	return &Service{
+		trees:     trees,
+		documents: documents,
+		groups:    groups,
+		shares:    shares,
+		remotes:   remotes,
+		folders:   folders,
+		usage:     usage,
+	}
+}
+
+// Export lays out the folders and documents a user owns, with a manifest of their
+// metadata, groups, active share links and usage. Content is exported only for
+// documents that passed the scan, the trash is left out. Share links are listed
+// without anything that opens them, an export may travel further than the links should.
+func (s *Service) Export(ctx context.Context, userID string) (dto.Archive, error) {
+	now := time.Now()
+
+	trees, err := s.trees.List(ctx, dto.Tree{ID: 0, UserID: userID})
+	if err != nil {
+		return dto.Archive{}, err
+	}
+
+	docs, err := s.documents.ListByTree(ctx, s.folders.GetTreeIDs(ctx, trees))
+	if err != nil {
+		return dto.Archive{}, err
+	}
+	trees = s.folders.FormTree(ctx, trees, docs)
+
+	groups, err := s.groups.List(ctx, userID)
+	if err != nil {
+		return dto.Archive{}, err
+	}
+
+	shares, err := s.shares.List(ctx, userID, now)
+	if err != nil {
+		return dto.Archive{}, err
+	}
+
+	for i := range shares {
+		shares[i].Token, shares[i].TokenHash, shares[i].Link = "", "", ""
+	}
+
+	usage, err := s.usage.UserUsage(ctx, userID)
+	if err != nil {
+		return dto.Archive{}, err
+	}
+
+	content, err := json.MarshalIndent(dto.Export{
+		UserID:     userID,
+		ExportedAt: now,
+		Usage:      usage,
+		Trees:      trees,
+		Groups:     groups,
+		Shares:     shares,
+	}, "", "  ")
+	if err != nil {
+		return dto.Archive{}, err
+	}
+
+	entries := []dto.ArchiveEntry{{Path: manifest, Content: content}, {Path: documents}}
+	for _, entry := range archive.Layout(0, trees) {
+		if entry.Document == nil || entry.Document.ScanStatus == modules.ScanClean {
+			entry.Path = documents + entry.Path
+			entries = append(entries, entry)
+		}
+	}
+
+	return dto.Archive{Name: "export-" + userID, Entries: entries}, nil
+}
+
+func (s *Service) WriteArchive(ctx context.Context, export dto.Archive, w io.Writer) error {
+	return archive.Write(w, export.Entries, func(doc dto.Document) (io.ReadCloser, error) {
+		doc, err := s.remotes.Get(ctx, doc)
+		return doc.ResponseContent, err
+	})
+}
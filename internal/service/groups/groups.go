@@ -0,0 +1,192 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+)
+
+type Service struct {
+	repos     repository.GroupRepository
+	documents repository.DocumentRepository
+}
+
+func NewService(repos repository.GroupRepository, documents repository.DocumentRepository) *Service {
+	return &Service{
+		repos:     repos,
+		documents: documents,
+	}
+}
+
+func (s *Service) Create(ctx context.Context, group dto.Group) (dto.Group, error) {
+	userId, ok := ctx.Value(modules.UserID).(string)
+	if !ok {
+		return group, fmt.Errorf("unauthorized action is prohibited")
+	}
+
+	group.UserID = userId
+
+	return s.repos.Create(ctx, group)
+}
+
+func (s *Service) Get(ctx context.Context, group dto.Group) (dto.Group, error) {
+	return s.readable(ctx, group.ID)
+}
+
+func (s *Service) List(ctx context.Context) ([]dto.Group, error) {
+	userId, ok := ctx.Value(modules.UserID).(string)
+	if !ok {
+		return nil, fmt.Errorf("unauthorized action is prohibited")
+	}
+
+	return s.repos.List(ctx, userId)
+}
+
+func (s *Service) Update(ctx context.Context, group dto.Group) (dto.Group, error) {
+	userId, ok := ctx.Value(modules.UserID).(string)
+	if !ok {
+		return group, fmt.Errorf("unauthorized action is prohibited")
+	}
+
+	group.UserID = userId
+
+	return s.repos.Update(ctx, group)
+}
+
+func (s *Service) Delete(ctx context.Context, group dto.Group) (dto.Group, error) {
+	userId, ok := ctx.Value(modules.UserID).(string)
+	if !ok {
+		return group, fmt.Errorf("unauthorized action is prohibited")
+	}
+
+	group.UserID = userId
+
+	return s.repos.Delete(ctx, group)
+}
+
+func (s *Service) AddMember(ctx context.Context, member dto.GroupMember) (dto.GroupMember, error) {
+	if _, err := s.owned(ctx, member.GroupID); err != nil {
+		return member, err
+	}
+
+	return s.repos.AddMember(ctx, member)
+}
+
+func (s *Service) RemoveMember(ctx context.Context, member dto.GroupMember) (dto.GroupMember, error) {
+	if _, err := s.owned(ctx, member.GroupID); err != nil {
+		return member, err
+	}
+
+	return s.repos.RemoveMember(ctx, member)
+}
+
+func (s *Service) AttachDocument(ctx context.Context, group dto.Group, documentID uint) (dto.Group, error) {
+	owned, err := s.owned(ctx, group.ID)
+	if err != nil {
+		return group, err
+	}
+
+	if err = s.repos.AttachDocument(ctx, owned, documentID); err != nil {
+		return owned, err
+	}
+
+	return owned, nil
+}
+
+func (s *Service) DetachDocument(ctx context.Context, group dto.Group, documentID uint) (dto.Group, error) {
+	owned, err := s.owned(ctx, group.ID)
+	if err != nil {
+		return group, err
+	}
+
+	if err = s.repos.DetachDocument(ctx, owned, documentID); err != nil {
+		return owned, err
+	}
+
+	return owned, nil
+}
+
+func (s *Service) AttachTree(ctx context.Context, group dto.Group, treeID uint) (dto.Group, error) {
+	owned, err := s.owned(ctx, group.ID)
+	if err != nil {
+		return group, err
+	}
+
+	if err = s.repos.AttachTree(ctx, owned, treeID); err != nil {
+		return owned, err
+	}
+
+	return s.repos.Get(ctx, owned)
+}
+
+func (s *Service) DetachTree(ctx context.Context, group dto.Group, treeID uint) (dto.Group, error) {
+	owned, err := s.owned(ctx, group.ID)
+	if err != nil {
+		return group, err
+	}
+
+	if err = s.repos.DetachTree(ctx, owned, treeID); err != nil {
+		return owned, err
+	}
+
+	return s.repos.Get(ctx, owned)
+}
+
+func (s *Service) ListDocuments(ctx context.Context, group dto.Group) ([]dto.Document, error) {
+	readable, err := s.readable(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.documents.ListByGroups(ctx, []uint{readable.ID})
+}
+
+// owned returns a group if the caller owns it
+func (s *Service) owned(ctx context.Context, id uint) (dto.Group, error) {
+	userId, ok := ctx.Value(modules.UserID).(string)
+	if !ok {
+		return dto.Group{}, fmt.Errorf("unauthorized action is prohibited")
+	}
+
+	group, err := s.repos.Get(ctx, dto.Group{ID: id})
+	if err != nil {
+		return group, err
+	}
+
+	if group.UserID != userId {
+		return dto.Group{}, gorm.ErrRecordNotFound
+	}
+
+	return group, nil
+}
+
+// readable returns a group if the caller owns it or is its member
+func (s *Service) readable(ctx context.Context, id uint) (dto.Group, error) {
+	userId, ok := ctx.Value(modules.UserID).(string)
+	if !ok {
+		return dto.Group{}, fmt.Errorf("unauthorized action is prohibited")
+	}
+
+	group, err := s.repos.Get(ctx, dto.Group{ID: id})
+	if err != nil {
+		return group, err
+	}
+
+	if group.UserID == userId {
+		return group, nil
+	}
+
+	member, err := s.repos.IsMember(ctx, dto.GroupMember{GroupID: id, UserID: userId})
+	if err != nil {
+		return dto.Group{}, err
+	}
+
+	if !member {
+		return dto.Group{}, gorm.ErrRecordNotFound
+	}
+
+	return group, nil
+}
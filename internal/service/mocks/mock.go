@@ -6,9 +6,9 @@ package mock_service
 
 import (
 	context "context"
+	io "io"
 	multipart "mime/multipart"
 	reflect "reflect"
-	time "time"
 
 	v8 "github.com/Nerzal/gocloak/v8"
 	gomock "github.com/golang/mock/gomock"
@@ -38,6 +38,21 @@ func (m *MockDocumentService) EXPECT() *MockDocumentServiceMockRecorder {
 	return m.recorder
 }
 
+// Copy mocks base method.
+func (m *MockDocumentService) Copy(ctx context.Context, doc dto.Document, treeID uint) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Copy", ctx, doc, treeID)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Copy indicates an expected call of Copy.
+func (mr *MockDocumentServiceMockRecorder) Copy(ctx, doc, treeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Copy", reflect.TypeOf((*MockDocumentService)(nil).Copy), ctx, doc, treeID)
+}
+
 // Create mocks base method.
 func (m *MockDocumentService) Create(ctx context.Context, in dto.Document, file *multipart.FileHeader) (dto.Document, error) {
 	m.ctrl.T.Helper()
@@ -113,19 +128,64 @@ func (mr *MockDocumentServiceMockRecorder) ListByTree(ctx, ids interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByTree", reflect.TypeOf((*MockDocumentService)(nil).ListByTree), ctx, ids)
 }
 
-// Share mocks base method.
-func (m *MockDocumentService) Share(ctx context.Context, doc dto.Document, duration time.Duration) (dto.Document, error) {
+// Move mocks base method.
+func (m *MockDocumentService) Move(ctx context.Context, doc dto.Document, treeID uint) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", ctx, doc, treeID)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockDocumentServiceMockRecorder) Move(ctx, doc, treeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockDocumentService)(nil).Move), ctx, doc, treeID)
+}
+
+// Preview mocks base method.
+func (m *MockDocumentService) Preview(ctx context.Context, doc dto.Document, size string) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Preview", ctx, doc, size)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Preview indicates an expected call of Preview.
+func (mr *MockDocumentServiceMockRecorder) Preview(ctx, doc, size interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Preview", reflect.TypeOf((*MockDocumentService)(nil).Preview), ctx, doc, size)
+}
+
+// Search mocks base method.
+func (m *MockDocumentService) Search(ctx context.Context, search dto.DocumentSearch) (dto.DocumentPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, search)
+	ret0, _ := ret[0].(dto.DocumentPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockDocumentServiceMockRecorder) Search(ctx, search interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockDocumentService)(nil).Search), ctx, search)
+}
+
+// Store mocks base method.
+func (m *MockDocumentService) Store(ctx context.Context, document dto.Document, file dto.UploadFile, content io.ReadSeeker) (dto.Document, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Share", ctx, doc, duration)
+	ret := m.ctrl.Call(m, "Store", ctx, document, file, content)
 	ret0, _ := ret[0].(dto.Document)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Share indicates an expected call of Share.
-func (mr *MockDocumentServiceMockRecorder) Share(ctx, doc, duration interface{}) *gomock.Call {
+// Store indicates an expected call of Store.
+func (mr *MockDocumentServiceMockRecorder) Store(ctx, document, file, content interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Share", reflect.TypeOf((*MockDocumentService)(nil).Share), ctx, doc, duration)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Store", reflect.TypeOf((*MockDocumentService)(nil).Store), ctx, document, file, content)
 }
 
 // Update mocks base method.
@@ -166,6 +226,36 @@ func (m *MockTreeService) EXPECT() *MockTreeServiceMockRecorder {
 	return m.recorder
 }
 
+// Archive mocks base method.
+func (m *MockTreeService) Archive(ctx context.Context, tree dto.Tree, selection dto.ArchiveSelection) (dto.Archive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Archive", ctx, tree, selection)
+	ret0, _ := ret[0].(dto.Archive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Archive indicates an expected call of Archive.
+func (mr *MockTreeServiceMockRecorder) Archive(ctx, tree, selection interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockTreeService)(nil).Archive), ctx, tree, selection)
+}
+
+// Copy mocks base method.
+func (m *MockTreeService) Copy(ctx context.Context, tree dto.Tree, parentID uint) (dto.Tree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Copy", ctx, tree, parentID)
+	ret0, _ := ret[0].(dto.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Copy indicates an expected call of Copy.
+func (mr *MockTreeServiceMockRecorder) Copy(ctx, tree, parentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Copy", reflect.TypeOf((*MockTreeService)(nil).Copy), ctx, tree, parentID)
+}
+
 // Create mocks base method.
 func (m *MockTreeService) Create(ctx context.Context, tree dto.Tree) (dto.Tree, error) {
 	m.ctrl.T.Helper()
@@ -239,6 +329,36 @@ func (mr *MockTreeServiceMockRecorder) GetTreeIDs(ctx, trees interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTreeIDs", reflect.TypeOf((*MockTreeService)(nil).GetTreeIDs), ctx, trees)
 }
 
+// Import mocks base method.
+func (m *MockTreeService) Import(ctx context.Context, tree dto.Tree, file *multipart.FileHeader) (dto.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, tree, file)
+	ret0, _ := ret[0].(dto.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockTreeServiceMockRecorder) Import(ctx, tree, file interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockTreeService)(nil).Import), ctx, tree, file)
+}
+
+// Instantiate mocks base method.
+func (m *MockTreeService) Instantiate(ctx context.Context, tree dto.Tree, parentID uint) (dto.Tree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Instantiate", ctx, tree, parentID)
+	ret0, _ := ret[0].(dto.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Instantiate indicates an expected call of Instantiate.
+func (mr *MockTreeServiceMockRecorder) Instantiate(ctx, tree, parentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Instantiate", reflect.TypeOf((*MockTreeService)(nil).Instantiate), ctx, tree, parentID)
+}
+
 // List mocks base method.
 func (m *MockTreeService) List(ctx context.Context, tree dto.Tree) ([]dto.Tree, error) {
 	m.ctrl.T.Helper()
@@ -254,6 +374,51 @@ func (mr *MockTreeServiceMockRecorder) List(ctx, tree interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTreeService)(nil).List), ctx, tree)
 }
 
+// ListTemplates mocks base method.
+func (m *MockTreeService) ListTemplates(ctx context.Context, role string) ([]dto.Tree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTemplates", ctx, role)
+	ret0, _ := ret[0].([]dto.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTemplates indicates an expected call of ListTemplates.
+func (mr *MockTreeServiceMockRecorder) ListTemplates(ctx, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTemplates", reflect.TypeOf((*MockTreeService)(nil).ListTemplates), ctx, role)
+}
+
+// Move mocks base method.
+func (m *MockTreeService) Move(ctx context.Context, tree dto.Tree, parentID uint) (dto.Tree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", ctx, tree, parentID)
+	ret0, _ := ret[0].(dto.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockTreeServiceMockRecorder) Move(ctx, tree, parentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockTreeService)(nil).Move), ctx, tree, parentID)
+}
+
+// Purge mocks base method.
+func (m *MockTreeService) Purge(ctx context.Context, tree dto.Tree) (dto.Purge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, tree)
+	ret0, _ := ret[0].(dto.Purge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockTreeServiceMockRecorder) Purge(ctx, tree interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockTreeService)(nil).Purge), ctx, tree)
+}
+
 // Update mocks base method.
 func (m *MockTreeService) Update(ctx context.Context, tree dto.Tree) (dto.Tree, error) {
 	m.ctrl.T.Helper()
@@ -269,6 +434,1239 @@ func (mr *MockTreeServiceMockRecorder) Update(ctx, tree interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTreeService)(nil).Update), ctx, tree)
 }
 
+// WriteArchive mocks base method.
+func (m *MockTreeService) WriteArchive(ctx context.Context, folder dto.Archive, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteArchive", ctx, folder, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteArchive indicates an expected call of WriteArchive.
+func (mr *MockTreeServiceMockRecorder) WriteArchive(ctx, folder, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteArchive", reflect.TypeOf((*MockTreeService)(nil).WriteArchive), ctx, folder, w)
+}
+
+// MockVersionService is a mock of VersionService interface.
+type MockVersionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockVersionServiceMockRecorder
+}
+
+// MockVersionServiceMockRecorder is the mock recorder for MockVersionService.
+type MockVersionServiceMockRecorder struct {
+	mock *MockVersionService
+}
+
+// NewMockVersionService creates a new mock instance.
+func NewMockVersionService(ctrl *gomock.Controller) *MockVersionService {
+	mock := &MockVersionService{ctrl: ctrl}
+	mock.recorder = &MockVersionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVersionService) EXPECT() *MockVersionServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockVersionService) Create(ctx context.Context, doc dto.Document, file *multipart.FileHeader) (dto.DocumentVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, doc, file)
+	ret0, _ := ret[0].(dto.DocumentVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockVersionServiceMockRecorder) Create(ctx, doc, file interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockVersionService)(nil).Create), ctx, doc, file)
+}
+
+// Get mocks base method.
+func (m *MockVersionService) Get(ctx context.Context, doc dto.Document, version uint) (dto.DocumentVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, doc, version)
+	ret0, _ := ret[0].(dto.DocumentVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockVersionServiceMockRecorder) Get(ctx, doc, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockVersionService)(nil).Get), ctx, doc, version)
+}
+
+// List mocks base method.
+func (m *MockVersionService) List(ctx context.Context, doc dto.Document) ([]dto.DocumentVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, doc)
+	ret0, _ := ret[0].([]dto.DocumentVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockVersionServiceMockRecorder) List(ctx, doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockVersionService)(nil).List), ctx, doc)
+}
+
+// Restore mocks base method.
+func (m *MockVersionService) Restore(ctx context.Context, doc dto.Document, version uint) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, doc, version)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockVersionServiceMockRecorder) Restore(ctx, doc, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockVersionService)(nil).Restore), ctx, doc, version)
+}
+
+// MockUploadService is a mock of UploadService interface.
+type MockUploadService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUploadServiceMockRecorder
+}
+
+// MockUploadServiceMockRecorder is the mock recorder for MockUploadService.
+type MockUploadServiceMockRecorder struct {
+	mock *MockUploadService
+}
+
+// NewMockUploadService creates a new mock instance.
+func NewMockUploadService(ctrl *gomock.Controller) *MockUploadService {
+	mock := &MockUploadService{ctrl: ctrl}
+	mock.recorder = &MockUploadServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUploadService) EXPECT() *MockUploadServiceMockRecorder {
+	return m.recorder
+}
+
+// Abort mocks base method.
+func (m *MockUploadService) Abort(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Abort", ctx, session)
+	ret0, _ := ret[0].(dto.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Abort indicates an expected call of Abort.
+func (mr *MockUploadServiceMockRecorder) Abort(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Abort", reflect.TypeOf((*MockUploadService)(nil).Abort), ctx, session)
+}
+
+// Complete mocks base method.
+func (m *MockUploadService) Complete(ctx context.Context, session dto.UploadSession) (dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, session)
+	ret0, _ := ret[0].(dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockUploadServiceMockRecorder) Complete(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockUploadService)(nil).Complete), ctx, session)
+}
+
+// Expire mocks base method.
+func (m *MockUploadService) Expire(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Expire indicates an expected call of Expire.
+func (mr *MockUploadServiceMockRecorder) Expire(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockUploadService)(nil).Expire), ctx)
+}
+
+// Get mocks base method.
+func (m *MockUploadService) Get(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, session)
+	ret0, _ := ret[0].(dto.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockUploadServiceMockRecorder) Get(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUploadService)(nil).Get), ctx, session)
+}
+
+// Initiate mocks base method.
+func (m *MockUploadService) Initiate(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Initiate", ctx, session)
+	ret0, _ := ret[0].(dto.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Initiate indicates an expected call of Initiate.
+func (mr *MockUploadServiceMockRecorder) Initiate(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Initiate", reflect.TypeOf((*MockUploadService)(nil).Initiate), ctx, session)
+}
+
+// List mocks base method.
+func (m *MockUploadService) List(ctx context.Context, session dto.UploadSession) ([]dto.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, session)
+	ret0, _ := ret[0].([]dto.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockUploadServiceMockRecorder) List(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUploadService)(nil).List), ctx, session)
+}
+
+// PutPart mocks base method.
+func (m *MockUploadService) PutPart(ctx context.Context, session dto.UploadSession, part dto.UploadPart) (dto.UploadPart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutPart", ctx, session, part)
+	ret0, _ := ret[0].(dto.UploadPart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutPart indicates an expected call of PutPart.
+func (mr *MockUploadServiceMockRecorder) PutPart(ctx, session, part interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutPart", reflect.TypeOf((*MockUploadService)(nil).PutPart), ctx, session, part)
+}
+
+// MockTrashService is a mock of TrashService interface.
+type MockTrashService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrashServiceMockRecorder
+}
+
+// MockTrashServiceMockRecorder is the mock recorder for MockTrashService.
+type MockTrashServiceMockRecorder struct {
+	mock *MockTrashService
+}
+
+// NewMockTrashService creates a new mock instance.
+func NewMockTrashService(ctrl *gomock.Controller) *MockTrashService {
+	mock := &MockTrashService{ctrl: ctrl}
+	mock.recorder = &MockTrashServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrashService) EXPECT() *MockTrashServiceMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockTrashService) List(ctx context.Context) ([]dto.TrashItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]dto.TrashItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTrashServiceMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTrashService)(nil).List), ctx)
+}
+
+// Purge mocks base method.
+func (m *MockTrashService) Purge(ctx context.Context) (dto.Purge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx)
+	ret0, _ := ret[0].(dto.Purge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockTrashServiceMockRecorder) Purge(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockTrashService)(nil).Purge), ctx)
+}
+
+// Restore mocks base method.
+func (m *MockTrashService) Restore(ctx context.Context, item dto.TrashItem) (dto.TrashItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, item)
+	ret0, _ := ret[0].(dto.TrashItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockTrashServiceMockRecorder) Restore(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockTrashService)(nil).Restore), ctx, item)
+}
+
+// MockDeletionService is a mock of DeletionService interface.
+type MockDeletionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeletionServiceMockRecorder
+}
+
+// MockDeletionServiceMockRecorder is the mock recorder for MockDeletionService.
+type MockDeletionServiceMockRecorder struct {
+	mock *MockDeletionService
+}
+
+// NewMockDeletionService creates a new mock instance.
+func NewMockDeletionService(ctrl *gomock.Controller) *MockDeletionService {
+	mock := &MockDeletionService{ctrl: ctrl}
+	mock.recorder = &MockDeletionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeletionService) EXPECT() *MockDeletionServiceMockRecorder {
+	return m.recorder
+}
+
+// Process mocks base method.
+func (m *MockDeletionService) Process(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Process", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Process indicates an expected call of Process.
+func (mr *MockDeletionServiceMockRecorder) Process(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Process", reflect.TypeOf((*MockDeletionService)(nil).Process), ctx)
+}
+
+// MockOutboxService is a mock of OutboxService interface.
+type MockOutboxService struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxServiceMockRecorder
+}
+
+// MockOutboxServiceMockRecorder is the mock recorder for MockOutboxService.
+type MockOutboxServiceMockRecorder struct {
+	mock *MockOutboxService
+}
+
+// NewMockOutboxService creates a new mock instance.
+func NewMockOutboxService(ctrl *gomock.Controller) *MockOutboxService {
+	mock := &MockOutboxService{ctrl: ctrl}
+	mock.recorder = &MockOutboxServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxService) EXPECT() *MockOutboxServiceMockRecorder {
+	return m.recorder
+}
+
+// Process mocks base method.
+func (m *MockOutboxService) Process(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Process", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Process indicates an expected call of Process.
+func (mr *MockOutboxServiceMockRecorder) Process(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Process", reflect.TypeOf((*MockOutboxService)(nil).Process), ctx)
+}
+
+// MockBlobService is a mock of BlobService interface.
+type MockBlobService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlobServiceMockRecorder
+}
+
+// MockBlobServiceMockRecorder is the mock recorder for MockBlobService.
+type MockBlobServiceMockRecorder struct {
+	mock *MockBlobService
+}
+
+// NewMockBlobService creates a new mock instance.
+func NewMockBlobService(ctrl *gomock.Controller) *MockBlobService {
+	mock := &MockBlobService{ctrl: ctrl}
+	mock.recorder = &MockBlobServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlobService) EXPECT() *MockBlobServiceMockRecorder {
+	return m.recorder
+}
+
+// Verify mocks base method.
+func (m *MockBlobService) Verify(ctx context.Context) (dto.Verification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx)
+	ret0, _ := ret[0].(dto.Verification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockBlobServiceMockRecorder) Verify(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockBlobService)(nil).Verify), ctx)
+}
+
+// MockReconcileService is a mock of ReconcileService interface.
+type MockReconcileService struct {
+	ctrl     *gomock.Controller
+	recorder *MockReconcileServiceMockRecorder
+}
+
+// MockReconcileServiceMockRecorder is the mock recorder for MockReconcileService.
+type MockReconcileServiceMockRecorder struct {
+	mock *MockReconcileService
+}
+
+// NewMockReconcileService creates a new mock instance.
+func NewMockReconcileService(ctrl *gomock.Controller) *MockReconcileService {
+	mock := &MockReconcileService{ctrl: ctrl}
+	mock.recorder = &MockReconcileServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReconcileService) EXPECT() *MockReconcileServiceMockRecorder {
+	return m.recorder
+}
+
+// Reconcile mocks base method.
+func (m *MockReconcileService) Reconcile(ctx context.Context, repair bool) (dto.Reconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconcile", ctx, repair)
+	ret0, _ := ret[0].(dto.Reconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reconcile indicates an expected call of Reconcile.
+func (mr *MockReconcileServiceMockRecorder) Reconcile(ctx, repair interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockReconcileService)(nil).Reconcile), ctx, repair)
+}
+
+// MockExportService is a mock of ExportService interface.
+type MockExportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportServiceMockRecorder
+}
+
+// MockExportServiceMockRecorder is the mock recorder for MockExportService.
+type MockExportServiceMockRecorder struct {
+	mock *MockExportService
+}
+
+// NewMockExportService creates a new mock instance.
+func NewMockExportService(ctrl *gomock.Controller) *MockExportService {
+	mock := &MockExportService{ctrl: ctrl}
+	mock.recorder = &MockExportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportService) EXPECT() *MockExportServiceMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockExportService) Export(ctx context.Context, userID string) (dto.Archive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, userID)
+	ret0, _ := ret[0].(dto.Archive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockExportServiceMockRecorder) Export(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockExportService)(nil).Export), ctx, userID)
+}
+
+// WriteArchive mocks base method.
+func (m *MockExportService) WriteArchive(ctx context.Context, export dto.Archive, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteArchive", ctx, export, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteArchive indicates an expected call of WriteArchive.
+func (mr *MockExportServiceMockRecorder) WriteArchive(ctx, export, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteArchive", reflect.TypeOf((*MockExportService)(nil).WriteArchive), ctx, export, w)
+}
+
+// MockPreviewService is a mock of PreviewService interface.
+type MockPreviewService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPreviewServiceMockRecorder
+}
+
+// MockPreviewServiceMockRecorder is the mock recorder for MockPreviewService.
+type MockPreviewServiceMockRecorder struct {
+	mock *MockPreviewService
+}
+
+// NewMockPreviewService creates a new mock instance.
+func NewMockPreviewService(ctrl *gomock.Controller) *MockPreviewService {
+	mock := &MockPreviewService{ctrl: ctrl}
+	mock.recorder = &MockPreviewServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPreviewService) EXPECT() *MockPreviewServiceMockRecorder {
+	return m.recorder
+}
+
+// Process mocks base method.
+func (m *MockPreviewService) Process(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Process", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Process indicates an expected call of Process.
+func (mr *MockPreviewServiceMockRecorder) Process(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Process", reflect.TypeOf((*MockPreviewService)(nil).Process), ctx)
+}
+
+// MockTextService is a mock of TextService interface.
+type MockTextService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTextServiceMockRecorder
+}
+
+// MockTextServiceMockRecorder is the mock recorder for MockTextService.
+type MockTextServiceMockRecorder struct {
+	mock *MockTextService
+}
+
+// NewMockTextService creates a new mock instance.
+func NewMockTextService(ctrl *gomock.Controller) *MockTextService {
+	mock := &MockTextService{ctrl: ctrl}
+	mock.recorder = &MockTextServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTextService) EXPECT() *MockTextServiceMockRecorder {
+	return m.recorder
+}
+
+// Process mocks base method.
+func (m *MockTextService) Process(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Process", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Process indicates an expected call of Process.
+func (mr *MockTextServiceMockRecorder) Process(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Process", reflect.TypeOf((*MockTextService)(nil).Process), ctx)
+}
+
+// MockQuotaService is a mock of QuotaService interface.
+type MockQuotaService struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuotaServiceMockRecorder
+}
+
+// MockQuotaServiceMockRecorder is the mock recorder for MockQuotaService.
+type MockQuotaServiceMockRecorder struct {
+	mock *MockQuotaService
+}
+
+// NewMockQuotaService creates a new mock instance.
+func NewMockQuotaService(ctrl *gomock.Controller) *MockQuotaService {
+	mock := &MockQuotaService{ctrl: ctrl}
+	mock.recorder = &MockQuotaServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuotaService) EXPECT() *MockQuotaServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockQuotaService) Delete(ctx context.Context, quota dto.Quota) (dto.Quota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, quota)
+	ret0, _ := ret[0].(dto.Quota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockQuotaServiceMockRecorder) Delete(ctx, quota interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockQuotaService)(nil).Delete), ctx, quota)
+}
+
+// List mocks base method.
+func (m *MockQuotaService) List(ctx context.Context) ([]dto.Quota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]dto.Quota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockQuotaServiceMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockQuotaService)(nil).List), ctx)
+}
+
+// Recalculate mocks base method.
+func (m *MockQuotaService) Recalculate(ctx context.Context) ([]dto.UsageCorrection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recalculate", ctx)
+	ret0, _ := ret[0].([]dto.UsageCorrection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recalculate indicates an expected call of Recalculate.
+func (mr *MockQuotaServiceMockRecorder) Recalculate(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recalculate", reflect.TypeOf((*MockQuotaService)(nil).Recalculate), ctx)
+}
+
+// Save mocks base method.
+func (m *MockQuotaService) Save(ctx context.Context, quota dto.Quota) (dto.Quota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, quota)
+	ret0, _ := ret[0].(dto.Quota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockQuotaServiceMockRecorder) Save(ctx, quota interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockQuotaService)(nil).Save), ctx, quota)
+}
+
+// Usage mocks base method.
+func (m *MockQuotaService) Usage(ctx context.Context) (dto.Usage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Usage", ctx)
+	ret0, _ := ret[0].(dto.Usage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Usage indicates an expected call of Usage.
+func (mr *MockQuotaServiceMockRecorder) Usage(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Usage", reflect.TypeOf((*MockQuotaService)(nil).Usage), ctx)
+}
+
+// UserUsage mocks base method.
+func (m *MockQuotaService) UserUsage(ctx context.Context, userID string) (dto.Usage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserUsage", ctx, userID)
+	ret0, _ := ret[0].(dto.Usage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserUsage indicates an expected call of UserUsage.
+func (mr *MockQuotaServiceMockRecorder) UserUsage(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserUsage", reflect.TypeOf((*MockQuotaService)(nil).UserUsage), ctx, userID)
+}
+
+// MockPolicyService is a mock of PolicyService interface.
+type MockPolicyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPolicyServiceMockRecorder
+}
+
+// MockPolicyServiceMockRecorder is the mock recorder for MockPolicyService.
+type MockPolicyServiceMockRecorder struct {
+	mock *MockPolicyService
+}
+
+// NewMockPolicyService creates a new mock instance.
+func NewMockPolicyService(ctrl *gomock.Controller) *MockPolicyService {
+	mock := &MockPolicyService{ctrl: ctrl}
+	mock.recorder = &MockPolicyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPolicyService) EXPECT() *MockPolicyServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockPolicyService) Delete(ctx context.Context, treeID uint) (dto.TreePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, treeID)
+	ret0, _ := ret[0].(dto.TreePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPolicyServiceMockRecorder) Delete(ctx, treeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPolicyService)(nil).Delete), ctx, treeID)
+}
+
+// Get mocks base method.
+func (m *MockPolicyService) Get(ctx context.Context, treeID uint) (dto.TreePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, treeID)
+	ret0, _ := ret[0].(dto.TreePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPolicyServiceMockRecorder) Get(ctx, treeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPolicyService)(nil).Get), ctx, treeID)
+}
+
+// Save mocks base method.
+func (m *MockPolicyService) Save(ctx context.Context, policy dto.TreePolicy) (dto.TreePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, policy)
+	ret0, _ := ret[0].(dto.TreePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockPolicyServiceMockRecorder) Save(ctx, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockPolicyService)(nil).Save), ctx, policy)
+}
+
+// MockShareService is a mock of ShareService interface.
+type MockShareService struct {
+	ctrl     *gomock.Controller
+	recorder *MockShareServiceMockRecorder
+}
+
+// MockShareServiceMockRecorder is the mock recorder for MockShareService.
+type MockShareServiceMockRecorder struct {
+	mock *MockShareService
+}
+
+// NewMockShareService creates a new mock instance.
+func NewMockShareService(ctrl *gomock.Controller) *MockShareService {
+	mock := &MockShareService{ctrl: ctrl}
+	mock.recorder = &MockShareServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShareService) EXPECT() *MockShareServiceMockRecorder {
+	return m.recorder
+}
+
+// Accesses mocks base method.
+func (m *MockShareService) Accesses(ctx context.Context, shareID uint) ([]dto.ShareAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Accesses", ctx, shareID)
+	ret0, _ := ret[0].([]dto.ShareAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Accesses indicates an expected call of Accesses.
+func (mr *MockShareServiceMockRecorder) Accesses(ctx, shareID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accesses", reflect.TypeOf((*MockShareService)(nil).Accesses), ctx, shareID)
+}
+
+// Archive mocks base method.
+func (m *MockShareService) Archive(ctx context.Context, request dto.ShareRequest) (dto.Archive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Archive", ctx, request)
+	ret0, _ := ret[0].(dto.Archive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Archive indicates an expected call of Archive.
+func (mr *MockShareServiceMockRecorder) Archive(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockShareService)(nil).Archive), ctx, request)
+}
+
+// Create mocks base method.
+func (m *MockShareService) Create(ctx context.Context, doc dto.Document, options dto.ShareOptions) (dto.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, doc, options)
+	ret0, _ := ret[0].(dto.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockShareServiceMockRecorder) Create(ctx, doc, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockShareService)(nil).Create), ctx, doc, options)
+}
+
+// CreateTree mocks base method.
+func (m *MockShareService) CreateTree(ctx context.Context, tree dto.Tree, options dto.ShareOptions) (dto.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTree", ctx, tree, options)
+	ret0, _ := ret[0].(dto.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTree indicates an expected call of CreateTree.
+func (mr *MockShareServiceMockRecorder) CreateTree(ctx, tree, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTree", reflect.TypeOf((*MockShareService)(nil).CreateTree), ctx, tree, options)
+}
+
+// List mocks base method.
+func (m *MockShareService) List(ctx context.Context) ([]dto.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]dto.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockShareServiceMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockShareService)(nil).List), ctx)
+}
+
+// Open mocks base method.
+func (m *MockShareService) Open(ctx context.Context, request dto.ShareRequest) (dto.Shared, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", ctx, request)
+	ret0, _ := ret[0].(dto.Shared)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockShareServiceMockRecorder) Open(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockShareService)(nil).Open), ctx, request)
+}
+
+// Revoke mocks base method.
+func (m *MockShareService) Revoke(ctx context.Context, shareID uint) (dto.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, shareID)
+	ret0, _ := ret[0].(dto.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockShareServiceMockRecorder) Revoke(ctx, shareID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockShareService)(nil).Revoke), ctx, shareID)
+}
+
+// WriteArchive mocks base method.
+func (m *MockShareService) WriteArchive(ctx context.Context, folder dto.Archive, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteArchive", ctx, folder, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteArchive indicates an expected call of WriteArchive.
+func (mr *MockShareServiceMockRecorder) WriteArchive(ctx, folder, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteArchive", reflect.TypeOf((*MockShareService)(nil).WriteArchive), ctx, folder, w)
+}
+
+// MockGroupService is a mock of GroupService interface.
+type MockGroupService struct {
+	ctrl     *gomock.Controller
+	recorder *MockGroupServiceMockRecorder
+}
+
+// MockGroupServiceMockRecorder is the mock recorder for MockGroupService.
+type MockGroupServiceMockRecorder struct {
+	mock *MockGroupService
+}
+
+// NewMockGroupService creates a new mock instance.
+func NewMockGroupService(ctrl *gomock.Controller) *MockGroupService {
+	mock := &MockGroupService{ctrl: ctrl}
+	mock.recorder = &MockGroupServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGroupService) EXPECT() *MockGroupServiceMockRecorder {
+	return m.recorder
+}
+
+// AddMember mocks base method.
+func (m *MockGroupService) AddMember(ctx context.Context, member dto.GroupMember) (dto.GroupMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMember", ctx, member)
+	ret0, _ := ret[0].(dto.GroupMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockGroupServiceMockRecorder) AddMember(ctx, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockGroupService)(nil).AddMember), ctx, member)
+}
+
+// AttachDocument mocks base method.
+func (m *MockGroupService) AttachDocument(ctx context.Context, group dto.Group, documentID uint) (dto.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachDocument", ctx, group, documentID)
+	ret0, _ := ret[0].(dto.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachDocument indicates an expected call of AttachDocument.
+func (mr *MockGroupServiceMockRecorder) AttachDocument(ctx, group, documentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachDocument", reflect.TypeOf((*MockGroupService)(nil).AttachDocument), ctx, group, documentID)
+}
+
+// AttachTree mocks base method.
+func (m *MockGroupService) AttachTree(ctx context.Context, group dto.Group, treeID uint) (dto.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachTree", ctx, group, treeID)
+	ret0, _ := ret[0].(dto.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachTree indicates an expected call of AttachTree.
+func (mr *MockGroupServiceMockRecorder) AttachTree(ctx, group, treeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachTree", reflect.TypeOf((*MockGroupService)(nil).AttachTree), ctx, group, treeID)
+}
+
+// Create mocks base method.
+func (m *MockGroupService) Create(ctx context.Context, group dto.Group) (dto.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, group)
+	ret0, _ := ret[0].(dto.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockGroupServiceMockRecorder) Create(ctx, group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockGroupService)(nil).Create), ctx, group)
+}
+
+// Delete mocks base method.
+func (m *MockGroupService) Delete(ctx context.Context, group dto.Group) (dto.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, group)
+	ret0, _ := ret[0].(dto.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockGroupServiceMockRecorder) Delete(ctx, group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockGroupService)(nil).Delete), ctx, group)
+}
+
+// DetachDocument mocks base method.
+func (m *MockGroupService) DetachDocument(ctx context.Context, group dto.Group, documentID uint) (dto.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachDocument", ctx, group, documentID)
+	ret0, _ := ret[0].(dto.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetachDocument indicates an expected call of DetachDocument.
+func (mr *MockGroupServiceMockRecorder) DetachDocument(ctx, group, documentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachDocument", reflect.TypeOf((*MockGroupService)(nil).DetachDocument), ctx, group, documentID)
+}
+
+// DetachTree mocks base method.
+func (m *MockGroupService) DetachTree(ctx context.Context, group dto.Group, treeID uint) (dto.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachTree", ctx, group, treeID)
+	ret0, _ := ret[0].(dto.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetachTree indicates an expected call of DetachTree.
+func (mr *MockGroupServiceMockRecorder) DetachTree(ctx, group, treeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachTree", reflect.TypeOf((*MockGroupService)(nil).DetachTree), ctx, group, treeID)
+}
+
+// Get mocks base method.
+func (m *MockGroupService) Get(ctx context.Context, group dto.Group) (dto.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, group)
+	ret0, _ := ret[0].(dto.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockGroupServiceMockRecorder) Get(ctx, group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockGroupService)(nil).Get), ctx, group)
+}
+
+// List mocks base method.
+func (m *MockGroupService) List(ctx context.Context) ([]dto.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]dto.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockGroupServiceMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockGroupService)(nil).List), ctx)
+}
+
+// ListDocuments mocks base method.
+func (m *MockGroupService) ListDocuments(ctx context.Context, group dto.Group) ([]dto.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDocuments", ctx, group)
+	ret0, _ := ret[0].([]dto.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDocuments indicates an expected call of ListDocuments.
+func (mr *MockGroupServiceMockRecorder) ListDocuments(ctx, group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDocuments", reflect.TypeOf((*MockGroupService)(nil).ListDocuments), ctx, group)
+}
+
+// RemoveMember mocks base method.
+func (m *MockGroupService) RemoveMember(ctx context.Context, member dto.GroupMember) (dto.GroupMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, member)
+	ret0, _ := ret[0].(dto.GroupMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockGroupServiceMockRecorder) RemoveMember(ctx, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockGroupService)(nil).RemoveMember), ctx, member)
+}
+
+// Update mocks base method.
+func (m *MockGroupService) Update(ctx context.Context, group dto.Group) (dto.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, group)
+	ret0, _ := ret[0].(dto.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockGroupServiceMockRecorder) Update(ctx, group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockGroupService)(nil).Update), ctx, group)
+}
+
+// MockAccessService is a mock of AccessService interface.
+type MockAccessService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessServiceMockRecorder
+}
+
+// MockAccessServiceMockRecorder is the mock recorder for MockAccessService.
+type MockAccessServiceMockRecorder struct {
+	mock *MockAccessService
+}
+
+// NewMockAccessService creates a new mock instance.
+func NewMockAccessService(ctrl *gomock.Controller) *MockAccessService {
+	mock := &MockAccessService{ctrl: ctrl}
+	mock.recorder = &MockAccessServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessService) EXPECT() *MockAccessServiceMockRecorder {
+	return m.recorder
+}
+
+// Grant mocks base method.
+func (m *MockAccessService) Grant(ctx context.Context, access dto.TreeAccess) (dto.TreeAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Grant", ctx, access)
+	ret0, _ := ret[0].(dto.TreeAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Grant indicates an expected call of Grant.
+func (mr *MockAccessServiceMockRecorder) Grant(ctx, access interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Grant", reflect.TypeOf((*MockAccessService)(nil).Grant), ctx, access)
+}
+
+// List mocks base method.
+func (m *MockAccessService) List(ctx context.Context, treeID uint) ([]dto.TreeAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, treeID)
+	ret0, _ := ret[0].([]dto.TreeAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAccessServiceMockRecorder) List(ctx, treeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAccessService)(nil).List), ctx, treeID)
+}
+
+// Revoke mocks base method.
+func (m *MockAccessService) Revoke(ctx context.Context, access dto.TreeAccess) (dto.TreeAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, access)
+	ret0, _ := ret[0].(dto.TreeAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockAccessServiceMockRecorder) Revoke(ctx, access interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockAccessService)(nil).Revoke), ctx, access)
+}
+
+// Shared mocks base method.
+func (m *MockAccessService) Shared(ctx context.Context) ([]dto.TreeAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Shared", ctx)
+	ret0, _ := ret[0].([]dto.TreeAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Shared indicates an expected call of Shared.
+func (mr *MockAccessServiceMockRecorder) Shared(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shared", reflect.TypeOf((*MockAccessService)(nil).Shared), ctx)
+}
+
+// MockPermissionService is a mock of PermissionService interface.
+type MockPermissionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPermissionServiceMockRecorder
+}
+
+// MockPermissionServiceMockRecorder is the mock recorder for MockPermissionService.
+type MockPermissionServiceMockRecorder struct {
+	mock *MockPermissionService
+}
+
+// NewMockPermissionService creates a new mock instance.
+func NewMockPermissionService(ctrl *gomock.Controller) *MockPermissionService {
+	mock := &MockPermissionService{ctrl: ctrl}
+	mock.recorder = &MockPermissionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPermissionService) EXPECT() *MockPermissionServiceMockRecorder {
+	return m.recorder
+}
+
+// Allowed mocks base method.
+func (m *MockPermissionService) Allowed(ctx context.Context, action string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Allowed", ctx, action)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Allowed indicates an expected call of Allowed.
+func (mr *MockPermissionServiceMockRecorder) Allowed(ctx, action interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Allowed", reflect.TypeOf((*MockPermissionService)(nil).Allowed), ctx, action)
+}
+
+// Roles mocks base method.
+func (m *MockPermissionService) Roles(action string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Roles", action)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Roles indicates an expected call of Roles.
+func (mr *MockPermissionServiceMockRecorder) Roles(action interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Roles", reflect.TypeOf((*MockPermissionService)(nil).Roles), action)
+}
+
 // MockInformationService is a mock of InformationService interface.
 type MockInformationService struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,166 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/remote"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/utils"
+	"gorm.io/gorm"
+	"time"
+)
+
+const batchSize = 100
+
+// claim keeps the events of a batch from other replicas while it is settled
+const claim = 10 * time.Minute
+
+type Service struct {
+	repos     repository.OutboxRepository
+	documents repository.DocumentRepository
+	blobs     repository.BlobRepository
+	remotes   remote.DocumentsRemote
+	cfg       *modules.Outbox
+}
+
+func NewService(repos repository.OutboxRepository, documents repository.DocumentRepository, blobs repository.BlobRepository, remotes remote.DocumentsRemote, cfg *modules.Outbox) *Service {
+	return &Service{
+		repos:     repos,
+		documents: documents,
+		blobs:     blobs,
+		remotes:   remotes,
+		cfg:       cfg,
+	}
+}
+
+// Process settles the events left behind by requests that didn't finish within the timeout
+func (s *Service) Process(ctx context.Context) (int, error) {
+	due, err := s.repos.Claim(ctx, time.Now().Add(-s.cfg.Timeout), time.Now().Add(claim), batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var settled int
+	for _, event := range due {
+		if err = s.settle(ctx, event); err != nil {
+			logrus.Errorf("[outbox error]: %+v - %+v", event, err)
+
+			event.Attempts++
+			event.LastError = err.Error()
+			event.RetryAt = time.Now().Add(utils.Backoff(event.Attempts))
+			if err = s.repos.Retry(ctx, event); err != nil {
+				return settled, err
+			}
+			continue
+		}
+
+		if err = s.repos.Done(ctx, event); err != nil {
+			return settled, err
+		}
+		settled++
+	}
+
+	return settled, nil
+}
+
+func (s *Service) settle(ctx context.Context, event dto.OutboxEvent) error {
+	switch event.Kind {
+	case modules.OutboxStore:
+		return s.store(ctx, event)
+	case modules.OutboxCopy:
+		return s.copy(ctx, event)
+	default:
+		return fmt.Errorf("unknown outbox event: %s", event.Kind)
+	}
+}
+
+// store settles a document whose content was on its way to spaces. The content is only in
+// the request, so a document whose object is there is marked ready and one without is purged.
+// A document sharing a blob waits on the object of the blob, whichever request uploaded it.
+func (s *Service) store(ctx context.Context, event dto.OutboxEvent) error {
+	doc, err := s.uploading(ctx, event)
+	if err != nil || doc.ID == 0 {
+		return err
+	}
+
+	// infected content never reaches spaces, the document is kept as a record of the upload
+	if doc.ScanStatus == modules.ScanInfected {
+		return s.ready(ctx, doc)
+	}
+
+	stored, err := s.remotes.Exists(ctx, s.remotes.Key(doc))
+	if err != nil {
+		return err
+	}
+
+	if !stored {
+		logrus.Warnf("[outbox]: document %d never reached spaces, purging it", doc.ID)
+		_, err = s.documents.Purge(ctx, []uint{doc.ID})
+		return err
+	}
+
+	// the upload stopped before the blob was marked, later references reuse it now
+	if doc.Checksum != "" {
+		if err = s.blobs.Stored(ctx, doc.Checksum); err != nil {
+			return err
+		}
+	}
+
+	return s.ready(ctx, doc)
+}
+
+// copy copies the content of a document from the object of its source again, a document
+// whose source is gone from spaces has nothing to copy and is purged
+func (s *Service) copy(ctx context.Context, event dto.OutboxEvent) error {
+	doc, err := s.uploading(ctx, event)
+	if err != nil || doc.ID == 0 {
+		return err
+	}
+
+	source, err := s.remotes.Exists(ctx, event.Source)
+	if err != nil {
+		return err
+	}
+
+	if !source {
+		logrus.Warnf("[outbox]: source of document %d is gone from spaces, purging it", doc.ID)
+		_, err = s.documents.Purge(ctx, []uint{doc.ID})
+		return err
+	}
+
+	if _, err = s.remotes.Copy(ctx, event.Source, doc); err != nil {
+		return err
+	}
+
+	return s.ready(ctx, doc)
+}
+
+// uploading returns the document of an event while it waits for its content, an empty one
+// once it is settled or gone, so an event may be settled more than once
+func (s *Service) uploading(ctx context.Context, event dto.OutboxEvent) (dto.Document, error) {
+	doc, err := s.documents.Find(ctx, event.DocumentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.Document{}, nil
+	}
+	if err != nil {
+		return dto.Document{}, err
+	}
+
+	if doc.State != modules.DocumentUploading {
+		return dto.Document{}, nil
+	}
+
+	return doc, nil
+}
+
+// ready marks a document ready, one settled meanwhile is left alone
+func (s *Service) ready(ctx context.Context, doc dto.Document) error {
+	if _, err := s.documents.Ready(ctx, doc); !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
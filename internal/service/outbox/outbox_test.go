@@ -0,0 +1,230 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	remotemocks "gitlab.com/a5805/ondeu/ondeu-back/internal/remote/mocks"
+	repomocks "gitlab.com/a5805/ondeu/ondeu-back/internal/repository/mocks"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestService_Process(t *testing.T) {
+	type mocks struct {
+		repos     *repomocks.MockOutboxRepository
+		documents *repomocks.MockDocumentRepository
+		blobs     *repomocks.MockBlobRepository
+		remotes   *remotemocks.MockDocumentsRemote
+	}
+	type mockBehavior func(m mocks, event dto.OutboxEvent)
+
+	uploading := dto.Document{ID: 7, State: modules.DocumentUploading, ScanStatus: modules.ScanClean, Checksum: "abcdef"}
+	blobKey := "blobs/ab/cd/abcdef"
+	source := "2026-10-18/11111111-2222-3333-4444-555555555555.pdf"
+
+	tests := []struct {
+		name          string
+		event         dto.OutboxEvent
+		mockBehavior  mockBehavior
+		expectedCount int
+	}{
+		{
+			name:  "Success. Stored content is marked ready",
+			event: dto.OutboxEvent{ID: 1, Kind: modules.OutboxStore, DocumentID: uploading.ID},
+			mockBehavior: func(m mocks, event dto.OutboxEvent) {
+				gomock.InOrder(
+					m.documents.EXPECT().Find(gomock.Any(), uploading.ID).Return(uploading, nil),
+					m.remotes.EXPECT().Key(uploading).Return(blobKey),
+					m.remotes.EXPECT().Exists(gomock.Any(), blobKey).Return(true, nil),
+					m.blobs.EXPECT().Stored(gomock.Any(), uploading.Checksum).Return(nil),
+					m.documents.EXPECT().Ready(gomock.Any(), uploading).Return(dto.Document{}, nil),
+					m.repos.EXPECT().Done(gomock.Any(), event).Return(nil),
+				)
+			},
+			expectedCount: 1,
+		},
+		{
+			name:  "Success. Missing content is purged",
+			event: dto.OutboxEvent{ID: 1, Kind: modules.OutboxStore, DocumentID: uploading.ID},
+			mockBehavior: func(m mocks, event dto.OutboxEvent) {
+				gomock.InOrder(
+					m.documents.EXPECT().Find(gomock.Any(), uploading.ID).Return(uploading, nil),
+					m.remotes.EXPECT().Key(uploading).Return(blobKey),
+					m.remotes.EXPECT().Exists(gomock.Any(), blobKey).Return(false, nil),
+					m.documents.EXPECT().Purge(gomock.Any(), []uint{uploading.ID}).Return(dto.Purge{Documents: 1}, nil),
+					m.repos.EXPECT().Done(gomock.Any(), event).Return(nil),
+				)
+			},
+			expectedCount: 1,
+		},
+		{
+			name:  "Success. Already settled document is left alone",
+			event: dto.OutboxEvent{ID: 1, Kind: modules.OutboxStore, DocumentID: uploading.ID},
+			mockBehavior: func(m mocks, event dto.OutboxEvent) {
+				settled := uploading
+				settled.State = modules.DocumentReady
+
+				gomock.InOrder(
+					m.documents.EXPECT().Find(gomock.Any(), uploading.ID).Return(settled, nil),
+					m.repos.EXPECT().Done(gomock.Any(), event).Return(nil),
+				)
+			},
+			expectedCount: 1,
+		},
+		{
+			name:  "Success. Removed document is left alone",
+			event: dto.OutboxEvent{ID: 1, Kind: modules.OutboxStore, DocumentID: uploading.ID},
+			mockBehavior: func(m mocks, event dto.OutboxEvent) {
+				gomock.InOrder(
+					m.documents.EXPECT().Find(gomock.Any(), uploading.ID).Return(dto.Document{}, gorm.ErrRecordNotFound),
+					m.repos.EXPECT().Done(gomock.Any(), event).Return(nil),
+				)
+			},
+			expectedCount: 1,
+		},
+		{
+			name:  "Success. Document settled by its request meanwhile",
+			event: dto.OutboxEvent{ID: 1, Kind: modules.OutboxStore, DocumentID: uploading.ID},
+			mockBehavior: func(m mocks, event dto.OutboxEvent) {
+				gomock.InOrder(
+					m.documents.EXPECT().Find(gomock.Any(), uploading.ID).Return(uploading, nil),
+					m.remotes.EXPECT().Key(uploading).Return(blobKey),
+					m.remotes.EXPECT().Exists(gomock.Any(), blobKey).Return(true, nil),
+					m.blobs.EXPECT().Stored(gomock.Any(), uploading.Checksum).Return(nil),
+					m.documents.EXPECT().Ready(gomock.Any(), uploading).Return(uploading, gorm.ErrRecordNotFound),
+					m.repos.EXPECT().Done(gomock.Any(), event).Return(nil),
+				)
+			},
+			expectedCount: 1,
+		},
+		{
+			name:  "Success. Infected content is kept as a record without being stored",
+			event: dto.OutboxEvent{ID: 1, Kind: modules.OutboxStore, DocumentID: uploading.ID},
+			mockBehavior: func(m mocks, event dto.OutboxEvent) {
+				infected := uploading
+				infected.ScanStatus = modules.ScanInfected
+
+				gomock.InOrder(
+					m.documents.EXPECT().Find(gomock.Any(), uploading.ID).Return(infected, nil),
+					m.documents.EXPECT().Ready(gomock.Any(), infected).Return(dto.Document{}, nil),
+					m.repos.EXPECT().Done(gomock.Any(), event).Return(nil),
+				)
+			},
+			expectedCount: 1,
+		},
+		{
+			name:  "Success. Copy is made again",
+			event: dto.OutboxEvent{ID: 1, Kind: modules.OutboxCopy, DocumentID: uploading.ID, Source: source},
+			mockBehavior: func(m mocks, event dto.OutboxEvent) {
+				gomock.InOrder(
+					m.documents.EXPECT().Find(gomock.Any(), uploading.ID).Return(uploading, nil),
+					m.remotes.EXPECT().Exists(gomock.Any(), source).Return(true, nil),
+					m.remotes.EXPECT().Copy(gomock.Any(), source, uploading).Return(uploading, nil),
+					m.documents.EXPECT().Ready(gomock.Any(), uploading).Return(dto.Document{}, nil),
+					m.repos.EXPECT().Done(gomock.Any(), event).Return(nil),
+				)
+			},
+			expectedCount: 1,
+		},
+		{
+			name:  "Success. Copy of a source gone from spaces is purged",
+			event: dto.OutboxEvent{ID: 1, Kind: modules.OutboxCopy, DocumentID: uploading.ID, Source: source},
+			mockBehavior: func(m mocks, event dto.OutboxEvent) {
+				gomock.InOrder(
+					m.documents.EXPECT().Find(gomock.Any(), uploading.ID).Return(uploading, nil),
+					m.remotes.EXPECT().Exists(gomock.Any(), source).Return(false, nil),
+					m.documents.EXPECT().Purge(gomock.Any(), []uint{uploading.ID}).Return(dto.Purge{Documents: 1}, nil),
+					m.repos.EXPECT().Done(gomock.Any(), event).Return(nil),
+				)
+			},
+			expectedCount: 1,
+		},
+		{
+			name:  "Failed. Spaces unreachable, the event is retried later",
+			event: dto.OutboxEvent{ID: 1, Kind: modules.OutboxStore, DocumentID: uploading.ID, Attempts: 2},
+			mockBehavior: func(m mocks, event dto.OutboxEvent) {
+				gomock.InOrder(
+					m.documents.EXPECT().Find(gomock.Any(), uploading.ID).Return(uploading, nil),
+					m.remotes.EXPECT().Key(uploading).Return(blobKey),
+					m.remotes.EXPECT().Exists(gomock.Any(), blobKey).Return(false, errors.New("connection refused")),
+					m.repos.EXPECT().Retry(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, retried dto.OutboxEvent) error {
+						assert.Equal(t, 3, retried.Attempts)
+						assert.Equal(t, "connection refused", retried.LastError)
+						assert.WithinDuration(t, time.Now().Add(4*time.Minute), retried.RetryAt, time.Second)
+						return nil
+					}),
+				)
+			},
+			expectedCount: 0,
+		},
+		{
+			name:  "Failed. Unknown kind is retried later",
+			event: dto.OutboxEvent{ID: 1, Kind: "move", DocumentID: uploading.ID},
+			mockBehavior: func(m mocks, event dto.OutboxEvent) {
+				m.repos.EXPECT().Retry(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, retried dto.OutboxEvent) error {
+					assert.Equal(t, 1, retried.Attempts)
+					assert.Equal(t, "unknown outbox event: move", retried.LastError)
+					return nil
+				})
+			},
+			expectedCount: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			m := mocks{
+				repos:     repomocks.NewMockOutboxRepository(c),
+				documents: repomocks.NewMockDocumentRepository(c),
+				blobs:     repomocks.NewMockBlobRepository(c),
+				remotes:   remotemocks.NewMockDocumentsRemote(c),
+			}
+			m.repos.EXPECT().
+				Claim(gomock.Any(), gomock.Any(), gomock.Any(), batchSize).
+				Return([]dto.OutboxEvent{tt.event}, nil)
+			tt.mockBehavior(m, tt.event)
+
+			s := NewService(m.repos, m.documents, m.blobs, m.remotes, &modules.Outbox{Timeout: time.Hour})
+
+			// Test
+			settled, err := s.Process(context.Background())
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCount, settled)
+		})
+	}
+}
+
+func TestService_Process_claim(t *testing.T) {
+	// Init Dependencies
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	repos := repomocks.NewMockOutboxRepository(c)
+	repos.EXPECT().
+		Claim(gomock.Any(), gomock.Any(), gomock.Any(), batchSize).
+		DoAndReturn(func(_ context.Context, before time.Time, until time.Time, _ int) ([]dto.OutboxEvent, error) {
+			// events are left to their request for the timeout and held from other replicas while settled
+			assert.WithinDuration(t, time.Now().Add(-time.Hour), before, time.Second)
+			assert.WithinDuration(t, time.Now().Add(claim), until, time.Second)
+			return nil, nil
+		})
+
+	s := NewService(repos, repomocks.NewMockDocumentRepository(c), repomocks.NewMockBlobRepository(c), remotemocks.NewMockDocumentsRemote(c), &modules.Outbox{Timeout: time.Hour})
+
+	// Test
+	settled, err := s.Process(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 0, settled)
+}
@@ -0,0 +1,239 @@
+package policies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/common"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/upload"
+	"gorm.io/gorm"
+	"strings"
+)
+
+type Service struct {
+	repos  repository.PolicyRepository
+	access common.Access
+	policy *modules.UploadPolicy
+}
+
+func NewService(repos repository.PolicyRepository, access common.Access, policy *modules.UploadPolicy) *Service {
+	return &Service{
+		repos:  repos,
+		access: access,
+		policy: policy,
+	}
+}
+
+func (s *Service) Get(ctx context.Context, treeID uint) (dto.TreePolicy, error) {
+	if _, err := s.access.Owner(ctx, treeID, modules.AccessViewer); err != nil {
+		return dto.TreePolicy{}, err
+	}
+
+	policy, err := s.repos.Get(ctx, treeID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// a tree without a policy of its own accepts whatever the roles do
+		return dto.TreePolicy{TreeID: treeID, Allow: []string{}, Deny: []string{}}, nil
+	}
+	return policy, err
+}
+
+func (s *Service) Save(ctx context.Context, policy dto.TreePolicy) (dto.TreePolicy, error) {
+	if _, err := s.access.Owner(ctx, policy.TreeID, modules.AccessOwner); err != nil {
+		return policy, err
+	}
+
+	if err := rule(policy).Validate(); err != nil {
+		return policy, &modules.ValidationError{Violations: []modules.Violation{
+			{Field: "policy", Rule: "entry", Message: err.Error()},
+		}}
+	}
+
+	return s.repos.Save(ctx, policy)
+}
+
+func (s *Service) Delete(ctx context.Context, treeID uint) (dto.TreePolicy, error) {
+	if _, err := s.access.Owner(ctx, treeID, modules.AccessOwner); err != nil {
+		return dto.TreePolicy{TreeID: treeID}, err
+	}
+
+	return s.repos.Delete(ctx, treeID)
+}
+
+// Check cleans the name of a file placed into a tree and sniffs its media type,
+// then reports every rule of the roles of the caller and of the tree it breaks
+func (s *Service) Check(ctx context.Context, treeID uint, file dto.UploadFile) (dto.UploadFile, error) {
+	var violations []modules.Violation
+
+	file.Name = upload.SanitizeName(file.Name)
+	if file.Name == "" {
+		violations = append(violations, modules.Violation{
+			Field: "name", Rule: "name", Message: "the file name is empty or invalid",
+		})
+	}
+	file.Extension = upload.Extension(file.Name)
+
+	declared := upload.BaseType(file.Type)
+	var sniffed string
+	if file.Head != nil {
+		sniffed = upload.DetectType(file.Head)
+	}
+
+	if sniffed != "" && !upload.Compatible(declared, sniffed) {
+		violations = append(violations, modules.Violation{
+			Field: "type", Rule: "content", Message: fmt.Sprintf("the content is %s, not %s", sniffed, declared),
+		})
+	}
+
+	// the client is trusted only with a type the content can't tell apart
+	file.Type = declared
+	if sniffed != "" && (declared == "" || declared == upload.OctetStream) {
+		file.Type = sniffed
+	}
+
+	rules, err := s.rules(ctx, treeID)
+	if err != nil {
+		return file, err
+	}
+
+	for _, rule := range rules {
+		violations = append(violations, broken(rule, file, sniffed)...)
+	}
+
+	if len(violations) > 0 {
+		return file, &modules.ValidationError{Violations: unique(violations)}
+	}
+
+	return file, nil
+}
+
+// rules returns the default rule with the size of the roles of the caller,
+// the rule of those roles and the rules of the tree and its ancestors
+func (s *Service) rules(ctx context.Context, treeID uint) ([]modules.UploadRule, error) {
+	roles, _ := ctx.Value(modules.Roles).([]string)
+
+	base := s.policy.Default
+	var roleSize int64
+	var role modules.UploadRule
+	anyType := false
+	for _, name := range roles {
+		granted, ok := s.policy.Roles[name]
+		if !ok {
+			continue
+		}
+		if granted.MaxSize > roleSize {
+			roleSize = granted.MaxSize
+		}
+		if len(granted.Allow) == 0 {
+			anyType = true
+		}
+		role.Allow = append(role.Allow, granted.Allow...)
+		role.Deny = append(role.Deny, granted.Deny...)
+	}
+
+	if roleSize > 0 {
+		base.MaxSize = roleSize
+	}
+	if anyType {
+		role.Allow = nil
+	}
+
+	rules := []modules.UploadRule{base, role}
+
+	if treeID == 0 {
+		return rules, nil
+	}
+
+	policies, err := s.repos.Inherited(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, policy := range policies {
+		rules = append(rules, rule(policy))
+	}
+
+	return rules, nil
+}
+
+// broken returns the violations of a rule by a file
+func broken(rule modules.UploadRule, file dto.UploadFile, sniffed string) []modules.Violation {
+	var violations []modules.Violation
+
+	if rule.MaxSize > 0 && file.Size > rule.MaxSize {
+		violations = append(violations, modules.Violation{
+			Field: "size", Rule: "maxSize", Message: fmt.Sprintf("the file is larger than %d bytes", rule.MaxSize),
+		})
+	}
+
+	if len(rule.Allow) > 0 && !matches(rule.Allow, file.Extension, file.Type) {
+		violations = append(violations, modules.Violation{
+			Field: "type", Rule: "allow", Message: fmt.Sprintf("%s files are not allowed", describe(file)),
+		})
+	}
+
+	if matches(rule.Deny, file.Extension, file.Type, sniffed) {
+		violations = append(violations, modules.Violation{
+			Field: "type", Rule: "deny", Message: fmt.Sprintf("%s files are denied", describe(file)),
+		})
+	}
+
+	return violations
+}
+
+// matches reports whether an entry lists the extension or one of the media types
+func matches(entries []string, extension string, types ...string) bool {
+	for _, entry := range entries {
+		entry = strings.ToLower(entry)
+
+		if strings.HasPrefix(entry, ".") {
+			if strings.EqualFold(entry, extension) {
+				return true
+			}
+			continue
+		}
+
+		for _, contentType := range types {
+			if contentType == "" {
+				continue
+			}
+			if entry == contentType ||
+				(strings.HasSuffix(entry, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(entry, "*"))) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// describe names the kind of a file in a violation
+func describe(file dto.UploadFile) string {
+	if file.Extension != "" {
+		return file.Extension
+	}
+	if file.Type != "" {
+		return file.Type
+	}
+	return "untyped"
+}
+
+// unique drops repeated violations, the rules of the roles and of the tree may break alike
+func unique(violations []modules.Violation) []modules.Violation {
+	seen := make(map[string]bool, len(violations))
+	kept := make([]modules.Violation, 0, len(violations))
+	for _, violation := range violations {
+		key := violation.Field + "/" + violation.Rule
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		kept = append(kept, violation)
+	}
+	return kept
+}
+
+func rule(policy dto.TreePolicy) modules.UploadRule {
+	return modules.UploadRule{MaxSize: policy.MaxSize, Allow: policy.Allow, Deny: policy.Deny}
+}
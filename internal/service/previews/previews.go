@@ -0,0 +1,91 @@
+package previews
+
+import (
+	"context"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/remote"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/preview"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/utils"
+	"time"
+)
+
+const batchSize = 10
+
+type Service struct {
+	repos   repository.PreviewRepository
+	remotes remote.DocumentsRemote
+}
+
+func NewService(repos repository.PreviewRepository, remotes remote.DocumentsRemote) *Service {
+	return &Service{
+		repos:   repos,
+		remotes: remotes,
+	}
+}
+
+func (s *Service) Process(ctx context.Context) (int, error) {
+	due, err := s.repos.ListDue(ctx, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var previewed int
+	for _, queued := range due {
+		err = s.preview(ctx, queued.Document)
+		// nothing will change for a format without previews, so it isn't retried
+		if errors.Is(err, preview.ErrUnsupported) {
+			queued.LastError = err.Error()
+			if err = s.repos.Skip(ctx, queued); err != nil {
+				return previewed, err
+			}
+			continue
+		}
+		if err != nil {
+			logrus.Errorf("[preview error]: %+v - %+v", queued.DocumentID, err)
+
+			queued.Attempts++
+			queued.LastError = err.Error()
+			queued.RetryAt = time.Now().Add(utils.Backoff(queued.Attempts))
+			if err = s.repos.Retry(ctx, queued); err != nil {
+				return previewed, err
+			}
+			continue
+		}
+
+		if err = s.repos.Ready(ctx, queued); err != nil {
+			return previewed, err
+		}
+		previewed++
+	}
+
+	return previewed, nil
+}
+
+// preview builds the thumbnails of the current content of a document and stores them beside it
+func (s *Service) preview(ctx context.Context, doc dto.Document) error {
+	if !preview.Supported(doc.Extension, doc.Type) {
+		return preview.ErrUnsupported
+	}
+
+	doc, err := s.remotes.Get(ctx, doc)
+	if err != nil {
+		return err
+	}
+	defer doc.ResponseContent.Close()
+
+	thumbnails, err := preview.Thumbnails(doc.Extension, doc.Type, doc.ResponseContent)
+	if err != nil {
+		return err
+	}
+
+	for size, content := range thumbnails {
+		if _, err = s.remotes.UploadPreview(ctx, doc, size, content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
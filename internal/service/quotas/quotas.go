@@ -0,0 +1,160 @@
+package quotas
+
+import (
+	"context"
+	"fmt"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+)
+
+type Service struct {
+	repos repository.QuotaRepository
+}
+
+func NewService(repos repository.QuotaRepository) *Service {
+	return &Service{
+		repos: repos,
+	}
+}
+
+// Check reports ErrQuota when storing more bytes for an owner exceeds a quota.
+// Content placed into a shared tree counts towards its owner, whose roles and
+// organization are the ones last seen when the owner stored content. It refuses
+// content before it is uploaded, the bytes are counted against the quotas again
+// when the rows are written, so concurrent uploads can't exceed them together.
+func (s *Service) Check(ctx context.Context, owner string, bytes int64) error {
+	userId, ok := ctx.Value(modules.UserID).(string)
+	if !ok {
+		return fmt.Errorf("unauthorized action is prohibited")
+	}
+
+	usage, err := s.repos.Usage(ctx, owner)
+	if err != nil {
+		return err
+	}
+
+	if owner == userId {
+		usage.Roles, usage.Organization = caller(ctx)
+		if err = s.repos.Seen(ctx, usage); err != nil {
+			return err
+		}
+	}
+
+	report, err := s.report(ctx, usage)
+	if err != nil {
+		return err
+	}
+
+	if report.MaxBytes != nil && report.Bytes+bytes > *report.MaxBytes {
+		return modules.ErrQuota
+	}
+
+	if report.OrganizationMax != nil && report.OrganizationBytes+bytes > *report.OrganizationMax {
+		return modules.ErrQuota
+	}
+
+	return nil
+}
+
+func (s *Service) Usage(ctx context.Context) (dto.Usage, error) {
+	userId, ok := ctx.Value(modules.UserID).(string)
+	if !ok {
+		return dto.Usage{}, fmt.Errorf("unauthorized action is prohibited")
+	}
+
+	usage, err := s.repos.Usage(ctx, userId)
+	if err != nil {
+		return dto.Usage{}, err
+	}
+
+	// the caller is reported with the roles of the token, not the ones last seen
+	usage.Roles, usage.Organization = caller(ctx)
+
+	return s.report(ctx, usage)
+}
+
+func (s *Service) UserUsage(ctx context.Context, userID string) (dto.Usage, error) {
+	usage, err := s.repos.Usage(ctx, userID)
+	if err != nil {
+		return dto.Usage{}, err
+	}
+
+	return s.report(ctx, usage)
+}
+
+func (s *Service) List(ctx context.Context) ([]dto.Quota, error) {
+	return s.repos.List(ctx)
+}
+
+func (s *Service) Save(ctx context.Context, quota dto.Quota) (dto.Quota, error) {
+	return s.repos.Save(ctx, quota)
+}
+
+func (s *Service) Delete(ctx context.Context, quota dto.Quota) (dto.Quota, error) {
+	return s.repos.Delete(ctx, quota)
+}
+
+func (s *Service) Recalculate(ctx context.Context) ([]dto.UsageCorrection, error) {
+	corrections, err := s.repos.Recalculate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, correction := range corrections {
+		logrus.Warnf("[usage drift]: %s - %d bytes recorded, %d counted from content", correction.UserID, correction.Before, correction.After)
+	}
+	return corrections, nil
+}
+
+// report resolves the quotas of a usage. A quota of the user overrides those
+// of its roles, of which the most generous applies.
+func (s *Service) report(ctx context.Context, usage dto.StorageUsage) (dto.Usage, error) {
+	report := dto.Usage{
+		UserID:       usage.UserID,
+		Bytes:        usage.Bytes,
+		Organization: usage.Organization,
+	}
+
+	quotas, err := s.repos.Applicable(ctx, usage)
+	if err != nil {
+		return report, err
+	}
+
+	var user, role *int64
+	for i, quota := range quotas {
+		switch quota.Subject {
+		case modules.QuotaUser:
+			user = &quotas[i].MaxBytes
+		case modules.QuotaRole:
+			if role == nil || quota.MaxBytes > *role {
+				role = &quotas[i].MaxBytes
+			}
+		case modules.QuotaOrganization:
+			report.OrganizationMax = &quotas[i].MaxBytes
+		}
+	}
+
+	report.MaxBytes = role
+	if user != nil {
+		report.MaxBytes = user
+	}
+
+	if usage.Organization != 0 {
+		if report.OrganizationBytes, err = s.repos.OrganizationBytes(ctx, usage.Organization); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// caller returns the roles and the organization of the caller from the token
+func caller(ctx context.Context) (pq.StringArray, int) {
+	roles, _ := ctx.Value(modules.Roles).([]string)
+	organization, _ := ctx.Value(modules.Organization).(int)
+
+	return roles, organization
+}
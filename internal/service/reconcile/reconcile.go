@@ -0,0 +1,473 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/remote"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"sort"
+	"time"
+)
+
+const batchSize = 1000
+
+// lock keeps replicas from reconciling at the same time
+const lock = 7240412
+
+type Service struct {
+	documents repository.DocumentRepository
+	versions  repository.VersionRepository
+	deletions repository.DeletionRepository
+	blobs     repository.BlobRepository
+	locks     repository.LockRepository
+	remotes   remote.DocumentsRemote
+	cfg       *modules.Reconcile
+}
+
+func NewService(documents repository.DocumentRepository, versions repository.VersionRepository, deletions repository.DeletionRepository, blobs repository.BlobRepository, locks repository.LockRepository, remotes remote.DocumentsRemote, cfg *modules.Reconcile) *Service {
+	return &Service{
+		documents: documents,
+		versions:  versions,
+		deletions: deletions,
+		blobs:     blobs,
+		locks:     locks,
+		remotes:   remotes,
+		cfg:       cfg,
+	}
+}
+
+// expectation is an object rows point at, a blob is pointed at by every document sharing it
+type expectation struct {
+	object   dto.StoredObject
+	changed  time.Time
+	pointers []pointer
+	listed   bool
+}
+
+// pointer is a document, or a version of one, expecting an object
+type pointer struct {
+	document uint
+	version  dto.DocumentVersion
+}
+
+// Reconcile pages through the objects in spaces and the documents with their versions in the
+// order of their keys, merging the two listings, and classifies what doesn't match. An object no row points at is an orphan, a row without its
+// object is missing content. Objects queued for deletion are left to the deletion worker and
+// a missing thumbnail is not a mismatch, as not every format has one.
+//
+// Mismatches changed within the grace period are reported as recent, as an upload stores its
+// row and its object one after the other. With repair, orphans are removed from spaces, a
+// version without its object is removed and a document without its content is marked
+// missing and moved into the trash, where its owner sees it until it is purged.
+// A run finding more to repair than the limit repairs nothing.
+//
+// Only one process reconciles at a time, another one finds ErrReconciling.
+func (s *Service) Reconcile(ctx context.Context, repair bool) (dto.Reconciliation, error) {
+	var report dto.Reconciliation
+	locked, err := s.locks.TryLock(ctx, lock, func(ctx context.Context) error {
+		var err error
+		report, err = s.reconcile(ctx, repair)
+		return err
+	})
+	if err == nil && !locked {
+		return report, modules.ErrReconciling
+	}
+
+	return report, err
+}
+
+func (s *Service) reconcile(ctx context.Context, repair bool) (dto.Reconciliation, error) {
+	report := dto.Reconciliation{DryRun: !repair, Mismatches: make([]dto.Mismatch, 0)}
+	cutoff := time.Now().Add(-s.cfg.Grace)
+
+	pending, err := s.pending(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	// the keys of documents lead with the day they were created, the keys of blobs follow them
+	objects := &listing{remotes: s.remotes}
+	if err = s.days(ctx, &report, objects, pending, cutoff); err != nil {
+		return report, err
+	}
+	if err = s.shared(ctx, &report, objects, pending, cutoff); err != nil {
+		return report, err
+	}
+
+	// no row expects the objects listed after the last blob
+	if err = s.merge(ctx, &report, objects, segment{}, pending, cutoff); err != nil {
+		return report, err
+	}
+
+	sort.SliceStable(report.Mismatches, func(i, j int) bool {
+		return report.Mismatches[i].Key < report.Mismatches[j].Key
+	})
+
+	if !repair {
+		return report, nil
+	}
+
+	planned := 0
+	for _, mismatch := range report.Mismatches {
+		if mismatch.Action == modules.MismatchPlanned {
+			planned++
+		}
+	}
+	if planned > s.cfg.MaxRepairs {
+		return report, fmt.Errorf("%w: %d of %d", modules.ErrTooManyRepairs, planned, s.cfg.MaxRepairs)
+	}
+
+	for i := range report.Mismatches {
+		if report.Mismatches[i].Action != modules.MismatchPlanned {
+			continue
+		}
+
+		report.Mismatches[i] = s.repair(ctx, report.Mismatches[i], cutoff)
+		if report.Mismatches[i].Action == modules.MismatchRepaired {
+			report.Repaired++
+		}
+	}
+
+	return report, nil
+}
+
+// orphan classifies an object no row points at
+func orphan(object dto.StoredObject, cutoff time.Time) dto.Mismatch {
+	mismatch := dto.Mismatch{
+		Class:        modules.MismatchOrphanObject,
+		Key:          object.Key,
+		Checksum:     object.Checksum,
+		Size:         object.Size,
+		LastModified: object.LastModified,
+		Action:       modules.MismatchPlanned,
+	}
+	if object.Checksum != "" {
+		mismatch.Class = modules.MismatchOrphanBlob
+	}
+	if object.LastModified == nil || object.LastModified.After(cutoff) {
+		mismatch.Action = modules.MismatchRecent
+	}
+	return mismatch
+}
+
+// missing classifies the rows pointing at an object missing from spaces. Documents without
+// their content are one mismatch, each version pointing at the object is another.
+func missing(key string, expectation *expectation, cutoff time.Time) []dto.Mismatch {
+	action := modules.MismatchPlanned
+	if expectation.changed.After(cutoff) {
+		action = modules.MismatchRecent
+	}
+
+	content := dto.Mismatch{Class: modules.MismatchMissingContent, Key: key, Action: action}
+	for _, pointer := range expectation.pointers {
+		if pointer.version.Version == 0 {
+			content.DocumentIDs = append(content.DocumentIDs, pointer.document)
+		}
+	}
+
+	var mismatches []dto.Mismatch
+	if len(content.DocumentIDs) > 0 {
+		mismatches = append(mismatches, content)
+	}
+
+	for _, pointer := range expectation.pointers {
+		// a version of a document losing its content is kept with it
+		if pointer.version.Version == 0 || contains(content.DocumentIDs, pointer.document) {
+			continue
+		}
+		mismatches = append(mismatches, dto.Mismatch{
+			Class:       modules.MismatchMissingVersion,
+			Key:         key,
+			Size:        pointer.version.Size,
+			DocumentIDs: []uint{pointer.document},
+			Version:     pointer.version.Version,
+			Action:      action,
+		})
+	}
+	return mismatches
+}
+
+// repair fixes a mismatch found earlier in the run
+func (s *Service) repair(ctx context.Context, mismatch dto.Mismatch, cutoff time.Time) dto.Mismatch {
+	var err error
+	mismatch.Action = modules.MismatchRepaired
+
+	switch mismatch.Class {
+	case modules.MismatchOrphanObject:
+		err = s.remotes.Remove(ctx, mismatch.Key)
+	case modules.MismatchOrphanBlob:
+		// the blob row decides, a document may have acquired the blob since the listing
+		var dropped bool
+		if dropped, err = s.blobs.Drop(ctx, mismatch.Checksum, cutoff); err == nil && !dropped {
+			mismatch.Action = modules.MismatchSkipped
+		}
+	case modules.MismatchMissingContent:
+		err = s.documents.Quarantine(ctx, mismatch.DocumentIDs)
+	case modules.MismatchMissingVersion:
+		err = s.versions.Remove(ctx, dto.DocumentVersion{DocumentID: mismatch.DocumentIDs[0], Version: mismatch.Version})
+	}
+
+	if err != nil {
+		logrus.Errorf("[reconcile error]: %s %s: %+v", mismatch.Class, mismatch.Key, err)
+		mismatch.Action = modules.MismatchFailed
+		mismatch.Error = err.Error()
+		return mismatch
+	}
+
+	logrus.Infof("[reconciled]: %s %s %s", mismatch.Action, mismatch.Class, mismatch.Key)
+	return mismatch
+}
+
+// listing pages through the objects in spaces in the order of their keys
+type listing struct {
+	remotes remote.DocumentsRemote
+	page    []dto.StoredObject
+	after   string
+	done    bool
+}
+
+// next returns the next object listed with a key before upto, with any key without upto
+func (l *listing) next(ctx context.Context, upto string) (dto.StoredObject, bool, error) {
+	if len(l.page) == 0 && !l.done {
+		page, err := l.remotes.List(ctx, l.after, batchSize)
+		if err != nil {
+			return dto.StoredObject{}, false, err
+		}
+
+		l.page = page
+		l.done = len(page) == 0
+		if !l.done {
+			l.after = page[len(page)-1].Key
+		}
+	}
+
+	if len(l.page) == 0 || (upto != "" && l.page[0].Key >= upto) {
+		return dto.StoredObject{}, false, nil
+	}
+
+	object := l.page[0]
+	l.page = l.page[1:]
+	return object, true, nil
+}
+
+// segment is a range of keys, from inclusive and upto exclusive, with the objects rows expect in it.
+// Segments follow one another, so each holds the expectations of one page of rows only.
+type segment struct {
+	from     string
+	upto     string
+	expected map[string]*expectation
+}
+
+// days merges the listing with the objects under the keys of documents, which lead with the
+// day a document was created and continue with its path, a page of the documents of a day at a time
+func (s *Service) days(ctx context.Context, report *dto.Reconciliation, objects *listing, pending map[string]bool, cutoff time.Time) error {
+	for next := (time.Time{}); ; {
+		first, err := s.documents.FirstCreated(ctx, next)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// the day in a key is that of the time as it is read
+		created := first.CreatedAt
+		day := time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, created.Location())
+		next = day.AddDate(0, 0, 1)
+
+		for after := uuid.Nil; ; {
+			docs, err := s.documents.ListCreated(ctx, day, next, after, batchSize)
+			if err != nil {
+				return err
+			}
+			if len(docs) == 0 {
+				break
+			}
+			report.Rows += len(docs)
+
+			last := docs[len(docs)-1]
+			page := segment{
+				from: s.remotes.Key(dto.Document{CreatedAt: docs[0].CreatedAt, Path: docs[0].Path}),
+				// the keys of a document continue its path with an extension or a version, both before "0"
+				upto: s.remotes.Key(dto.Document{CreatedAt: last.CreatedAt, Path: last.Path}) + "0",
+			}
+			if err = s.expect(ctx, &page, docs); err != nil {
+				return err
+			}
+			if err = s.merge(ctx, report, objects, page, pending, cutoff); err != nil {
+				return err
+			}
+
+			after = last.Path
+		}
+	}
+}
+
+// shared merges the listing with the objects under the keys of blobs, which follow their checksums,
+// a page of the checksums documents and their versions point at at a time
+func (s *Service) shared(ctx context.Context, report *dto.Reconciliation, objects *listing, pending map[string]bool, cutoff time.Time) error {
+	for after := ""; ; {
+		checksums, err := s.documents.ListChecksums(ctx, after, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(checksums) == 0 {
+			return nil
+		}
+
+		docs, err := s.documents.ListByChecksums(ctx, checksums)
+		if err != nil {
+			return err
+		}
+
+		last := checksums[len(checksums)-1]
+		page := segment{
+			from: s.remotes.Key(dto.Document{Checksum: checksums[0]}),
+			// the thumbnails of a blob continue its key with an extension, before "0"
+			upto: s.remotes.Key(dto.Document{Checksum: last}) + "0",
+		}
+		if err = s.expect(ctx, &page, docs); err != nil {
+			return err
+		}
+		if err = s.merge(ctx, report, objects, page, pending, cutoff); err != nil {
+			return err
+		}
+
+		after = last
+	}
+}
+
+// expect collects the objects documents and their versions point at within a segment
+func (s *Service) expect(ctx context.Context, page *segment, docs []dto.Document) error {
+	ids := make([]uint, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.ID)
+	}
+
+	versions, err := s.versions.ListByDocuments(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	byDocument := map[uint][]dto.DocumentVersion{}
+	for _, version := range versions {
+		byDocument[version.DocumentID] = append(byDocument[version.DocumentID], version)
+	}
+
+	page.expected = map[string]*expectation{}
+	for _, doc := range docs {
+		// the objects of a deleting document are queued for deletion
+		if doc.State == modules.DocumentDeleting {
+			continue
+		}
+
+		byVersion := map[uint]dto.DocumentVersion{}
+		for _, version := range byDocument[doc.ID] {
+			byVersion[version.Version] = version
+		}
+
+		for _, object := range s.remotes.Objects(doc, byDocument[doc.ID]) {
+			if object.Key < page.from || object.Key >= page.upto {
+				continue
+			}
+
+			changed := doc.UpdatedAt
+			if object.Version != 0 {
+				changed = byVersion[object.Version].CreatedAt
+			}
+
+			e, ok := page.expected[object.Key]
+			if !ok {
+				e = &expectation{object: object}
+				page.expected[object.Key] = e
+			}
+			if changed.After(e.changed) {
+				e.changed = changed
+			}
+			// an uploading document is settled by the outbox, infected content is never stored
+			// and a missing document was found without its content before
+			if !object.Preview && doc.State == modules.DocumentReady && doc.ScanStatus != modules.ScanInfected {
+				e.pointers = append(e.pointers, pointer{document: doc.ID, version: byVersion[object.Version]})
+			}
+		}
+	}
+	return nil
+}
+
+// merge walks the objects listed up to the end of a segment next to the objects expected in it.
+// An object listed but not expected is an orphan, one expected but not listed is missing.
+func (s *Service) merge(ctx context.Context, report *dto.Reconciliation, objects *listing, page segment, pending map[string]bool, cutoff time.Time) error {
+	for {
+		object, ok, err := objects.next(ctx, page.upto)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		report.Objects++
+
+		if e, ok := page.expected[object.Key]; ok {
+			e.listed = true
+			continue
+		}
+		if pending[object.Key] {
+			continue
+		}
+		report.Mismatches = append(report.Mismatches, orphan(object, cutoff))
+	}
+
+	for key, expectation := range page.expected {
+		if expectation.listed || pending[key] || expectation.object.Preview {
+			continue
+		}
+		report.Mismatches = append(report.Mismatches, missing(key, expectation, cutoff)...)
+	}
+	return nil
+}
+
+// pending returns the keys of objects queued for deletion
+func (s *Service) pending(ctx context.Context) (map[string]bool, error) {
+	pending := map[string]bool{}
+
+	for after := uint(0); ; {
+		deletions, err := s.deletions.ListAfter(ctx, after, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(deletions) == 0 {
+			return pending, nil
+		}
+
+		for _, deletion := range deletions {
+			var versions []dto.DocumentVersion
+			if deletion.Version != 0 {
+				versions = append(versions, dto.DocumentVersion{Version: deletion.Version, Extension: deletion.Extension})
+			}
+
+			for _, object := range s.remotes.Objects(deletion.Document(), versions) {
+				// a queued version leaves the current content of its document alone
+				if object.Version == deletion.Version {
+					pending[object.Key] = true
+				}
+			}
+		}
+
+		after = deletions[len(deletions)-1].ID
+	}
+}
+
+func contains(ids []uint, id uint) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
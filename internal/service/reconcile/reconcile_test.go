@@ -0,0 +1,92 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	remotemocks "gitlab.com/a5805/ondeu/ondeu-back/internal/remote/mocks"
+	repomocks "gitlab.com/a5805/ondeu/ondeu-back/internal/repository/mocks"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+// key names objects the way spaces do, without the thumbnails
+func key(doc dto.Document) string {
+	if doc.Checksum != "" {
+		return fmt.Sprintf("blobs/%s/%s/%s", doc.Checksum[:2], doc.Checksum[2:4], doc.Checksum)
+	}
+	return fmt.Sprintf("%s/%s%s", doc.CreatedAt.Format("2006-01-02"), doc.Path.String(), doc.Extension)
+}
+
+func TestService_Reconcile(t *testing.T) {
+	// Init Dependencies
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	documents := repomocks.NewMockDocumentRepository(c)
+	versions := repomocks.NewMockVersionRepository(c)
+	deletions := repomocks.NewMockDeletionRepository(c)
+	locks := repomocks.NewMockLockRepository(c)
+	remotes := remotemocks.NewMockDocumentsRemote(c)
+
+	created := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+	day := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	old := created.Add(time.Hour)
+
+	stored := dto.Document{ID: 1, CreatedAt: created, UpdatedAt: created, Extension: ".txt", State: modules.DocumentReady,
+		Path: uuid.MustParse("11111111-1111-1111-1111-111111111111")}
+	lost := dto.Document{ID: 2, CreatedAt: created, UpdatedAt: created, Extension: ".txt", State: modules.DocumentReady,
+		Path: uuid.MustParse("22222222-2222-2222-2222-222222222222")}
+	shared := dto.Document{ID: 3, CreatedAt: created, UpdatedAt: created, State: modules.DocumentReady,
+		Checksum: "aa0f3c5e3e2c24f3d7aa0f3c5e3e2c24f3d7aa0f3c5e3e2c24f3d7aa0f3c5e3e"}
+	strayKey := "2026-10-01/33333333-3333-3333-3333-333333333333.txt"
+	strayChecksum := "ee01aa0f3c5e3e2c24f3d7aa0f3c5e3e2c24f3d7aa0f3c5e3e2c24f3d7aa0f3c"
+	strayBlob := key(dto.Document{Checksum: strayChecksum})
+
+	locks.EXPECT().TryLock(gomock.Any(), int64(lock), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ int64, fn func(ctx context.Context) error) (bool, error) {
+			return true, fn(ctx)
+		})
+	deletions.EXPECT().ListAfter(gomock.Any(), uint(0), batchSize).Return(nil, nil)
+	remotes.EXPECT().Key(gomock.Any()).DoAndReturn(key).AnyTimes()
+	remotes.EXPECT().Objects(gomock.Any(), gomock.Any()).DoAndReturn(func(doc dto.Document, _ []dto.DocumentVersion) []dto.StoredObject {
+		return []dto.StoredObject{{Key: key(doc), DocumentID: doc.ID}}
+	}).AnyTimes()
+	versions.EXPECT().ListByDocuments(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	// the objects are listed in the order of their keys, over two pages
+	remotes.EXPECT().List(gomock.Any(), "", int64(batchSize)).
+		Return([]dto.StoredObject{{Key: key(stored), LastModified: &old}, {Key: strayKey, LastModified: &old}}, nil)
+	remotes.EXPECT().List(gomock.Any(), strayKey, int64(batchSize)).
+		Return([]dto.StoredObject{{Key: key(shared), Checksum: shared.Checksum, LastModified: &old}, {Key: strayBlob, Checksum: strayChecksum, LastModified: &old}}, nil)
+	remotes.EXPECT().List(gomock.Any(), strayBlob, int64(batchSize)).Return(nil, nil)
+
+	// the documents of a day, then the blobs
+	documents.EXPECT().FirstCreated(gomock.Any(), time.Time{}).Return(stored, nil)
+	documents.EXPECT().ListCreated(gomock.Any(), day, day.AddDate(0, 0, 1), uuid.Nil, batchSize).Return([]dto.Document{stored, lost}, nil)
+	documents.EXPECT().ListCreated(gomock.Any(), day, day.AddDate(0, 0, 1), lost.Path, batchSize).Return(nil, nil)
+	documents.EXPECT().FirstCreated(gomock.Any(), day.AddDate(0, 0, 1)).Return(dto.Document{}, gorm.ErrRecordNotFound)
+	documents.EXPECT().ListChecksums(gomock.Any(), "", batchSize).Return([]string{shared.Checksum}, nil)
+	documents.EXPECT().ListByChecksums(gomock.Any(), []string{shared.Checksum}).Return([]dto.Document{shared}, nil)
+	documents.EXPECT().ListChecksums(gomock.Any(), shared.Checksum, batchSize).Return(nil, nil)
+
+	s := NewService(documents, versions, deletions, repomocks.NewMockBlobRepository(c), locks, remotes, &modules.Reconcile{Grace: time.Hour, MaxRepairs: 10})
+
+	// Test
+	report, err := s.Reconcile(context.Background(), false)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Rows)
+	assert.Equal(t, 4, report.Objects)
+	assert.Equal(t, []dto.Mismatch{
+		{Class: modules.MismatchMissingContent, Key: key(lost), DocumentIDs: []uint{2}, Action: modules.MismatchPlanned},
+		{Class: modules.MismatchOrphanObject, Key: strayKey, LastModified: &old, Action: modules.MismatchPlanned},
+		{Class: modules.MismatchOrphanBlob, Key: strayBlob, Checksum: strayChecksum, LastModified: &old, Action: modules.MismatchPlanned},
+	}, report.Mismatches)
+}
@@ -3,16 +3,34 @@ package service
 import (
 	"context"
 	"github.com/Nerzal/gocloak/v8"
+	"github.com/sirupsen/logrus"
 	"gitlab.com/a5805/ondeu/ondeu-back/internal/remote"
 	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/access"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/blobs"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/common"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/deletions"
 	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/documents"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/exports"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/groups"
 	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/information"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/outbox"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/policies"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/previews"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/quotas"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/reconcile"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/shares"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/texts"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/trash"
 	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/tree"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/uploads"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/versions"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/clamd"
 	keycloak2 "gitlab.com/a5805/ondeu/ondeu-back/pkg/gocloak"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"io"
 	"mime/multipart"
-	"time"
 )
 
 //go:generate mockgen -source=service.go -destination=mocks/mock.go
@@ -20,18 +38,26 @@ import (
 type DocumentService interface {
 	// Create creates a new document
 	Create(ctx context.Context, in dto.Document, file *multipart.FileHeader) (dto.Document, error)
+	// Store creates a new document from content checked like an uploaded file
+	Store(ctx context.Context, document dto.Document, file dto.UploadFile, content io.ReadSeeker) (dto.Document, error)
 	// Get returns a document
 	Get(ctx context.Context, doc dto.Document, download bool) (dto.Document, error)
 	// Update updates a document
 	Update(ctx context.Context, doc dto.Document) (dto.Document, error)
-	// Delete deletes a document
+	// Delete moves a document to the trash
 	Delete(ctx context.Context, doc dto.Document) (dto.Document, error)
+	// Move moves a document into another tree
+	Move(ctx context.Context, doc dto.Document, treeID uint) (dto.Document, error)
+	// Copy copies a document with its content into another tree
+	Copy(ctx context.Context, doc dto.Document, treeID uint) (dto.Document, error)
+	// Search returns a page of documents the caller can access matching a search
+	Search(ctx context.Context, search dto.DocumentSearch) (dto.DocumentPage, error)
+	// Preview returns a thumbnail of a size of a document
+	Preview(ctx context.Context, doc dto.Document, size string) (dto.Document, error)
 
-	// Share create share link for a document
-	Share(ctx context.Context, doc dto.Document, duration time.Duration) (dto.Document, error)
-	// ListByTree returns a slice of documents by tree id
+	// ListByTree returns a slice of documents by tree id, the caller resolves the access to the trees
 	ListByTree(ctx context.Context, ids []uint) ([]dto.Document, error)
-	// ListByGroups returns a slice of documents by group id
+	// ListByGroups returns a slice of documents by group id, the caller checks the membership of the groups
 	ListByGroups(ctx context.Context, ids []uint) ([]dto.Document, error)
 }
 
@@ -44,8 +70,24 @@ type TreeService interface {
 	List(ctx context.Context, tree dto.Tree) ([]dto.Tree, error)
 	// Update deletes a tree
 	Update(ctx context.Context, tree dto.Tree) (dto.Tree, error)
-	// Delete deletes a tree
+	// Delete moves a tree with its subtree to the trash
 	Delete(ctx context.Context, tree dto.Tree) (dto.Tree, error)
+	// Purge permanently deletes a tree with its subtree and documents
+	Purge(ctx context.Context, tree dto.Tree) (dto.Purge, error)
+	// Move moves a tree with its subtree under another parent
+	Move(ctx context.Context, tree dto.Tree, parentID uint) (dto.Tree, error)
+	// Copy copies a tree with its subtree and documents under another parent
+	Copy(ctx context.Context, tree dto.Tree, parentID uint) (dto.Tree, error)
+	// ListTemplates returns templates the caller's roles may use, optionally narrowed to one role
+	ListTemplates(ctx context.Context, role string) ([]dto.Tree, error)
+	// Instantiate copies a template with its documents into the caller's tree
+	Instantiate(ctx context.Context, tree dto.Tree, parentID uint) (dto.Tree, error)
+	// Archive lays out a tree with its subtrees and documents for a ZIP download
+	Archive(ctx context.Context, tree dto.Tree, selection dto.ArchiveSelection) (dto.Archive, error)
+	// WriteArchive streams the documents of an archive as a ZIP
+	WriteArchive(ctx context.Context, folder dto.Archive, w io.Writer) error
+	// Import unpacks a ZIP archive into a tree and reports what it created and rejected
+	Import(ctx context.Context, tree dto.Tree, file *multipart.FileHeader) (dto.ImportReport, error)
 
 	// GetTreeIDs returns a slice of tree ids
 	GetTreeIDs(ctx context.Context, trees []dto.Tree) []uint
@@ -54,6 +96,168 @@ type TreeService interface {
 	FormTree(ctx context.Context, trees []dto.Tree, docs []dto.Document) []dto.Tree
 }
 
+type VersionService interface {
+	// Create uploads a new version of a document and makes it current
+	Create(ctx context.Context, doc dto.Document, file *multipart.FileHeader) (dto.DocumentVersion, error)
+	// List returns all versions of a document, newest first
+	List(ctx context.Context, doc dto.Document) ([]dto.DocumentVersion, error)
+	// Get returns a specific version of a document with its content
+	Get(ctx context.Context, doc dto.Document, version uint) (dto.DocumentVersion, error)
+	// Restore makes an older version the current content of a document
+	Restore(ctx context.Context, doc dto.Document, version uint) (dto.Document, error)
+}
+
+type UploadService interface {
+	// Initiate starts a resumable upload of a document
+	Initiate(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error)
+	// Get returns an upload session with its received parts
+	Get(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error)
+	// List returns unfinished uploads of the caller in a tree
+	List(ctx context.Context, session dto.UploadSession) ([]dto.UploadSession, error)
+	// PutPart uploads a numbered chunk of a document
+	PutPart(ctx context.Context, session dto.UploadSession, part dto.UploadPart) (dto.UploadPart, error)
+	// Complete assembles the uploaded chunks into a new document
+	Complete(ctx context.Context, session dto.UploadSession) (dto.Document, error)
+	// Abort discards an upload session and its chunks
+	Abort(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error)
+	// Expire aborts the upload sessions that outlived their lifetime and returns how many were aborted
+	Expire(ctx context.Context) (int, error)
+}
+
+type TrashService interface {
+	// List returns trees and documents the caller moved to the trash
+	List(ctx context.Context) ([]dto.TrashItem, error)
+	// Restore takes a tree or a document out of the trash
+	Restore(ctx context.Context, item dto.TrashItem) (dto.TrashItem, error)
+	// Purge permanently deletes items kept in the trash longer than the retention period
+	Purge(ctx context.Context) (dto.Purge, error)
+}
+
+type DeletionService interface {
+	// Process removes queued objects from spaces and returns how many are gone
+	Process(ctx context.Context) (int, error)
+}
+
+type OutboxService interface {
+	// Process settles the side effects in spaces requests left behind and returns how many are settled
+	Process(ctx context.Context) (int, error)
+}
+
+type BlobService interface {
+	// Verify re-hashes every stored blob and reports those not matching their checksum
+	Verify(ctx context.Context) (dto.Verification, error)
+}
+
+type ReconcileService interface {
+	// Reconcile classifies the mismatches between spaces and the database and repairs them unless in a dry run
+	Reconcile(ctx context.Context, repair bool) (dto.Reconciliation, error)
+}
+
+type ExportService interface {
+	// Export lays out the folders and documents of a user with a manifest of its data
+	Export(ctx context.Context, userID string) (dto.Archive, error)
+	// WriteArchive streams the documents of an export as a ZIP
+	WriteArchive(ctx context.Context, export dto.Archive, w io.Writer) error
+}
+
+type PreviewService interface {
+	// Process builds thumbnails of queued documents and returns how many are previewed
+	Process(ctx context.Context) (int, error)
+}
+
+type TextService interface {
+	// Process extracts the text of queued documents and returns how many are indexed
+	Process(ctx context.Context) (int, error)
+}
+
+type QuotaService interface {
+	// Usage returns the bytes the caller stores against its quotas
+	Usage(ctx context.Context) (dto.Usage, error)
+	// UserUsage returns the bytes a user stores against its quotas
+	UserUsage(ctx context.Context, userID string) (dto.Usage, error)
+	// List returns all quotas
+	List(ctx context.Context) ([]dto.Quota, error)
+	// Save creates a quota or changes the limit of its subject
+	Save(ctx context.Context, quota dto.Quota) (dto.Quota, error)
+	// Delete deletes a quota, its subject becomes unlimited
+	Delete(ctx context.Context, quota dto.Quota) (dto.Quota, error)
+	// Recalculate corrects the usage of users that drifted from the content they store
+	Recalculate(ctx context.Context) ([]dto.UsageCorrection, error)
+}
+
+type PolicyService interface {
+	// Get returns the upload policy of a tree, an empty one when it has none
+	Get(ctx context.Context, treeID uint) (dto.TreePolicy, error)
+	// Save sets the upload policy of a tree the caller owns
+	Save(ctx context.Context, policy dto.TreePolicy) (dto.TreePolicy, error)
+	// Delete removes the upload policy of a tree the caller owns
+	Delete(ctx context.Context, treeID uint) (dto.TreePolicy, error)
+}
+
+type ShareService interface {
+	// Create creates a share link for a document
+	Create(ctx context.Context, doc dto.Document, options dto.ShareOptions) (dto.Share, error)
+	// CreateTree creates a share link for a folder with its subfolders
+	CreateTree(ctx context.Context, tree dto.Tree, options dto.ShareOptions) (dto.Share, error)
+	// List returns usable share links the caller created or of documents the caller owns
+	List(ctx context.Context) ([]dto.Share, error)
+	// Revoke stops a share link from working
+	Revoke(ctx context.Context, shareID uint) (dto.Share, error)
+	// Accesses returns the attempts to open a share link, newest first
+	Accesses(ctx context.Context, shareID uint) ([]dto.ShareAccess, error)
+	// Open resolves a share link to the content of a document or the structure of a folder without authentication
+	Open(ctx context.Context, request dto.ShareRequest) (dto.Shared, error)
+	// Archive lays out the folder of a share link for a ZIP download without authentication
+	Archive(ctx context.Context, request dto.ShareRequest) (dto.Archive, error)
+	// WriteArchive streams the documents of an archive as a ZIP
+	WriteArchive(ctx context.Context, folder dto.Archive, w io.Writer) error
+}
+
+type GroupService interface {
+	// Create creates a new group owned by the caller
+	Create(ctx context.Context, group dto.Group) (dto.Group, error)
+	// Get returns a group the caller owns or is a member of
+	Get(ctx context.Context, group dto.Group) (dto.Group, error)
+	// List returns groups the caller owns or is a member of
+	List(ctx context.Context) ([]dto.Group, error)
+	// Update updates a group of the caller
+	Update(ctx context.Context, group dto.Group) (dto.Group, error)
+	// Delete deletes a group of the caller
+	Delete(ctx context.Context, group dto.Group) (dto.Group, error)
+	// AddMember adds a user to a group of the caller
+	AddMember(ctx context.Context, member dto.GroupMember) (dto.GroupMember, error)
+	// RemoveMember removes a user from a group of the caller
+	RemoveMember(ctx context.Context, member dto.GroupMember) (dto.GroupMember, error)
+	// AttachDocument shares a document of the caller with a group
+	AttachDocument(ctx context.Context, group dto.Group, documentID uint) (dto.Group, error)
+	// DetachDocument stops sharing a document with a group
+	DetachDocument(ctx context.Context, group dto.Group, documentID uint) (dto.Group, error)
+	// AttachTree shares a tree of the caller with a group
+	AttachTree(ctx context.Context, group dto.Group, treeID uint) (dto.Group, error)
+	// DetachTree stops sharing a tree with a group
+	DetachTree(ctx context.Context, group dto.Group, treeID uint) (dto.Group, error)
+	// ListDocuments returns documents shared with a group
+	ListDocuments(ctx context.Context, group dto.Group) ([]dto.Document, error)
+}
+
+type AccessService interface {
+	// Grant shares a tree with a user, a group or a role, or changes the granted level
+	Grant(ctx context.Context, access dto.TreeAccess) (dto.TreeAccess, error)
+	// Revoke stops sharing a tree
+	Revoke(ctx context.Context, access dto.TreeAccess) (dto.TreeAccess, error)
+	// List returns the access list of a tree
+	List(ctx context.Context, treeID uint) ([]dto.TreeAccess, error)
+	// Shared returns trees of other users shared with the caller
+	Shared(ctx context.Context) ([]dto.TreeAccess, error)
+}
+
+type PermissionService interface {
+	// Roles returns the roles allowed to perform an action
+	Roles(action string) []string
+	// Allowed reports whether the roles of the caller allow an action
+	Allowed(ctx context.Context, action string) bool
+}
+
 type InformationService interface {
 	// GetRoles returns a slice of users
 	GetRoles(ctx context.Context) ([]*gocloak.Role, error)
@@ -62,13 +266,63 @@ type InformationService interface {
 type Services struct {
 	TreeService
 	DocumentService
+	VersionService
+	UploadService
+	TrashService
+	DeletionService
+	OutboxService
+	TextService
+	PreviewService
+	BlobService
+	ReconcileService
+	ExportService
+	QuotaService
+	PolicyService
+	ShareService
+	GroupService
+	AccessService
+	PermissionService
 	InformationService
 }
 
 func NewServices(cfg *modules.AppConfigs, keycloak keycloak2.IKeycloak, repos *repository.Repository, remotes *remote.Remote) *Services {
+	accessService := access.NewService(repos.AccessRepository, repos.TreeRepository)
+	quotaService := quotas.NewService(repos.QuotaRepository)
+	scanner := newScanner(cfg.Scanner)
+	policyService := policies.NewService(repos.PolicyRepository, accessService, cfg.Uploads)
+	documentService := documents.NewService(repos.DocumentRepository, repos.TreeRepository, repos.PreviewRepository, repos.BlobRepository, remotes, accessService, quotaService, scanner, policyService, cfg.Permissions)
+	treeService := tree.NewService(repos.TreeRepository, documentService, accessService, remotes, cfg.Permissions, cfg.Imports)
+
 	return &Services{
-		TreeService:        tree.NewService(repos.TreeRepository),
-		DocumentService:    documents.NewService(repos.DocumentRepository, remotes),
+		TreeService:        treeService,
+		DocumentService:    documentService,
+		VersionService:     versions.NewService(repos.DocumentRepository, repos.VersionRepository, repos.PreviewRepository, repos.BlobRepository, remotes, accessService, quotaService, scanner, policyService),
+		UploadService:      uploads.NewService(repos.UploadRepository, repos.DocumentRepository, repos.PreviewRepository, repos.BlobRepository, remotes, accessService, quotaService, scanner, policyService, cfg.Permissions, cfg.Sessions),
+		TrashService:       trash.NewService(repos.TreeRepository, repos.DocumentRepository, accessService, cfg.Trash),
+		DeletionService:    deletions.NewService(repos.DeletionRepository, remotes),
+		OutboxService:      outbox.NewService(repos.OutboxRepository, repos.DocumentRepository, repos.BlobRepository, remotes, cfg.Outbox),
+		TextService:        texts.NewService(repos.TextRepository, remotes),
+		PreviewService:     previews.NewService(repos.PreviewRepository, remotes),
+		BlobService:        blobs.NewService(repos.BlobRepository, remotes),
+		ReconcileService:   reconcile.NewService(repos.DocumentRepository, repos.VersionRepository, repos.DeletionRepository, repos.BlobRepository, repos.LockRepository, remotes, cfg.Reconcile),
+		ExportService:      exports.NewService(repos.TreeRepository, repos.DocumentRepository, repos.GroupRepository, repos.ShareRepository, remotes, treeService, quotaService),
+		QuotaService:       quotaService,
+		PolicyService:      policyService,
+		ShareService:       shares.NewService(repos.ShareRepository, repos.DocumentRepository, repos.TreeRepository, remotes, accessService, treeService, cfg.Shares),
+		GroupService:       groups.NewService(repos.GroupRepository, repos.DocumentRepository),
+		AccessService:      accessService,
+		PermissionService:  cfg.Permissions,
 		InformationService: information.NewService(cfg.Keycloak, keycloak),
 	}
 }
+
+// newScanner returns the clamd client uploads are scanned with,
+// the fake one asked for in development only detects the EICAR test file
+func newScanner(cfg *modules.Scanner) common.Scanner {
+	if cfg.Fake {
+		logrus.Warn("SCANNER is fake, uploads are only checked for the EICAR test file")
+		return clamd.Fake{}
+	}
+
+	return clamd.NewClient(cfg.Address, cfg.Timeout)
+}
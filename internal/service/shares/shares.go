@@ -0,0 +1,449 @@
+package shares
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/remote"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/common"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/archive"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/upload"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"io"
+	"strings"
+	"time"
+)
+
+// linkPath is the route share links are opened at
+const linkPath = "/s/%s"
+
+// tokenBytes is the randomness of a share token, it is base64 encoded into 32 characters
+const tokenBytes = 24
+
+type Service struct {
+	repos     repository.ShareRepository
+	documents repository.DocumentRepository
+	trees     repository.TreeRepository
+	remotes   remote.DocumentsRemote
+	access    common.Access
+	folders   common.Folders
+	cfg       *modules.Shares
+}
+
+func NewService(repos repository.ShareRepository, documents repository.DocumentRepository, trees repository.TreeRepository, remotes remote.DocumentsRemote, access common.Access, folders common.Folders, cfg *modules.Shares) *Service {
+	return &Service{
+		repos:     repos,
+		documents: documents,
+		trees:     trees,
+		remotes:   remotes,
+		access:    access,
+		folders:   folders,
+		cfg:       cfg,
+	}
+}
+
+func (s *Service) Create(ctx context.Context, doc dto.Document, options dto.ShareOptions) (dto.Share, error) {
+	owner, err := s.access.Owner(ctx, doc.TreeID, modules.AccessEditor)
+	if err != nil {
+		return dto.Share{}, err
+	}
+
+	doc.UserID = owner
+
+	document, err := s.documents.Get(ctx, doc)
+	if err != nil {
+		return dto.Share{}, err
+	}
+
+	if document.ScanStatus != modules.ScanClean {
+		return dto.Share{}, modules.ErrNotClean
+	}
+
+	return s.issue(ctx, dto.Share{
+		Kind:       modules.ShareDocument,
+		DocumentID: &document.ID,
+		TreeID:     doc.TreeID,
+		UserID:     owner,
+	}, options)
+}
+
+// CreateTree creates a share link for a folder, its documents not scanned clean are listed but not served
+func (s *Service) CreateTree(ctx context.Context, tree dto.Tree, options dto.ShareOptions) (dto.Share, error) {
+	owner, err := s.access.Owner(ctx, tree.ID, modules.AccessEditor)
+	if err != nil {
+		return dto.Share{}, err
+	}
+
+	return s.issue(ctx, dto.Share{
+		Kind:   modules.ShareTree,
+		TreeID: tree.ID,
+		UserID: owner,
+	}, options)
+}
+
+// issue stores a share link created by the caller with the limits of the options
+func (s *Service) issue(ctx context.Context, share dto.Share, options dto.ShareOptions) (dto.Share, error) {
+	userId, ok := ctx.Value(modules.UserID).(string)
+	if !ok {
+		return dto.Share{}, fmt.Errorf("unauthorized action is prohibited")
+	}
+
+	lifetime := s.cfg.Lifetime
+	if options.ExpiresIn > 0 {
+		if options.ExpiresIn > int64(s.cfg.MaxLifetime/time.Second) {
+			return dto.Share{}, modules.ErrShareLifetime
+		}
+		lifetime = time.Duration(options.ExpiresIn) * time.Second
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return dto.Share{}, err
+	}
+
+	share.TokenHash = hashToken(token)
+	share.CreatedBy = userId
+	share.ExpiresAt = time.Now().Add(lifetime)
+	share.MaxDownloads = options.MaxDownloads
+
+	if options.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(options.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return dto.Share{}, err
+		}
+		share.PasswordHash = string(hash)
+	}
+
+	share, err = s.repos.Create(ctx, share)
+	if err != nil {
+		return share, err
+	}
+
+	// the token is handed out once, afterwards only its hash is known
+	share.Token = token
+
+	return present(share), nil
+}
+
+func (s *Service) List(ctx context.Context) ([]dto.Share, error) {
+	userId, ok := ctx.Value(modules.UserID).(string)
+	if !ok {
+		return nil, fmt.Errorf("unauthorized action is prohibited")
+	}
+
+	shares, err := s.repos.List(ctx, userId, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range shares {
+		shares[i] = present(shares[i])
+	}
+	return shares, nil
+}
+
+func (s *Service) Revoke(ctx context.Context, shareID uint) (dto.Share, error) {
+	share, err := s.manageable(ctx, shareID)
+	if err != nil {
+		return share, err
+	}
+
+	share, err = s.repos.Revoke(ctx, share)
+	if err != nil {
+		return share, err
+	}
+
+	return present(share), nil
+}
+
+func (s *Service) Accesses(ctx context.Context, shareID uint) ([]dto.ShareAccess, error) {
+	if _, err := s.manageable(ctx, shareID); err != nil {
+		return nil, err
+	}
+
+	return s.repos.Accesses(ctx, shareID)
+}
+
+// Open resolves a share link to the content of its document, or to the structure
+// of its folder or a document in it. Every attempt on an existing link is logged,
+// including the ones refused.
+func (s *Service) Open(ctx context.Context, request dto.ShareRequest) (dto.Shared, error) {
+	share, err := s.byToken(ctx, request.Token)
+	if err != nil {
+		return dto.Shared{}, err
+	}
+
+	shared, outcome, err := s.open(ctx, share, request)
+	if err == nil && shared.Trees == nil {
+		shared.Document, outcome, err = s.download(ctx, share, shared.Document, request.Download)
+	}
+
+	s.log(ctx, share, request, outcome)
+	if err != nil {
+		return dto.Shared{}, err
+	}
+
+	return shared, nil
+}
+
+// Archive lays out the folder of a share link for a ZIP download, which counts as one download.
+// Documents not scanned clean are left out.
+func (s *Service) Archive(ctx context.Context, request dto.ShareRequest) (dto.Archive, error) {
+	share, err := s.byToken(ctx, request.Token)
+	if err != nil {
+		return dto.Archive{}, err
+	}
+
+	if share.Kind != modules.ShareTree {
+		return dto.Archive{}, modules.ErrNoShare
+	}
+
+	folder, outcome, err := s.archive(ctx, share, request)
+	s.log(ctx, share, request, outcome)
+	return folder, err
+}
+
+// WriteArchive streams the documents of an archive as a ZIP
+func (s *Service) WriteArchive(ctx context.Context, folder dto.Archive, w io.Writer) error {
+	return archive.Write(w, folder.Entries, func(doc dto.Document) (io.ReadCloser, error) {
+		doc, err := s.remotes.Get(ctx, doc)
+		return doc.ResponseContent, err
+	})
+}
+
+func (s *Service) byToken(ctx context.Context, token string) (dto.Share, error) {
+	share, err := s.repos.ByToken(ctx, hashToken(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return share, modules.ErrNoShare
+	}
+	return share, err
+}
+
+// open decides what a request on a share link gets and returns the outcome to log,
+// empty when the decision failed
+func (s *Service) open(ctx context.Context, share dto.Share, request dto.ShareRequest) (dto.Shared, string, error) {
+	if outcome, err := admit(share, request.Password); err != nil {
+		return dto.Shared{}, outcome, err
+	}
+
+	if share.Kind != modules.ShareTree {
+		if request.DocumentID != 0 && request.DocumentID != share.Document.ID {
+			return dto.Shared{}, "", modules.ErrNoShare
+		}
+
+		document := *share.Document
+		document.TreeID = share.TreeID
+
+		outcome, err := release(share, document)
+		return dto.Shared{Document: document}, outcome, err
+	}
+
+	trees, err := s.folder(ctx, share)
+	if err != nil {
+		return dto.Shared{}, "", err
+	}
+
+	// the structure of a folder is shown without counting a download
+	if request.DocumentID == 0 {
+		return dto.Shared{Trees: trees}, modules.ShareGranted, nil
+	}
+
+	for _, tree := range trees {
+		for _, document := range tree.Documents {
+			if document.ID == request.DocumentID {
+				outcome, err := release(share, document)
+				return dto.Shared{Document: document}, outcome, err
+			}
+		}
+	}
+
+	return dto.Shared{}, "", modules.ErrNoShare
+}
+
+func (s *Service) archive(ctx context.Context, share dto.Share, request dto.ShareRequest) (dto.Archive, string, error) {
+	if outcome, err := admit(share, request.Password); err != nil {
+		return dto.Archive{}, outcome, err
+	}
+
+	trees, err := s.folder(ctx, share)
+	if err != nil {
+		return dto.Archive{}, "", err
+	}
+
+	var entries []dto.ArchiveEntry
+	for _, entry := range archive.Layout(share.TreeID, trees) {
+		if entry.Document == nil || entry.Document.ScanStatus == modules.ScanClean {
+			entries = append(entries, entry)
+		}
+	}
+
+	if outcome, err := s.use(ctx, share); err != nil {
+		return dto.Archive{}, outcome, err
+	}
+
+	name := upload.SanitizeName(share.Tree.Name)
+	if name == "" {
+		name = "folder"
+	}
+
+	return dto.Archive{Name: name + archive.Extension, Entries: entries}, modules.ShareGranted, nil
+}
+
+// folder returns the shared folder followed by its subfolders, with their documents
+func (s *Service) folder(ctx context.Context, share dto.Share) ([]dto.Tree, error) {
+	subtrees, err := s.trees.List(ctx, dto.Tree{ID: share.TreeID, UserID: share.UserID})
+	if err != nil {
+		return nil, err
+	}
+
+	root := *share.Tree
+	root.Documents = nil
+	trees := append([]dto.Tree{root}, subtrees...)
+
+	docs, err := s.documents.ListByTree(ctx, s.folders.GetTreeIDs(ctx, trees))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.folders.FormTree(ctx, trees, docs), nil
+}
+
+// release lets a document of a share link be downloaded when it is clean and downloads are left
+func release(share dto.Share, document dto.Document) (string, error) {
+	if document.ScanStatus != modules.ScanClean {
+		return modules.ShareNotClean, modules.ErrNotClean
+	}
+
+	if share.MaxDownloads != 0 && share.Downloads >= share.MaxDownloads {
+		return modules.ShareExhausted, modules.ErrShareGone
+	}
+
+	return modules.ShareGranted, nil
+}
+
+// download opens the content of a document of a share link and counts the download once
+// it opened. A download is counted on the request from the first byte, so resuming it
+// with a range or revalidating a cached copy is not counted again.
+func (s *Service) download(ctx context.Context, share dto.Share, document dto.Document, download dto.Download) (dto.Document, string, error) {
+	document.Download = download
+
+	document, err := s.remotes.Get(ctx, document)
+	if err != nil {
+		return document, "", err
+	}
+
+	if document.Download.NotModified || !fromStart(download.Range) {
+		return document, modules.ShareGranted, nil
+	}
+
+	outcome, err := s.use(ctx, share)
+	if err != nil {
+		document.ResponseContent.Close()
+		return document, outcome, err
+	}
+
+	return document, outcome, nil
+}
+
+// fromStart reports whether a download without a range or with one from the first byte is asked for
+func fromStart(ranges string) bool {
+	return ranges == "" || strings.HasPrefix(strings.ReplaceAll(ranges, " ", ""), "bytes=0-")
+}
+
+func (s *Service) use(ctx context.Context, share dto.Share) (string, error) {
+	used, err := s.repos.Use(ctx, share)
+	if err != nil {
+		return "", err
+	}
+	if !used {
+		return modules.ShareExhausted, modules.ErrShareGone
+	}
+
+	return modules.ShareGranted, nil
+}
+
+func (s *Service) log(ctx context.Context, share dto.Share, request dto.ShareRequest, outcome string) {
+	if outcome == "" {
+		return
+	}
+
+	access := dto.ShareAccess{
+		ShareID:   share.ID,
+		IP:        request.IP,
+		UserAgent: request.UserAgent,
+		Outcome:   outcome,
+	}
+	if err := s.repos.Log(ctx, access); err != nil {
+		logrus.Errorf("[share access log error] - %+v", err)
+	}
+}
+
+// admit checks that a share link still works and the password matches
+func admit(share dto.Share, password string) (string, error) {
+	if share.RevokedAt != nil {
+		return modules.ShareRevoked, modules.ErrShareGone
+	}
+
+	if time.Now().After(share.ExpiresAt) {
+		return modules.ShareExpired, modules.ErrShareGone
+	}
+
+	if share.PasswordHash != "" &&
+		bcrypt.CompareHashAndPassword([]byte(share.PasswordHash), []byte(password)) != nil {
+		return modules.SharePassword, modules.ErrSharePassword
+	}
+
+	return "", nil
+}
+
+// manageable returns a share link the caller created or whose document the caller owns
+func (s *Service) manageable(ctx context.Context, shareID uint) (dto.Share, error) {
+	userId, ok := ctx.Value(modules.UserID).(string)
+	if !ok {
+		return dto.Share{}, fmt.Errorf("unauthorized action is prohibited")
+	}
+
+	share, err := s.repos.Get(ctx, shareID)
+	if err != nil {
+		return share, err
+	}
+
+	if share.UserID != userId && share.CreatedBy != userId {
+		return dto.Share{}, modules.ErrForbidden
+	}
+
+	return share, nil
+}
+
+// hashToken is what a share link is stored and looked up by, a leaked table doesn't open links
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func newToken() (string, error) {
+	token := make([]byte, tokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// present fills the fields of a share link derived from what is stored,
+// the link only while its token is known
+func present(share dto.Share) dto.Share {
+	share.Protected = share.PasswordHash != ""
+	if share.Token != "" {
+		share.Link = fmt.Sprintf(linkPath, share.Token)
+	}
+	return share
+}
@@ -0,0 +1,74 @@
+package texts
+
+import (
+	"context"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/remote"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/extract"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/utils"
+	"time"
+)
+
+const batchSize = 20
+
+type Service struct {
+	repos   repository.TextRepository
+	remotes remote.DocumentsRemote
+}
+
+func NewService(repos repository.TextRepository, remotes remote.DocumentsRemote) *Service {
+	return &Service{
+		repos:   repos,
+		remotes: remotes,
+	}
+}
+
+func (s *Service) Process(ctx context.Context) (int, error) {
+	due, err := s.repos.ListDue(ctx, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var extracted int
+	for _, text := range due {
+		text.Content, err = s.extract(ctx, text.Document)
+		if err != nil {
+			logrus.Errorf("[extraction error]: %+v - %+v", text.DocumentID, err)
+
+			// the first attempt may run before the content is uploaded
+			text.Attempts++
+			text.LastError = err.Error()
+			text.RetryAt = time.Now().Add(utils.Backoff(text.Attempts))
+			if err = s.repos.Retry(ctx, text); err != nil {
+				return extracted, err
+			}
+			continue
+		}
+
+		if err = s.repos.Save(ctx, text); err != nil {
+			return extracted, err
+		}
+		extracted++
+	}
+
+	return extracted, nil
+}
+
+// extract reads the text of the current content of a document,
+// formats without text are indexed as empty so they aren't retried
+func (s *Service) extract(ctx context.Context, doc dto.Document) (string, error) {
+	doc, err := s.remotes.Get(ctx, doc)
+	if err != nil {
+		return "", err
+	}
+	defer doc.ResponseContent.Close()
+
+	text, err := extract.Text(doc.Extension, doc.Type, doc.ResponseContent)
+	if errors.Is(err, extract.ErrUnsupported) {
+		return "", nil
+	}
+	return text, err
+}
@@ -0,0 +1,180 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/common"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"sort"
+	"time"
+)
+
+type Service struct {
+	trees     repository.TreeRepository
+	documents repository.DocumentRepository
+	access    common.Access
+	retention time.Duration
+}
+
+func NewService(trees repository.TreeRepository, documents repository.DocumentRepository, access common.Access, cfg *modules.Trash) *Service {
+	return &Service{
+		trees:     trees,
+		documents: documents,
+		access:    access,
+		retention: cfg.Retention,
+	}
+}
+
+func (s *Service) List(ctx context.Context) ([]dto.TrashItem, error) {
+	userId, ok := ctx.Value(modules.UserID).(string)
+	if !ok {
+		return nil, fmt.Errorf("unauthorized action is prohibited")
+	}
+
+	trees, err := s.trees.ListTrash(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := s.documents.ListTrash(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.TrashItem, 0, len(trees)+len(docs))
+	for _, tree := range trees {
+		items = append(items, s.item(modules.TrashTree, tree.ID, tree.Name, tree.ParentID, tree.DeletedAt.Time))
+	}
+
+	for _, doc := range docs {
+		item := s.item(modules.TrashDocument, doc.ID, doc.Name+doc.Extension, doc.TreeID, doc.DeletedAt.Time)
+		// a document found without its content waits in the trash to be purged
+		item.Missing = doc.State == modules.DocumentMissing
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(*items[j].DeletedAt)
+	})
+
+	return items, nil
+}
+
+func (s *Service) Restore(ctx context.Context, item dto.TrashItem) (dto.TrashItem, error) {
+	userId, ok := ctx.Value(modules.UserID).(string)
+	if !ok {
+		return item, fmt.Errorf("unauthorized action is prohibited")
+	}
+
+	if err := s.destination(ctx, userId, item); err != nil {
+		return item, err
+	}
+
+	switch item.Kind {
+	case modules.TrashTree:
+		tree, err := s.trees.Restore(ctx, dto.Tree{ID: item.ID, UserID: userId})
+		if err != nil {
+			return item, err
+		}
+		return s.item(modules.TrashTree, tree.ID, tree.Name, tree.ParentID, time.Time{}), nil
+	case modules.TrashDocument:
+		doc, err := s.documents.Restore(ctx, dto.Document{ID: item.ID, UserID: userId})
+		if err != nil {
+			return item, err
+		}
+		return s.item(modules.TrashDocument, doc.ID, doc.Name+doc.Extension, doc.TreeID, time.Time{}), nil
+	}
+
+	return item, fmt.Errorf("unknown kind of trash item: %s", item.Kind)
+}
+
+func (s *Service) Purge(ctx context.Context) (dto.Purge, error) {
+	before := time.Now().Add(-s.retention)
+
+	docs, err := s.documents.ListExpired(ctx, before)
+	if err != nil {
+		return dto.Purge{}, err
+	}
+
+	ids := make([]uint, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.ID)
+	}
+
+	purge, err := s.documents.Purge(ctx, ids)
+	if err != nil {
+		return purge, err
+	}
+
+	trees, err := s.trees.PurgeExpired(ctx, before)
+	if err != nil {
+		return purge, err
+	}
+
+	purge.Folders += trees.Folders
+	purge.Documents += trees.Documents
+	purge.Bytes += trees.Bytes
+
+	return purge, nil
+}
+
+// destination checks that the caller may still edit the folder a trashed item returns to.
+// A folder that is gone or trashed itself sends the item to a root of the caller, a document
+// without its content has nothing to restore.
+func (s *Service) destination(ctx context.Context, userId string, item dto.TrashItem) error {
+	items, err := s.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, trashed := range items {
+		if trashed.Kind != item.Kind || trashed.ID != item.ID {
+			continue
+		}
+
+		if trashed.Missing {
+			return modules.ErrMissingContent
+		}
+
+		if trashed.ParentID == 0 {
+			return nil
+		}
+
+		owner, err := s.access.Owner(ctx, trashed.ParentID, modules.AccessEditor)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// the item stays with its owner, so it can't return into a tree of another user
+		if owner != userId {
+			return modules.ErrForbidden
+		}
+		return nil
+	}
+
+	return gorm.ErrRecordNotFound
+}
+
+func (s *Service) item(kind string, id uint, name string, parentID uint, deletedAt time.Time) dto.TrashItem {
+	item := dto.TrashItem{
+		ID:       id,
+		Kind:     kind,
+		Name:     name,
+		ParentID: parentID,
+	}
+
+	if !deletedAt.IsZero() {
+		purgeAt := deletedAt.Add(s.retention)
+		item.DeletedAt = &deletedAt
+		item.PurgeAt = &purgeAt
+	}
+
+	return item
+}
@@ -0,0 +1,77 @@
+package trash
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	repomocks "gitlab.com/a5805/ondeu/ondeu-back/internal/repository/mocks"
+	commonmocks "gitlab.com/a5805/ondeu/ondeu-back/internal/service/common/mocks"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestService_Purge(t *testing.T) {
+	// Init Dependencies
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	trees := repomocks.NewMockTreeRepository(c)
+	documents := repomocks.NewMockDocumentRepository(c)
+
+	// expired documents are purged, which marks them deleting and queues their objects
+	gomock.InOrder(
+		documents.EXPECT().
+			ListExpired(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, before time.Time) ([]dto.Document, error) {
+				assert.WithinDuration(t, time.Now().Add(-30*24*time.Hour), before, time.Second)
+				return []dto.Document{{ID: 7}, {ID: 8}}, nil
+			}),
+		documents.EXPECT().Purge(gomock.Any(), []uint{7, 8}).Return(dto.Purge{Documents: 2, Bytes: 10}, nil),
+		trees.EXPECT().PurgeExpired(gomock.Any(), gomock.Any()).Return(dto.Purge{Folders: 1, Documents: 1, Bytes: 5}, nil),
+	)
+
+	s := NewService(trees, documents, commonmocks.NewMockAccess(c), &modules.Trash{Retention: 30 * 24 * time.Hour})
+
+	// Test
+	purge, err := s.Purge(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, dto.Purge{Folders: 1, Documents: 3, Bytes: 15}, purge)
+}
+
+func TestService_Restore(t *testing.T) {
+	// Init Dependencies
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	trees := repomocks.NewMockTreeRepository(c)
+	documents := repomocks.NewMockDocumentRepository(c)
+	deleted := gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+	// a document the reconciler found without its content is listed as missing and stays in the trash
+	trees.EXPECT().ListTrash(gomock.Any(), "owner").Return(nil, nil).Times(2)
+	documents.EXPECT().ListTrash(gomock.Any(), "owner").
+		Return([]dto.Document{{ID: 7, Name: "essay", Extension: ".docx", DeletedAt: deleted, State: modules.DocumentMissing}}, nil).
+		Times(2)
+
+	s := NewService(trees, documents, commonmocks.NewMockAccess(c), &modules.Trash{Retention: 30 * 24 * time.Hour})
+	ctx := context.WithValue(context.Background(), modules.UserID, "owner")
+
+	// Test
+	items, err := s.List(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.True(t, items[0].Missing)
+
+	// Test
+	_, err = s.Restore(ctx, dto.TrashItem{ID: 7, Kind: modules.TrashDocument})
+
+	// Assert
+	assert.ErrorIs(t, err, modules.ErrMissingContent)
+}
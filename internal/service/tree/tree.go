@@ -1,20 +1,43 @@
 package tree
 
 import (
+	"archive/zip"
 	"context"
+	"errors"
 	"fmt"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/remote"
 	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/common"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/archive"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
 	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/upload"
+	"gorm.io/gorm"
+	"io"
+	"mime/multipart"
+	"os"
+	"path"
+	"strings"
 )
 
 type Service struct {
-	repos repository.TreeRepository
+	repos       repository.TreeRepository
+	documents   common.Documents
+	access      common.Access
+	remotes     remote.DocumentsRemote
+	permissions modules.Permissions
+	imports     *modules.Imports
 }
 
-func NewService(repos repository.TreeRepository) *Service {
+func NewService(repos repository.TreeRepository, documents common.Documents, access common.Access, remotes remote.DocumentsRemote, permissions modules.Permissions, imports *modules.Imports) *Service {
 	return &Service{
-		repos: repos,
+		repos:       repos,
+		documents:   documents,
+		access:      access,
+		remotes:     remotes,
+		permissions: permissions,
+		imports:     imports,
 	}
 }
 
@@ -25,14 +48,25 @@ func (s *Service) Create(ctx context.Context, in dto.Tree) (dto.Tree, error) {
 	}
 	in.UserID = userId
 
+	if in.ParentID != 0 {
+		owner, err := s.access.Owner(ctx, in.ParentID, modules.AccessEditor)
+		if err != nil {
+			return in, err
+		}
+		in.UserID = owner
+	}
+
+	if isTemplate(in) && !s.permissions.Allowed(ctx, modules.CreateTemplate) {
+		return in, modules.ErrForbidden
+	}
+
 	return s.repos.Create(ctx, in)
 }
 
 func (s *Service) Get(ctx context.Context, tree dto.Tree) (dto.Tree, error) {
-	//userId, ok := ctx.Value(modules.UserID).(string)
-	//if !ok {
-	//	return nil, fmt.Errorf("unauthorized action is prohibited")
-	//}
+	if _, err := s.access.Owner(ctx, tree.ID, modules.AccessViewer); err != nil {
+		return dto.Tree{}, err
+	}
 
 	tree, err := s.repos.Get(ctx, tree)
 	if err != nil {
@@ -49,6 +83,14 @@ func (s *Service) List(ctx context.Context, tree dto.Tree) ([]dto.Tree, error) {
 
 	tree.UserID = userId
 
+	if tree.ID != 0 {
+		owner, err := s.access.Owner(ctx, tree.ID, modules.AccessViewer)
+		if err != nil {
+			return nil, err
+		}
+		tree.UserID = owner
+	}
+
 	trees, err := s.repos.List(ctx, tree)
 	if err != nil {
 		return nil, err
@@ -57,27 +99,504 @@ func (s *Service) List(ctx context.Context, tree dto.Tree) ([]dto.Tree, error) {
 }
 
 func (s *Service) Delete(ctx context.Context, doc dto.Tree) (dto.Tree, error) {
-	userId, ok := ctx.Value(modules.UserID).(string)
-	if !ok {
-		return doc, fmt.Errorf("unauthorized action is prohibited")
+	owner, err := s.deleter(ctx, doc.ID, modules.AccessEditor)
+	if err != nil {
+		return doc, err
 	}
 
-	doc.UserID = userId
+	doc.UserID = owner
 
 	return s.repos.Delete(ctx, doc)
 }
 
+func (s *Service) Purge(ctx context.Context, tree dto.Tree) (dto.Purge, error) {
+	owner, err := s.deleter(ctx, tree.ID, modules.AccessOwner)
+	if err != nil {
+		return dto.Purge{}, err
+	}
+
+	tree.UserID = owner
+
+	return s.repos.Purge(ctx, tree)
+}
+
 func (s *Service) Update(ctx context.Context, doc dto.Tree) (dto.Tree, error) {
-	userId, ok := ctx.Value(modules.UserID).(string)
-	if !ok {
-		return doc, fmt.Errorf("unauthorized action is prohibited")
+	owner, err := s.access.Owner(ctx, doc.ID, modules.AccessEditor)
+	if err != nil {
+		return doc, err
 	}
 
-	doc.UserID = userId
+	doc.UserID = owner
+
+	if isTemplate(doc) && !s.permissions.Allowed(ctx, modules.CreateTemplate) {
+		return doc, modules.ErrForbidden
+	}
 
 	return s.repos.Update(ctx, doc)
 }
 
+func (s *Service) Move(ctx context.Context, tree dto.Tree, parentID uint) (dto.Tree, error) {
+	owner, err := s.access.Owner(ctx, tree.ID, modules.AccessEditor)
+	if err != nil {
+		return tree, err
+	}
+
+	// a tree stays with its owner, so it can't be moved into a tree of another user
+	if destination, err := s.destination(ctx, parentID); err != nil {
+		return tree, err
+	} else if destination != owner {
+		return tree, modules.ErrForbidden
+	}
+
+	tree.UserID = owner
+	tree.ParentID = parentID
+
+	return s.repos.Move(ctx, tree)
+}
+
+func (s *Service) Copy(ctx context.Context, tree dto.Tree, parentID uint) (dto.Tree, error) {
+	if _, err := s.access.Owner(ctx, tree.ID, modules.AccessViewer); err != nil {
+		return tree, err
+	}
+
+	source, err := s.repos.Get(ctx, dto.Tree{ID: tree.ID})
+	if err != nil {
+		return tree, err
+	}
+
+	owner, err := s.destination(ctx, parentID)
+	if err != nil {
+		return tree, err
+	}
+
+	return s.copy(ctx, source, parentID, owner, false)
+}
+
+// Archive lays out a tree with its subtrees and documents for a ZIP download,
+// narrowed to the selected documents when there are any. Documents not scanned clean are left out.
+func (s *Service) Archive(ctx context.Context, tree dto.Tree, selection dto.ArchiveSelection) (dto.Archive, error) {
+	owner, err := s.access.Owner(ctx, tree.ID, modules.AccessViewer)
+	if err != nil {
+		return dto.Archive{}, err
+	}
+
+	root, err := s.repos.Get(ctx, dto.Tree{ID: tree.ID})
+	if err != nil {
+		return dto.Archive{}, err
+	}
+
+	subtrees, err := s.repos.List(ctx, dto.Tree{ID: tree.ID, UserID: owner})
+	if err != nil {
+		return dto.Archive{}, err
+	}
+
+	root.Documents = nil
+	trees := append([]dto.Tree{root}, subtrees...)
+
+	docs, err := s.documents.ListByTree(ctx, s.GetTreeIDs(ctx, trees))
+	if err != nil {
+		return dto.Archive{}, err
+	}
+
+	if len(selection.DocumentIDs) > 0 {
+		if docs, err = selected(docs, selection.DocumentIDs); err != nil {
+			return dto.Archive{}, err
+		}
+	}
+
+	var entries []dto.ArchiveEntry
+	for _, entry := range archive.Layout(tree.ID, s.FormTree(ctx, trees, docs)) {
+		// a selection is archived without the folders left empty
+		if entry.Document == nil && len(selection.DocumentIDs) > 0 {
+			continue
+		}
+		if entry.Document == nil || entry.Document.ScanStatus == modules.ScanClean {
+			entries = append(entries, entry)
+		}
+	}
+
+	name := upload.SanitizeName(root.Name)
+	if name == "" {
+		name = "folder"
+	}
+
+	return dto.Archive{Name: name + archive.Extension, Entries: entries}, nil
+}
+
+// WriteArchive streams the documents of an archive as a ZIP, reading one object at a time
+func (s *Service) WriteArchive(ctx context.Context, folder dto.Archive, w io.Writer) error {
+	return archive.Write(w, folder.Entries, func(doc dto.Document) (io.ReadCloser, error) {
+		doc, err := s.remotes.Get(ctx, doc)
+		return doc.ResponseContent, err
+	})
+}
+
+// Import unpacks a ZIP archive into a tree, creating a subtree for each of its folders and
+// a document for each of its files. Files are checked like single uploads, a file breaking
+// the rules is reported as rejected and the rest of the archive is still imported.
+func (s *Service) Import(ctx context.Context, tree dto.Tree, file *multipart.FileHeader) (dto.ImportReport, error) {
+	report := dto.ImportReport{Entries: []dto.ImportEntry{}}
+
+	owner, err := s.access.Owner(ctx, tree.ID, modules.AccessEditor)
+	if err != nil {
+		return report, err
+	}
+
+	root, err := s.repos.Get(ctx, dto.Tree{ID: tree.ID})
+	if err != nil {
+		return report, err
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		return report, err
+	}
+	defer content.Close()
+
+	listing, err := archive.Read(content, file.Size, *s.imports)
+	if err != nil {
+		return report, err
+	}
+
+	for _, rejection := range listing.Rejected {
+		kind := modules.ImportDocument
+		if strings.HasSuffix(rejection.Path, "/") {
+			kind = modules.ImportFolder
+		}
+
+		report.Rejected++
+		report.Entries = append(report.Entries, dto.ImportEntry{
+			Path:   rejection.Path,
+			Kind:   kind,
+			Status: modules.ImportRejected,
+			Reason: rejection.Reason,
+		})
+	}
+
+	// folders come before their subfolders, so a parent is always created first.
+	// What is inside a folder that failed is reported as failed too.
+	folders := map[string]uint{"": root.ID}
+	for _, folder := range listing.Folders {
+		parent := path.Dir(folder)
+		if parent == "." {
+			parent = ""
+		}
+
+		if _, ok := folders[parent]; !ok {
+			failed(&report, folder+"/", modules.ImportFolder, reasonNoParent)
+			continue
+		}
+
+		created, err := s.repos.Create(ctx, dto.Tree{
+			UserID:   owner,
+			ParentID: folders[parent],
+			Name:     path.Base(folder),
+			Role:     root.Role,
+		})
+		if err != nil {
+			logrus.Errorf("[import error]: %+v - %+v", folder, err)
+			failed(&report, folder+"/", modules.ImportFolder, reasonFailed)
+			continue
+		}
+		folders[folder] = created.ID
+
+		report.Created++
+		report.Entries = append(report.Entries, dto.ImportEntry{
+			Path:   folder + "/",
+			Kind:   modules.ImportFolder,
+			Status: modules.ImportCreated,
+			ID:     created.ID,
+		})
+	}
+
+	for _, entry := range listing.Files {
+		treeID, ok := folders[entry.Folder]
+		if !ok {
+			failed(&report, entry.Path(), modules.ImportDocument, reasonNoParent)
+			continue
+		}
+
+		doc, err := s.importFile(ctx, treeID, entry)
+		imported := dto.ImportEntry{Path: entry.Path(), Kind: modules.ImportDocument, ID: doc.ID}
+
+		if err != nil {
+			if !rejected(err) {
+				logrus.Errorf("[import error]: %+v - %+v", entry.Path(), err)
+				failed(&report, entry.Path(), modules.ImportDocument, reasonFailed)
+				continue
+			}
+
+			var invalid *modules.ValidationError
+			if errors.As(err, &invalid) {
+				imported.Violations = invalid.Violations
+			}
+			imported.Status, imported.Reason = modules.ImportRejected, err.Error()
+			report.Rejected++
+		} else {
+			imported.Status = modules.ImportCreated
+			report.Created++
+		}
+		report.Entries = append(report.Entries, imported)
+	}
+
+	return report, nil
+}
+
+// importFile stores a file of an archive as a document of a tree. The file is unpacked
+// to disk first, as its content is read again to be scanned and uploaded.
+func (s *Service) importFile(ctx context.Context, treeID uint, file archive.File) (dto.Document, error) {
+	content, err := file.Open()
+	if err != nil {
+		return dto.Document{}, err
+	}
+	defer content.Close()
+
+	unpacked, err := os.CreateTemp("", "import-*")
+	if err != nil {
+		return dto.Document{}, err
+	}
+	defer os.Remove(unpacked.Name())
+	defer unpacked.Close()
+
+	if _, err = io.Copy(unpacked, content); err != nil {
+		return dto.Document{}, err
+	}
+	if _, err = unpacked.Seek(0, io.SeekStart); err != nil {
+		return dto.Document{}, err
+	}
+
+	return s.documents.Store(ctx, dto.Document{TreeID: treeID}, dto.UploadFile{Name: file.Name, Size: file.Size}, unpacked)
+}
+
+// Reasons an entry of an archive failed to be created, the errors themselves are logged
+const (
+	reasonFailed   = "the entry could not be created"
+	reasonNoParent = "the folder it is in could not be created"
+)
+
+// failed reports an entry of an archive that couldn't be created, the import goes on
+func failed(report *dto.ImportReport, path, kind, reason string) {
+	report.Failed++
+	report.Entries = append(report.Entries, dto.ImportEntry{
+		Path:   path,
+		Kind:   kind,
+		Status: modules.ImportFailed,
+		Reason: reason,
+	})
+}
+
+// rejected reports whether an error rejects a file of an archive for its content, rather than the file failing to be created
+func rejected(err error) bool {
+	var invalid *modules.ValidationError
+	return errors.As(err, &invalid) ||
+		errors.Is(err, modules.ErrQuota) ||
+		errors.Is(err, modules.ErrInfected) ||
+		errors.Is(err, zip.ErrChecksum) ||
+		errors.Is(err, zip.ErrFormat) ||
+		errors.Is(err, zip.ErrAlgorithm)
+}
+
+// selected keeps the documents with the ids, every id must be one of the documents
+func selected(docs []dto.Document, ids []uint) ([]dto.Document, error) {
+	byID := make(map[uint]dto.Document, len(docs))
+	for _, doc := range docs {
+		byID[doc.ID] = doc
+	}
+
+	kept := make([]dto.Document, 0, len(ids))
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		doc, ok := byID[id]
+		if !ok {
+			return nil, modules.ErrNotInTree
+		}
+		if !seen[id] {
+			seen[id] = true
+			kept = append(kept, doc)
+		}
+	}
+	return kept, nil
+}
+
+func (s *Service) ListTemplates(ctx context.Context, role string) ([]dto.Tree, error) {
+	roles, err := templateRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if role != "" {
+		if roles != nil && !contains(roles, role) {
+			return []dto.Tree{}, nil
+		}
+		roles = []string{role}
+	}
+
+	return s.repos.ListTemplates(ctx, roles)
+}
+
+func (s *Service) Instantiate(ctx context.Context, tree dto.Tree, parentID uint) (dto.Tree, error) {
+	roles, err := templateRoles(ctx)
+	if err != nil {
+		return tree, err
+	}
+
+	source, err := s.repos.Get(ctx, dto.Tree{ID: tree.ID})
+	if err != nil {
+		return tree, err
+	}
+
+	// templates of other roles are reported as missing
+	if source.UserID == "" || source.Template == nil || !*source.Template ||
+		(roles != nil && !contains(roles, source.Role)) {
+		return tree, gorm.ErrRecordNotFound
+	}
+
+	owner, err := s.destination(ctx, parentID)
+	if err != nil {
+		return tree, err
+	}
+
+	return s.copy(ctx, source, parentID, owner, true)
+}
+
+// copy deep-copies the subtree of source with its documents under the parent.
+// An instance of a template is a plain tree, so its copies lose the template flag.
+func (s *Service) copy(ctx context.Context, source dto.Tree, parentID uint, userID string, instance bool) (dto.Tree, error) {
+	// the subtree is read before anything is created,
+	// so copying a tree into itself doesn't copy the copy
+	descendants, err := s.repos.List(ctx, dto.Tree{ID: source.ID, UserID: source.UserID})
+	if err != nil {
+		return source, err
+	}
+
+	docs, err := s.documents.ListByTree(ctx, append([]uint{source.ID}, s.GetTreeIDs(ctx, descendants)...))
+	if err != nil {
+		return source, err
+	}
+
+	// the copies are placed under the copies of their parents by the repository
+	trees := make([]dto.Tree, 0, len(descendants))
+	for _, descendant := range descendants {
+		copied := duplicate(descendant, descendant.ParentID, userID, instance)
+		copied.ID = descendant.ID
+		trees = append(trees, copied)
+	}
+
+	root, copies, err := s.repos.Copy(ctx, duplicate(source, parentID, userID, instance), source.ID, trees)
+	if err != nil {
+		return root, err
+	}
+
+	for _, doc := range docs {
+		if instance {
+			doc.Template = new(bool)
+		}
+
+		if _, err = s.documents.Duplicate(ctx, doc, copies[doc.TreeID]); err != nil {
+			s.discard(ctx, root)
+			return root, err
+		}
+	}
+
+	return root, nil
+}
+
+// discard removes a copy that wasn't completed with the documents copied so far,
+// the error of the copy is the one reported
+func (s *Service) discard(ctx context.Context, tree dto.Tree) {
+	if _, err := s.repos.Delete(ctx, tree); err != nil {
+		logrus.Errorf("[discard error]: %+v - %+v", tree.ID, err)
+		return
+	}
+
+	if _, err := s.repos.Purge(ctx, tree); err != nil {
+		logrus.Errorf("[discard error]: %+v - %+v", tree.ID, err)
+	}
+}
+
+// destination returns the owner of the tree content is placed into,
+// a zero parent is the root of the caller
+func (s *Service) destination(ctx context.Context, parentID uint) (string, error) {
+	if parentID == 0 {
+		userId, ok := ctx.Value(modules.UserID).(string)
+		if !ok {
+			return "", fmt.Errorf("unauthorized action is prohibited")
+		}
+		return userId, nil
+	}
+
+	return s.access.Owner(ctx, parentID, modules.AccessEditor)
+}
+
+// deleter returns the user the caller deletes a tree as,
+// callers allowed to delete content of other users act as the owner of any tree
+func (s *Service) deleter(ctx context.Context, id uint, level string) (string, error) {
+	owner, err := s.access.Owner(ctx, id, level)
+	if err != nil && s.permissions.Allowed(ctx, modules.DeleteAnyContent) {
+		return s.ownerOf(ctx, id)
+	}
+	return owner, err
+}
+
+// ownerOf returns the owner of a tree, used by callers allowed to delete content of other users
+func (s *Service) ownerOf(ctx context.Context, id uint) (string, error) {
+	tree, err := s.repos.Get(ctx, dto.Tree{ID: id})
+	if err != nil {
+		return "", err
+	}
+	if tree.UserID == "" {
+		return "", gorm.ErrRecordNotFound
+	}
+	return tree.UserID, nil
+}
+
+// isTemplate reports whether a tree is marked as a template
+func isTemplate(tree dto.Tree) bool {
+	return tree.Template != nil && *tree.Template
+}
+
+// duplicate returns an unsaved copy of a tree placed under the parent
+func duplicate(tree dto.Tree, parentID uint, userID string, instance bool) dto.Tree {
+	copied := dto.Tree{
+		UserID:   userID,
+		ParentID: parentID,
+		Name:     tree.Name,
+		Role:     tree.Role,
+		Template: tree.Template,
+		Group:    tree.Group,
+	}
+
+	if instance {
+		copied.Template = new(bool)
+	}
+
+	return copied
+}
+
+// templateRoles returns the roles whose templates the caller may see,
+// nil means admins and managers who see every template
+func templateRoles(ctx context.Context) ([]string, error) {
+	roles, ok := ctx.Value(modules.Roles).([]string)
+	if !ok {
+		return nil, fmt.Errorf("unauthorized action is prohibited")
+	}
+
+	if contains(roles, modules.Admin) || contains(roles, modules.Manager) {
+		return nil, nil
+	}
+
+	return roles, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Service) GetTreeIDs(ctx context.Context, trees []dto.Tree) []uint {
 	var treeIds []uint
 	for _, tree := range trees {
@@ -0,0 +1,373 @@
+package uploads
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/remote"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/common"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/upload"
+	"io"
+	"time"
+)
+
+const batchSize = 100
+
+type Service struct {
+	repos       repository.UploadRepository
+	documents   repository.DocumentRepository
+	previews    repository.PreviewRepository
+	blobs       repository.BlobRepository
+	remotes     remote.DocumentsRemote
+	access      common.Access
+	quotas      common.Quotas
+	scanner     common.Scanner
+	policy      common.Policy
+	permissions modules.Permissions
+	cfg         *modules.UploadSessions
+}
+
+func NewService(repos repository.UploadRepository, documents repository.DocumentRepository, previews repository.PreviewRepository, blobs repository.BlobRepository, remotes remote.DocumentsRemote, access common.Access, quotas common.Quotas, scanner common.Scanner, policy common.Policy, permissions modules.Permissions, cfg *modules.UploadSessions) *Service {
+	return &Service{
+		repos:       repos,
+		documents:   documents,
+		previews:    previews,
+		blobs:       blobs,
+		remotes:     remotes,
+		access:      access,
+		quotas:      quotas,
+		scanner:     scanner,
+		policy:      policy,
+		permissions: permissions,
+		cfg:         cfg,
+	}
+}
+
+func (s *Service) Initiate(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error) {
+	userId, ok := ctx.Value(modules.UserID).(string)
+	if !ok {
+		return session, fmt.Errorf("unauthorized action is prohibited")
+	}
+
+	if _, err := s.owner(ctx, session); err != nil {
+		return session, err
+	}
+
+	// the content is sniffed from the first part, the size is known on completion
+	checked, err := s.policy.Check(ctx, session.TreeID, dto.UploadFile{Name: session.Name, Type: session.Type})
+	if err != nil {
+		return session, err
+	}
+
+	// the object key is derived from these, so they are fixed before the
+	// multipart upload starts and reused for the document on completion
+	session.ID = uuid.New()
+	session.UserID = userId
+	session.Path = uuid.New()
+	session.CreatedAt = time.Now()
+	session.ExpiresAt = session.CreatedAt.Add(s.cfg.Lifetime)
+	session.Name = checked.Name
+	session.Extension = checked.Extension
+	session.Type = checked.Type
+	if session.Type == "" {
+		session.Type = upload.OctetStream
+	}
+
+	session, err = s.remotes.InitiateUpload(ctx, session)
+	if err != nil {
+		return session, err
+	}
+
+	return s.repos.Create(ctx, session)
+}
+
+func (s *Service) Get(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error) {
+	userId, ok := ctx.Value(modules.UserID).(string)
+	if !ok {
+		return session, fmt.Errorf("unauthorized action is prohibited")
+	}
+
+	session.UserID = userId
+
+	return s.repos.Get(ctx, session)
+}
+
+func (s *Service) List(ctx context.Context, session dto.UploadSession) ([]dto.UploadSession, error) {
+	userId, ok := ctx.Value(modules.UserID).(string)
+	if !ok {
+		return nil, fmt.Errorf("unauthorized action is prohibited")
+	}
+
+	session.UserID = userId
+
+	return s.repos.List(ctx, session)
+}
+
+func (s *Service) PutPart(ctx context.Context, session dto.UploadSession, part dto.UploadPart) (dto.UploadPart, error) {
+	if part.Number < modules.MinUploadPart || part.Number > modules.MaxUploadPart {
+		return part, fmt.Errorf("part number must be between %d and %d", modules.MinUploadPart, modules.MaxUploadPart)
+	}
+
+	stored, err := s.Get(ctx, session)
+	if err != nil {
+		return part, err
+	}
+
+	part.SessionID = stored.ID
+
+	owner, err := s.owner(ctx, stored)
+	if err != nil {
+		return part, err
+	}
+
+	// the parts received so far count towards the size limit and the quota,
+	// a part sent again replaces the one received before
+	file := dto.UploadFile{Name: stored.Name, Type: stored.Type, Size: part.Size}
+	for _, received := range stored.Parts {
+		if received.Number != part.Number {
+			file.Size += received.Size
+		}
+	}
+
+	// the first part starts the file, so its type is sniffed from it
+	if part.Number == modules.MinUploadPart {
+		if file.Head, err = upload.Head(part.RequestContent); err != nil {
+			return part, err
+		}
+	}
+
+	if _, err = s.policy.Check(ctx, stored.TreeID, file); err != nil {
+		return part, err
+	}
+
+	if err = s.quotas.Check(ctx, owner, file.Size); err != nil {
+		return part, err
+	}
+
+	part, err = s.remotes.UploadPart(ctx, stored, part)
+	if err != nil {
+		return part, err
+	}
+
+	return s.repos.SavePart(ctx, part)
+}
+
+func (s *Service) Complete(ctx context.Context, session dto.UploadSession) (dto.Document, error) {
+	stored, err := s.Get(ctx, session)
+	if err != nil {
+		return dto.Document{}, err
+	}
+
+	if len(stored.Parts) == 0 {
+		return dto.Document{}, fmt.Errorf("upload has no parts")
+	}
+
+	var size int64
+	for i, part := range stored.Parts {
+		if part.Number != int64(i+1) {
+			return dto.Document{}, fmt.Errorf("part %d is missing", i+1)
+		}
+		size += part.Size
+	}
+
+	// the access may have changed since the session started, so it is resolved again
+	owner, err := s.owner(ctx, stored)
+	if err != nil {
+		return dto.Document{}, err
+	}
+
+	if _, err = s.policy.Check(ctx, stored.TreeID, dto.UploadFile{Name: stored.Name, Type: stored.Type, Size: size}); err != nil {
+		return dto.Document{}, err
+	}
+
+	// the session stays open, so the caller can free space and complete it again
+	if err = s.quotas.Check(ctx, owner, size); err != nil {
+		return dto.Document{}, err
+	}
+
+	// the document is written before the parts are assembled, so an object in
+	// spaces always has a row, the outbox settles it if this request stops midway
+	document, err := s.documents.Create(ctx, dto.Document{
+		UserID:    owner,
+		TreeID:    stored.TreeID,
+		CreatedAt: stored.CreatedAt,
+		Name:      stored.Name,
+		Extension: stored.Extension,
+		Size:      size,
+		Type:      stored.Type,
+		Path:      stored.Path,
+		Template:  stored.Template,
+		State:     modules.DocumentUploading,
+	})
+	if err != nil {
+		return document, err
+	}
+
+	// the key of the session is queued for deletion with the document,
+	// so the session is aborted instead of being completed again
+	if _, err = s.remotes.CompleteUpload(ctx, stored); err != nil {
+		common.Discard(ctx, s.documents, document)
+		s.abort(ctx, stored)
+		return document, err
+	}
+
+	document = common.Settle(ctx, s.documents, document)
+
+	// the chunks are assembled, so the session is over whatever the scan finds
+	if err = s.repos.Delete(ctx, stored); err != nil {
+		return document, err
+	}
+
+	document, checksum, err := s.scan(ctx, document)
+	if err != nil {
+		return document, err
+	}
+
+	document = s.deduplicate(ctx, document, checksum)
+
+	return document, s.previews.Queue(ctx, document.ID)
+}
+
+// owner returns the owner of the tree a session uploads into, the session itself
+// stays with the caller. Only callers allowed to create templates upload one.
+func (s *Service) owner(ctx context.Context, session dto.UploadSession) (string, error) {
+	owner, err := s.access.Owner(ctx, session.TreeID, modules.AccessEditor)
+	if err != nil {
+		return "", err
+	}
+
+	if session.Template != nil && *session.Template && !s.permissions.Allowed(ctx, modules.CreateTemplate) {
+		return "", modules.ErrForbidden
+	}
+
+	return owner, nil
+}
+
+// scan stores the result of the malware scan of an assembled document and returns the
+// checksum of its content, hashed on the same read, empty when it couldn't be read whole.
+// The content is in spaces already, so a document that can't be scanned is purged with it.
+func (s *Service) scan(ctx context.Context, doc dto.Document) (dto.Document, string, error) {
+	content, err := s.remotes.Get(ctx, doc)
+	if err != nil {
+		common.Discard(ctx, s.documents, doc)
+		return doc, "", err
+	}
+	defer content.ResponseContent.Close()
+
+	hash := sha256.New()
+	body := io.TeeReader(content.ResponseContent, hash)
+
+	if doc, err = common.Scan(ctx, s.scanner, s.documents, doc, body); err != nil {
+		return doc, "", err
+	}
+
+	// the scanner may stop reading once it is sure, the rest is hashed all the same
+	if _, err = io.Copy(io.Discard, body); err != nil {
+		logrus.Errorf("[checksum error]: %+v - %+v", doc.ID, err)
+		return doc, "", nil
+	}
+
+	return doc, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// deduplicate points an assembled document at the blob of its content, copying its object
+// to the blob when no upload stored it yet. The document works under its own key as well,
+// so it is kept as it is when that fails.
+func (s *Service) deduplicate(ctx context.Context, doc dto.Document, checksum string) dto.Document {
+	if checksum == "" {
+		return doc
+	}
+
+	blob, err := s.blobs.Acquire(ctx, dto.Blob{Checksum: checksum, Size: doc.Size})
+	if err != nil {
+		logrus.Errorf("[blob error]: %+v - %+v", checksum, err)
+		return doc
+	}
+
+	shared := doc
+	shared.Checksum = checksum
+
+	if !blob.Stored {
+		if _, err = s.remotes.Copy(ctx, s.remotes.Key(doc), shared); err != nil {
+			logrus.Errorf("[blob error]: %+v - %+v", checksum, err)
+			s.release(ctx, checksum)
+			return doc
+		}
+
+		if err = s.blobs.Stored(ctx, checksum); err != nil {
+			logrus.Errorf("[blob error]: %+v - %+v", checksum, err)
+			s.release(ctx, checksum)
+			return doc
+		}
+	}
+
+	if shared, err = s.documents.Deduplicate(ctx, shared); err != nil {
+		logrus.Errorf("[blob error]: %+v - %+v", checksum, err)
+		s.release(ctx, checksum)
+		return doc
+	}
+
+	return shared
+}
+
+// release drops a reference taken for a document that wasn't pointed at its blob
+func (s *Service) release(ctx context.Context, checksum string) {
+	if err := s.blobs.Release(ctx, checksum); err != nil {
+		logrus.Errorf("[blob error]: %+v - %+v", checksum, err)
+	}
+}
+
+// abort ends a session whose parts can't be assembled,
+// the error of the completion is the one reported
+func (s *Service) abort(ctx context.Context, session dto.UploadSession) {
+	if _, err := s.remotes.AbortUpload(ctx, session); err != nil {
+		logrus.Errorf("[abort error]: %+v - %+v", session.ID, err)
+	}
+
+	if err := s.repos.Delete(ctx, session); err != nil {
+		logrus.Errorf("[abort error]: %+v - %+v", session.ID, err)
+	}
+}
+
+// Expire aborts the sessions that outlived their lifetime, so the parts of uploads nobody
+// completes don't stay in spaces. A session that can't be aborted is tried on the next run.
+func (s *Service) Expire(ctx context.Context) (int, error) {
+	expired, err := s.repos.ListExpired(ctx, time.Now(), batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var aborted int
+	for _, session := range expired {
+		if _, err = s.remotes.AbortUpload(ctx, session); err != nil {
+			logrus.Errorf("[expiry error]: %+v - %+v", session.ID, err)
+			continue
+		}
+
+		if err = s.repos.Delete(ctx, session); err != nil {
+			return aborted, err
+		}
+		aborted++
+	}
+
+	return aborted, nil
+}
+
+func (s *Service) Abort(ctx context.Context, session dto.UploadSession) (dto.UploadSession, error) {
+	stored, err := s.Get(ctx, session)
+	if err != nil {
+		return stored, err
+	}
+
+	if _, err = s.remotes.AbortUpload(ctx, stored); err != nil {
+		return stored, err
+	}
+
+	return stored, s.repos.Delete(ctx, stored)
+}
@@ -0,0 +1,47 @@
+package uploads
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	remotemocks "gitlab.com/a5805/ondeu/ondeu-back/internal/remote/mocks"
+	repomocks "gitlab.com/a5805/ondeu/ondeu-back/internal/repository/mocks"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"testing"
+	"time"
+)
+
+func TestService_Expire(t *testing.T) {
+	// Init Dependencies
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	repos := repomocks.NewMockUploadRepository(c)
+	remotes := remotemocks.NewMockDocumentsRemote(c)
+
+	stale := dto.UploadSession{ID: uuid.New()}
+	stuck := dto.UploadSession{ID: uuid.New()}
+
+	// a session whose parts can't be aborted keeps its row for the next run
+	repos.EXPECT().
+		ListExpired(gomock.Any(), gomock.Any(), batchSize).
+		DoAndReturn(func(_ context.Context, before time.Time, _ int) ([]dto.UploadSession, error) {
+			assert.WithinDuration(t, time.Now(), before, time.Second)
+			return []dto.UploadSession{stale, stuck}, nil
+		})
+	remotes.EXPECT().AbortUpload(gomock.Any(), stale).Return(stale, nil)
+	remotes.EXPECT().AbortUpload(gomock.Any(), stuck).Return(stuck, errors.New("spaces are unavailable"))
+	repos.EXPECT().Delete(gomock.Any(), stale).Return(nil)
+
+	s := NewService(repos, nil, nil, nil, remotes, nil, nil, nil, nil, nil, &modules.UploadSessions{Lifetime: 24 * time.Hour})
+
+	// Test
+	aborted, err := s.Expire(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, aborted)
+}
@@ -0,0 +1,340 @@
+package versions
+
+import (
+	"context"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/remote"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/repository"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service/common"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/upload"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/utils"
+	"gorm.io/gorm"
+	"io"
+	"mime/multipart"
+)
+
+type Service struct {
+	documents repository.DocumentRepository
+	versions  repository.VersionRepository
+	previews  repository.PreviewRepository
+	blobs     repository.BlobRepository
+	remotes   remote.DocumentsRemote
+	access    common.Access
+	quotas    common.Quotas
+	scanner   common.Scanner
+	policy    common.Policy
+}
+
+func NewService(documents repository.DocumentRepository, versions repository.VersionRepository, previews repository.PreviewRepository, blobs repository.BlobRepository, remotes remote.DocumentsRemote, access common.Access, quotas common.Quotas, scanner common.Scanner, policy common.Policy) *Service {
+	return &Service{
+		documents: documents,
+		versions:  versions,
+		previews:  previews,
+		blobs:     blobs,
+		remotes:   remotes,
+		access:    access,
+		quotas:    quotas,
+		scanner:   scanner,
+		policy:    policy,
+	}
+}
+
+func (s *Service) Create(ctx context.Context, doc dto.Document, file *multipart.FileHeader) (dto.DocumentVersion, error) {
+	stored, err := s.document(ctx, doc, modules.AccessEditor)
+	if err != nil {
+		return dto.DocumentVersion{}, err
+	}
+
+	// blocked content is deleted rather than replaced,
+	// its history could bring it back
+	if stored.ScanStatus != modules.ScanClean {
+		return dto.DocumentVersion{}, modules.ErrNotClean
+	}
+
+	history, err := s.versions.List(ctx, stored.ID)
+	if err != nil {
+		return dto.DocumentVersion{}, err
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		return dto.DocumentVersion{}, err
+	}
+	defer content.Close()
+
+	head, err := upload.Head(content)
+	if err != nil {
+		return dto.DocumentVersion{}, err
+	}
+
+	checked, err := s.policy.Check(ctx, doc.TreeID, dto.UploadFile{
+		Name: file.Filename,
+		Type: file.Header.Get("Content-Type"),
+		Size: file.Size,
+		Head: head,
+	})
+	if err != nil {
+		return dto.DocumentVersion{}, err
+	}
+
+	// the new version becomes the current content, which counts once as a version,
+	// the snapshot of a document without history moves its content into the history
+	if err = s.quotas.Check(ctx, stored.UserID, file.Size); err != nil {
+		return dto.DocumentVersion{}, err
+	}
+
+	// infected content is refused before anything is stored
+	if err = common.Check(ctx, s.scanner, content); err != nil {
+		return dto.DocumentVersion{}, err
+	}
+
+	if _, err = content.Seek(0, io.SeekStart); err != nil {
+		return dto.DocumentVersion{}, err
+	}
+
+	// documents uploaded before their first new version have no history yet,
+	// so the current content is kept as a version before it is overwritten
+	if len(history) == 0 {
+		// a snapshot of a blob is one more reference to it instead of a copy
+		if stored.Checksum != "" {
+			if _, err = s.blobs.Acquire(ctx, dto.Blob{Checksum: stored.Checksum, Size: stored.Size}); err != nil {
+				return dto.DocumentVersion{}, err
+			}
+		}
+
+		snapshot, err := s.versions.Create(ctx, current(stored))
+		if err != nil {
+			s.release(ctx, stored.Checksum)
+			return snapshot, err
+		}
+
+		if _, err = s.remotes.SnapshotVersion(ctx, stored, snapshot); err != nil {
+			s.remove(ctx, snapshot)
+			return snapshot, err
+		}
+
+		history = append(history, snapshot)
+	}
+
+	version := dto.DocumentVersion{
+		DocumentID:     stored.ID,
+		UserID:         stored.UserID,
+		Name:           checked.Name,
+		Extension:      checked.Extension,
+		Size:           file.Size,
+		Type:           checked.Type,
+		RequestContent: content,
+	}
+
+	version.Checksum, err = utils.ChecksumSeeker(content)
+	if err != nil {
+		return version, err
+	}
+
+	blob, err := s.blobs.Acquire(ctx, dto.Blob{Checksum: version.Checksum, Size: version.Size})
+	if err != nil {
+		return version, err
+	}
+
+	version, err = s.versions.Create(ctx, version)
+	if err != nil {
+		s.release(ctx, blob.Checksum)
+		return version, err
+	}
+
+	// identical content is stored once, the version goes with its reference when it can't be
+	if !blob.Stored {
+		if _, err = s.remotes.UploadVersion(ctx, stored, version); err != nil {
+			s.remove(ctx, version)
+			return version, err
+		}
+
+		if err = s.blobs.Stored(ctx, blob.Checksum); err != nil {
+			return version, err
+		}
+	}
+
+	if err = s.promote(ctx, stored, version); err != nil {
+		return version, err
+	}
+
+	version.Current = true
+
+	return version, nil
+}
+
+func (s *Service) List(ctx context.Context, doc dto.Document) ([]dto.DocumentVersion, error) {
+	stored, err := s.document(ctx, doc, modules.AccessViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.versions.List(ctx, stored.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(history) == 0 {
+		return []dto.DocumentVersion{current(stored)}, nil
+	}
+
+	for i := range history {
+		history[i].Current = history[i].Version == stored.Version
+	}
+
+	return history, nil
+}
+
+func (s *Service) Get(ctx context.Context, doc dto.Document, number uint) (dto.DocumentVersion, error) {
+	stored, err := s.document(ctx, doc, modules.AccessViewer)
+	if err != nil {
+		return dto.DocumentVersion{}, err
+	}
+
+	if stored.ScanStatus != modules.ScanClean {
+		return dto.DocumentVersion{}, modules.ErrNotClean
+	}
+
+	version, err := s.versions.Get(ctx, dto.DocumentVersion{DocumentID: stored.ID, Version: number})
+	if errors.Is(err, gorm.ErrRecordNotFound) && number == stored.Version {
+		// a document without history only has its current content
+		stored.Download = doc.Download
+
+		content, err := s.remotes.Get(ctx, stored)
+		if err != nil {
+			return dto.DocumentVersion{}, err
+		}
+
+		version = current(stored)
+		version.ResponseContent = content.ResponseContent
+		version.Download = content.Download
+
+		return version, nil
+	}
+	if err != nil {
+		return version, err
+	}
+
+	version.Current = version.Version == stored.Version
+	version.Download = doc.Download
+
+	return s.remotes.GetVersion(ctx, stored, version)
+}
+
+func (s *Service) Restore(ctx context.Context, doc dto.Document, number uint) (dto.Document, error) {
+	stored, err := s.document(ctx, doc, modules.AccessEditor)
+	if err != nil {
+		return stored, err
+	}
+
+	if number == stored.Version {
+		return stored, nil
+	}
+
+	version, err := s.versions.Get(ctx, dto.DocumentVersion{DocumentID: stored.ID, Version: number})
+	if err != nil {
+		return stored, err
+	}
+
+	if err = s.promote(ctx, stored, version); err != nil {
+		return stored, err
+	}
+
+	stored.Size = version.Size
+	stored.Type = version.Type
+	stored.Version = version.Version
+	stored.Checksum = version.Checksum
+
+	return stored, nil
+}
+
+// document returns a stored document from a tree the caller has at least the level on
+func (s *Service) document(ctx context.Context, doc dto.Document, level string) (dto.Document, error) {
+	owner, err := s.access.Owner(ctx, doc.TreeID, level)
+	if err != nil {
+		return doc, err
+	}
+
+	doc.UserID = owner
+
+	return s.documents.Get(ctx, doc)
+}
+
+// promote makes a version the current content of a document. A document follows
+// the blob of its version, so promoting a blob is a reference instead of a copy.
+func (s *Service) promote(ctx context.Context, doc dto.Document, version dto.DocumentVersion) error {
+	previous := doc.Checksum
+	doc.Checksum = version.Checksum
+	doc.Extension = version.Extension
+
+	if _, err := s.remotes.PromoteVersion(ctx, doc, version); err != nil {
+		return err
+	}
+
+	if doc.Checksum != "" {
+		if _, err := s.blobs.Acquire(ctx, dto.Blob{Checksum: doc.Checksum, Size: version.Size}); err != nil {
+			return err
+		}
+	}
+
+	doc.Size = version.Size
+	doc.Type = version.Type
+	doc.Version = version.Version
+
+	if _, err := s.documents.UpdateContent(ctx, doc); err != nil {
+		s.release(ctx, doc.Checksum)
+		return err
+	}
+
+	if previous != "" {
+		if err := s.blobs.Release(ctx, previous); err != nil {
+			return err
+		}
+	}
+
+	// the thumbnails show the old content until they are built again
+	return s.previews.Queue(ctx, doc.ID)
+}
+
+func current(doc dto.Document) dto.DocumentVersion {
+	version := doc.Version
+	if version == 0 {
+		version = 1
+	}
+
+	return dto.DocumentVersion{
+		DocumentID: doc.ID,
+		UserID:     doc.UserID,
+		Version:    version,
+		CreatedAt:  doc.UpdatedAt,
+		Name:       doc.Name,
+		Extension:  doc.Extension,
+		Size:       doc.Size,
+		Type:       doc.Type,
+		Checksum:   doc.Checksum,
+		Current:    true,
+	}
+}
+
+// remove deletes a version whose content didn't reach spaces, releasing its blob,
+// the error of the upload is the one reported
+func (s *Service) remove(ctx context.Context, version dto.DocumentVersion) {
+	if err := s.versions.Remove(ctx, version); err != nil {
+		logrus.Errorf("[version error]: %+v - %+v", version.DocumentID, err)
+	}
+}
+
+// release drops a reference taken for a row that wasn't written,
+// the error of the write is the one reported
+func (s *Service) release(ctx context.Context, checksum string) {
+	if checksum == "" {
+		return
+	}
+
+	if err := s.blobs.Release(ctx, checksum); err != nil {
+		logrus.Errorf("[blob error]: %+v - %+v", checksum, err)
+	}
+}
@@ -0,0 +1,107 @@
+package versions
+
+import (
+	"bytes"
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	remotemocks "gitlab.com/a5805/ondeu/ondeu-back/internal/remote/mocks"
+	repomocks "gitlab.com/a5805/ondeu/ondeu-back/internal/repository/mocks"
+	commonmocks "gitlab.com/a5805/ondeu/ondeu-back/internal/service/common/mocks"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"mime/multipart"
+	"testing"
+)
+
+// formFile builds the file header of a multipart form holding content
+func formFile(t *testing.T, name, content string) *multipart.FileHeader {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", name)
+	assert.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	form, err := multipart.NewReader(&body, w.Boundary()).ReadForm(1 << 20)
+	assert.NoError(t, err)
+
+	return form.File["file"][0]
+}
+
+func TestService_Usage(t *testing.T) {
+	// Init Dependencies
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	documents := repomocks.NewMockDocumentRepository(c)
+	versions := repomocks.NewMockVersionRepository(c)
+	previews := repomocks.NewMockPreviewRepository(c)
+	blobs := repomocks.NewMockBlobRepository(c)
+	remotes := remotemocks.NewMockDocumentsRemote(c)
+	access := commonmocks.NewMockAccess(c)
+	quotas := commonmocks.NewMockQuotas(c)
+	scanner := commonmocks.NewMockScanner(c)
+	policy := commonmocks.NewMockPolicy(c)
+
+	// the usage of the owner follows the quota checks that let content in:
+	// the upload of the document counted its 5 bytes
+	usage := int64(5)
+	stored := dto.Document{ID: 7, TreeID: 1, UserID: "owner", Name: "notes", Extension: ".txt", Size: 5, Version: 1, ScanStatus: modules.ScanClean}
+	file := formFile(t, "notes.txt", "new content")
+
+	access.EXPECT().Owner(gomock.Any(), uint(1), gomock.Any()).Return("owner", nil).AnyTimes()
+	documents.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ dto.Document) (dto.Document, error) {
+		return stored, nil
+	}).AnyTimes()
+	quotas.EXPECT().Check(gomock.Any(), "owner", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, bytes int64) error {
+		usage += bytes
+		return nil
+	}).AnyTimes()
+
+	// the new version moves the current content into the history and becomes the current content
+	versions.EXPECT().List(gomock.Any(), uint(7)).Return(nil, nil)
+	policy.EXPECT().Check(gomock.Any(), uint(1), gomock.Any()).Return(dto.UploadFile{Name: "notes", Extension: ".txt", Type: "text/plain"}, nil)
+	scanner.EXPECT().Scan(gomock.Any(), gomock.Any()).Return("", nil)
+	versions.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, version dto.DocumentVersion) (dto.DocumentVersion, error) {
+		if version.Version == 0 {
+			version.Version = 2
+		}
+		return version, nil
+	}).Times(2)
+	remotes.EXPECT().SnapshotVersion(gomock.Any(), gomock.Any(), gomock.Any()).Return(dto.DocumentVersion{}, nil)
+	blobs.EXPECT().Acquire(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, blob dto.Blob) (dto.Blob, error) {
+		blob.Stored = true
+		return blob, nil
+	}).AnyTimes()
+	blobs.EXPECT().Release(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	remotes.EXPECT().PromoteVersion(gomock.Any(), gomock.Any(), gomock.Any()).Return(dto.Document{}, nil).Times(2)
+	documents.EXPECT().UpdateContent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, doc dto.Document) (dto.Document, error) {
+		stored = doc
+		return doc, nil
+	}).Times(2)
+	previews.EXPECT().Queue(gomock.Any(), uint(7)).Return(nil).Times(2)
+
+	// restoring the first version brings back content kept in the history
+	versions.EXPECT().Get(gomock.Any(), dto.DocumentVersion{DocumentID: 7, Version: 1}).
+		Return(dto.DocumentVersion{DocumentID: 7, UserID: "owner", Version: 1, Extension: ".txt", Size: 5}, nil)
+
+	s := NewService(documents, versions, previews, blobs, remotes, access, quotas, scanner, policy)
+
+	// Test
+	version, err := s.Create(context.Background(), dto.Document{ID: 7, TreeID: 1}, file)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), version.Version)
+	assert.Equal(t, int64(5+11), usage)
+
+	// Test
+	restored, err := s.Restore(context.Background(), dto.Document{ID: 7, TreeID: 1}, 1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), restored.Version)
+	assert.Equal(t, int64(5+11), usage)
+}
@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
+	"time"
+)
+
+// NewDeleter periodically removes queued objects from spaces
+func NewDeleter(deletions service.DeletionService, interval time.Duration) *Job {
+	return Periodic("deletion", func(ctx context.Context) error {
+		deleted, err := deletions.Process(ctx)
+		if deleted > 0 {
+			logrus.Infof("[deleted from spaces]: %d objects", deleted)
+		}
+		return err
+	}, interval)
+}
@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
+	"time"
+)
+
+// NewExpirer periodically aborts the upload sessions nobody completed in time
+func NewExpirer(uploads service.UploadService, interval time.Duration) *Job {
+	return Periodic("expiry", func(ctx context.Context) error {
+		aborted, err := uploads.Expire(ctx)
+		if aborted > 0 {
+			logrus.Infof("[expired uploads]: %d sessions", aborted)
+		}
+		return err
+	}, interval)
+}
@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
+	"time"
+)
+
+// NewExtractor periodically extracts the text of queued documents for search
+func NewExtractor(texts service.TextService, interval time.Duration) *Job {
+	return Periodic("extraction", func(ctx context.Context) error {
+		extracted, err := texts.Process(ctx)
+		if extracted > 0 {
+			logrus.Infof("[extracted text]: %d documents", extracted)
+		}
+		return err
+	}, interval)
+}
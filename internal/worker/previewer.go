@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
+	"time"
+)
+
+// NewPreviewer periodically builds thumbnails of queued documents
+func NewPreviewer(previews service.PreviewService, interval time.Duration) *Job {
+	return Periodic("preview", func(ctx context.Context) error {
+		previewed, err := previews.Process(ctx)
+		if previewed > 0 {
+			logrus.Infof("[previewed]: %d documents", previewed)
+		}
+		return err
+	}, interval)
+}
@@ -0,0 +1,21 @@
+package worker
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
+	"time"
+)
+
+// NewPurger periodically removes expired items from the trash
+func NewPurger(trash service.TrashService, interval time.Duration) *Job {
+	return Periodic("purge", func(ctx context.Context) error {
+		purged, err := trash.Purge(ctx)
+		if err != nil {
+			return err
+		}
+
+		logrus.Infof("[purged from trash]: %+v", purged)
+		return nil
+	}, interval)
+}
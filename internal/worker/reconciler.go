@@ -0,0 +1,30 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+)
+
+// NewReconciler periodically compares spaces with the database. Listing the whole bucket
+// is slow, so unlike the other jobs it waits for the first tick instead of running at start.
+func NewReconciler(reconcile service.ReconcileService, cfg *modules.Reconcile) *Job {
+	return Periodic("reconcile", func(ctx context.Context) error {
+		report, err := reconcile.Reconcile(ctx, cfg.Repair)
+		if errors.Is(err, modules.ErrReconciling) {
+			// another replica has the run
+			logrus.Debugf("[reconcile skipped]: %+v", err)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(report.Mismatches) > 0 {
+			logrus.Warnf("[reconciled spaces]: %d mismatches, %d repaired, dry run %t", len(report.Mismatches), report.Repaired, report.DryRun)
+		}
+		return nil
+	}, cfg.Interval).Delayed()
+}
@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/a5805/ondeu/ondeu-back/internal/service"
+	"time"
+)
+
+// NewRelay periodically settles the outbox events requests left behind
+func NewRelay(outbox service.OutboxService, interval time.Duration) *Job {
+	return Periodic("outbox", func(ctx context.Context) error {
+		settled, err := outbox.Process(ctx)
+		if settled > 0 {
+			logrus.Infof("[settled outbox]: %d events", settled)
+		}
+		return err
+	}, interval)
+}
@@ -0,0 +1,62 @@
+// Package worker runs the periodic jobs of the api next to the http server
+package worker
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// Job runs a function periodically until its context is cancelled
+type Job struct {
+	name     string
+	fn       func(ctx context.Context) error
+	interval time.Duration
+	delayed  bool
+}
+
+// Periodic builds a job running fn at start and on every tick of the interval. A failed
+// run is logged under the name of the job and the next tick runs it again.
+func Periodic(name string, fn func(ctx context.Context) error, interval time.Duration) *Job {
+	return &Job{
+		name:     name,
+		fn:       fn,
+		interval: interval,
+	}
+}
+
+// Delayed makes a job wait for the first tick instead of running at start
+func (j *Job) Delayed() *Job {
+	j.delayed = true
+	return j
+}
+
+// Run runs the job on every tick until the context is cancelled
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	if j.delayed && !wait(ctx, ticker) {
+		return
+	}
+
+	for {
+		if err := j.fn(ctx); err != nil {
+			logrus.Errorf("[%s error]: %+v", j.name, err)
+		}
+
+		if !wait(ctx, ticker) {
+			return
+		}
+	}
+}
+
+// wait blocks until the next tick and reports false once the context is cancelled
+func wait(ctx context.Context, ticker *time.Ticker) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-ticker.C:
+		return true
+	}
+}
@@ -0,0 +1,143 @@
+package archive
+
+import (
+	"archive/zip"
+	"fmt"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/upload"
+	"io"
+	"path"
+	"strings"
+)
+
+// Extension is the extension of a downloaded archive
+const Extension = ".zip"
+
+// Layout places a folder and its subfolders with their documents at paths named
+// after them. The trees are the folder followed by its subfolders, with documents
+// attached the way FormTree does. Names repeated within a folder are numbered.
+func Layout(rootID uint, trees []dto.Tree) []dto.ArchiveEntry {
+	byID := make(map[uint]dto.Tree, len(trees))
+	for _, tree := range trees {
+		byID[tree.ID] = tree
+	}
+
+	taken := map[string]map[string]bool{}
+	folders := map[uint]string{rootID: ""}
+
+	// the path of a folder needs the path of its parent, which may come later in the slice
+	var folderOf func(id uint) (string, bool)
+	folderOf = func(id uint) (string, bool) {
+		if folder, ok := folders[id]; ok {
+			return folder, true
+		}
+		tree, ok := byID[id]
+		if !ok || tree.ParentID == id {
+			return "", false
+		}
+		parent, ok := folderOf(tree.ParentID)
+		if !ok {
+			return "", false
+		}
+		folders[id] = parent + unique(taken, parent, name(tree.Name, "", "folder")) + "/"
+		return folders[id], true
+	}
+
+	var entries []dto.ArchiveEntry
+	for _, tree := range trees {
+		folder, ok := folderOf(tree.ID)
+		if !ok {
+			continue
+		}
+		if tree.ID != rootID {
+			entries = append(entries, dto.ArchiveEntry{Path: folder})
+		}
+	}
+
+	for _, tree := range trees {
+		folder, ok := folderOf(tree.ID)
+		if !ok {
+			continue
+		}
+		for i := range tree.Documents {
+			doc := tree.Documents[i]
+			file := unique(taken, folder, name(doc.Name, doc.Extension, "document"))
+			entries = append(entries, dto.ArchiveEntry{Path: folder + file, Document: &doc})
+		}
+	}
+
+	return entries
+}
+
+// Write streams the entries into a ZIP archive, each document is opened only while it is written
+func Write(w io.Writer, entries []dto.ArchiveEntry, open func(dto.Document) (io.ReadCloser, error)) error {
+	archive := zip.NewWriter(w)
+
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry.Path, Method: zip.Deflate}
+		if entry.Document != nil {
+			header.Modified = entry.Document.UpdatedAt
+		} else if entry.Content == nil {
+			header.Method = zip.Store
+		}
+
+		file, err := archive.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+
+		if entry.Document == nil {
+			if _, err = file.Write(entry.Content); err != nil {
+				return fmt.Errorf("%s: %w", entry.Path, err)
+			}
+			continue
+		}
+
+		if err = copyContent(file, *entry.Document, open); err != nil {
+			return fmt.Errorf("%s: %w", entry.Path, err)
+		}
+	}
+
+	return archive.Close()
+}
+
+func copyContent(w io.Writer, doc dto.Document, open func(dto.Document) (io.ReadCloser, error)) error {
+	content, err := open(doc)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	_, err = io.Copy(w, content)
+	return err
+}
+
+// name returns the cleaned name of a file or folder with its extension, a fallback when nothing is left
+func name(base, extension, fallback string) string {
+	base = upload.SanitizeName(base)
+	if base == "" {
+		base = fallback
+	}
+	if extension != "" && !strings.EqualFold(path.Ext(base), extension) {
+		base += extension
+	}
+	return base
+}
+
+// unique numbers a name already taken in a folder, so "a.pdf" becomes "a (2).pdf"
+func unique(taken map[string]map[string]bool, folder, name string) string {
+	if taken[folder] == nil {
+		taken[folder] = map[string]bool{}
+	}
+
+	extension := path.Ext(name)
+	base := strings.TrimSuffix(name, extension)
+
+	candidate := name
+	for i := 2; taken[folder][strings.ToLower(candidate)]; i++ {
+		candidate = fmt.Sprintf("%s (%d)%s", base, i, extension)
+	}
+
+	taken[folder][strings.ToLower(candidate)] = true
+	return candidate
+}
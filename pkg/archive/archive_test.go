@@ -0,0 +1,147 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules/dto"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLayout(t *testing.T) {
+	tests := []struct {
+		name  string
+		trees []dto.Tree
+		want  []string
+	}{
+		{
+			name: "should place documents of the folder at the top",
+			trees: []dto.Tree{
+				{ID: 1, Name: "Reading list", Documents: []dto.Document{{ID: 10, Name: "syllabus", Extension: ".pdf"}}},
+			},
+			want: []string{"syllabus.pdf"},
+		},
+		{
+			name: "should nest subfolders",
+			trees: []dto.Tree{
+				{ID: 1, Name: "Reading list"},
+				{ID: 2, ParentID: 1, Name: "Week 1", Documents: []dto.Document{{ID: 10, Name: "intro.pdf", Extension: ".pdf"}}},
+				{ID: 3, ParentID: 2, Name: "Extra"},
+			},
+			want: []string{"Week 1/", "Week 1/Extra/", "Week 1/intro.pdf"},
+		},
+		{
+			name: "should number repeated names",
+			trees: []dto.Tree{
+				{ID: 1, Name: "Reading list", Documents: []dto.Document{
+					{ID: 10, Name: "notes", Extension: ".txt"},
+					{ID: 11, Name: "Notes.txt", Extension: ".txt"},
+				}},
+				{ID: 2, ParentID: 1, Name: "notes.txt"},
+			},
+			want: []string{"notes.txt/", "notes (2).txt", "Notes (3).txt"},
+		},
+		{
+			name: "should clean names that escape the folder",
+			trees: []dto.Tree{
+				{ID: 1, Name: "Reading list"},
+				{ID: 2, ParentID: 1, Name: "../..", Documents: []dto.Document{{ID: 10, Name: `..\secret`, Extension: ".txt"}}},
+			},
+			want: []string{"folder/", "folder/secret.txt"},
+		},
+		{
+			name: "should skip trees outside the folder",
+			trees: []dto.Tree{
+				{ID: 1, Name: "Reading list"},
+				{ID: 5, ParentID: 4, Name: "Elsewhere", Documents: []dto.Document{{ID: 10, Name: "a.txt"}}},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, entry := range Layout(1, tt.trees) {
+				got = append(got, entry.Path)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Layout() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	contents := map[uint]string{10: "first", 11: "second"}
+	open := func(doc dto.Document) (io.ReadCloser, error) {
+		content, ok := contents[doc.ID]
+		if !ok {
+			return nil, errors.New("missing object")
+		}
+		return io.NopCloser(strings.NewReader(content)), nil
+	}
+
+	tests := []struct {
+		name    string
+		entries []dto.ArchiveEntry
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "should write folders and documents",
+			entries: []dto.ArchiveEntry{
+				{Path: "Week 1/"},
+				{Path: "a.txt", Document: &dto.Document{ID: 10}},
+				{Path: "Week 1/b.txt", Document: &dto.Document{ID: 11}},
+			},
+			want: map[string]string{"Week 1/": "", "a.txt": "first", "Week 1/b.txt": "second"},
+		},
+		{
+			name: "should write content as it is",
+			entries: []dto.ArchiveEntry{
+				{Path: "manifest.json", Content: []byte(`{"userID":"u1"}`)},
+				{Path: "documents/a.txt", Document: &dto.Document{ID: 10}},
+			},
+			want: map[string]string{"manifest.json": `{"userID":"u1"}`, "documents/a.txt": "first"},
+		},
+		{
+			name:    "should fail on a missing object",
+			entries: []dto.ArchiveEntry{{Path: "c.txt", Document: &dto.Document{ID: 12}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Write(&buf, tt.entries, open)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			if err != nil {
+				t.Fatalf("zip.NewReader() error = %v", err)
+			}
+
+			got := map[string]string{}
+			for _, file := range reader.File {
+				content, err := file.Open()
+				if err != nil {
+					t.Fatalf("Open() error = %v", err)
+				}
+				data, _ := io.ReadAll(content)
+				content.Close()
+				got[file.Name] = string(data)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Write() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
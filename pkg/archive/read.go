@@ -0,0 +1,151 @@
+package archive
+
+import (
+	"archive/zip"
+	"fmt"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/upload"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// encrypted is the general purpose flag of an encrypted entry
+const encrypted = 0x1
+
+// junk are files archivers of desktop systems add, they are skipped silently
+var junk = map[string]bool{"__MACOSX": true, ".DS_Store": true, "Thumbs.db": true, "desktop.ini": true}
+
+// Listing is the content of an archive with cleaned paths, folders come before their subfolders
+type Listing struct {
+	Folders  []string
+	Files    []File
+	Rejected []Rejection
+}
+
+// File is a file of an archive placed into a folder, the top of the archive is the empty folder
+type File struct {
+	Folder string
+	Name   string
+	Size   int64
+	file   *zip.File
+}
+
+// Rejection is an entry of an archive that is not unpacked
+type Rejection struct {
+	Path   string
+	Reason string
+}
+
+// Path returns the cleaned path of a file within the archive
+func (f File) Path() string {
+	return path.Join(f.Folder, f.Name)
+}
+
+// Open streams the content of a file, reading more than its declared size fails
+func (f File) Open() (io.ReadCloser, error) {
+	return f.file.Open()
+}
+
+// Read lists the folders and files of a ZIP archive without unpacking them. An archive
+// breaking the limits is refused as a whole, entries with unsafe paths are rejected.
+// The sizes limited are the ones the archive declares, reading more than declared fails.
+func Read(r io.ReaderAt, size int64, limits modules.Imports) (Listing, error) {
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return Listing{}, fmt.Errorf("%w: %v", modules.ErrBadArchive, err)
+	}
+
+	if len(reader.File) > limits.MaxEntries {
+		return Listing{}, fmt.Errorf("%w: more than %d entries", modules.ErrArchiveLimits, limits.MaxEntries)
+	}
+
+	var listing Listing
+	var total int64
+	folders := map[string]bool{}
+
+	for _, file := range reader.File {
+		segments, reason := clean(file.Name)
+		if reason == "" && file.Flags&encrypted != 0 {
+			reason = "the entry is encrypted"
+		}
+		if reason == "" && !file.Mode().IsDir() && !file.Mode().IsRegular() {
+			reason = "the entry is not a regular file"
+		}
+		if reason != "" {
+			listing.Rejected = append(listing.Rejected, Rejection{Path: file.Name, Reason: reason})
+			continue
+		}
+		if segments == nil {
+			continue
+		}
+
+		if file.Mode().IsDir() {
+			addFolders(folders, segments)
+			continue
+		}
+
+		total += int64(file.UncompressedSize64)
+		if file.UncompressedSize64 > uint64(limits.MaxSize) || total > limits.MaxSize {
+			return Listing{}, fmt.Errorf("%w: more than %d bytes unpacked", modules.ErrArchiveLimits, limits.MaxSize)
+		}
+		if file.UncompressedSize64 > file.CompressedSize64*uint64(limits.MaxRatio) {
+			return Listing{}, fmt.Errorf("%w: %s is compressed more than %d times", modules.ErrArchiveLimits, file.Name, limits.MaxRatio)
+		}
+
+		folder := segments[:len(segments)-1]
+		addFolders(folders, folder)
+		listing.Files = append(listing.Files, File{
+			Folder: strings.Join(folder, "/"),
+			Name:   segments[len(segments)-1],
+			Size:   int64(file.UncompressedSize64),
+			file:   file,
+		})
+	}
+
+	for folder := range folders {
+		listing.Folders = append(listing.Folders, folder)
+	}
+	// a parent sorts before its subfolders, as it is a prefix of them
+	sort.Strings(listing.Folders)
+
+	return listing, nil
+}
+
+// clean splits the path of an entry into cleaned names, nil for junk to skip.
+// A reason is returned for a path that could escape the folder it is unpacked into.
+func clean(name string) ([]string, string) {
+	name = strings.ReplaceAll(name, `\`, "/")
+	if strings.HasPrefix(name, "/") || len(name) > 1 && name[1] == ':' {
+		return nil, "the path is absolute"
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(name, "/") {
+		switch segment {
+		case "", ".":
+			continue
+		case "..":
+			return nil, "the path leaves the archive"
+		}
+
+		if junk[segment] || strings.HasPrefix(segment, "._") {
+			return nil, ""
+		}
+
+		cleaned := upload.SanitizeName(segment)
+		if cleaned == "" {
+			return nil, "the path has an empty name"
+		}
+		segments = append(segments, cleaned)
+	}
+	return segments, ""
+}
+
+// addFolders adds a folder and its parents
+func addFolders(folders map[string]bool, segments []string) {
+	for i := 1; i <= len(segments); i++ {
+		folders[strings.Join(segments[:i], "/")] = true
+	}
+}
@@ -0,0 +1,164 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type entry struct {
+	name    string
+	content string
+	mode    os.FileMode
+}
+
+func zipOf(t *testing.T, entries ...entry) *bytes.Reader {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+		if e.mode != 0 {
+			header.SetMode(e.mode)
+		}
+		w, err := writer.CreateHeader(header)
+		if err != nil {
+			t.Fatalf("CreateHeader() error = %v", err)
+		}
+		io.WriteString(w, e.content)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestRead(t *testing.T) {
+	limits := modules.Imports{MaxEntries: 10, MaxSize: 1 << 20, MaxRatio: 100}
+
+	tests := []struct {
+		name         string
+		entries      []entry
+		limits       modules.Imports
+		wantFolders  []string
+		wantFiles    []string
+		wantRejected []string
+		wantErr      error
+	}{
+		{
+			name: "should list folders before their subfolders",
+			entries: []entry{
+				{name: "Week 1/Extra/notes.txt", content: "notes"},
+				{name: "Week 1/"},
+				{name: "syllabus.pdf", content: "syllabus"},
+				{name: "Empty/"},
+			},
+			limits:      limits,
+			wantFolders: []string{"Empty", "Week 1", "Week 1/Extra"},
+			wantFiles:   []string{"Week 1/Extra/notes.txt", "syllabus.pdf"},
+		},
+		{
+			name: "should reject paths that escape the archive",
+			entries: []entry{
+				{name: "../secret.txt", content: "a"},
+				{name: "/etc/passwd", content: "a"},
+				{name: `C:\boot.ini`, content: "a"},
+				{name: `docs\..\..\x.txt`, content: "a"},
+				{name: "docs/./a.txt", content: "a"},
+			},
+			limits:       limits,
+			wantFolders:  []string{"docs"},
+			wantFiles:    []string{"docs/a.txt"},
+			wantRejected: []string{"../secret.txt", "/etc/passwd", `C:\boot.ini`, `docs\..\..\x.txt`},
+		},
+		{
+			name: "should reject links and skip junk of archivers",
+			entries: []entry{
+				{name: "link", content: "/etc/passwd", mode: os.ModeSymlink | 0777},
+				{name: "__MACOSX/._a.txt", content: "a"},
+				{name: "docs/.DS_Store", content: "a"},
+				{name: "docs/a.txt", content: "a"},
+			},
+			limits:       limits,
+			wantFolders:  []string{"docs"},
+			wantFiles:    []string{"docs/a.txt"},
+			wantRejected: []string{"link"},
+		},
+		{
+			name:    "should refuse too many entries",
+			entries: []entry{{name: "a.txt"}, {name: "b.txt"}},
+			limits:  modules.Imports{MaxEntries: 1, MaxSize: 1 << 20, MaxRatio: 100},
+			wantErr: modules.ErrArchiveLimits,
+		},
+		{
+			name:    "should refuse too much content",
+			entries: []entry{{name: "a.txt", content: "abc"}, {name: "b.txt", content: "def"}},
+			limits:  modules.Imports{MaxEntries: 10, MaxSize: 5, MaxRatio: 100},
+			wantErr: modules.ErrArchiveLimits,
+		},
+		{
+			name:    "should refuse highly compressed content",
+			entries: []entry{{name: "zeros", content: strings.Repeat("0", 1<<16)}},
+			limits:  limits,
+			wantErr: modules.ErrArchiveLimits,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := zipOf(t, tt.entries...)
+			got, err := Read(r, r.Size(), tt.limits)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Read() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			var files, rejected []string
+			for _, file := range got.Files {
+				files = append(files, file.Path())
+			}
+			for _, rejection := range got.Rejected {
+				rejected = append(rejected, rejection.Path)
+			}
+			if !reflect.DeepEqual(got.Folders, tt.wantFolders) {
+				t.Errorf("Read() folders = %q, want %q", got.Folders, tt.wantFolders)
+			}
+			if !reflect.DeepEqual(files, tt.wantFiles) {
+				t.Errorf("Read() files = %q, want %q", files, tt.wantFiles)
+			}
+			if !reflect.DeepEqual(rejected, tt.wantRejected) {
+				t.Errorf("Read() rejected = %q, want %q", rejected, tt.wantRejected)
+			}
+		})
+	}
+}
+
+func TestReadFormat(t *testing.T) {
+	r := strings.NewReader("not an archive")
+	if _, err := Read(r, r.Size(), modules.Imports{MaxEntries: 1}); !errors.Is(err, modules.ErrBadArchive) {
+		t.Errorf("Read() error = %v, want %v", err, modules.ErrBadArchive)
+	}
+}
+
+func TestFileOpen(t *testing.T) {
+	r := zipOf(t, entry{name: "docs/a.txt", content: "first"})
+	listing, err := Read(r, r.Size(), modules.Imports{MaxEntries: 1, MaxSize: 10, MaxRatio: 100})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	content, err := listing.Files[0].Open()
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer content.Close()
+	if data, _ := io.ReadAll(content); string(data) != "first" {
+		t.Errorf("Open() = %q, want %q", data, "first")
+	}
+}
@@ -0,0 +1,125 @@
+// Package clamd scans contents for malware with a clamd daemon over its INSTREAM protocol
+package clamd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// chunkSize is the size of the chunks a content is streamed to clamd in
+const chunkSize = 64 << 10
+
+// ErrScan is returned when clamd can't scan a content, e.g. it exceeds StreamMaxLength
+var ErrScan = errors.New("malware scan failed")
+
+// Client scans contents with a clamd daemon listening on a TCP address
+type Client struct {
+	address string
+	timeout time.Duration
+}
+
+func NewClient(address string, timeout time.Duration) *Client {
+	return &Client{
+		address: address,
+		timeout: timeout,
+	}
+}
+
+// Scan streams a content to clamd and returns the name of the signature
+// it matches, an empty name means the content is clean
+func (c *Client) Scan(ctx context.Context, r io.Reader) (string, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err = conn.SetDeadline(deadline); err != nil {
+		return "", err
+	}
+
+	if err = stream(conn, r); err != nil {
+		return "", err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil {
+		return "", err
+	}
+
+	return parse(strings.TrimRight(reply, "\x00\n"))
+}
+
+// stream sends a content as chunks prefixed by their length, an empty chunk ends it
+func stream(w io.Writer, r io.Reader) error {
+	if _, err := w.Write([]byte("zINSTREAM\x00")); err != nil {
+		return err
+	}
+
+	chunk := make([]byte, 4+chunkSize)
+	for {
+		n, err := io.ReadFull(r, chunk[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(chunk[:4], uint32(n))
+			if _, werr := w.Write(chunk[:4+n]); werr != nil {
+				return werr
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := w.Write([]byte{0, 0, 0, 0})
+	return err
+}
+
+// parse reads a reply like "stream: OK" or "stream: Eicar-Signature FOUND"
+func parse(reply string) (string, error) {
+	result := strings.TrimPrefix(reply, "stream: ")
+
+	switch {
+	case result == "OK":
+		return "", nil
+	case strings.HasSuffix(result, " FOUND"):
+		return strings.TrimSuffix(result, " FOUND"), nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrScan, reply)
+	}
+}
+
+// eicar is the standard antivirus test file every scanner detects
+var eicar = []byte(`X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`)
+
+// Fake detects only the EICAR test file, it stands in for clamd
+// in tests and in local setups without a daemon
+type Fake struct{}
+
+func (Fake) Scan(ctx context.Context, r io.Reader) (string, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+
+	if bytes.Contains(content, eicar) {
+		return "Eicar-Test-Signature", nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,149 @@
+package clamd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serve answers one INSTREAM command with the reply for the streamed content
+func serve(t *testing.T, reply func(content []byte) string) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("can't listen on loopback: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		if command, err := r.ReadString(0); err != nil || command != "zINSTREAM\x00" {
+			return
+		}
+
+		var content []byte
+		for {
+			var size uint32
+			if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			chunk := make([]byte, size)
+			if _, err := io.ReadFull(r, chunk); err != nil {
+				return
+			}
+			content = append(content, chunk...)
+		}
+
+		conn.Write([]byte(reply(content) + "\x00"))
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestClient_Scan(t *testing.T) {
+	large := bytes.Repeat([]byte("a"), 3*chunkSize+17)
+
+	tests := []struct {
+		name    string
+		content []byte
+		reply   func(content []byte) string
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "should report a clean content",
+			content: []byte("syllabus"),
+			reply: func(content []byte) string {
+				if string(content) != "syllabus" {
+					return "stream: unexpected content ERROR"
+				}
+				return "stream: OK"
+			},
+		},
+		{
+			name:    "should stream a content in chunks",
+			content: large,
+			reply: func(content []byte) string {
+				if !bytes.Equal(content, large) {
+					return "stream: unexpected content ERROR"
+				}
+				return "stream: OK"
+			},
+		},
+		{
+			name:    "should report the signature found",
+			content: eicar,
+			reply: func(content []byte) string {
+				return "stream: Eicar-Test-Signature FOUND"
+			},
+			want: "Eicar-Test-Signature",
+		},
+		{
+			name:    "should fail on an error reply",
+			content: large,
+			reply: func(content []byte) string {
+				return "INSTREAM size limit exceeded. ERROR"
+			},
+			wantErr: ErrScan,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(serve(t, tt.reply), time.Second)
+
+			got, err := client.Scan(context.Background(), bytes.NewReader(tt.content))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Scan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Scan() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFake_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "should pass a clean content",
+			content: "syllabus",
+		},
+		{
+			name:    "should detect the test file inside a content",
+			content: "header " + string(eicar) + " trailer",
+			want:    "Eicar-Test-Signature",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Fake{}.Scan(context.Background(), strings.NewReader(tt.content))
+			if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Scan() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Package extract pulls plain text out of document contents for full-text search
+package extract
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrUnsupported is returned for contents no text can be extracted from
+var ErrUnsupported = errors.New("unsupported document format")
+
+const (
+	// MaxContent is the number of bytes read from a document or from one part of an archive
+	MaxContent = 64 << 20
+	// MaxText is the number of bytes of text kept, a tsvector can't exceed 1MB
+	MaxText = 512 << 10
+)
+
+// Text returns the text of a document, the format is chosen by the extension
+// and falls back to the media type
+func Text(extension, contentType string, r io.Reader) (string, error) {
+	kind := format(extension, contentType)
+	if kind == "" {
+		return "", ErrUnsupported
+	}
+
+	content, err := io.ReadAll(io.LimitReader(r, MaxContent))
+	if err != nil {
+		return "", err
+	}
+
+	var text string
+	switch kind {
+	case "text":
+		text = string(content)
+	case "html":
+		text = htmlText(content)
+	case "pdf":
+		text = pdfText(content)
+	case "docx":
+		text, err = officeText(content, "word/document.xml", "word/footnotes.xml")
+	case "xlsx":
+		text, err = officeText(content, "xl/sharedStrings.xml", "xl/worksheets/")
+	case "pptx":
+		text, err = officeText(content, "ppt/slides/", "ppt/notesSlides/")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return truncate(normalize(text), MaxText), nil
+}
+
+func format(extension, contentType string) string {
+	switch strings.ToLower(strings.TrimPrefix(extension, ".")) {
+	case "txt", "text", "md", "markdown", "csv":
+		return "text"
+	case "html", "htm":
+		return "html"
+	case "pdf":
+		return "pdf"
+	case "docx":
+		return "docx"
+	case "xlsx":
+		return "xlsx"
+	case "pptx":
+		return "pptx"
+	}
+
+	switch strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0])) {
+	case "text/plain", "text/markdown", "text/csv":
+		return "text"
+	case "text/html":
+		return "html"
+	case "application/pdf":
+		return "pdf"
+	case "application/vnd.openxmlformats-officedocument.wordprocessingml.document":
+		return "docx"
+	case "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":
+		return "xlsx"
+	case "application/vnd.openxmlformats-officedocument.presentationml.presentation":
+		return "pptx"
+	}
+
+	return ""
+}
+
+// normalize collapses whitespace and drops what postgres can't store in a text column
+func normalize(text string) string {
+	text = strings.ToValidUTF8(text, " ")
+	text = strings.ReplaceAll(text, "\x00", " ")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// truncate cuts the text to at most limit bytes without splitting a character
+func truncate(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+
+	for limit > 0 && !utf8.RuneStart(text[limit]) {
+		limit--
+	}
+	return text[:limit]
+}
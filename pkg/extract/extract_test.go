@@ -0,0 +1,173 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	type args struct {
+		extension   string
+		contentType string
+		content     []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr error
+	}{
+		{
+			name: "should read plain text",
+			args: args{
+				extension: ".txt",
+				content:   []byte("Алгебра\n\n  және геометрия\x00"),
+			},
+			want: "Алгебра және геометрия",
+		},
+		{
+			name: "should read markdown by media type",
+			args: args{
+				contentType: "text/markdown; charset=utf-8",
+				content:     []byte("# Syllabus\n\n* week one"),
+			},
+			want: "# Syllabus * week one",
+		},
+		{
+			name: "should strip html",
+			args: args{
+				extension: ".HTML",
+				content:   []byte(`<html><head><style>p{color:red}</style><script>alert("x")</script></head><body><!-- draft --><p>Tom &amp; Jerry</p><p>Лекция</p></body></html>`),
+			},
+			want: "Tom & Jerry Лекция",
+		},
+		{
+			name: "should read docx",
+			args: args{
+				extension: ".docx",
+				content: archive(t, map[string]string{
+					"word/document.xml": `<w:document xmlns:w="w"><w:body><w:p><w:r><w:t>Hello</w:t></w:r><w:r><w:t xml:space="preserve"> world</w:t></w:r></w:p><w:p><w:r><w:t>Сәлем</w:t></w:r></w:p></w:body></w:document>`,
+					"word/styles.xml":   `<w:styles xmlns:w="w"><w:t>ignored</w:t></w:styles>`,
+				}),
+			},
+			want: "Hello world Сәлем",
+		},
+		{
+			name: "should read xlsx",
+			args: args{
+				extension: ".xlsx",
+				content: archive(t, map[string]string{
+					"xl/sharedStrings.xml":     `<sst><si><t>Grade</t></si><si><t>Excellent</t></si></sst>`,
+					"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row><c t="inlineStr"><is><t>Inline</t></is></c><c><v>42</v></c></row></sheetData></worksheet>`,
+				}),
+			},
+			want: "Grade Excellent Inline",
+		},
+		{
+			name: "should read pptx",
+			args: args{
+				extension: "pptx",
+				content: archive(t, map[string]string{
+					"ppt/slides/slide1.xml": `<p:sld xmlns:p="p" xmlns:a="a"><a:p><a:r><a:t>First slide</a:t></a:r></a:p></p:sld>`,
+					"ppt/slides/slide2.xml": `<p:sld xmlns:p="p" xmlns:a="a"><a:p><a:r><a:t>Second</a:t></a:r></a:p></p:sld>`,
+				}),
+			},
+			want: "First slide Second",
+		},
+		{
+			name: "should read pdf",
+			args: args{
+				extension: ".pdf",
+				content: pdf(t,
+					"BT /F1 12 Tf 72 712 Td (Hello \\(PDF\\)) Tj ET",
+					"BT /F1 12 Tf [(Wor) -20 (ld) -400 (again)] TJ T* <414243> Tj ET"),
+			},
+			want: "Hello (PDF) World again ABC",
+		},
+		{
+			name: "should reject images",
+			args: args{
+				extension:   ".png",
+				contentType: "image/png",
+				content:     []byte{0x89, 'P', 'N', 'G'},
+			},
+			want:    "",
+			wantErr: ErrUnsupported,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Text(tt.args.extension, tt.args.contentType, bytes.NewReader(tt.args.content))
+			if err != tt.wantErr {
+				t.Errorf("Text() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Text() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_truncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  string
+	}{
+		{name: "should keep short text", text: "short", limit: 10, want: "short"},
+		{name: "should cut ascii", text: "abcdef", limit: 3, want: "abc"},
+		{name: "should not split a character", text: "аб", limit: 3, want: "а"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncate(tt.text, tt.limit); got != tt.want {
+				t.Errorf("truncate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func archive(t *testing.T, files map[string]string) []byte {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for name, content := range files {
+		file, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = file.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+// pdf builds a document with the first stream stored as is and the second one deflated
+func pdf(t *testing.T, plain, deflated string) []byte {
+	var compressed bytes.Buffer
+	writer := zlib.NewWriter(&compressed)
+	if _, err := writer.Write([]byte(deflated)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var document strings.Builder
+	document.WriteString("%PDF-1.4\n")
+	fmt.Fprintf(&document, "4 0 obj\n<< /Length %d >>\nstream\n%s\nendstream\nendobj\n", len(plain), plain)
+	fmt.Fprintf(&document, "5 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", compressed.Len())
+	document.Write(compressed.Bytes())
+	document.WriteString("\nendstream\nendobj\n%%EOF")
+
+	return []byte(document.String())
+}
@@ -0,0 +1,21 @@
+package extract
+
+import (
+	"html"
+	"regexp"
+)
+
+var (
+	htmlHidden   = regexp.MustCompile(`(?is)<(script|style|noscript)\b[^>]*>.*?</(script|style|noscript)\s*>`)
+	htmlComments = regexp.MustCompile(`(?s)<!--.*?-->`)
+	htmlTags     = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// htmlText strips markup, scripts and styles and decodes entities
+func htmlText(content []byte) string {
+	text := htmlComments.ReplaceAll(content, []byte(" "))
+	text = htmlHidden.ReplaceAll(text, []byte(" "))
+	text = htmlTags.ReplaceAll(text, []byte(" "))
+
+	return html.UnescapeString(string(text))
+}
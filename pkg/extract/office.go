@@ -0,0 +1,82 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"sort"
+	"strings"
+)
+
+// officeText reads the text runs of the xml parts of an office open xml
+// archive whose names start with one of the prefixes
+func officeText(content []byte, prefixes ...string) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return "", err
+	}
+
+	parts := make([]*zip.File, 0)
+	for _, file := range archive.File {
+		if !strings.HasSuffix(file.Name, ".xml") {
+			continue
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(file.Name, prefix) {
+				parts = append(parts, file)
+				break
+			}
+		}
+	}
+
+	sort.Slice(parts, func(i, j int) bool { return parts[i].Name < parts[j].Name })
+
+	var text strings.Builder
+	for _, part := range parts {
+		if err = xmlText(part, &text); err != nil {
+			return "", err
+		}
+	}
+
+	return text.String(), nil
+}
+
+// xmlText writes the content of every t element of a part, words, spreadsheets and
+// slides all keep their text runs in t, paragraphs and cells end with a space
+func xmlText(part *zip.File, text *strings.Builder) error {
+	reader, err := part.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	// the limit keeps a crafted archive from inflating without bound
+	decoder := xml.NewDecoder(io.LimitReader(reader, MaxContent))
+
+	var inText bool
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			inText = element.Name.Local == "t"
+		case xml.EndElement:
+			inText = false
+			switch element.Name.Local {
+			case "p", "si", "c", "tab", "br":
+				text.WriteString(" ")
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(element)
+			}
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package extract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// pdfText reads the strings shown by the text operators of every content stream.
+// It is a best effort: text drawn with embedded CID fonts has no usable encoding
+// and is skipped.
+func pdfText(content []byte) string {
+	var text strings.Builder
+	for _, stream := range pdfStreams(content) {
+		pdfOperators(stream, &text)
+	}
+	return text.String()
+}
+
+// pdfStreams returns the unfiltered and inflated flate streams of a document
+func pdfStreams(content []byte) [][]byte {
+	streams := make([][]byte, 0)
+
+	for offset := 0; ; {
+		start := bytes.Index(content[offset:], []byte("stream"))
+		if start < 0 {
+			return streams
+		}
+		start += offset
+
+		// endstream contains the keyword too
+		if start >= 3 && string(content[start-3:start]) == "end" {
+			offset = start + len("stream")
+			continue
+		}
+
+		header := content[:start]
+		if object := bytes.LastIndex(header, []byte("obj")); object >= 0 {
+			header = header[object:]
+		}
+
+		data := start + len("stream")
+		if data < len(content) && content[data] == '\r' {
+			data++
+		}
+		if data < len(content) && content[data] == '\n' {
+			data++
+		}
+
+		end := bytes.Index(content[data:], []byte("endstream"))
+		if end < 0 {
+			return streams
+		}
+		end += data
+		offset = end + len("endstream")
+
+		switch {
+		case !bytes.Contains(header, []byte("/Filter")):
+			streams = append(streams, content[data:end])
+		case bytes.Contains(header, []byte("/FlateDecode")):
+			reader, err := zlib.NewReader(bytes.NewReader(content[data:end]))
+			if err != nil {
+				continue
+			}
+			inflated, _ := io.ReadAll(io.LimitReader(reader, MaxContent))
+			reader.Close()
+			streams = append(streams, inflated)
+		}
+	}
+}
+
+// pdfOperators writes the operands of Tj, TJ, ' and " and breaks words on text moves
+func pdfOperators(stream []byte, text *strings.Builder) {
+	var operands []string
+
+	for i := 0; i < len(stream); {
+		c := stream[i]
+		switch {
+		case c == '(':
+			literal, next := pdfLiteral(stream, i)
+			operands = append(operands, literal)
+			i = next
+		case c == '<' && i+1 < len(stream) && stream[i+1] != '<':
+			end := bytes.IndexByte(stream[i:], '>')
+			if end < 0 {
+				return
+			}
+			if decoded, ok := pdfHex(stream[i+1 : i+end]); ok {
+				operands = append(operands, decoded)
+			}
+			i += end + 1
+		case c == '%':
+			for i < len(stream) && stream[i] != '\n' && stream[i] != '\r' {
+				i++
+			}
+		case isPDFDelimiter(c) || isPDFSpace(c):
+			i++
+		default:
+			start := i
+			for i < len(stream) && !isPDFDelimiter(stream[i]) && !isPDFSpace(stream[i]) {
+				i++
+			}
+			token := string(stream[start:i])
+
+			// large negative kerning inside a TJ array separates words
+			if number, err := strconv.ParseFloat(token, 64); err == nil {
+				if number < -200 && len(operands) > 0 {
+					operands = append(operands, " ")
+				}
+				continue
+			}
+
+			switch token {
+			case "Tj", "TJ", "'", "\"":
+				for _, operand := range operands {
+					text.WriteString(operand)
+				}
+			case "Td", "TD", "T*", "Tm", "ET":
+				text.WriteString(" ")
+			}
+			operands = operands[:0]
+		}
+	}
+}
+
+// pdfLiteral reads a balanced literal string starting at the opening parenthesis
+func pdfLiteral(stream []byte, start int) (string, int) {
+	var literal strings.Builder
+	depth := 0
+
+	for i := start; i < len(stream); i++ {
+		c := stream[i]
+		switch c {
+		case '\\':
+			i++
+			if i >= len(stream) {
+				return literal.String(), i
+			}
+			switch e := stream[i]; e {
+			case 'n', 'r':
+				literal.WriteByte(' ')
+			case 't':
+				literal.WriteByte('\t')
+			case 'b', 'f':
+			case '\r', '\n':
+				// a line continuation
+			default:
+				if e >= '0' && e <= '7' {
+					end := i + 1
+					for end < len(stream) && end < i+3 && stream[end] >= '0' && stream[end] <= '7' {
+						end++
+					}
+					code, _ := strconv.ParseUint(string(stream[i:end]), 8, 8)
+					literal.WriteByte(byte(code))
+					i = end - 1
+					continue
+				}
+				literal.WriteByte(e)
+			}
+		case '(':
+			if depth > 0 {
+				literal.WriteByte(c)
+			}
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return literal.String(), i + 1
+			}
+			literal.WriteByte(c)
+		default:
+			literal.WriteByte(c)
+		}
+	}
+
+	return literal.String(), len(stream)
+}
+
+// pdfHex decodes a hex string, glyph ids of embedded fonts are not text and are rejected
+func pdfHex(encoded []byte) (string, bool) {
+	cleaned := bytes.Map(func(r rune) rune {
+		if isPDFSpace(byte(r)) {
+			return -1
+		}
+		return r
+	}, encoded)
+	if len(cleaned)%2 == 1 {
+		cleaned = append(cleaned, '0')
+	}
+
+	decoded := make([]byte, hex.DecodedLen(len(cleaned)))
+	if _, err := hex.Decode(decoded, cleaned); err != nil {
+		return "", false
+	}
+
+	for _, b := range decoded {
+		if b < 0x20 || b > 0x7e {
+			return "", false
+		}
+	}
+	return string(decoded), true
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
@@ -0,0 +1,106 @@
+package migrate
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	file := func(content string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(content)}
+	}
+
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		want    []Migration
+		wantErr bool
+	}{
+		{
+			name: "should order migrations by version",
+			files: fstest.MapFS{
+				"0010_indexes.up.sql":   file("create index"),
+				"0010_indexes.down.sql": file("drop index"),
+				"0002_initial.up.sql":   file("create table"),
+				"0002_initial.down.sql": file("drop table"),
+				"migrations.go":         file("package migrations"),
+			},
+			want: []Migration{
+				{Version: 2, Name: "initial", Up: "create table", Down: "drop table"},
+				{Version: 10, Name: "indexes", Up: "create index", Down: "drop index"},
+			},
+		},
+		{
+			name:    "should refuse a migration without a down file",
+			files:   fstest.MapFS{"0001_initial.up.sql": file("create table")},
+			wantErr: true,
+		},
+		{
+			name: "should refuse a version with two names",
+			files: fstest.MapFS{
+				"0001_initial.up.sql": file("create table"),
+				"0001_other.down.sql": file("drop table"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "should refuse two files of a direction",
+			files: fstest.MapFS{
+				"0001_initial.up.sql":   file("create table"),
+				"1_initial.up.sql":      file("create table"),
+				"0001_initial.down.sql": file("drop table"),
+			},
+			wantErr: true,
+		},
+		{
+			name:    "should refuse a misnamed file",
+			files:   fstest.MapFS{"initial.sql": file("create table")},
+			wantErr: true,
+		},
+		{
+			name:    "should refuse a zero version",
+			files:   fstest.MapFS{"0000_initial.up.sql": file("create table"), "0000_initial.down.sql": file("drop table")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(tt.files)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKnown(t *testing.T) {
+	migrator := NewMigrator(nil, []Migration{{Version: 1}, {Version: 2}})
+
+	tests := []struct {
+		name    string
+		applied []int64
+		wantErr error
+	}{
+		{name: "should accept a schema behind", applied: []int64{1}},
+		{name: "should accept the same schema", applied: []int64{1, 2}},
+		{name: "should refuse a newer schema", applied: []int64{1, 2, 3}, wantErr: ErrNewerSchema},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applied := map[int64]time.Time{}
+			for _, version := range tt.applied {
+				applied[version] = time.Now()
+			}
+
+			if err := migrator.known(applied); !errors.Is(err, tt.wantErr) {
+				t.Errorf("known() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
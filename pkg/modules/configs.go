@@ -1,11 +1,87 @@
 package modules
 
+import "time"
+
 type AppConfigs struct {
 	Port          string
 	LogLevel      string
 	Keycloak      *Keycloak
 	Database      *Postgre
 	ObjectStorage *ObjectStorage
+	Trash         *Trash
+	Deletions     *Deletions
+	Texts         *Texts
+	Previews      *Previews
+	Scanner       *Scanner
+	Shares        *Shares
+	Imports       *Imports
+	Reconcile     *Reconcile
+	Outbox        *Outbox
+	Sessions      *UploadSessions
+	Uploads       *UploadPolicy
+	Permissions   Permissions
+}
+
+type Trash struct {
+	Retention     time.Duration
+	PurgeInterval time.Duration
+}
+
+type Deletions struct {
+	Interval time.Duration
+}
+
+type Texts struct {
+	Interval time.Duration
+}
+
+type Previews struct {
+	Interval time.Duration
+}
+
+// Scanner is the clamd daemon uploads are scanned with. Fake scans without a daemon
+// for development, only the EICAR test file is detected then.
+type Scanner struct {
+	Address string
+	Timeout time.Duration
+	Fake    bool
+}
+
+// Shares sets the lifetime of share links created without one and the longest one allowed
+type Shares struct {
+	Lifetime    time.Duration
+	MaxLifetime time.Duration
+}
+
+// Imports limits what an uploaded ZIP archive may unpack into, guarding against zip bombs
+type Imports struct {
+	MaxEntries int
+	MaxSize    int64
+	MaxRatio   int64
+}
+
+// Reconcile schedules the comparison of spaces with the database. Mismatches younger than
+// the grace period are left alone as uploads and deletions in progress, and no more than
+// MaxRepairs are repaired in a run, so a wrong bucket doesn't wipe the database.
+type Reconcile struct {
+	Interval   time.Duration
+	Grace      time.Duration
+	Repair     bool
+	MaxRepairs int
+}
+
+// Outbox sets how often pending side effects in spaces are looked at, and how long a
+// request has to store the content of a document before the outbox settles it
+type Outbox struct {
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+// UploadSessions sets how long a resumable upload may take before its session and the
+// parts received are discarded, and how often expired sessions are looked for
+type UploadSessions struct {
+	Lifetime time.Duration
+	Interval time.Duration
 }
 
 type ObjectStorage struct {
@@ -9,4 +9,112 @@ const (
 	Token    = "token"
 	ClientID = "clientId"
 	UserID   = "userId"
+	Roles    = "roles"
+	// Organization is the customer id of the organization of the caller, zero without one
+	Organization = "organization"
+)
+
+// Kinds of items in the trash
+const (
+	TrashTree     = "tree"
+	TrashDocument = "document"
+)
+
+// S3 multipart upload limits
+const (
+	MinUploadPart = 1
+	MaxUploadPart = 10000
+
+	MaxUploadPartSize = 5 << 30
+)
+
+// Access levels granted on a tree, each includes the ones before it
+const (
+	AccessViewer = "viewer"
+	AccessEditor = "editor"
+	AccessOwner  = "owner"
+)
+
+// Subjects a storage quota applies to
+const (
+	QuotaUser         = "user"
+	QuotaRole         = "role"
+	QuotaOrganization = "organization"
+)
+
+// Scan statuses of a document, only clean content can be downloaded or shared
+const (
+	ScanPending  = "pending"
+	ScanClean    = "clean"
+	ScanInfected = "infected"
+)
+
+// States of a document, only ready documents are listed and served. An uploading document
+// waits for its content to reach spaces, a deleting one for its objects to be removed and
+// a missing one lost its content, it is kept until the content is restored or it is purged.
+const (
+	DocumentUploading = "uploading"
+	DocumentReady     = "ready"
+	DocumentDeleting  = "deleting"
+	DocumentMissing   = "missing"
+)
+
+// Kinds of outbox events. A stored document waits for the request uploading its content,
+// a copy for its content to be copied from the source object.
+const (
+	OutboxStore = "store"
+	OutboxCopy  = "copy"
+)
+
+// Subjects a tree can be shared with
+const (
+	SubjectUser  = "user"
+	SubjectGroup = "group"
+	SubjectRole  = "role"
+)
+
+// Kinds of content a share link opens to
+const (
+	ShareDocument = "document"
+	ShareTree     = "tree"
+)
+
+// Outcomes of an attempt to open a share link
+const (
+	ShareGranted   = "granted"
+	SharePassword  = "password"
+	ShareExpired   = "expired"
+	ShareRevoked   = "revoked"
+	ShareExhausted = "exhausted"
+	ShareNotClean  = "not-clean"
+)
+
+// Kinds of entries imported from an archive
+const (
+	ImportFolder   = "folder"
+	ImportDocument = "document"
+)
+
+// Outcomes of importing an entry of an archive
+const (
+	ImportCreated  = "created"
+	ImportRejected = "rejected"
+	ImportFailed   = "failed"
+)
+
+// Classes of mismatches between spaces and the database
+const (
+	MismatchOrphanObject   = "orphan-object"
+	MismatchOrphanBlob     = "orphan-blob"
+	MismatchMissingContent = "missing-content"
+	MismatchMissingVersion = "missing-version"
+)
+
+// What reconciliation did about a mismatch
+const (
+	MismatchRecent   = "recent"
+	MismatchPlanned  = "planned"
+	MismatchRepaired = "repaired"
+	MismatchSkipped  = "skipped"
+	MismatchFailed   = "failed"
 )
@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+// TreeAccess grants a user, a group or a role access to a tree and its whole subtree
+type TreeAccess struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	TreeID    uint      `json:"treeID" gorm:"uniqueIndex:idx_tree_access_subject"`
+	Tree      *Tree     `json:"tree,omitempty" gorm:"constraint:OnDelete:CASCADE;"`
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+	Subject   string    `json:"subject" binding:"required,oneof=user group role" gorm:"varchar(16);uniqueIndex:idx_tree_access_subject"`
+	SubjectID string    `json:"subjectID" binding:"required" gorm:"varchar(255);uniqueIndex:idx_tree_access_subject"`
+	Level     string    `json:"level" binding:"required,oneof=viewer editor owner" gorm:"varchar(16)"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+// Archive is a folder laid out for a ZIP download
+type Archive struct {
+	Name    string
+	Entries []ArchiveEntry
+}
+
+// ArchiveEntry is a document at a slash separated path of an archive, or content
+// written as it is. An entry with neither is a folder and its path ends with a slash.
+type ArchiveEntry struct {
+	Path     string
+	Document *Document
+	Content  []byte
+}
+
+// ArchiveSelection narrows the archive of a folder to some of its documents, all of them when empty
+type ArchiveSelection struct {
+	DocumentIDs []uint `form:"documentID"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+// Blob is content stored once under its SHA-256 checksum. Refs counts the
+// documents and versions pointing at it, the object is removed with the last one.
+// Stored is set once the object reached spaces, until then every reference uploads it.
+type Blob struct {
+	Checksum   string     `json:"checksum" gorm:"primarykey;type:varchar(64)"`
+	CreatedAt  time.Time  `json:"createdAt" gorm:"<-:create"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+	Size       int64      `json:"size"`
+	Refs       int64      `json:"refs"`
+	Stored     bool       `json:"stored"`
+	VerifiedAt *time.Time `json:"verifiedAt,omitempty"`
+}
+
+// BlobMismatch is a stored blob whose content doesn't hash to its checksum
+type BlobMismatch struct {
+	Checksum string `json:"checksum"`
+	Actual   string `json:"actual,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Verification reports a run of the integrity verification of stored blobs
+type Verification struct {
+	Checked    int            `json:"checked"`
+	Mismatches []BlobMismatch `json:"mismatches"`
+}
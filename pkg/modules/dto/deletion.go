@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// ObjectDeletion is a queued removal of one object from spaces. It is written
+// in the same transaction that deletes the owning rows and kept until the
+// object is gone.
+type ObjectDeletion struct {
+	ID         uint      `gorm:"primarykey"`
+	CreatedAt  time.Time `gorm:"<-:create"`
+	UpdatedAt  time.Time
+	DocumentID uint
+	UploadedAt time.Time
+	Path       uuid.UUID `gorm:"type:uuid"`
+	Extension  string    `gorm:"varchar(10)"`
+	Version    uint
+	Checksum   string `gorm:"type:varchar(64);index"`
+	Attempts   int
+	LastError  string
+	RetryAt    time.Time `gorm:"index"`
+}
+
+// Document returns the document the object belonged to, enough to build its key.
+// A deletion of a blob has only the checksum.
+func (d ObjectDeletion) Document() Document {
+	return Document{ID: d.DocumentID, CreatedAt: d.UploadedAt, Path: d.Path, Extension: d.Extension, Checksum: d.Checksum}
+}
+
+// Purge reports what a permanent deletion removed
+type Purge struct {
+	Folders   int64 `json:"folders"`
+	Documents int64 `json:"documents"`
+	Bytes     int64 `json:"bytes"`
+}
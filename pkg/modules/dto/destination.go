@@ -0,0 +1,6 @@
+package dto
+
+// Destination is the tree an item is moved or copied into, zero is the root
+type Destination struct {
+	ParentID uint `json:"parentID" form:"parentID"`
+}
@@ -2,23 +2,33 @@ package dto
 
 import (
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"io"
 	"time"
 )
 
 type Document struct {
-	ID              uint          `json:"id" gorm:"<-:create;primarykey;"`
-	UserID          string        `json:"-"  gorm:"<-:create;varchar(50)"`
-	TreeID          uint          `json:"-"  gorm:"->;-:migration;column:tree_id"`
-	CreatedAt       time.Time     `json:"createdAt" gorm:"<-:create;"`
-	UpdatedAt       time.Time     `json:"updatedAt"`
-	Name            string        `form:"name,omitempty" json:"name,omitempty" gorm:"varchar(2000)"`
-	Extension       string        `json:"extension,omitempty" gorm:"varchar(10);<-:create"`
-	Size            int64         `json:"size,omitempty" gorm:"number;<-:create;"`
-	Type            string        `json:"type,omitempty" gorm:"varchar(255);<-:create"`
-	Path            uuid.UUID     `json:"path,omitempty" gorm:"<-:create;type:uuid;default:gen_random_uuid()"`
-	Template        *bool         `json:"template" form:"template,omitempty" gorm:"default:false"`
-	ShareLink       string        `json:"shareLink,omitempty" gorm:"-:all"`
-	RequestContent  io.ReadSeeker `gorm:"-:all" json:"-"`
-	ResponseContent []byte        `gorm:"-:all" json:"-"`
+	ID              uint           `json:"id" gorm:"<-:create;primarykey;"`
+	UserID          string         `json:"-"  gorm:"<-:create;varchar(50)"`
+	TreeID          uint           `json:"-"  gorm:"->;-:migration;column:tree_id"`
+	CreatedAt       time.Time      `json:"createdAt" gorm:"<-:create;"`
+	UpdatedAt       time.Time      `json:"updatedAt"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
+	Name            string         `form:"name,omitempty" json:"name,omitempty" gorm:"varchar(2000)"`
+	Extension       string         `json:"extension,omitempty" gorm:"varchar(10);<-:create"`
+	Size            int64          `json:"size,omitempty" gorm:"number;<-:create;"`
+	Type            string         `json:"type,omitempty" gorm:"varchar(255);<-:create"`
+	Path            uuid.UUID      `json:"path,omitempty" gorm:"<-:create;type:uuid;default:gen_random_uuid()"`
+	Template        *bool          `json:"template" form:"template,omitempty" gorm:"default:false"`
+	Version         uint           `json:"version,omitempty" gorm:"<-:create;default:1"`
+	Checksum        string         `json:"checksum,omitempty" gorm:"<-:create;type:varchar(64);index"`
+	ScanStatus      string         `json:"scanStatus,omitempty" gorm:"<-:create;varchar(20);default:clean"`
+	State           string         `json:"-" gorm:"<-:create;varchar(20);default:ready"`
+	PreviewURL      string         `json:"previewUrl,omitempty" gorm:"-:all"`
+	Preview         bool           `json:"-" gorm:"->;-:migration"`
+	Snippet         string         `json:"snippet,omitempty" gorm:"->;-:migration"`
+	Rank            float64        `json:"rank,omitempty" gorm:"->;-:migration"`
+	RequestContent  io.ReadSeeker  `gorm:"-:all" json:"-"`
+	ResponseContent io.ReadCloser  `gorm:"-:all" json:"-"`
+	Download        Download       `gorm:"-:all" json:"-"`
 }
@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+// Download carries the range and conditional headers of a download request
+// and the metadata of the stored object that answers it.
+type Download struct {
+	Range           string
+	IfNoneMatch     string
+	IfModifiedSince time.Time
+
+	ContentLength int64
+	ContentRange  string
+	ETag          string
+	LastModified  time.Time
+	NotModified   bool
+}
@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+// Export is the manifest of a user's data, written next to the content of its documents
+type Export struct {
+	UserID     string    `json:"userID"`
+	ExportedAt time.Time `json:"exportedAt"`
+	Usage      Usage     `json:"usage"`
+	Trees      []Tree    `json:"trees"`
+	Groups     []Group   `json:"groups"`
+	Shares     []Share   `json:"shares"`
+}
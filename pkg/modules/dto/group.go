@@ -1,14 +1,37 @@
 package dto
 
-import "time"
+import (
+	"gorm.io/gorm"
+	"time"
+)
 
 type Group struct {
-	ID        uint       `gorm:"primarykey" json:"id,omitempty"`
-	CreatedAt time.Time  `json:"createdAt,omitempty"`
-	UpdatedAt time.Time  `json:"updatedAt,omitempty"`
-	DeletedAt time.Time  `json:"deletedAt,omitempty"`
-	Name      string     `gorm:"varchar(2000)" json:"name" binding:"required"`
-	Desc      string     `gorm:"text" json:"desc,omitempty"`
-	Role      string     `json:"role" gorm:"varchar(255)"`
-	Documents []Document `json:"documents,omitempty" gorm:"many2many:group_documents;"`
+	ID        uint           `gorm:"primarykey" json:"id,omitempty"`
+	UserID    string         `json:"-" gorm:"<-:create;varchar(255)"`
+	CreatedAt time.Time      `json:"createdAt,omitempty"`
+	UpdatedAt time.Time      `json:"updatedAt,omitempty"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	Name      string         `gorm:"varchar(2000)" json:"name" binding:"required"`
+	Desc      string         `gorm:"text" json:"desc,omitempty"`
+	Role      string         `json:"role" gorm:"varchar(255)"`
+	Members   []GroupMember  `json:"members,omitempty" gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE;"`
+	Trees     []Tree         `json:"trees,omitempty" gorm:"many2many:group_trees;"`
+	Documents []Document     `json:"documents,omitempty" gorm:"many2many:group_documents;"`
+}
+
+// GroupMember is a keycloak user, identified by the sub claim, in a group
+type GroupMember struct {
+	GroupID   uint      `json:"-" gorm:"primarykey;autoIncrement:false"`
+	UserID    string    `json:"userID" binding:"required" gorm:"primarykey;varchar(255)"`
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+}
+
+type GroupDocuments struct {
+	GroupID    uint `gorm:"primarykey"`
+	DocumentID uint `gorm:"primarykey"`
+}
+
+type GroupTrees struct {
+	GroupID uint `gorm:"primarykey"`
+	TreeID  uint `gorm:"primarykey"`
 }
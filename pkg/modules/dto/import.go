@@ -0,0 +1,22 @@
+package dto
+
+import "gitlab.com/a5805/ondeu/ondeu-back/pkg/modules"
+
+// ImportReport tells entry by entry what importing an archive into a folder created, rejected
+// and failed to create. What was created before a failure is kept.
+type ImportReport struct {
+	Created  int           `json:"created"`
+	Rejected int           `json:"rejected"`
+	Failed   int           `json:"failed"`
+	Entries  []ImportEntry `json:"entries"`
+}
+
+// ImportEntry is a folder or file of an archive, the id is the created tree or document
+type ImportEntry struct {
+	Path       string              `json:"path"`
+	Kind       string              `json:"kind"`
+	Status     string              `json:"status"`
+	ID         uint                `json:"id,omitempty"`
+	Reason     string              `json:"reason,omitempty"`
+	Violations []modules.Violation `json:"violations,omitempty"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// OutboxEvent is a side effect in spaces of a change to a document. It is written in the
+// same transaction as the change and kept until the side effect is settled, by the request
+// that made the change or by the outbox worker once the request had its time. A copy keeps
+// the key of the object it copies from as its source.
+type OutboxEvent struct {
+	ID         uint      `gorm:"primarykey"`
+	CreatedAt  time.Time `gorm:"<-:create"`
+	UpdatedAt  time.Time
+	Kind       string `gorm:"type:varchar(20)"`
+	DocumentID uint   `gorm:"index"`
+	Source     string
+	Attempts   int
+	LastError  string
+	RetryAt    time.Time `gorm:"index"`
+}
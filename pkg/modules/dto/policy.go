@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"github.com/lib/pq"
+	"time"
+)
+
+// TreePolicy narrows the files accepted into a tree and its whole subtree,
+// entries are extensions like ".pdf" or media types like "image/*"
+type TreePolicy struct {
+	TreeID    uint           `json:"treeID" gorm:"primarykey;autoIncrement:false"`
+	Tree      *Tree          `json:"-" gorm:"constraint:OnDelete:CASCADE;"`
+	CreatedAt time.Time      `json:"createdAt,omitempty"`
+	UpdatedAt time.Time      `json:"updatedAt,omitempty"`
+	MaxSize   int64          `json:"maxSize" binding:"min=0"`
+	Allow     pq.StringArray `json:"allow" gorm:"type:text[]"`
+	Deny      pq.StringArray `json:"deny" gorm:"type:text[]"`
+}
+
+// UploadFile is a file checked against the upload policy before it is stored,
+// the checked file carries its cleaned name, extension and media type
+type UploadFile struct {
+	Name      string
+	Extension string
+	Type      string
+	Size      int64
+	// Head is the start of the content the media type is sniffed from, nil when it isn't known yet
+	Head []byte
+}
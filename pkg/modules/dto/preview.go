@@ -0,0 +1,16 @@
+package dto
+
+import "time"
+
+// DocumentPreview tracks the thumbnails of a document. It is queued once the
+// content of a document is uploaded and marked ready by the previewer.
+type DocumentPreview struct {
+	DocumentID uint      `gorm:"primarykey;autoIncrement:false"`
+	Document   Document  `gorm:"constraint:OnDelete:CASCADE;"`
+	CreatedAt  time.Time `gorm:"<-:create"`
+	UpdatedAt  time.Time
+	Ready      bool
+	Attempts   int
+	LastError  string
+	RetryAt    time.Time `gorm:"index"`
+}
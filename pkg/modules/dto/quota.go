@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"github.com/lib/pq"
+	"time"
+)
+
+// Quota limits the bytes stored by a user, by every user with a role, or by
+// all users of an organization together. Without a quota storage is unlimited.
+type Quota struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"createdAt" gorm:"<-:create"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Subject   string    `json:"subject" binding:"required,oneof=user role organization" gorm:"varchar(20);uniqueIndex:idx_quota_subject"`
+	SubjectID string    `json:"subjectID" binding:"required" gorm:"varchar(255);uniqueIndex:idx_quota_subject"`
+	MaxBytes  int64     `json:"maxBytes" binding:"min=0"`
+}
+
+// StorageUsage is the bytes a user stores in documents and their versions.
+// The roles and the organization are the ones the user last uploaded with,
+// so quotas also apply to content others upload into trees shared by the user.
+type StorageUsage struct {
+	UserID       string         `json:"userID" gorm:"primarykey;varchar(50)"`
+	UpdatedAt    time.Time      `json:"updatedAt"`
+	Bytes        int64          `json:"bytes" gorm:"not null;default:0"`
+	Organization int            `json:"organization,omitempty" gorm:"index"`
+	Roles        pq.StringArray `json:"roles" gorm:"type:text[]"`
+}
+
+// UsageCorrection is the usage of a user recalculated from the content it stores
+type UsageCorrection struct {
+	UserID string `json:"userID"`
+	Before int64  `json:"before"`
+	After  int64  `json:"after"`
+}
+
+// Usage reports the stored bytes against the quotas that apply, a missing limit is unlimited
+type Usage struct {
+	UserID            string `json:"userID"`
+	Bytes             int64  `json:"bytes"`
+	MaxBytes          *int64 `json:"maxBytes"`
+	Organization      int    `json:"organization,omitempty"`
+	OrganizationBytes int64  `json:"organizationBytes,omitempty"`
+	OrganizationMax   *int64 `json:"organizationMaxBytes,omitempty"`
+}
@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+// StoredObject is an object in spaces, or one a document expects to find there
+type StoredObject struct {
+	Key          string     `json:"key"`
+	Size         int64      `json:"size,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	// Checksum is set on the object of a blob
+	Checksum   string `json:"checksum,omitempty"`
+	DocumentID uint   `json:"documentID,omitempty"`
+	Version    uint   `json:"version,omitempty"`
+	// Preview is a thumbnail, which not every document has
+	Preview bool `json:"preview,omitempty"`
+}
+
+// Mismatch is an object in spaces no row points at, or a row whose object is missing
+// from spaces, with what reconciliation did about it
+type Mismatch struct {
+	Class        string     `json:"class"`
+	Key          string     `json:"key"`
+	Checksum     string     `json:"checksum,omitempty"`
+	Size         int64      `json:"size,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	DocumentIDs  []uint     `json:"documentIDs,omitempty"`
+	Version      uint       `json:"version,omitempty"`
+	Action       string     `json:"action"`
+	Error        string     `json:"error,omitempty"`
+}
+
+// Reconciliation reports the mismatches between spaces and the database found in a run
+type Reconciliation struct {
+	DryRun     bool       `json:"dryRun"`
+	Rows       int        `json:"rows"`
+	Objects    int        `json:"objects"`
+	Repaired   int        `json:"repaired"`
+	Mismatches []Mismatch `json:"mismatches"`
+}
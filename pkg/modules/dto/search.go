@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+// DocumentSearch filters, sorts and pages documents, every filter is optional
+type DocumentSearch struct {
+	// Query is matched against the extracted text of documents
+	Query       string     `form:"q"`
+	Name        string     `form:"name"`
+	Extension   string     `form:"extension"`
+	Type        string     `form:"type"`
+	MinSize     *int64     `form:"minSize" binding:"omitempty,min=0"`
+	MaxSize     *int64     `form:"maxSize" binding:"omitempty,min=0"`
+	CreatedFrom *time.Time `form:"createdFrom" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   *time.Time `form:"createdTo" time_format:"2006-01-02T15:04:05Z07:00"`
+	UpdatedFrom *time.Time `form:"updatedFrom" time_format:"2006-01-02T15:04:05Z07:00"`
+	UpdatedTo   *time.Time `form:"updatedTo" time_format:"2006-01-02T15:04:05Z07:00"`
+	Template    *bool      `form:"template"`
+	TreeID      uint       `form:"treeID"`
+	// Sort is a field to order by, a leading minus sorts descending,
+	// rank orders by relevance to the query
+	Sort   string `form:"sort" binding:"omitempty,oneof=name -name size -size createdAt -createdAt updatedAt -updatedAt rank -rank"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor"`
+
+	// UserID and SharedTrees scope the search to documents the caller can access
+	UserID      string `form:"-"`
+	SharedTrees []uint `form:"-"`
+}
+
+// DocumentPage is a page of search results, Next is the cursor of the following page
+type DocumentPage struct {
+	Documents []Document `json:"documents"`
+	Next      string     `json:"next,omitempty"`
+}
@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"time"
+)
+
+// Share is a link to a document or a folder resolved by its token through /s/:token.
+// It stops working once revoked, expired or when its downloads are used up. Only the
+// SHA-256 of the token is stored, the token and the link are returned on creation.
+type Share struct {
+	ID           uint       `json:"id" gorm:"primarykey"`
+	Token        string     `json:"token,omitempty" gorm:"-:all"`
+	TokenHash    string     `json:"-" gorm:"<-:create;varchar(64);uniqueIndex;not null"`
+	Kind         string     `json:"kind" gorm:"<-:create;varchar(20);default:document"`
+	DocumentID   *uint      `json:"documentID,omitempty" gorm:"<-:create;index"`
+	Document     *Document  `json:"-" gorm:"constraint:OnDelete:CASCADE;"`
+	TreeID       uint       `json:"treeID" gorm:"<-:create;index"`
+	Tree         *Tree      `json:"-" gorm:"constraint:OnDelete:CASCADE;"`
+	UserID       string     `json:"userID" gorm:"<-:create;varchar(50);index"`
+	CreatedBy    string     `json:"createdBy" gorm:"<-:create;varchar(50);index"`
+	CreatedAt    time.Time  `json:"createdAt" gorm:"<-:create"`
+	ExpiresAt    time.Time  `json:"expiresAt"`
+	MaxDownloads int        `json:"maxDownloads"`
+	Downloads    int        `json:"downloads" gorm:"not null;default:0"`
+	PasswordHash string     `json:"-" gorm:"<-:create;varchar(60)"`
+	Protected    bool       `json:"protected" gorm:"-:all"`
+	RevokedAt    *time.Time `json:"revokedAt,omitempty"`
+	Link         string     `json:"link,omitempty" gorm:"-:all"`
+}
+
+// ShareOptions limits a new share link, zero values take the defaults:
+// the default lifetime, no password and unlimited downloads
+type ShareOptions struct {
+	ExpiresIn    int64  `json:"expiresIn" binding:"min=0"`
+	Password     string `json:"password" binding:"max=72"`
+	MaxDownloads int    `json:"maxDownloads" binding:"min=0"`
+}
+
+// ShareAccess records an attempt to open a share link and its outcome
+type ShareAccess struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	ShareID   uint      `json:"shareID" gorm:"index"`
+	Share     *Share    `json:"-" gorm:"constraint:OnDelete:CASCADE;"`
+	CreatedAt time.Time `json:"createdAt"`
+	IP        string    `json:"ip" gorm:"varchar(45)"`
+	UserAgent string    `json:"userAgent" gorm:"varchar(512)"`
+	Outcome   string    `json:"outcome" gorm:"varchar(20)"`
+}
+
+// ShareRequest is an attempt to open a share link, or a document in the folder it shares
+type ShareRequest struct {
+	Token      string
+	DocumentID uint
+	Password   string
+	IP         string
+	UserAgent  string
+	Download   Download
+}
+
+// Shared is what a share link opens to, the content of a document
+// or the structure of a folder with the documents in it
+type Shared struct {
+	Document Document
+	Trees    []Tree
+}
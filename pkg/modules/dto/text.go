@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// DocumentText is the text of a document indexed for full-text search. It is
+// queued whenever the content of a document changes and filled in by the extractor.
+type DocumentText struct {
+	DocumentID uint      `gorm:"primarykey;autoIncrement:false"`
+	Document   Document  `gorm:"constraint:OnDelete:CASCADE;"`
+	CreatedAt  time.Time `gorm:"<-:create"`
+	UpdatedAt  time.Time
+	Content    string `gorm:"type:text"`
+	Search     string `gorm:"->;type:tsvector;index:idx_document_texts_search,type:gin"`
+	Extracted  bool
+	Attempts   int
+	LastError  string
+	RetryAt    time.Time `gorm:"index"`
+}
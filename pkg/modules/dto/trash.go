@@ -0,0 +1,15 @@
+package dto
+
+import (
+	"time"
+)
+
+type TrashItem struct {
+	ID        uint       `json:"id" uri:"id" binding:"required"`
+	Kind      string     `json:"kind" uri:"kind" binding:"required,oneof=tree document"`
+	Name      string     `json:"name,omitempty"`
+	ParentID  uint       `json:"parentID,omitempty"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+	PurgeAt   *time.Time `json:"purgeAt,omitempty"`
+	Missing   bool       `json:"missing,omitempty"`
+}
@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"io"
+	"time"
+)
+
+type UploadSession struct {
+	ID        uuid.UUID    `json:"id" gorm:"<-:create;primarykey;type:uuid;default:gen_random_uuid()"`
+	UserID    string       `json:"-" gorm:"<-:create;varchar(50)"`
+	TreeID    uint         `json:"treeID" gorm:"<-:create"`
+	CreatedAt time.Time    `json:"createdAt" gorm:"<-:create"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+	ExpiresAt time.Time    `json:"expiresAt" gorm:"<-:create;index"`
+	Name      string       `json:"name" binding:"required" gorm:"varchar(2000);<-:create"`
+	Extension string       `json:"extension,omitempty" gorm:"varchar(10);<-:create"`
+	Type      string       `json:"type,omitempty" gorm:"varchar(255);<-:create"`
+	Template  *bool        `json:"template,omitempty" gorm:"<-:create;default:false"`
+	Path      uuid.UUID    `json:"-" gorm:"<-:create;type:uuid"`
+	UploadID  string       `json:"-" gorm:"<-:create;varchar(1024)"`
+	Parts     []UploadPart `json:"parts" gorm:"foreignKey:SessionID"`
+}
+
+type UploadPart struct {
+	SessionID      uuid.UUID     `json:"-" gorm:"<-:create;primarykey;type:uuid"`
+	Number         int64         `json:"number" gorm:"<-:create;primarykey;autoIncrement:false"`
+	ETag           string        `json:"etag" gorm:"column:etag;varchar(255)"`
+	Size           int64         `json:"size"`
+	UpdatedAt      time.Time     `json:"updatedAt"`
+	RequestContent io.ReadSeeker `gorm:"-:all" json:"-"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"io"
+	"time"
+)
+
+type DocumentVersion struct {
+	ID              uint          `json:"id" gorm:"<-:create;primarykey;"`
+	DocumentID      uint          `json:"documentID" gorm:"<-:create;index"`
+	UserID          string        `json:"-" gorm:"<-:create;varchar(50)"`
+	Version         uint          `json:"version" gorm:"<-:create;"`
+	CreatedAt       time.Time     `json:"createdAt" gorm:"<-:create;"`
+	Name            string        `json:"name,omitempty" gorm:"varchar(2000);<-:create"`
+	Extension       string        `json:"extension,omitempty" gorm:"varchar(10);<-:create"`
+	Size            int64         `json:"size,omitempty" gorm:"<-:create;"`
+	Type            string        `json:"type,omitempty" gorm:"varchar(255);<-:create"`
+	Checksum        string        `json:"checksum,omitempty" gorm:"<-:create;type:varchar(64);index"`
+	Current         bool          `json:"current" gorm:"-:all"`
+	RequestContent  io.ReadSeeker `gorm:"-:all" json:"-"`
+	ResponseContent io.ReadCloser `gorm:"-:all" json:"-"`
+	Download        Download      `gorm:"-:all" json:"-"`
+}
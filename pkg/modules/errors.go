@@ -0,0 +1,26 @@
+package modules
+
+import "errors"
+
+var (
+	ErrInvalidRange   = errors.New("requested range not satisfiable")
+	ErrNoRootTree     = errors.New("no root folder to restore into")
+	ErrTreeCycle      = errors.New("a folder can't be moved into itself or its subfolder")
+	ErrNoParentTree   = errors.New("a document must be placed into a folder")
+	ErrForbidden      = errors.New("you can not perform this action")
+	ErrInvalidCursor  = errors.New("invalid page cursor")
+	ErrNoPreview      = errors.New("no preview of the document")
+	ErrQuota          = errors.New("storage quota exceeded")
+	ErrInfected       = errors.New("the file contains malware")
+	ErrNotClean       = errors.New("the document is not scanned clean")
+	ErrNotInTree      = errors.New("a selected document is not in the folder")
+	ErrNoShare        = errors.New("the share link does not exist")
+	ErrShareGone      = errors.New("the share link is expired, revoked or used up")
+	ErrSharePassword  = errors.New("the share link requires a valid password")
+	ErrShareLifetime  = errors.New("the share lifetime exceeds the limit")
+	ErrBadArchive     = errors.New("the file is not a readable ZIP archive")
+	ErrArchiveLimits  = errors.New("the archive exceeds the unpacking limits")
+	ErrTooManyRepairs = errors.New("the mismatches to repair exceed the limit of a run")
+	ErrReconciling    = errors.New("another process is reconciling")
+	ErrMissingContent = errors.New("the content of the document is missing")
+)
@@ -0,0 +1,90 @@
+package modules
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Actions guarded by the permission matrix
+const (
+	ReadContent      = "read-content"
+	WriteContent     = "write-content"
+	ShareContent     = "share-content"
+	DeleteAnyContent = "delete-any-content"
+	CreateTemplate   = "create-template"
+	UseTemplate      = "use-template"
+	ManageGroups     = "manage-groups"
+	ReadGroups       = "read-groups"
+	ReadInfo         = "read-info"
+	ManageQuotas     = "manage-quotas"
+)
+
+var actions = []string{
+	ReadContent, WriteContent, ShareContent, DeleteAnyContent,
+	CreateTemplate, UseTemplate, ManageGroups, ReadGroups, ReadInfo,
+	ManageQuotas,
+}
+
+//go:embed permissions.json
+var defaultPermissions []byte
+
+// Permissions maps an action to the roles allowed to perform it.
+// An action missing from the matrix is denied to everyone.
+type Permissions map[string][]string
+
+// LoadPermissions reads the matrix from a JSON file, an empty path loads the default matrix
+func LoadPermissions(path string) (Permissions, error) {
+	content := defaultPermissions
+	if path != "" {
+		var err error
+		if content, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	var permissions Permissions
+	if err := json.Unmarshal(content, &permissions); err != nil {
+		return nil, err
+	}
+
+	for action := range permissions {
+		if !contains(actions, action) {
+			return nil, fmt.Errorf("unknown action in permissions: %s", action)
+		}
+	}
+
+	return permissions, nil
+}
+
+// Roles returns the roles allowed to perform the action
+func (p Permissions) Roles(action string) []string {
+	return p[action]
+}
+
+// Allows reports whether any of the roles may perform the action
+func (p Permissions) Allows(action string, roles []string) bool {
+	for _, role := range roles {
+		if contains(p[action], role) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allowed reports whether the roles of the caller stored in the context may perform the action
+func (p Permissions) Allowed(ctx context.Context, action string) bool {
+	roles, _ := ctx.Value(Roles).([]string)
+	return p.Allows(action, roles)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package modules
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+//go:embed uploads.json
+var defaultUploadPolicy []byte
+
+// UploadRule limits the files accepted by an upload. Entries of the lists are
+// extensions like ".pdf" or media types like "image/png" and "image/*".
+type UploadRule struct {
+	MaxSize int64    `json:"maxSize,omitempty"`
+	Allow   []string `json:"allow,omitempty"`
+	Deny    []string `json:"deny,omitempty"`
+}
+
+// UploadPolicy is the rule every upload follows and the rules of roles. The largest
+// size of the roles of a caller replaces the default one, the files allowed to any
+// of them are accepted and the files denied to any of them are refused.
+type UploadPolicy struct {
+	Default UploadRule            `json:"default"`
+	Roles   map[string]UploadRule `json:"roles"`
+}
+
+// LoadUploadPolicy reads the policy from a JSON file, an empty path loads the default policy
+func LoadUploadPolicy(path string) (*UploadPolicy, error) {
+	content := defaultUploadPolicy
+	if path != "" {
+		var err error
+		if content, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	var policy UploadPolicy
+	if err := json.Unmarshal(content, &policy); err != nil {
+		return nil, err
+	}
+
+	rules := []UploadRule{policy.Default}
+	for _, rule := range policy.Roles {
+		rules = append(rules, rule)
+	}
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &policy, nil
+}
+
+// Validate reports entries that are neither an extension nor a media type
+func (r UploadRule) Validate() error {
+	if r.MaxSize < 0 {
+		return fmt.Errorf("negative max size in upload rule: %d", r.MaxSize)
+	}
+
+	for _, entry := range append(append([]string{}, r.Allow...), r.Deny...) {
+		if !strings.HasPrefix(entry, ".") && !strings.Contains(entry, "/") {
+			return fmt.Errorf("unknown entry in upload rule: %s", entry)
+		}
+	}
+	return nil
+}
+
+// Violation is a rule of the upload policy a file breaks
+type Violation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationError reports every rule of the upload policy a file breaks
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Message)
+	}
+	return "upload rejected: " + strings.Join(messages, "; ")
+}
@@ -0,0 +1,41 @@
+package preview
+
+import (
+	"bytes"
+)
+
+// pdfImage returns the first jpeg image embedded in a document,
+// scanned documents are usually a jpeg per page
+func pdfImage(content []byte) []byte {
+	for offset := 0; ; {
+		filter := bytes.Index(content[offset:], []byte("/DCTDecode"))
+		if filter < 0 {
+			return nil
+		}
+		filter += offset
+		offset = filter + len("/DCTDecode")
+
+		// the stream follows the dictionary naming its filter
+		start := bytes.Index(content[filter:], []byte("stream"))
+		if start < 0 {
+			return nil
+		}
+		data := filter + start + len("stream")
+		if data < len(content) && content[data] == '\r' {
+			data++
+		}
+		if data < len(content) && content[data] == '\n' {
+			data++
+		}
+
+		end := bytes.Index(content[data:], []byte("endstream"))
+		if end < 0 {
+			return nil
+		}
+
+		// the filter may belong to a dictionary of an object without a stream
+		if image := content[data : data+end]; bytes.HasPrefix(image, []byte{0xff, 0xd8}) {
+			return image
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Package preview builds resized WebP thumbnails of images and PDFs.
+//
+// A PDF is previewed by the first JPEG image it embeds, which for scanned documents
+// is the first page. Pages of text or vector drawings are not rendered, so such a PDF
+// has no thumbnail and is reported as unsupported.
+package preview
+
+import (
+	"bytes"
+	"errors"
+	_ "golang.org/x/image/bmp"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"strings"
+)
+
+// ErrUnsupported is returned for contents no thumbnail can be built from
+var ErrUnsupported = errors.New("unsupported document format")
+
+const (
+	// MaxContent is the number of bytes read from a document
+	MaxContent = 64 << 20
+	// MaxPixels bounds the decoded size of an image, so a small file can't exhaust memory
+	MaxPixels = 50_000_000
+	// DefaultSize is the size returned when none is asked for
+	DefaultSize = "medium"
+	// ContentType is the media type of every thumbnail
+	ContentType = "image/webp"
+)
+
+// Sizes maps the name of a thumbnail size to the length of its longer side in pixels
+var Sizes = map[string]int{
+	"small":  160,
+	"medium": 480,
+	"large":  1280,
+}
+
+// Supported reports whether a thumbnail can be built for a document,
+// the format is chosen by the extension and falls back to the media type
+func Supported(extension, contentType string) bool {
+	return format(extension, contentType) != ""
+}
+
+// Thumbnails returns a WebP thumbnail of every size of Sizes by its name
+func Thumbnails(extension, contentType string, r io.Reader) (map[string][]byte, error) {
+	kind := format(extension, contentType)
+	if kind == "" {
+		return nil, ErrUnsupported
+	}
+
+	content, err := io.ReadAll(io.LimitReader(r, MaxContent))
+	if err != nil {
+		return nil, err
+	}
+
+	// a pdf is previewed by the first jpeg it embeds, rendering pages needs a renderer
+	if kind == "pdf" {
+		if content = pdfImage(content); content == nil {
+			return nil, ErrUnsupported
+		}
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width*config.Height > MaxPixels {
+		return nil, errors.New("image is too large to preview")
+	}
+
+	source, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnails := make(map[string][]byte, len(Sizes))
+	for name, size := range Sizes {
+		var buf bytes.Buffer
+		if err = encodeWebP(&buf, resize(source, size)); err != nil {
+			return nil, err
+		}
+		thumbnails[name] = buf.Bytes()
+	}
+
+	return thumbnails, nil
+}
+
+// resize scales an image so its longer side is at most size, smaller images are not enlarged.
+// Transparent parts are laid over white, so a thumbnail looks the same on any background.
+func resize(source image.Image, size int) image.Image {
+	bounds := source.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width > size || height > size {
+		if width >= height {
+			width, height = size, max(1, height*size/width)
+		} else {
+			width, height = max(1, width*size/height), size
+		}
+	}
+
+	thumbnail := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(thumbnail, thumbnail.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(thumbnail, thumbnail.Bounds(), source, bounds, draw.Over, nil)
+
+	return thumbnail
+}
+
+func format(extension, contentType string) string {
+	switch strings.ToLower(strings.TrimPrefix(extension, ".")) {
+	case "jpg", "jpeg", "png", "gif", "webp", "bmp", "tif", "tiff":
+		return "image"
+	case "pdf":
+		return "pdf"
+	}
+
+	switch strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0])) {
+	case "image/jpeg", "image/png", "image/gif", "image/webp", "image/bmp", "image/tiff":
+		return "image"
+	case "application/pdf":
+		return "pdf"
+	}
+
+	return ""
+}
@@ -0,0 +1,128 @@
+package preview
+
+import (
+	"bytes"
+	"errors"
+	"golang.org/x/image/webp"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"testing"
+)
+
+func TestThumbnails(t *testing.T) {
+	type args struct {
+		extension   string
+		contentType string
+		content     []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string]image.Point
+		wantErr error
+	}{
+		{
+			name: "should resize a landscape png",
+			args: args{
+				extension: ".PNG",
+				content:   encode(t, png.Encode, 2000, 1000),
+			},
+			want: map[string]image.Point{
+				"small":  {160, 80},
+				"medium": {480, 240},
+				"large":  {1280, 640},
+			},
+		},
+		{
+			name: "should not enlarge a small jpeg chosen by media type",
+			args: args{
+				contentType: "image/jpeg",
+				content:     encode(t, encodeJPEG, 300, 600),
+			},
+			want: map[string]image.Point{
+				"small":  {80, 160},
+				"medium": {240, 480},
+				"large":  {300, 600},
+			},
+		},
+		{
+			name: "should preview the image embedded in a pdf",
+			args: args{
+				extension: ".pdf",
+				content: bytes.Join([][]byte{
+					[]byte("%PDF-1.4\n1 0 obj\n<< /Type /XObject /Subtype /Image /Filter /DCTDecode >>\nstream\n"),
+					encode(t, encodeJPEG, 100, 100),
+					[]byte("\nendstream\nendobj\n%%EOF"),
+				}, nil),
+			},
+			want: map[string]image.Point{
+				"small":  {100, 100},
+				"medium": {100, 100},
+				"large":  {100, 100},
+			},
+		},
+		{
+			name: "should reject a pdf without images",
+			args: args{
+				extension: ".pdf",
+				content:   []byte("%PDF-1.4\n1 0 obj\n<< /Length 0 >>\nstream\n\nendstream\nendobj\n%%EOF"),
+			},
+			wantErr: ErrUnsupported,
+		},
+		{
+			name: "should reject a document",
+			args: args{
+				extension: ".docx",
+				content:   []byte("PK"),
+			},
+			wantErr: ErrUnsupported,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Thumbnails(tt.args.extension, tt.args.contentType, bytes.NewReader(tt.args.content))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Thumbnails() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Thumbnails() returned %d sizes, want %d", len(got), len(tt.want))
+			}
+
+			for name, want := range tt.want {
+				config, err := webp.DecodeConfig(bytes.NewReader(got[name]))
+				if err != nil {
+					t.Fatalf("Thumbnails() %s is not a webp: %v", name, err)
+				}
+				if config.Width != want.X || config.Height != want.Y {
+					t.Errorf("Thumbnails() %s = %dx%d, want %dx%d", name, config.Width, config.Height, want.X, want.Y)
+				}
+			}
+		})
+	}
+}
+
+func encodeJPEG(w io.Writer, img image.Image) error {
+	return jpeg.Encode(w, img, nil)
+}
+
+// encode returns an image of the size filled with a half transparent colour
+func encode(t *testing.T, encoder func(io.Writer, image.Image) error, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.NRGBA{R: 200, G: 40, B: 40, A: 128})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := encoder(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
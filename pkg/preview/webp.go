@@ -0,0 +1,524 @@
+package preview
+
+import (
+	"bytes"
+	"container/heap"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"io"
+)
+
+// The encoder writes lossless WebP (VP8L). Thumbnails are small, so it keeps to
+// the subtract green and predictor transforms with one set of prefix codes, and
+// backward references only to the pixels on the left and above, which is what
+// shrinks the plain background of scans and drawings. There is no color cache.
+const (
+	// tileBits is the log-2 side of the tiles a predictor is chosen for
+	tileBits = 4
+	// maxCodeLength bounds the prefix codes of pixels, maxLengthCodeLength the code of their lengths
+	maxCodeLength       = 15
+	maxLengthCodeLength = 7
+	// minCopy is the shortest run worth a backward reference, maxCopy the longest one
+	minCopy = 3
+	maxCopy = 4096
+	// aboveCode and leftCode are the distance codes of the pixel above and on the left
+	aboveCode = 1
+	leftCode  = 2
+)
+
+// predictors are the modes tried for every tile: left, top, the average of both and select
+var predictors = []uint32{1, 2, 7, 11}
+
+// lengthCodeOrder is the order the lengths of the code of code lengths are written in
+var lengthCodeOrder = []int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// alphabets are the sizes of the green, red, blue, alpha and distance codes
+var alphabets = []int{256 + 24, 256, 256, 256, 40}
+
+// encodeWebP writes an image as a lossless WebP
+func encodeWebP(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	opaque := true
+	pixels := make([]uint32, 0, width*height)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			opaque = opaque && c.A == 0xff
+			pixels = append(pixels, uint32(c.A)<<24|uint32(c.R)<<16|uint32(c.G)<<8|uint32(c.B))
+		}
+	}
+
+	var bits bitWriter
+	bits.write(0x2f, 8)
+	bits.write(uint32(width-1), 14)
+	bits.write(uint32(height-1), 14)
+	if opaque {
+		bits.write(0, 1)
+	} else {
+		bits.write(1, 1)
+	}
+	bits.write(0, 3)
+
+	// the transforms are undone in the reverse order, so green is added back last
+	bits.write(1, 1)
+	bits.write(2, 2)
+	subtractGreen(pixels)
+
+	bits.write(1, 1)
+	bits.write(0, 2)
+	bits.write(tileBits-2, 3)
+	modes, residuals := predict(pixels, width, height)
+	writeImage(&bits, modes, tiles(width), false)
+
+	bits.write(0, 1)
+	writeImage(&bits, residuals, width, true)
+
+	data := bits.bytes()
+	padded := len(data) + len(data)%2
+
+	var out bytes.Buffer
+	out.WriteString("RIFF")
+	_ = binary.Write(&out, binary.LittleEndian, uint32(4+8+padded))
+	out.WriteString("WEBPVP8L")
+	_ = binary.Write(&out, binary.LittleEndian, uint32(len(data)))
+	out.Write(data)
+	if padded != len(data) {
+		out.WriteByte(0)
+	}
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+func subtractGreen(pixels []uint32) {
+	for i, p := range pixels {
+		green := p >> 8 & 0xff
+		red := (p>>16 - green) & 0xff
+		blue := (p - green) & 0xff
+		pixels[i] = p&0xff00ff00 | red<<16 | blue
+	}
+}
+
+// predict chooses the predictor of every tile by the smallest residuals and returns
+// the image of the modes with the residuals of the pixels
+func predict(pixels []uint32, width, height int) ([]uint32, []uint32) {
+	tilesX, tilesY := tiles(width), tiles(height)
+	modes := make([]uint32, tilesX*tilesY)
+	residuals := make([]uint32, len(pixels))
+
+	for ty := 0; ty < tilesY; ty++ {
+		for tx := 0; tx < tilesX; tx++ {
+			best, bestCost := predictors[0], -1
+			for _, mode := range predictors {
+				cost := 0
+				forTile(tx, ty, width, height, func(x, y int) {
+					cost += magnitude(sub(pixels[y*width+x], prediction(pixels, width, x, y, mode)))
+				})
+				if bestCost < 0 || cost < bestCost {
+					best, bestCost = mode, cost
+				}
+			}
+
+			modes[ty*tilesX+tx] = 0xff000000 | best<<8
+			forTile(tx, ty, width, height, func(x, y int) {
+				residuals[y*width+x] = sub(pixels[y*width+x], prediction(pixels, width, x, y, best))
+			})
+		}
+	}
+
+	return modes, residuals
+}
+
+func tiles(size int) int {
+	return (size + 1<<tileBits - 1) >> tileBits
+}
+
+func forTile(tx, ty, width, height int, fn func(x, y int)) {
+	for y := ty << tileBits; y < min(height, (ty+1)<<tileBits); y++ {
+		for x := tx << tileBits; x < min(width, (tx+1)<<tileBits); x++ {
+			fn(x, y)
+		}
+	}
+}
+
+// prediction returns the value a pixel is predicted by, the first pixel, row and
+// column have fixed predictors whatever the mode of their tile
+func prediction(pixels []uint32, width, x, y int, mode uint32) uint32 {
+	i := y*width + x
+	switch {
+	case x == 0 && y == 0:
+		return 0xff000000
+	case y == 0:
+		return pixels[i-1]
+	case x == 0:
+		return pixels[i-width]
+	}
+
+	left, top, topLeft := pixels[i-1], pixels[i-width], pixels[i-width-1]
+	switch mode {
+	case 1:
+		return left
+	case 2:
+		return top
+	case 7:
+		return average(left, top)
+	default:
+		if distance(top, topLeft) < distance(left, topLeft) {
+			return left
+		}
+		return top
+	}
+}
+
+func sub(a, b uint32) uint32 {
+	var r uint32
+	for shift := 0; shift < 32; shift += 8 {
+		r |= (a>>shift - b>>shift) & 0xff << shift
+	}
+	return r
+}
+
+func average(a, b uint32) uint32 {
+	var r uint32
+	for shift := 0; shift < 32; shift += 8 {
+		r |= (a>>shift&0xff + b>>shift&0xff) / 2 << shift
+	}
+	return r
+}
+
+func distance(a, b uint32) int {
+	var d int
+	for shift := 0; shift < 32; shift += 8 {
+		d += abs(int(a>>shift&0xff) - int(b>>shift&0xff))
+	}
+	return d
+}
+
+// magnitude estimates the cost of a residual, small values either side of zero are cheap
+func magnitude(residual uint32) int {
+	var m int
+	for shift := 0; shift < 32; shift += 8 {
+		v := int(residual >> shift & 0xff)
+		m += min(v, 256-v)
+	}
+	return m
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// token is a literal pixel, or a copy of length pixels from the distance code
+type token struct {
+	pixel  uint32
+	length int
+	code   int
+}
+
+// writeImage writes the pixels of an image of the width with one prefix code for each channel
+func writeImage(bits *bitWriter, pixels []uint32, width int, main bool) {
+	// no color cache, and the main image has no meta prefix codes
+	bits.write(0, 1)
+	if main {
+		bits.write(0, 1)
+	}
+
+	tokens := references(pixels, width)
+
+	histograms := make([][]int, len(alphabets))
+	for i, size := range alphabets {
+		histograms[i] = make([]int, size)
+	}
+	for _, t := range tokens {
+		if t.length > 0 {
+			length, _, _ := prefix(t.length)
+			code, _, _ := prefix(t.code)
+			histograms[0][256+length]++
+			histograms[4][code]++
+			continue
+		}
+		histograms[0][t.pixel>>8&0xff]++
+		histograms[1][t.pixel>>16&0xff]++
+		histograms[2][t.pixel&0xff]++
+		histograms[3][t.pixel>>24]++
+	}
+
+	codes := make([]prefixCode, len(alphabets))
+	for i, histogram := range histograms {
+		codes[i] = newPrefixCode(histogram, maxCodeLength)
+		codes[i].writeTo(bits)
+	}
+
+	for _, t := range tokens {
+		if t.length > 0 {
+			symbol, extraBits, extra := prefix(t.length)
+			codes[0].writeSymbol(bits, 256+symbol)
+			bits.write(extra, extraBits)
+
+			symbol, extraBits, extra = prefix(t.code)
+			codes[4].writeSymbol(bits, symbol)
+			bits.write(extra, extraBits)
+			continue
+		}
+		codes[0].writeSymbol(bits, int(t.pixel>>8&0xff))
+		codes[1].writeSymbol(bits, int(t.pixel>>16&0xff))
+		codes[2].writeSymbol(bits, int(t.pixel&0xff))
+		codes[3].writeSymbol(bits, int(t.pixel>>24))
+	}
+}
+
+// references replaces runs repeating the pixels on the left or above with copies of them
+func references(pixels []uint32, width int) []token {
+	tokens := make([]token, 0, len(pixels))
+	for i := 0; i < len(pixels); {
+		length, code := matching(pixels, i, 1), leftCode
+		if above := matching(pixels, i, width); above > length {
+			length, code = above, aboveCode
+		}
+
+		if length < minCopy {
+			tokens = append(tokens, token{pixel: pixels[i]})
+			i++
+			continue
+		}
+
+		tokens = append(tokens, token{length: length, code: code})
+		i += length
+	}
+	return tokens
+}
+
+// matching returns how many pixels from i repeat the ones the distance back
+func matching(pixels []uint32, i, distance int) int {
+	if i < distance {
+		return 0
+	}
+
+	n := 0
+	for i+n < len(pixels) && n < maxCopy && pixels[i+n] == pixels[i+n-distance] {
+		n++
+	}
+	return n
+}
+
+// prefix splits a length or a distance code into its symbol and extra bits
+func prefix(value int) (int, uint, uint32) {
+	n := value - 1
+	if n < 4 {
+		return n, 0, 0
+	}
+
+	high := 0
+	for n>>(high+1) > 0 {
+		high++
+	}
+	second := n >> (high - 1) & 1
+	return 2*high + second, uint(high - 1), uint32(n & (1<<(high-1) - 1))
+}
+
+// prefixCode is a canonical Huffman code. A code of one symbol takes no bits.
+type prefixCode struct {
+	lengths []int
+	codes   []uint32
+	used    []int
+}
+
+func newPrefixCode(histogram []int, limit int) prefixCode {
+	code := prefixCode{lengths: make([]int, len(histogram)), codes: make([]uint32, len(histogram))}
+	for symbol, count := range histogram {
+		if count > 0 {
+			code.used = append(code.used, symbol)
+		}
+	}
+
+	switch len(code.used) {
+	case 0:
+		code.used = []int{0}
+		fallthrough
+	case 1:
+		code.lengths[code.used[0]] = 1
+		return code
+	}
+
+	counts := append([]int(nil), histogram...)
+	for {
+		code.lengths = huffmanLengths(counts)
+		if maxLength(code.lengths) <= limit {
+			break
+		}
+		// flatter counts give shorter codes, all equal counts give a balanced tree
+		for i, count := range counts {
+			if count > 0 {
+				counts[i] = (count + 1) / 2
+			}
+		}
+	}
+
+	// canonical codes are assigned by length, then by symbol
+	var lengthCounts [maxCodeLength + 2]uint32
+	for _, length := range code.lengths {
+		lengthCounts[length]++
+	}
+	lengthCounts[0] = 0
+
+	var next [maxCodeLength + 2]uint32
+	for length, value := 1, uint32(0); length < len(next); length++ {
+		value = (value + lengthCounts[length-1]) << 1
+		next[length] = value
+	}
+	for symbol, length := range code.lengths {
+		if length > 0 {
+			code.codes[symbol] = next[length]
+			next[length]++
+		}
+	}
+
+	return code
+}
+
+// writeTo writes the code, up to two symbols below 256 fit the simple form
+func (c prefixCode) writeTo(bits *bitWriter) {
+	if len(c.used) <= 2 && c.used[len(c.used)-1] < 256 {
+		bits.write(1, 1)
+		bits.write(uint32(len(c.used)-1), 1)
+		if c.used[0] < 2 {
+			bits.write(0, 1)
+			bits.write(uint32(c.used[0]), 1)
+		} else {
+			bits.write(1, 1)
+			bits.write(uint32(c.used[0]), 8)
+		}
+		if len(c.used) == 2 {
+			bits.write(uint32(c.used[1]), 8)
+		}
+		return
+	}
+
+	bits.write(0, 1)
+
+	histogram := make([]int, len(lengthCodeOrder))
+	for _, length := range c.lengths {
+		histogram[length]++
+	}
+	lengthCode := newPrefixCode(histogram, maxLengthCodeLength)
+
+	count := len(lengthCodeOrder)
+	for count > 4 && lengthCode.lengths[lengthCodeOrder[count-1]] == 0 {
+		count--
+	}
+	bits.write(uint32(count-4), 4)
+	for _, symbol := range lengthCodeOrder[:count] {
+		bits.write(uint32(lengthCode.lengths[symbol]), 3)
+	}
+
+	// every length is written, so the number of them is left out
+	bits.write(0, 1)
+	for _, length := range c.lengths {
+		lengthCode.writeSymbol(bits, length)
+	}
+}
+
+func (c prefixCode) writeSymbol(bits *bitWriter, symbol int) {
+	if len(c.used) == 1 {
+		return
+	}
+
+	// codes are read from their most significant bit
+	length := c.lengths[symbol]
+	var reversed uint32
+	for i := 0; i < length; i++ {
+		reversed |= (c.codes[symbol] >> i & 1) << (length - 1 - i)
+	}
+	bits.write(reversed, uint(length))
+}
+
+func maxLength(lengths []int) int {
+	var m int
+	for _, length := range lengths {
+		m = max(m, length)
+	}
+	return m
+}
+
+// huffmanLengths returns the code length of every symbol of a histogram with at least two used symbols
+func huffmanLengths(histogram []int) []int {
+	nodes := &nodeHeap{}
+	parents := make([]int, 0, 2*len(histogram))
+	leaves := make([]int, len(histogram))
+	for symbol, count := range histogram {
+		leaves[symbol] = -1
+		if count > 0 {
+			leaves[symbol] = len(parents)
+			heap.Push(nodes, node{count: count, index: len(parents)})
+			parents = append(parents, -1)
+		}
+	}
+
+	for nodes.Len() > 1 {
+		a, b := heap.Pop(nodes).(node), heap.Pop(nodes).(node)
+		parent := len(parents)
+		parents = append(parents, -1)
+		parents[a.index], parents[b.index] = parent, parent
+		heap.Push(nodes, node{count: a.count + b.count, index: parent})
+	}
+
+	lengths := make([]int, len(histogram))
+	for symbol, leaf := range leaves {
+		for i := leaf; i >= 0 && parents[i] >= 0; i = parents[i] {
+			lengths[symbol]++
+		}
+	}
+	return lengths
+}
+
+type node struct {
+	count int
+	index int
+}
+
+// nodeHeap orders nodes by count, ties by index so codes don't depend on the heap
+type nodeHeap []node
+
+func (h nodeHeap) Len() int { return len(h) }
+func (h nodeHeap) Less(i, j int) bool {
+	return h[i].count < h[j].count || (h[i].count == h[j].count && h[i].index < h[j].index)
+}
+func (h nodeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *nodeHeap) Push(x interface{}) { *h = append(*h, x.(node)) }
+func (h *nodeHeap) Pop() interface{} {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+// bitWriter packs values from their least significant bit
+type bitWriter struct {
+	buf   []byte
+	acc   uint64
+	count uint
+}
+
+func (b *bitWriter) write(value uint32, n uint) {
+	b.acc |= uint64(value) << b.count
+	b.count += n
+	for b.count >= 8 {
+		b.buf = append(b.buf, byte(b.acc))
+		b.acc >>= 8
+		b.count -= 8
+	}
+}
+
+func (b *bitWriter) bytes() []byte {
+	if b.count > 0 {
+		b.buf = append(b.buf, byte(b.acc))
+		b.acc, b.count = 0, 0
+	}
+	return b.buf
+}
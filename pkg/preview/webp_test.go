@@ -0,0 +1,106 @@
+package preview
+
+import (
+	"bytes"
+	"golang.org/x/image/webp"
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+)
+
+func TestEncodeWebP(t *testing.T) {
+	tests := []struct {
+		name   string
+		width  int
+		height int
+		pixel  func(x, y int) color.NRGBA
+	}{
+		{
+			name:   "should encode a single pixel",
+			width:  1,
+			height: 1,
+			pixel:  func(x, y int) color.NRGBA { return color.NRGBA{R: 10, G: 20, B: 30, A: 255} },
+		},
+		{
+			name:   "should encode a solid image",
+			width:  40,
+			height: 30,
+			pixel:  func(x, y int) color.NRGBA { return color.NRGBA{R: 255, G: 255, B: 255, A: 255} },
+		},
+		{
+			name:   "should encode a gradient with partial tiles",
+			width:  53,
+			height: 37,
+			pixel: func(x, y int) color.NRGBA {
+				return color.NRGBA{R: uint8(x * 4), G: uint8(y * 6), B: uint8(x + y), A: 255}
+			},
+		},
+		{
+			name:   "should encode noise with transparency",
+			width:  64,
+			height: 48,
+			pixel: func() func(x, y int) color.NRGBA {
+				random := rand.New(rand.NewSource(1))
+				return func(x, y int) color.NRGBA {
+					return color.NRGBA{R: uint8(random.Intn(256)), G: uint8(random.Intn(256)), B: uint8(random.Intn(256)), A: uint8(random.Intn(256))}
+				}
+			}(),
+		},
+		{
+			name:   "should encode a page longer than one copy",
+			width:  300,
+			height: 200,
+			pixel:  func(x, y int) color.NRGBA { return color.NRGBA{R: 255, G: 255, B: 255, A: 255} },
+		},
+		{
+			name:   "should encode rows repeating the one above",
+			width:  70,
+			height: 20,
+			pixel: func(x, y int) color.NRGBA {
+				return color.NRGBA{R: uint8(x * x * 31), G: uint8(x * 57), B: uint8(x ^ 0x5a), A: 255}
+			},
+		},
+		{
+			name:   "should encode a wide strip",
+			width:  1280,
+			height: 2,
+			pixel: func(x, y int) color.NRGBA {
+				return color.NRGBA{R: uint8(x % 7 * 30), G: uint8(x / 5), B: uint8(y * 200), A: 255}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := image.NewNRGBA(image.Rect(0, 0, tt.width, tt.height))
+			for y := 0; y < tt.height; y++ {
+				for x := 0; x < tt.width; x++ {
+					img.SetNRGBA(x, y, tt.pixel(x, y))
+				}
+			}
+
+			var buf bytes.Buffer
+			if err := encodeWebP(&buf, img); err != nil {
+				t.Fatalf("encodeWebP() error = %v", err)
+			}
+
+			decoded, err := webp.Decode(&buf)
+			if err != nil {
+				t.Fatalf("encodeWebP() wrote an invalid webp: %v", err)
+			}
+
+			if decoded.Bounds() != img.Bounds() {
+				t.Fatalf("encodeWebP() bounds = %v, want %v", decoded.Bounds(), img.Bounds())
+			}
+
+			for y := 0; y < tt.height; y++ {
+				for x := 0; x < tt.width; x++ {
+					got := color.NRGBAModel.Convert(decoded.At(x, y)).(color.NRGBA)
+					if want := img.NRGBAAt(x, y); got != want {
+						t.Fatalf("encodeWebP() pixel %d,%d = %v, want %v", x, y, got, want)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Package upload inspects uploaded files: it sniffs their media type from the
+// content and cleans their names before they are stored
+package upload
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// SniffLen is the number of leading bytes the media type is detected from
+	SniffLen = 512
+	// MaxName is the number of characters a file name is cut to, keeping its extension
+	MaxName = 255
+	// MaxExtension is the length of the extension column, longer ones aren't extensions
+	MaxExtension = 10
+)
+
+// OctetStream is the media type of content of an unknown format
+const OctetStream = "application/octet-stream"
+
+// executables are the headers of the binaries http.DetectContentType doesn't know
+var executables = []struct {
+	magic       []byte
+	contentType string
+}{
+	{[]byte("MZ"), "application/x-msdownload"},
+	{[]byte("\x7fELF"), "application/x-executable"},
+	{[]byte("\xfe\xed\xfa\xce"), "application/x-mach-binary"},
+	{[]byte("\xfe\xed\xfa\xcf"), "application/x-mach-binary"},
+	{[]byte("\xce\xfa\xed\xfe"), "application/x-mach-binary"},
+	{[]byte("\xcf\xfa\xed\xfe"), "application/x-mach-binary"},
+}
+
+// Head reads the leading bytes of a content and rewinds it
+func Head(r io.ReadSeeker) ([]byte, error) {
+	head := make([]byte, SniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+
+	if _, err = r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return head[:n], nil
+}
+
+// DetectType returns the media type of a content by its leading bytes, without parameters
+func DetectType(head []byte) string {
+	for _, executable := range executables {
+		if bytes.HasPrefix(head, executable.magic) {
+			return executable.contentType
+		}
+	}
+
+	return BaseType(http.DetectContentType(head))
+}
+
+// BaseType strips the parameters of a media type, an invalid one is empty
+func BaseType(contentType string) string {
+	base, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return base
+}
+
+// Compatible reports whether a declared media type can describe a content of the
+// sniffed type. Sniffing only tells families apart, so a text or a zip based
+// format and media of the same kind are accepted under any fitting name.
+func Compatible(declared, sniffed string) bool {
+	if declared == sniffed || declared == "" || declared == OctetStream || sniffed == OctetStream {
+		return true
+	}
+
+	declaredKind, _, _ := strings.Cut(declared, "/")
+	sniffedKind, _, _ := strings.Cut(sniffed, "/")
+
+	switch sniffedKind {
+	case "text":
+		return declaredKind == "text" || textual(declared)
+	case "image", "audio", "video":
+		return declaredKind == sniffedKind
+	}
+
+	if sniffed == "application/zip" {
+		return zipped(declared)
+	}
+
+	return false
+}
+
+// textual reports whether an application media type is written as text
+func textual(contentType string) bool {
+	for _, suffix := range []string{"json", "xml", "javascript", "x-sh", "sql", "x-yaml", "x-tex", "rtf"} {
+		if strings.HasSuffix(contentType, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// zipped reports whether a media type is a format stored as a zip archive
+func zipped(contentType string) bool {
+	for _, kind := range []string{"zip", "openxmlformats", "opendocument", "java-archive", "package-archive"} {
+		if strings.Contains(contentType, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// SanitizeName keeps the last element of a path and drops control and reserved
+// characters, leading and trailing dots and spaces. A name too long for a file
+// system is cut keeping its extension. Nothing may remain of an invalid name.
+func SanitizeName(name string) string {
+	// some browsers send the full path of a file
+	name = name[strings.LastIndexAny(name, `/\`)+1:]
+
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == utf8.RuneError || strings.ContainsRune(`<>:"|?*`, r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.Trim(name, " .")
+
+	if utf8.RuneCountInString(name) <= MaxName {
+		return name
+	}
+
+	extension := []rune(Extension(name))
+	base := []rune(strings.TrimSuffix(name, string(extension)))
+	return strings.TrimRight(string(base[:MaxName-len(extension)]), " .") + string(extension)
+}
+
+// Extension returns the extension of a name, one too long to be stored isn't an extension
+func Extension(name string) string {
+	extension := filepath.Ext(name)
+	if len(extension) < 2 || len(extension) > MaxExtension {
+		return ""
+	}
+	return extension
+}
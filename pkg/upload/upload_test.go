@@ -0,0 +1,103 @@
+package upload
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectType(t *testing.T) {
+	tests := []struct {
+		name string
+		head []byte
+		want string
+	}{
+		{name: "should detect a pdf", head: []byte("%PDF-1.7\n%âãÏÓ"), want: "application/pdf"},
+		{name: "should detect a windows executable", head: []byte("MZ\x90\x00\x03\x00\x00\x00"), want: "application/x-msdownload"},
+		{name: "should detect a linux executable", head: []byte("\x7fELF\x02\x01\x01"), want: "application/x-executable"},
+		{name: "should strip the charset of text", head: []byte("Алгебра және геометрия"), want: "text/plain"},
+		{name: "should detect a zip", head: []byte("PK\x03\x04\x14\x00\x06\x00"), want: "application/zip"},
+		{name: "should not know an empty content", head: []byte{}, want: "text/plain"},
+		{name: "should not know binary noise", head: []byte{0x00, 0x01, 0x02, 0x03}, want: OctetStream},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectType(tt.head); got != tt.want {
+				t.Errorf("DetectType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompatible(t *testing.T) {
+	tests := []struct {
+		name     string
+		declared string
+		sniffed  string
+		want     bool
+	}{
+		{name: "should accept the same type", declared: "application/pdf", sniffed: "application/pdf", want: true},
+		{name: "should accept an undeclared type", declared: "", sniffed: "application/pdf", want: true},
+		{name: "should accept an unknown content", declared: "application/pdf", sniffed: OctetStream, want: true},
+		{name: "should accept json as text", declared: "application/json", sniffed: "text/plain", want: true},
+		{name: "should accept csv as text", declared: "text/csv", sniffed: "text/plain", want: true},
+		{name: "should accept an office document as zip", declared: "application/vnd.openxmlformats-officedocument.wordprocessingml.document", sniffed: "application/zip", want: true},
+		{name: "should accept an image of another format", declared: "image/jpg", sniffed: "image/png", want: true},
+		{name: "should refuse an executable labelled as pdf", declared: "application/pdf", sniffed: "application/x-msdownload", want: false},
+		{name: "should refuse a pdf labelled as image", declared: "image/png", sniffed: "application/pdf", want: false},
+		{name: "should refuse text labelled as zip", declared: "application/zip", sniffed: "text/plain", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Compatible(tt.declared, tt.sniffed); got != tt.want {
+				t.Errorf("Compatible() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	long := strings.Repeat("ә", 300)
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "should keep a plain name", input: "Силлабус 2023.docx", want: "Силлабус 2023.docx"},
+		{name: "should keep the base of a windows path", input: `C:\Users\student\report.pdf`, want: "report.pdf"},
+		{name: "should drop a traversal", input: "../../etc/passwd", want: "passwd"},
+		{name: "should drop control characters", input: "re\x00po\nrt\u200e.pdf", want: "report\u200e.pdf"},
+		{name: "should drop reserved characters", input: `what?<is>"this"|*.txt`, want: "whatisthis.txt"},
+		{name: "should trim dots and spaces", input: "  ..hidden. ", want: "hidden"},
+		{name: "should leave nothing of a dot name", input: "..", want: ""},
+		{name: "should cut a long name keeping its extension", input: long + ".pdf", want: strings.Repeat("ә", MaxName-4) + ".pdf"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeName(tt.input); got != tt.want {
+				t.Errorf("SanitizeName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtension(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "should return the extension", input: "report.PDF", want: ".PDF"},
+		{name: "should return the last extension", input: "archive.tar.gz", want: ".gz"},
+		{name: "should ignore a bare dot", input: "report.", want: ""},
+		{name: "should ignore a too long extension", input: "notes.averyverylongone", want: ""},
+		{name: "should ignore a missing extension", input: "Makefile", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Extension(tt.input); got != tt.want {
+				t.Errorf("Extension() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
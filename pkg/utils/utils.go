@@ -1,8 +1,54 @@
 package utils
 
-import "strconv"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strconv"
+	"time"
+)
+
+// maxBackoff caps the delay between attempts of a queued job
+const maxBackoff = time.Hour
 
 func ParseUint(s string) (uint, error) {
 	parsed, err := strconv.ParseUint(s, 10, 64)
 	return uint(parsed), err
 }
+
+// Checksum returns the hex encoded SHA-256 of the content streamed from r
+func Checksum(r io.Reader) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// ChecksumSeeker returns the checksum of the content of r and rewinds it,
+// so the same content can be uploaded afterwards
+func ChecksumSeeker(r io.ReadSeeker) (string, error) {
+	checksum, err := Checksum(r)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = r.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return checksum, nil
+}
+
+// Backoff returns the delay before the next attempt of a queued job, starting at a minute
+// and doubling after every failed attempt up to an hour
+func Backoff(attempts int) time.Duration {
+	delay := time.Minute
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > maxBackoff {
+		return maxBackoff
+	}
+	return delay
+}
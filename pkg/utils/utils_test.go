@@ -1,6 +1,11 @@
 package utils
 
-import "testing"
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestParseUint(t *testing.T) {
 	type args struct {
@@ -58,3 +63,93 @@ func TestParseUint(t *testing.T) {
 		})
 	}
 }
+
+func TestChecksumSeeker(t *testing.T) {
+	type args struct {
+		content string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "should hash empty content",
+			args: args{
+				content: "",
+			},
+			want: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+		{
+			name: "should hash content",
+			args: args{
+				content: "abc",
+			},
+			want: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := strings.NewReader(tt.args.content)
+			got, err := ChecksumSeeker(r)
+			if err != nil {
+				t.Fatalf("ChecksumSeeker() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ChecksumSeeker() got = %v, want %v", got, tt.want)
+			}
+
+			rest, _ := io.ReadAll(r)
+			if string(rest) != tt.args.content {
+				t.Errorf("ChecksumSeeker() left %q to read, want %q", rest, tt.args.content)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	type args struct {
+		attempts int
+	}
+	tests := []struct {
+		name string
+		args args
+		want time.Duration
+	}{
+		{
+			name: "should wait a minute after the first attempt",
+			args: args{
+				attempts: 1,
+			},
+			want: time.Minute,
+		},
+		{
+			name: "should double the delay after every attempt",
+			args: args{
+				attempts: 4,
+			},
+			want: 8 * time.Minute,
+		},
+		{
+			name: "should wait at most an hour",
+			args: args{
+				attempts: 7,
+			},
+			want: time.Hour,
+		},
+		{
+			name: "should stay at an hour after many attempts",
+			args: args{
+				attempts: 1000,
+			},
+			want: time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Backoff(tt.args.attempts); got != tt.want {
+				t.Errorf("Backoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}